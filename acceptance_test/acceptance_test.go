@@ -10,10 +10,13 @@ import (
 	"net/url"
 	"os"
 	"testing"
+	"time"
 
 	"theskyinflames/car-sharing/cmd/service"
 	"theskyinflames/car-sharing/internal/app"
 	"theskyinflames/car-sharing/internal/infra/api"
+	"theskyinflames/car-sharing/internal/infra/repository"
+	"theskyinflames/car-sharing/internal/infra/webhooks"
 
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
@@ -23,11 +26,17 @@ const srvPort = ":8080"
 
 func TestAcceptanceTest(t *testing.T) {
 	log := log.New(os.Stdout, "car-sharing: ", os.O_APPEND)
-	commandBus := app.BuildCommandQueryBus(log, app.BuildEventsBus())
+	gr := repository.NewGroupsRepository()
+	evr := repository.NewCarRepository()
+	hr := repository.NewJourneysHistoryRepository()
+	wr := repository.NewWebhooksRepository()
+	wn := app.NewWebhooksNotifier(wr, webhooks.NewSender(time.Second), log, 1, 0)
+	commandBus := app.BuildCommandQueryBus(log, app.BuildEventsBus(log, hr, wn, app.NewEventsHub()), &gr, &evr, repository.NewUnitOfWork(&gr, &evr), hr, wr, repository.NewReservationsRepository())
 
 	ctx, cancel := context.WithCancel(context.Background())
-	go service.Run(ctx, srvPort)
+	go service.Run(ctx, srvPort, service.Config{})
 	defer cancel()
+	waitForServer(t)
 
 	var (
 		carID1 = uuid.New().String()
@@ -120,8 +129,9 @@ func TestAcceptanceTest(t *testing.T) {
 
 		t.Run(`when the group with id=gID1 and 3 people is located, then car with six seats is returned`, func(t *testing.T) {
 			expectedRs := api.LocateRsJson{
-				Id:    carID2,
-				Seats: 6,
+				Id:     carID2,
+				Seats:  6,
+				Status: api.LocateRsJsonStatusAvailable,
 			}
 			unmarshalRsFunc := func(t *testing.T, b []byte) any {
 				var rs api.LocateRsJson
@@ -142,8 +152,9 @@ func TestAcceptanceTest(t *testing.T) {
 
 		t.Run(`when the group of 4 people is located, then car with four seats is returned`, func(t *testing.T) {
 			expectedRs := api.LocateRsJson{
-				Id:    carID1,
-				Seats: 4,
+				Id:     carID1,
+				Seats:  4,
+				Status: api.LocateRsJsonStatusAvailable,
 			}
 			unmarshalRsFunc := func(t *testing.T, b []byte) any {
 				var rs api.LocateRsJson
@@ -231,8 +242,9 @@ func TestAcceptanceTest(t *testing.T) {
 			})
 
 			expectedRs := api.LocateRsJson{
-				Id:    carID2,
-				Seats: 6,
+				Id:     carID2,
+				Seats:  6,
+				Status: api.LocateRsJsonStatusAvailable,
 			}
 			unmarshalRsFunc := func(t *testing.T, b []byte) any {
 				var rs api.LocateRsJson
@@ -261,6 +273,36 @@ func TestAcceptanceTest(t *testing.T) {
 				nil,
 			})
 		})
+
+		t.Run(`when the metrics endpoint is called, then the fleet state is exported`, func(t *testing.T) {
+			resp, err := http.Get("http://localhost" + srvPort + "/metrics")
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			body, err := io.ReadAll(resp.Body)
+			require.NoError(t, err)
+			require.Contains(t, string(body), "car_sharing_fleet_seats 10")
+			require.Contains(t, string(body), "car_sharing_fleet_occupied_seats 8")
+			require.Contains(t, string(body), `car_sharing_queue_waiting_groups{people="4"} 1`)
+			require.Contains(t, string(body), `car_sharing_bus_handler_duration_seconds_count{command="dropOff.group"} 1`)
+		})
+
+		t.Run(`when the wait times endpoint is called, then the groups that got on a car are reported by size`, func(t *testing.T) {
+			resp, err := http.Get("http://localhost" + srvPort + "/v1/stats/wait-times?window=1h")
+			require.NoError(t, err)
+			defer resp.Body.Close()
+			require.Equal(t, http.StatusOK, resp.StatusCode)
+
+			var rs api.WaitTimesRsJson
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&rs))
+			require.Equal(t, float64(3600), rs.WindowSeconds)
+			require.Len(t, rs.Groups, 2)
+			require.Equal(t, 3, rs.Groups[0].People)
+			require.Equal(t, 1, rs.Groups[0].Journeys)
+			require.Equal(t, 4, rs.Groups[1].People)
+			require.Equal(t, 2, rs.Groups[1].Journeys)
+		})
 	})
 }
 
@@ -323,3 +365,15 @@ func buildJSONRq(t *testing.T, rq interface{}) *bytes.Buffer {
 	require.NoError(t, err)
 	return bytes.NewBuffer(b)
 }
+
+func waitForServer(t *testing.T) {
+	for i := 0; i < 50; i++ {
+		resp, err := http.Get("http://localhost" + srvPort + "/status")
+		if err == nil {
+			resp.Body.Close()
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	t.Fatal("the server did not start")
+}
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"log"
 
 	"theskyinflames/car-sharing/cmd/service"
 )
@@ -9,5 +10,9 @@ import (
 const srvPort = ":80"
 
 func main() {
-	service.Run(context.Background(), srvPort)
+	cfg, err := service.NewConfigFromEnv()
+	if err != nil {
+		log.Fatalf("wrong configuration: %s", err.Error())
+	}
+	service.Run(context.Background(), srvPort, cfg)
 }
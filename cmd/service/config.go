@@ -0,0 +1,150 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+)
+
+// Config is the service configuration
+type Config struct {
+	// AssignmentStrategy is the name of the strategy used to choose the car where a group gets on.
+	// Allowed values are best-fit, worst-fit, first-fit and nearest. By default, worst-fit is applied.
+	AssignmentStrategy string
+
+	// AgingMaxWait is the waiting time after which a group reserves the next car that frees seats for it. Zero disables it.
+	AgingMaxWait time.Duration
+	// AgingMaxOvertakes is the number of times that a group can be overtaken before it reserves the next car that frees seats for it. Zero disables it.
+	AgingMaxOvertakes int
+
+	// MaxWaitingTime is the waiting time after which a group that has not got on a car expires. Zero disables it.
+	MaxWaitingTime time.Duration
+
+	// ReservationHoldBack is the time before the start of a reservation from which its seats are held back.
+	// By default, 30 minutes.
+	ReservationHoldBack time.Duration
+
+	// MaxPickupRadiusKm is the farthest, in kilometers, that a car goes to pick up a group. Zero disables it.
+	MaxPickupRadiusKm float64
+
+	// MinCarSeats and MaxCarSeats are the seats that a car can have. The largest group allowed fills the largest car allowed.
+	// By default, from 4 to 6 seats.
+	MinCarSeats int
+	MaxCarSeats int
+
+	// Storage is the storage used by the repositories. Allowed values are memory, sqlite and eventstore. By default, memory is used.
+	Storage string
+	// SQLiteDSN is the SQLite data source name, used when the storage is sqlite
+	SQLiteDSN string
+	// EventStorePath is the file of the event store, used when the storage is eventstore
+	EventStorePath string
+
+	// WaitTimesWindow is the default window of the waiting times stats. By default, one hour.
+	WaitTimesWindow time.Duration
+}
+
+// Environment variables used to configure the service
+const (
+	AssignmentStrategyEnv  = "CAR_SHARING_ASSIGNMENT_STRATEGY"
+	AgingMaxWaitEnv        = "CAR_SHARING_AGING_MAX_WAIT"
+	AgingMaxOvertakesEnv   = "CAR_SHARING_AGING_MAX_OVERTAKES"
+	MaxWaitingTimeEnv      = "CAR_SHARING_MAX_WAITING_TIME"
+	ReservationHoldBackEnv = "CAR_SHARING_RESERVATION_HOLD_BACK"
+	MaxPickupRadiusKmEnv   = "CAR_SHARING_MAX_PICKUP_RADIUS_KM"
+	MinCarSeatsEnv         = "CAR_SHARING_MIN_CAR_SEATS"
+	MaxCarSeatsEnv         = "CAR_SHARING_MAX_CAR_SEATS"
+	StorageEnv             = "CAR_SHARING_STORAGE"
+	SQLiteDSNEnv           = "CAR_SHARING_SQLITE_DSN"
+	EventStorePathEnv      = "CAR_SHARING_EVENT_STORE_PATH"
+	WaitTimesWindowEnv     = "CAR_SHARING_WAIT_TIMES_WINDOW"
+)
+
+// Allowed storages
+const (
+	MemoryStorage     = "memory"
+	SQLiteStorage     = "sqlite"
+	EventStoreStorage = "eventstore"
+)
+
+const (
+	defaultSQLiteDSN       = "car-sharing.db"
+	defaultEventStorePath  = "car-sharing.events"
+	defaultWaitTimesWindow = time.Hour
+)
+
+// NewConfigFromEnv returns the service configuration read from the environment
+func NewConfigFromEnv() (Config, error) {
+	cfg := Config{
+		AssignmentStrategy: os.Getenv(AssignmentStrategyEnv),
+		Storage:            os.Getenv(StorageEnv),
+		SQLiteDSN:          os.Getenv(SQLiteDSNEnv),
+		EventStorePath:     os.Getenv(EventStorePathEnv),
+	}
+
+	if v := os.Getenv(AgingMaxWaitEnv); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("%s: %w", AgingMaxWaitEnv, err)
+		}
+		cfg.AgingMaxWait = d
+	}
+
+	if v := os.Getenv(AgingMaxOvertakesEnv); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("%s: %w", AgingMaxOvertakesEnv, err)
+		}
+		cfg.AgingMaxOvertakes = n
+	}
+
+	if v := os.Getenv(MaxWaitingTimeEnv); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("%s: %w", MaxWaitingTimeEnv, err)
+		}
+		cfg.MaxWaitingTime = d
+	}
+
+	if v := os.Getenv(ReservationHoldBackEnv); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("%s: %w", ReservationHoldBackEnv, err)
+		}
+		cfg.ReservationHoldBack = d
+	}
+
+	if v := os.Getenv(MaxPickupRadiusKmEnv); v != "" {
+		km, err := strconv.ParseFloat(v, 64)
+		if err != nil {
+			return Config{}, fmt.Errorf("%s: %w", MaxPickupRadiusKmEnv, err)
+		}
+		cfg.MaxPickupRadiusKm = km
+	}
+
+	if v := os.Getenv(MinCarSeatsEnv); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("%s: %w", MinCarSeatsEnv, err)
+		}
+		cfg.MinCarSeats = n
+	}
+
+	if v := os.Getenv(MaxCarSeatsEnv); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("%s: %w", MaxCarSeatsEnv, err)
+		}
+		cfg.MaxCarSeats = n
+	}
+
+	if v := os.Getenv(WaitTimesWindowEnv); v != "" {
+		d, err := time.ParseDuration(v)
+		if err != nil {
+			return Config{}, fmt.Errorf("%s: %w", WaitTimesWindowEnv, err)
+		}
+		cfg.WaitTimesWindow = d
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/infra/repository"
+	"theskyinflames/car-sharing/internal/infra/repository/eventstore"
+	"theskyinflames/car-sharing/internal/infra/repository/sqlite"
+)
+
+// storage groups the repositories of the configured storage
+type storage struct {
+	gr    app.GroupsRepository
+	evr   app.CarsRepository
+	uow   app.UnitOfWork
+	hr    app.JourneysHistoryRepository
+	ob    app.Outbox
+	rr    app.ReservationsRepository
+	wr    app.WebhooksRepository
+	close func()
+}
+
+// buildStorage returns the repositories for the configured storage
+func buildStorage(ctx context.Context, cfg Config) (storage, error) {
+	switch cfg.Storage {
+	case "", MemoryStorage:
+		gr := repository.NewGroupsRepository()
+		evr := repository.NewCarRepository()
+		ob := repository.NewOutbox()
+		rr := repository.NewReservationsRepository()
+		return storage{
+			gr:    &gr,
+			evr:   &evr,
+			uow:   repository.NewUnitOfWork(&gr, &evr).WithOutbox(&ob).WithReservations(&rr),
+			hr:    repository.NewJourneysHistoryRepository(),
+			ob:    ob,
+			rr:    rr,
+			wr:    repository.NewWebhooksRepository(),
+			close: func() {},
+		}, nil
+	case SQLiteStorage:
+		dsn := cfg.SQLiteDSN
+		if dsn == "" {
+			dsn = defaultSQLiteDSN
+		}
+		db, err := sqlite.Open(ctx, dsn)
+		if err != nil {
+			return storage{}, err
+		}
+		return storage{
+			gr:    sqlite.NewGroupsRepository(db),
+			evr:   sqlite.NewCarRepository(db),
+			uow:   sqlite.NewUnitOfWork(db),
+			hr:    sqlite.NewJourneysHistoryRepository(db),
+			ob:    sqlite.NewOutbox(db),
+			rr:    sqlite.NewReservationsRepository(db),
+			wr:    sqlite.NewWebhooksRepository(db),
+			close: func() { _ = db.Close() },
+		}, nil
+	case EventStoreStorage:
+		path := cfg.EventStorePath
+		if path == "" {
+			path = defaultEventStorePath
+		}
+		s, err := eventstore.Open(path)
+		if err != nil {
+			return storage{}, err
+		}
+		return storage{
+			gr:    eventstore.NewGroupsRepository(s),
+			evr:   eventstore.NewCarRepository(s),
+			uow:   eventstore.NewUnitOfWork(s),
+			hr:    eventstore.NewJourneysHistoryRepository(s),
+			ob:    eventstore.NewOutbox(s),
+			rr:    eventstore.NewReservationsRepository(s),
+			wr:    eventstore.NewWebhooksRepository(s),
+			close: func() { _ = s.Close() },
+		}, nil
+	default:
+		return storage{}, fmt.Errorf("unknown storage %q", cfg.Storage)
+	}
+}
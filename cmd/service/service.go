@@ -6,23 +6,44 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"time"
 
 	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/domain"
 	"theskyinflames/car-sharing/internal/infra/api"
+	"theskyinflames/car-sharing/internal/infra/metrics"
+	"theskyinflames/car-sharing/internal/infra/webhooks"
 
 	"github.com/go-chi/chi"
 	"github.com/go-chi/chi/middleware"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/rs/cors"
 )
 
+const (
+	outboxRelayInterval   = time.Second
+	outboxRelayMaxBackoff = 30 * time.Second
+
+	webhookAttempts = 5
+	webhookBackoff  = time.Second
+	webhookTimeout  = 10 * time.Second
+
+	journeyEventsKeepAlive = 15 * time.Second
+	fleetBoardSendBuffer   = 32
+
+	expiryScanInterval      = 10 * time.Second
+	reservationScanInterval = 10 * time.Second
+)
+
 // Run Starts the API server
-func Run(ctx context.Context, srvPort string) {
+func Run(ctx context.Context, srvPort string, cfg Config) {
 	r := chi.NewRouter()
 
 	cors := cors.New(cors.Options{
 		AllowedOrigins: []string{"*"},
 		AllowedHeaders: []string{"*"},
-		AllowedMethods: []string{"GET", "POST", "PUT", "POST"},
+		AllowedMethods: []string{"GET", "POST", "PUT", "PATCH", "DELETE"},
 	})
 	r.Use(cors.Handler)
 	r.Use(middleware.Logger)
@@ -33,12 +54,103 @@ func Run(ctx context.Context, srvPort string) {
 
 	log := log.New(os.Stdout, "car-sharing: ", os.O_APPEND)
 
-	commandBus := app.BuildCommandQueryBus(log, app.BuildEventsBus())
+	strategy, err := domain.ParseAssignmentStrategy(cfg.AssignmentStrategy)
+	if err != nil {
+		fmt.Printf("wrong assignment strategy %q: %s\n", cfg.AssignmentStrategy, err.Error())
+		return
+	}
+
+	agingPolicy := domain.AgingPolicy{
+		MaxWait:      cfg.AgingMaxWait,
+		MaxOvertakes: cfg.AgingMaxOvertakes,
+	}
+
+	capacityLimits := domain.DefaultCapacityLimits
+	if cfg.MinCarSeats != 0 {
+		capacityLimits.MinSeats = cfg.MinCarSeats
+	}
+	if cfg.MaxCarSeats != 0 {
+		capacityLimits.MaxSeats = cfg.MaxCarSeats
+	}
+	capacityLimits, err = domain.NewCapacityLimits(capacityLimits.MinSeats, capacityLimits.MaxSeats)
+	if err != nil {
+		fmt.Printf("wrong car seats: %s\n", err.Error())
+		return
+	}
+
+	st, err := buildStorage(ctx, cfg)
+	if err != nil {
+		fmt.Printf("something went wrong trying to build the storage: %s\n", err.Error())
+		return
+	}
+	defer st.close()
+
+	wn := app.NewWebhooksNotifier(st.wr, webhooks.NewSender(webhookTimeout), log, webhookAttempts, webhookBackoff)
+	go wn.Run(ctx)
+
+	hub := app.NewEventsHub()
+	eventsBus := app.BuildEventsBus(log, st.hr, wn, hub)
+	// the events are stored in the outbox with the changes of their command, and the relay delivers them
+	relay := app.NewOutboxRelay(st.ob, eventsBus, log, outboxRelayInterval, outboxRelayMaxBackoff)
+	go relay.Run(ctx)
+
+	clock := app.SystemClock{}
+	busLatency := metrics.NewBusLatency()
+	commandBus := app.BuildCommandQueryBus(log, eventsBus, st.gr, st.evr, st.uow, st.hr, st.wr, st.rr,
+		app.WithFleetOptions(
+			domain.WithAssignmentStrategy(strategy),
+			domain.WithAgingPolicy(agingPolicy),
+			domain.WithCapacityLimits(capacityLimits),
+			domain.WithReservationHoldBack(cfg.ReservationHoldBack),
+			domain.WithMaxPickupRadius(cfg.MaxPickupRadiusKm),
+		),
+		app.WithClock(clock),
+		app.WithOutbox(st.ob),
+		// the latency is added before the relay, so it wraps the command but not the delivery of its events
+		app.WithCommandHandlerMiddleware(busLatency.ChMw()),
+		app.WithCommandHandlerMiddleware(relay.ChMw()),
+		app.WithQueryHandlerMiddleware(busLatency.QhMw()),
+	)
+
+	board := app.NewFleetBoard(commandBus, hub, log, fleetBoardSendBuffer)
+	go board.Run(ctx)
+
+	if cfg.MaxWaitingTime > 0 {
+		expiry := app.NewExpiryScheduler(st.gr, commandBus, log, clock, cfg.MaxWaitingTime, expiryScanInterval)
+		go expiry.Run(ctx)
+	}
+
+	reservations := app.NewReservationScheduler(st.rr, commandBus, log, clock, reservationScanInterval)
+	go reservations.Run(ctx)
+
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(busLatency, metrics.NewFleetCollector(commandBus, log))
+	r.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{}))
 
 	r.Put("/v1/cars", api.InitializeFleet(commandBus))
+	r.Post("/v1/cars", api.AddCars(commandBus))
+	r.Delete("/v1/cars/{id}", api.RetireCar(commandBus))
+	r.Patch("/v1/cars/{id}", api.UpdateCar(commandBus))
+	r.Post("/v1/cars/{id}/out-of-service", api.TakeCarOutOfService(commandBus))
+	r.Post("/v1/cars/{id}/back-in-service", api.PutCarBackInService(commandBus))
+	r.Post("/v1/cars/{id}/position", api.ReportCarPosition(commandBus))
 	r.Post("/v1/journey", api.Journey(commandBus))
 	r.Post("/v1/journey/dropoff", api.DropOff(commandBus))
+	r.Post("/v1/journey/cancel", api.CancelJourney(commandBus))
+	r.Post("/v1/reservations", api.ScheduleJourney(commandBus))
 	r.Post("/v1/journey/locate", api.Locate(commandBus))
+	r.Get("/v1/journey/{id}/events", api.JourneyEvents(commandBus, hub, journeyEventsKeepAlive))
+	r.Get("/v1/fleet/board", api.FleetBoard(board))
+	r.Get("/v1/sites", api.Sites(commandBus))
+
+	waitTimesWindow := cfg.WaitTimesWindow
+	if waitTimesWindow == 0 {
+		waitTimesWindow = defaultWaitTimesWindow
+	}
+	r.Get("/v1/stats/wait-times", api.WaitTimes(commandBus, waitTimesWindow))
+
+	r.Post("/v1/webhooks", api.RegisterWebhook(commandBus))
+	r.Get("/v1/webhooks/dead-letters", api.DeadLetters(commandBus))
 
 	fmt.Printf("serving at port %s\n", srvPort)
 	if err := http.ListenAndServe(srvPort, r); err != nil {
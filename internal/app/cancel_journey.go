@@ -0,0 +1,98 @@
+package app
+
+import (
+	"context"
+
+	"theskyinflames/car-sharing/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/theskyinflames/cqrs-eda/pkg/cqrs"
+	"github.com/theskyinflames/cqrs-eda/pkg/events"
+)
+
+// CancelJourneyCmd is a command
+type CancelJourneyCmd struct {
+	GroupID uuid.UUID
+	Reason  domain.CancelReason
+}
+
+// CancelJourneyName is self-described
+var CancelJourneyName = "cancel.journey"
+
+// Name implements the Command interface
+func (cmd CancelJourneyCmd) Name() string {
+	return CancelJourneyName
+}
+
+// CancelJourney is a command handler. Unlike DropOff, it's only for the waiting groups that give up their journey,
+// so the groups that arrive and the ones that give up can be told apart
+type CancelJourney struct {
+	gr  GroupsRepository
+	evr CarsRepository
+	rr  ReservationsRepository
+
+	fleetOpts []domain.FleetOption
+}
+
+// NewCancelJourney is a constructor
+func NewCancelJourney(
+	gr GroupsRepository,
+	evr CarsRepository,
+	rr ReservationsRepository,
+	fleetOpts ...domain.FleetOption,
+) CancelJourney {
+	return CancelJourney{gr: gr, evr: evr, rr: rr, fleetOpts: fleetOpts}
+}
+
+// Handle implements CommandHandler interface
+func (ch CancelJourney) Handle(ctx context.Context, cmd cqrs.Command) ([]events.Event, error) {
+	co, ok := cmd.(CancelJourneyCmd)
+	if !ok {
+		return nil, NewInvalidCommandError(CancelJourneyName, cmd.Name())
+	}
+
+	return leaveWaitingList(ctx, ch.gr, ch.evr, ch.rr, ch.fleetOpts, co.GroupID,
+		func(fleet *domain.Fleet, g *domain.Group) ([]domain.Car, domain.Journeys, error) {
+			return fleet.Cancel(g, co.Reason)
+		})
+}
+
+// leaveWaitingList removes a waiting group from the fleet with the leave function, which records why it leaves,
+// and persists the changes. It returns their events
+func leaveWaitingList(
+	ctx context.Context,
+	gr GroupsRepository,
+	evr CarsRepository,
+	rr ReservationsRepository,
+	fleetOpts []domain.FleetOption,
+	groupID uuid.UUID,
+	leave func(*domain.Fleet, *domain.Group) ([]domain.Car, domain.Journeys, error),
+) ([]events.Event, error) {
+	g, err := gr.FindByID(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+	if g.IsOnJourney() {
+		return nil, domain.ErrGroupOnJourney
+	}
+
+	fleet, err := loadFleet(ctx, gr, evr, rr, fleetOpts)
+	if err != nil {
+		return nil, err
+	}
+	changed, onJourney, err := leave(&fleet, &g)
+	if err != nil {
+		return nil, err
+	}
+
+	evs := g.Events()
+	if err := gr.RemoveByID(ctx, g.ID()); err != nil {
+		return nil, err
+	}
+
+	reassignedEvs, err := saveReassignment(ctx, gr, evr, fleet, changed, onJourney)
+	if err != nil {
+		return nil, err
+	}
+	return append(evs, reassignedEvs...), nil
+}
@@ -0,0 +1,115 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/domain"
+	"theskyinflames/car-sharing/internal/fixtures"
+	"theskyinflames/car-sharing/internal/helpers"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/theskyinflames/cqrs-eda/pkg/cqrs"
+)
+
+func TestCancelJourney(t *testing.T) {
+	var (
+		randomErr = errors.New("")
+
+		gID     = uuid.New()
+		waiting = fixtures.Group{ID: helpers.UUIDPtr(gID), People: helpers.IntPtr(4)}.Build()
+	)
+	testCases := []struct {
+		name            string
+		cmd             cqrs.Command
+		gr              *GroupsRepositoryMock
+		cr              *CarsRepositoryMock
+		expectedErrFunc func(*testing.T, error)
+	}{
+		{
+			name: `Given an invalid command, when it's called, then an error is returned`,
+			cmd:  newInvalidCommand(),
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorAs(t, err, &app.InvalidCommandError{})
+			},
+		},
+		{
+			name: `Given groups repository that returns an error on FindByID method,
+				when it's called, then an error is returned`,
+			cmd: app.CancelJourneyCmd{GroupID: gID, Reason: domain.CancelReasonGaveUp},
+			gr: &GroupsRepositoryMock{
+				FindByIDFunc: func(_ context.Context, _ uuid.UUID) (domain.Group, error) {
+					return domain.Group{}, randomErr
+				},
+			},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, randomErr)
+			},
+		},
+		{
+			name: `Given a group on journey, when it's called, then an error is returned`,
+			cmd:  app.CancelJourneyCmd{GroupID: gID, Reason: domain.CancelReasonGaveUp},
+			gr: &GroupsRepositoryMock{
+				FindByIDFunc: func(_ context.Context, _ uuid.UUID) (domain.Group, error) {
+					return fixtures.Group{ID: helpers.UUIDPtr(gID), Car: helpers.EvPtr(fixtures.Car{}.Build())}.Build(), nil
+				},
+			},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, domain.ErrGroupOnJourney)
+			},
+		},
+		{
+			name: `Given groups repository that returns an error on RemoveByID method,
+				when it's called, then an error is returned`,
+			cmd: app.CancelJourneyCmd{GroupID: gID, Reason: domain.CancelReasonGaveUp},
+			gr: &GroupsRepositoryMock{
+				FindByIDFunc: func(_ context.Context, _ uuid.UUID) (domain.Group, error) {
+					return waiting, nil
+				},
+				FindGroupsWithoutCarFunc: func(_ context.Context) ([]domain.Group, error) {
+					return []domain.Group{waiting}, nil
+				},
+				RemoveByIDFunc: func(_ context.Context, _ uuid.UUID) error {
+					return randomErr
+				},
+			},
+			cr: &CarsRepositoryMock{},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, randomErr)
+			},
+		},
+		{
+			name: `Given a waiting group, when it's called, then it's removed and a group cancelled event is returned`,
+			cmd:  app.CancelJourneyCmd{GroupID: gID, Reason: domain.CancelReasonPlansChanged},
+			gr: &GroupsRepositoryMock{
+				FindByIDFunc: func(_ context.Context, _ uuid.UUID) (domain.Group, error) {
+					return waiting, nil
+				},
+				FindGroupsWithoutCarFunc: func(_ context.Context) ([]domain.Group, error) {
+					return []domain.Group{waiting}, nil
+				},
+			},
+			cr: &CarsRepositoryMock{},
+		},
+	}
+
+	for _, tc := range testCases {
+		ch := app.NewCancelJourney(tc.gr, tc.cr, &ReservationsRepositoryMock{})
+		evs, err := ch.Handle(context.Background(), tc.cmd)
+		require.Equal(t, tc.expectedErrFunc == nil, err == nil, tc.name)
+		if err != nil {
+			tc.expectedErrFunc(t, err)
+			continue
+		}
+
+		require.Len(t, tc.gr.RemoveByIDCalls(), 1, tc.name)
+		require.Equal(t, gID, tc.gr.RemoveByIDCalls()[0].ID, tc.name)
+		require.Len(t, evs, 1, tc.name)
+		ev, ok := evs[0].(domain.GroupCancelledEvent)
+		require.True(t, ok, tc.name)
+		require.Equal(t, domain.CancelReasonPlansChanged, ev.Reason(), tc.name)
+	}
+}
@@ -0,0 +1,532 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"theskyinflames/car-sharing/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/theskyinflames/cqrs-eda/pkg/cqrs"
+	"github.com/theskyinflames/cqrs-eda/pkg/events"
+)
+
+// AddCarsCmd is a command
+type AddCarsCmd struct {
+	Cars []Car
+}
+
+// AddCarsName is self-described
+var AddCarsName = "add.cars"
+
+// Name implements the Command interface
+func (cmd AddCarsCmd) Name() string {
+	return AddCarsName
+}
+
+// AddCars is a command handler. Unlike InitializeFleet, the cars are added to the current fleet,
+// and the waiting groups that fit in them get on them
+type AddCars struct {
+	gr  GroupsRepository
+	evr CarsRepository
+	rr  ReservationsRepository
+
+	fleetOpts []domain.FleetOption
+}
+
+// NewAddCars is a constructor
+func NewAddCars(
+	gr GroupsRepository,
+	evr CarsRepository,
+	rr ReservationsRepository,
+	fleetOpts ...domain.FleetOption,
+) AddCars {
+	return AddCars{gr: gr, evr: evr, rr: rr, fleetOpts: fleetOpts}
+}
+
+// Handle implements CommandHandler interface
+func (ch AddCars) Handle(ctx context.Context, cmd cqrs.Command) ([]events.Event, error) {
+	co, ok := cmd.(AddCarsCmd)
+	if !ok {
+		return nil, NewInvalidCommandError(AddCarsName, cmd.Name())
+	}
+
+	var newCars []domain.Car
+	for _, car := range co.Cars {
+		seats, err := capacityLimits(ch.fleetOpts).ParseCarCapacity(car.Seats.Int())
+		if err != nil {
+			return nil, err
+		}
+		newCars = append(newCars, domain.NewCar(car.ID, seats, domain.WithSite(car.Site), domain.WithFeatures(car.Features...)))
+	}
+
+	fleet, err := loadFleet(ctx, ch.gr, ch.evr, ch.rr, ch.fleetOpts)
+	if err != nil {
+		return nil, err
+	}
+	added, onJourney, err := fleet.AddCars(newCars)
+	if err != nil {
+		return nil, err
+	}
+
+	var evs []events.Event
+	for i := range added {
+		evs = append(evs, added[i].Events()...)
+	}
+	if err := ch.evr.AddAll(ctx, added); err != nil {
+		return nil, err
+	}
+
+	reassignedEvs, err := saveReassignment(ctx, ch.gr, ch.evr, fleet, nil, onJourney)
+	if err != nil {
+		return nil, err
+	}
+	return append(evs, reassignedEvs...), nil
+}
+
+// RetireCarCmd is a command
+type RetireCarCmd struct {
+	CarID uuid.UUID
+}
+
+// RetireCarName is self-described
+var RetireCarName = "retire.car"
+
+// Name implements the Command interface
+func (cmd RetireCarCmd) Name() string {
+	return RetireCarName
+}
+
+// RetireCar is a command handler. The car is removed straight away if it's empty. Otherwise, it's removed
+// once its last group is dropped off, and meanwhile no other group can get on it
+type RetireCar struct {
+	gr  GroupsRepository
+	evr CarsRepository
+	rr  ReservationsRepository
+
+	fleetOpts []domain.FleetOption
+}
+
+// NewRetireCar is a constructor
+func NewRetireCar(
+	gr GroupsRepository,
+	evr CarsRepository,
+	rr ReservationsRepository,
+	fleetOpts ...domain.FleetOption,
+) RetireCar {
+	return RetireCar{gr: gr, evr: evr, rr: rr, fleetOpts: fleetOpts}
+}
+
+// Handle implements CommandHandler interface
+func (ch RetireCar) Handle(ctx context.Context, cmd cqrs.Command) ([]events.Event, error) {
+	co, ok := cmd.(RetireCarCmd)
+	if !ok {
+		return nil, NewInvalidCommandError(RetireCarName, cmd.Name())
+	}
+
+	car, err := ch.evr.FindByID(ctx, co.CarID)
+	if err != nil {
+		return nil, err
+	}
+
+	fleet, err := loadFleet(ctx, ch.gr, ch.evr, ch.rr, ch.fleetOpts)
+	if err != nil {
+		return nil, err
+	}
+	unservable := fleet.UnservableGroups()
+	changed, onJourney, err := fleet.Retire(&car)
+	if err != nil {
+		return nil, err
+	}
+
+	evs := car.Events()
+	if err := saveCar(ctx, ch.evr, car); err != nil {
+		return nil, err
+	}
+
+	reassignedEvs, err := saveReassignment(ctx, ch.gr, ch.evr, fleet, changed, onJourney)
+	if err != nil {
+		return nil, err
+	}
+	evs = append(evs, reassignedEvs...)
+	return append(evs, unservableEvents(fleet, unservable)...), nil
+}
+
+// UpdateCarCmd is a command
+type UpdateCarCmd struct {
+	CarID uuid.UUID
+	Seats domain.CarCapacity
+}
+
+// UpdateCarName is self-described
+var UpdateCarName = "update.car"
+
+// Name implements the Command interface
+func (cmd UpdateCarCmd) Name() string {
+	return UpdateCarName
+}
+
+// UpdateCar is a command handler. It changes the capacity of a car, and the waiting groups that fit in the new seats get on it
+type UpdateCar struct {
+	gr  GroupsRepository
+	evr CarsRepository
+	rr  ReservationsRepository
+
+	fleetOpts []domain.FleetOption
+}
+
+// NewUpdateCar is a constructor
+func NewUpdateCar(
+	gr GroupsRepository,
+	evr CarsRepository,
+	rr ReservationsRepository,
+	fleetOpts ...domain.FleetOption,
+) UpdateCar {
+	return UpdateCar{gr: gr, evr: evr, rr: rr, fleetOpts: fleetOpts}
+}
+
+// Handle implements CommandHandler interface
+func (ch UpdateCar) Handle(ctx context.Context, cmd cqrs.Command) ([]events.Event, error) {
+	co, ok := cmd.(UpdateCarCmd)
+	if !ok {
+		return nil, NewInvalidCommandError(UpdateCarName, cmd.Name())
+	}
+
+	seats, err := capacityLimits(ch.fleetOpts).ParseCarCapacity(co.Seats.Int())
+	if err != nil {
+		return nil, err
+	}
+
+	car, err := ch.evr.FindByID(ctx, co.CarID)
+	if err != nil {
+		return nil, err
+	}
+
+	fleet, err := loadFleet(ctx, ch.gr, ch.evr, ch.rr, ch.fleetOpts)
+	if err != nil {
+		return nil, err
+	}
+	unservable := fleet.UnservableGroups()
+	changed, onJourney, err := fleet.ChangeCapacity(&car, seats)
+	if err != nil {
+		return nil, err
+	}
+
+	evs := car.Events()
+	if err := ch.evr.Update(ctx, car); err != nil {
+		return nil, err
+	}
+
+	reassignedEvs, err := saveReassignment(ctx, ch.gr, ch.evr, fleet, changed, onJourney)
+	if err != nil {
+		return nil, err
+	}
+	evs = append(evs, reassignedEvs...)
+	return append(evs, unservableEvents(fleet, unservable)...), nil
+}
+
+// TakeCarOutOfServiceCmd is a command
+type TakeCarOutOfServiceCmd struct {
+	CarID uuid.UUID
+}
+
+// TakeCarOutOfServiceName is self-described
+var TakeCarOutOfServiceName = "take.car.out.of.service"
+
+// Name implements the Command interface
+func (cmd TakeCarOutOfServiceCmd) Name() string {
+	return TakeCarOutOfServiceName
+}
+
+// TakeCarOutOfService is a command handler. A car with groups on journey is drained: they are not ejected,
+// but no other group can get on it, and it's out of service once they are dropped off
+type TakeCarOutOfService struct {
+	gr  GroupsRepository
+	evr CarsRepository
+	rr  ReservationsRepository
+
+	fleetOpts []domain.FleetOption
+}
+
+// NewTakeCarOutOfService is a constructor
+func NewTakeCarOutOfService(
+	gr GroupsRepository,
+	evr CarsRepository,
+	rr ReservationsRepository,
+	fleetOpts ...domain.FleetOption,
+) TakeCarOutOfService {
+	return TakeCarOutOfService{gr: gr, evr: evr, rr: rr, fleetOpts: fleetOpts}
+}
+
+// Handle implements CommandHandler interface
+func (ch TakeCarOutOfService) Handle(ctx context.Context, cmd cqrs.Command) ([]events.Event, error) {
+	co, ok := cmd.(TakeCarOutOfServiceCmd)
+	if !ok {
+		return nil, NewInvalidCommandError(TakeCarOutOfServiceName, cmd.Name())
+	}
+
+	car, err := ch.evr.FindByID(ctx, co.CarID)
+	if err != nil {
+		return nil, err
+	}
+
+	fleet, err := loadFleet(ctx, ch.gr, ch.evr, ch.rr, ch.fleetOpts)
+	if err != nil {
+		return nil, err
+	}
+	changed, onJourney, err := fleet.TakeOutOfService(&car)
+	if err != nil {
+		return nil, err
+	}
+
+	evs := car.Events()
+	if err := ch.evr.Update(ctx, car); err != nil {
+		return nil, err
+	}
+
+	reassignedEvs, err := saveReassignment(ctx, ch.gr, ch.evr, fleet, changed, onJourney)
+	if err != nil {
+		return nil, err
+	}
+	return append(evs, reassignedEvs...), nil
+}
+
+// PutCarBackInServiceCmd is a command
+type PutCarBackInServiceCmd struct {
+	CarID uuid.UUID
+}
+
+// PutCarBackInServiceName is self-described
+var PutCarBackInServiceName = "put.car.back.in.service"
+
+// Name implements the Command interface
+func (cmd PutCarBackInServiceCmd) Name() string {
+	return PutCarBackInServiceName
+}
+
+// PutCarBackInService is a command handler. The car is available again, and the waiting groups that fit in it get on it
+type PutCarBackInService struct {
+	gr  GroupsRepository
+	evr CarsRepository
+	rr  ReservationsRepository
+
+	fleetOpts []domain.FleetOption
+}
+
+// NewPutCarBackInService is a constructor
+func NewPutCarBackInService(
+	gr GroupsRepository,
+	evr CarsRepository,
+	rr ReservationsRepository,
+	fleetOpts ...domain.FleetOption,
+) PutCarBackInService {
+	return PutCarBackInService{gr: gr, evr: evr, rr: rr, fleetOpts: fleetOpts}
+}
+
+// Handle implements CommandHandler interface
+func (ch PutCarBackInService) Handle(ctx context.Context, cmd cqrs.Command) ([]events.Event, error) {
+	co, ok := cmd.(PutCarBackInServiceCmd)
+	if !ok {
+		return nil, NewInvalidCommandError(PutCarBackInServiceName, cmd.Name())
+	}
+
+	car, err := ch.evr.FindByID(ctx, co.CarID)
+	if err != nil {
+		return nil, err
+	}
+
+	fleet, err := loadFleet(ctx, ch.gr, ch.evr, ch.rr, ch.fleetOpts)
+	if err != nil {
+		return nil, err
+	}
+	onJourney, err := fleet.BackInService(&car)
+	if err != nil {
+		return nil, err
+	}
+
+	evs := car.Events()
+	if err := ch.evr.Update(ctx, car); err != nil {
+		return nil, err
+	}
+
+	reassignedEvs, err := saveReassignment(ctx, ch.gr, ch.evr, fleet, nil, onJourney)
+	if err != nil {
+		return nil, err
+	}
+	return append(evs, reassignedEvs...), nil
+}
+
+// ReportCarPositionCmd is a command
+type ReportCarPositionCmd struct {
+	CarID    uuid.UUID
+	Position domain.Location
+}
+
+// ReportCarPositionName is self-described
+var ReportCarPositionName = "report.car.position"
+
+// Name implements the Command interface
+func (cmd ReportCarPositionCmd) Name() string {
+	return ReportCarPositionName
+}
+
+// ReportCarPosition is a command handler. It sets the current position of a car, and the waiting groups
+// that are within the pickup radius now get on it
+type ReportCarPosition struct {
+	gr  GroupsRepository
+	evr CarsRepository
+	rr  ReservationsRepository
+
+	fleetOpts []domain.FleetOption
+}
+
+// NewReportCarPosition is a constructor
+func NewReportCarPosition(
+	gr GroupsRepository,
+	evr CarsRepository,
+	rr ReservationsRepository,
+	fleetOpts ...domain.FleetOption,
+) ReportCarPosition {
+	return ReportCarPosition{gr: gr, evr: evr, rr: rr, fleetOpts: fleetOpts}
+}
+
+// Handle implements CommandHandler interface
+func (ch ReportCarPosition) Handle(ctx context.Context, cmd cqrs.Command) ([]events.Event, error) {
+	co, ok := cmd.(ReportCarPositionCmd)
+	if !ok {
+		return nil, NewInvalidCommandError(ReportCarPositionName, cmd.Name())
+	}
+
+	position, err := domain.NewLocation(co.Position.Lat, co.Position.Lng)
+	if err != nil {
+		return nil, err
+	}
+
+	car, err := ch.evr.FindByID(ctx, co.CarID)
+	if err != nil {
+		return nil, err
+	}
+
+	fleet, err := loadFleet(ctx, ch.gr, ch.evr, ch.rr, ch.fleetOpts)
+	if err != nil {
+		return nil, err
+	}
+	onJourney, err := fleet.ReportPosition(&car, position)
+	if err != nil {
+		return nil, err
+	}
+
+	evs := car.Events()
+	if err := ch.evr.Update(ctx, car); err != nil {
+		return nil, err
+	}
+
+	reassignedEvs, err := saveReassignment(ctx, ch.gr, ch.evr, fleet, nil, onJourney)
+	if err != nil {
+		return nil, err
+	}
+	return append(evs, reassignedEvs...), nil
+}
+
+// loadFleet returns the fleet with all the cars, the waiting groups and the upcoming reservations.
+// The fleet options customize the business rules applied by the Fleet domain service
+func loadFleet(
+	ctx context.Context,
+	gr GroupsRepository,
+	evr CarsRepository,
+	rr ReservationsRepository,
+	fleetOpts []domain.FleetOption,
+) (domain.Fleet, error) {
+	cars, err := evr.FindAll(ctx)
+	if err != nil {
+		return domain.Fleet{}, err
+	}
+	wg, err := gr.FindGroupsWithoutCar(ctx)
+	if err != nil {
+		return domain.Fleet{}, err
+	}
+	opts, err := withReservations(ctx, rr, fleetOpts)
+	if err != nil {
+		return domain.Fleet{}, err
+	}
+	return domain.NewFleet(cars, wg, opts...), nil
+}
+
+// withReservations returns the fleet options plus the upcoming reservations, so their seats are held back
+func withReservations(ctx context.Context, rr ReservationsRepository, fleetOpts []domain.FleetOption) ([]domain.FleetOption, error) {
+	rs, err := rr.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	opts := make([]domain.FleetOption, 0, len(fleetOpts)+1)
+	opts = append(opts, fleetOpts...)
+	return append(opts, domain.WithReservations(rs)), nil
+}
+
+// capacityLimits returns the seats that a car can have, as set by the fleet options
+func capacityLimits(fleetOpts []domain.FleetOption) domain.CapacityLimits {
+	return domain.NewFleet(nil, nil, fleetOpts...).CapacityLimits()
+}
+
+// fleetNow returns the current time, as told by the clock of the fleet options
+func fleetNow(fleetOpts []domain.FleetOption) time.Time {
+	return domain.NewFleet(nil, nil, fleetOpts...).Now()
+}
+
+// unservableEvents returns the events of the waiting groups that don't fit in any car of their site anymore,
+// but did before its change
+func unservableEvents(fleet domain.Fleet, before []domain.Group) []events.Event {
+	was := make(map[uuid.UUID]struct{}, len(before))
+	for _, g := range before {
+		was[g.ID()] = struct{}{}
+	}
+	var evs []events.Event
+	for _, g := range fleet.UnservableGroups() {
+		if _, ok := was[g.ID()]; !ok {
+			evs = append(evs, domain.NewGroupUnservableEvent(g, fleet.LargestCapacityFor(g)))
+		}
+	}
+	return evs
+}
+
+// saveCar updates the car, or removes it once it has retired
+func saveCar(ctx context.Context, evr CarsRepository, car domain.Car) error {
+	if car.IsRetired() {
+		return evr.RemoveByID(ctx, car.ID())
+	}
+	return evr.Update(ctx, car)
+}
+
+// saveReassignment persists the cars changed by a reassignment of the waiting groups, the groups that got on them,
+// and the groups that have been overtaken. It returns their events, which are collected before persisting the aggregates,
+// so they are not stored with them
+func saveReassignment(
+	ctx context.Context,
+	gr GroupsRepository,
+	evr CarsRepository,
+	fleet domain.Fleet,
+	changed []domain.Car,
+	onJourney domain.Journeys,
+) ([]events.Event, error) {
+	var evs []events.Event
+	for i := range changed {
+		evs = append(evs, changed[i].Events()...)
+		if err := evr.Update(ctx, changed[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, oj := range onJourney {
+		evs = append(evs, oj.Events()...)
+		if err := gr.Update(ctx, oj); err != nil {
+			return nil, err
+		}
+	}
+
+	for _, og := range fleet.OvertakenGroups() {
+		if err := gr.Update(ctx, og); err != nil {
+			return nil, err
+		}
+	}
+	return evs, nil
+}
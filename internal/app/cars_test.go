@@ -0,0 +1,596 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/domain"
+	"theskyinflames/car-sharing/internal/fixtures"
+	"theskyinflames/car-sharing/internal/helpers"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/theskyinflames/cqrs-eda/pkg/cqrs"
+)
+
+func TestAddCars(t *testing.T) {
+	var (
+		randomErr = errors.New("")
+
+		carID = uuid.New()
+		gID   = uuid.New()
+	)
+	testCases := []struct {
+		name            string
+		cmd             cqrs.Command
+		gr              *GroupsRepositoryMock
+		cr              *CarsRepositoryMock
+		expectedErrFunc func(*testing.T, error)
+	}{
+		{
+			name: `Given an invalid command, when it's called, then an error is returned`,
+			cmd:  newInvalidCommand(),
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorAs(t, err, &app.InvalidCommandError{})
+			},
+		},
+		{
+			name: `Given a car with a wrong capacity, when it's called, then an error is returned`,
+			cmd:  app.AddCarsCmd{Cars: []app.Car{{ID: carID, Seats: 7}}},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, domain.ErrCapacityNotSupported)
+			},
+		},
+		{
+			name: `Given a cars repository that returns an error on AddAll method,
+				when it's called, then an error is returned`,
+			cmd: app.AddCarsCmd{Cars: []app.Car{{ID: carID, Seats: domain.CarCapacity4}}},
+			gr:  &GroupsRepositoryMock{},
+			cr: &CarsRepositoryMock{
+				AddAllFunc: func(_ context.Context, _ []domain.Car) error {
+					return randomErr
+				},
+			},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, randomErr)
+			},
+		},
+		{
+			name: `Given a waiting group,
+				when a car where it fits is added, then the group gets on it`,
+			cmd: app.AddCarsCmd{Cars: []app.Car{{ID: carID, Seats: domain.CarCapacity4}}},
+			gr: &GroupsRepositoryMock{
+				FindGroupsWithoutCarFunc: func(_ context.Context) ([]domain.Group, error) {
+					return []domain.Group{fixtures.Group{ID: helpers.UUIDPtr(gID), People: helpers.IntPtr(3)}.Build()}, nil
+				},
+			},
+			cr: &CarsRepositoryMock{},
+		},
+	}
+
+	for _, tc := range testCases {
+		ch := app.NewAddCars(tc.gr, tc.cr, &ReservationsRepositoryMock{})
+		evs, err := ch.Handle(context.Background(), tc.cmd)
+		require.Equal(t, tc.expectedErrFunc == nil, err == nil, tc.name)
+		if err != nil {
+			tc.expectedErrFunc(t, err)
+			continue
+		}
+
+		require.Len(t, tc.cr.AddAllCalls(), 1)
+		added := tc.cr.AddAllCalls()[0].Cars
+		require.Len(t, added, 1)
+		require.Equal(t, carID, added[0].ID())
+		require.Equal(t, 1, added[0].Availability())
+		require.Len(t, tc.gr.UpdateCalls(), 1)
+		require.Equal(t, gID, tc.gr.UpdateCalls()[0].G.ID())
+		require.NotEmpty(t, evs)
+	}
+}
+
+func TestRetireCar(t *testing.T) {
+	var (
+		randomErr = errors.New("")
+
+		gID = uuid.New()
+	)
+	testCases := []struct {
+		name            string
+		cmd             cqrs.Command
+		gr              *GroupsRepositoryMock
+		cr              *CarsRepositoryMock
+		expectedRemoved bool
+		expectedErrFunc func(*testing.T, error)
+	}{
+		{
+			name: `Given an invalid command, when it's called, then an error is returned`,
+			cmd:  newInvalidCommand(),
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorAs(t, err, &app.InvalidCommandError{})
+			},
+		},
+		{
+			name: `Given a cars repository that returns an error on FindByID method,
+				when it's called, then an error is returned`,
+			cmd: app.RetireCarCmd{},
+			cr: &CarsRepositoryMock{
+				FindByIDFunc: func(_ context.Context, _ uuid.UUID) (domain.Car, error) {
+					return domain.Car{}, randomErr
+				},
+			},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, randomErr)
+			},
+		},
+		{
+			name: `Given a retiring car, when it's called, then an error is returned`,
+			cmd:  app.RetireCarCmd{},
+			gr:   &GroupsRepositoryMock{},
+			cr: &CarsRepositoryMock{
+				FindByIDFunc: func(_ context.Context, _ uuid.UUID) (domain.Car, error) {
+					return fixtures.Car{Retiring: true}.Build(), nil
+				},
+			},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, domain.ErrCarRetiring)
+			},
+		},
+		{
+			name: `Given an empty car, when it's called, then it's removed`,
+			cmd:  app.RetireCarCmd{},
+			gr:   &GroupsRepositoryMock{},
+			cr: &CarsRepositoryMock{
+				FindByIDFunc: func(_ context.Context, _ uuid.UUID) (domain.Car, error) {
+					return fixtures.Car{}.Build(), nil
+				},
+			},
+			expectedRemoved: true,
+		},
+		{
+			name: `Given a car with groups on journey, when it's called, then it's kept as retiring`,
+			cmd:  app.RetireCarCmd{},
+			gr:   &GroupsRepositoryMock{},
+			cr: &CarsRepositoryMock{
+				FindByIDFunc: func(_ context.Context, _ uuid.UUID) (domain.Car, error) {
+					return fixtures.Car{
+						Journeys: domain.Journeys{gID: fixtures.Group{ID: helpers.UUIDPtr(gID)}.Build()},
+					}.Build(), nil
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		ch := app.NewRetireCar(tc.gr, tc.cr, &ReservationsRepositoryMock{})
+		evs, err := ch.Handle(context.Background(), tc.cmd)
+		require.Equal(t, tc.expectedErrFunc == nil, err == nil, tc.name)
+		if err != nil {
+			tc.expectedErrFunc(t, err)
+			continue
+		}
+
+		require.Len(t, evs, 1, tc.name)
+		if tc.expectedRemoved {
+			require.Len(t, tc.cr.RemoveByIDCalls(), 1, tc.name)
+			require.Empty(t, tc.cr.UpdateCalls(), tc.name)
+			require.Equal(t, domain.CarRetiredEventName, evs[0].Name(), tc.name)
+			continue
+		}
+		require.Empty(t, tc.cr.RemoveByIDCalls(), tc.name)
+		require.Len(t, tc.cr.UpdateCalls(), 1, tc.name)
+		require.True(t, tc.cr.UpdateCalls()[0].Car.IsRetiring(), tc.name)
+		require.Equal(t, domain.CarRetiringEventName, evs[0].Name(), tc.name)
+	}
+
+	t.Run(`Given the only six seats car and a waiting group of 5 people,
+		when the car is retired, then the group is reported as unservable`, func(t *testing.T) {
+		var (
+			onBoard = fixtures.Group{ID: helpers.UUIDPtr(uuid.New()), People: helpers.IntPtr(2)}.Build()
+			waiting = fixtures.Group{ID: helpers.UUIDPtr(uuid.New()), People: helpers.IntPtr(5)}.Build()
+			car     = fixtures.Car{
+				Capacity: helpers.CarCapacityPtr(domain.CarCapacity6),
+				Journeys: domain.Journeys{onBoard.ID(): onBoard},
+			}.Build()
+			gr = &GroupsRepositoryMock{
+				FindGroupsWithoutCarFunc: func(_ context.Context) ([]domain.Group, error) {
+					return []domain.Group{waiting}, nil
+				},
+			}
+			cr = &CarsRepositoryMock{
+				FindByIDFunc: func(_ context.Context, _ uuid.UUID) (domain.Car, error) {
+					return car, nil
+				},
+				FindAllFunc: func(_ context.Context) ([]domain.Car, error) {
+					return []domain.Car{car, fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity4)}.Build()}, nil
+				},
+			}
+		)
+		evs, err := app.NewRetireCar(gr, cr, &ReservationsRepositoryMock{}).Handle(context.Background(), app.RetireCarCmd{CarID: car.ID()})
+		require.NoError(t, err)
+		require.Len(t, evs, 2)
+		require.Equal(t, domain.CarRetiringEventName, evs[0].Name())
+		require.Equal(t, domain.GroupUnservableEventName, evs[1].Name())
+		require.Equal(t, waiting.ID(), evs[1].AggregateID())
+	})
+}
+
+func TestUpdateCar(t *testing.T) {
+	var (
+		randomErr = errors.New("")
+
+		gID = uuid.New()
+	)
+	testCases := []struct {
+		name            string
+		cmd             cqrs.Command
+		gr              *GroupsRepositoryMock
+		cr              *CarsRepositoryMock
+		expectedErrFunc func(*testing.T, error)
+	}{
+		{
+			name: `Given an invalid command, when it's called, then an error is returned`,
+			cmd:  newInvalidCommand(),
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorAs(t, err, &app.InvalidCommandError{})
+			},
+		},
+		{
+			name: `Given a wrong capacity, when it's called, then an error is returned`,
+			cmd:  app.UpdateCarCmd{Seats: 3},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, domain.ErrCapacityNotSupported)
+			},
+		},
+		{
+			name: `Given a cars repository that returns an error on FindByID method,
+				when it's called, then an error is returned`,
+			cmd: app.UpdateCarCmd{Seats: domain.CarCapacity6},
+			cr: &CarsRepositoryMock{
+				FindByIDFunc: func(_ context.Context, _ uuid.UUID) (domain.Car, error) {
+					return domain.Car{}, randomErr
+				},
+			},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, randomErr)
+			},
+		},
+		{
+			name: `Given a car with more people on journey than the new capacity,
+				when it's called, then an error is returned`,
+			cmd: app.UpdateCarCmd{Seats: domain.CarCapacity4},
+			gr:  &GroupsRepositoryMock{},
+			cr: &CarsRepositoryMock{
+				FindByIDFunc: func(_ context.Context, _ uuid.UUID) (domain.Car, error) {
+					return fixtures.Car{
+						Capacity: helpers.CarCapacityPtr(domain.CarCapacity6),
+						Journeys: domain.Journeys{gID: fixtures.Group{ID: helpers.UUIDPtr(gID), People: helpers.IntPtr(5)}.Build()},
+					}.Build(), nil
+				},
+			},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, domain.ErrCapacityBelowOccupancy)
+			},
+		},
+		{
+			name: `Given a waiting group that doesn't fit in a car,
+				when its capacity is increased, then the group gets on it`,
+			cmd: app.UpdateCarCmd{Seats: domain.CarCapacity6},
+			gr: &GroupsRepositoryMock{
+				FindGroupsWithoutCarFunc: func(_ context.Context) ([]domain.Group, error) {
+					return []domain.Group{fixtures.Group{ID: helpers.UUIDPtr(gID), People: helpers.IntPtr(6)}.Build()}, nil
+				},
+			},
+			cr: &CarsRepositoryMock{
+				FindByIDFunc: func(_ context.Context, _ uuid.UUID) (domain.Car, error) {
+					return fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity4)}.Build(), nil
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		ch := app.NewUpdateCar(tc.gr, tc.cr, &ReservationsRepositoryMock{})
+		_, err := ch.Handle(context.Background(), tc.cmd)
+		require.Equal(t, tc.expectedErrFunc == nil, err == nil, tc.name)
+		if err != nil {
+			tc.expectedErrFunc(t, err)
+			continue
+		}
+
+		require.Len(t, tc.cr.UpdateCalls(), 1, tc.name)
+		car := tc.cr.UpdateCalls()[0].Car
+		require.Equal(t, domain.CarCapacity6, car.Capacity(), tc.name)
+		require.Contains(t, car.Journeys(), gID, tc.name)
+		require.Len(t, tc.gr.UpdateCalls(), 1, tc.name)
+		require.Equal(t, gID, tc.gr.UpdateCalls()[0].G.ID(), tc.name)
+	}
+
+	t.Run(`Given waiting groups of 5 and 6 people,
+		when the capacity of the only six seats car is decreased to 5,
+		then only the group of 6 is reported as unservable`, func(t *testing.T) {
+		var (
+			onBoard = fixtures.Group{ID: helpers.UUIDPtr(uuid.New()), People: helpers.IntPtr(2)}.Build()
+			five    = fixtures.Group{ID: helpers.UUIDPtr(uuid.New()), People: helpers.IntPtr(5)}.Build()
+			six     = fixtures.Group{ID: helpers.UUIDPtr(uuid.New()), People: helpers.IntPtr(6)}.Build()
+			car     = fixtures.Car{
+				Capacity: helpers.CarCapacityPtr(domain.CarCapacity6),
+				Journeys: domain.Journeys{onBoard.ID(): onBoard},
+			}.Build()
+			gr = &GroupsRepositoryMock{
+				FindGroupsWithoutCarFunc: func(_ context.Context) ([]domain.Group, error) {
+					return []domain.Group{five, six}, nil
+				},
+			}
+			cr = &CarsRepositoryMock{
+				FindByIDFunc: func(_ context.Context, _ uuid.UUID) (domain.Car, error) {
+					return car, nil
+				},
+				FindAllFunc: func(_ context.Context) ([]domain.Car, error) {
+					return []domain.Car{car}, nil
+				},
+			}
+		)
+		evs, err := app.NewUpdateCar(gr, cr, &ReservationsRepositoryMock{}).Handle(context.Background(), app.UpdateCarCmd{CarID: car.ID(), Seats: domain.CarCapacity5})
+		require.NoError(t, err)
+		require.Len(t, evs, 2)
+		require.Equal(t, domain.CarUpdatedEventName, evs[0].Name())
+		require.Equal(t, domain.GroupUnservableEventName, evs[1].Name())
+		require.Equal(t, six.ID(), evs[1].AggregateID())
+	})
+}
+
+func TestTakeCarOutOfService(t *testing.T) {
+	var (
+		randomErr = errors.New("")
+
+		gID          = uuid.New()
+		outOfService = domain.CarOutOfService
+	)
+	testCases := []struct {
+		name            string
+		cmd             cqrs.Command
+		gr              *GroupsRepositoryMock
+		cr              *CarsRepositoryMock
+		expectedStatus  domain.CarStatus
+		expectedErrFunc func(*testing.T, error)
+	}{
+		{
+			name: `Given an invalid command, when it's called, then an error is returned`,
+			cmd:  newInvalidCommand(),
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorAs(t, err, &app.InvalidCommandError{})
+			},
+		},
+		{
+			name: `Given a cars repository that returns an error on FindByID method,
+				when it's called, then an error is returned`,
+			cmd: app.TakeCarOutOfServiceCmd{},
+			cr: &CarsRepositoryMock{
+				FindByIDFunc: func(_ context.Context, _ uuid.UUID) (domain.Car, error) {
+					return domain.Car{}, randomErr
+				},
+			},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, randomErr)
+			},
+		},
+		{
+			name: `Given an out of service car, when it's called, then an error is returned`,
+			cmd:  app.TakeCarOutOfServiceCmd{},
+			gr:   &GroupsRepositoryMock{},
+			cr: &CarsRepositoryMock{
+				FindByIDFunc: func(_ context.Context, _ uuid.UUID) (domain.Car, error) {
+					return fixtures.Car{Status: &outOfService}.Build(), nil
+				},
+			},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, domain.ErrCarOutOfService)
+			},
+		},
+		{
+			name: `Given a car with groups on journey, when it's called, then it's drained`,
+			cmd:  app.TakeCarOutOfServiceCmd{},
+			gr:   &GroupsRepositoryMock{},
+			cr: &CarsRepositoryMock{
+				FindByIDFunc: func(_ context.Context, _ uuid.UUID) (domain.Car, error) {
+					return fixtures.Car{
+						Journeys: domain.Journeys{gID: fixtures.Group{ID: helpers.UUIDPtr(gID)}.Build()},
+					}.Build(), nil
+				},
+			},
+			expectedStatus: domain.CarDraining,
+		},
+	}
+
+	for _, tc := range testCases {
+		ch := app.NewTakeCarOutOfService(tc.gr, tc.cr, &ReservationsRepositoryMock{})
+		evs, err := ch.Handle(context.Background(), tc.cmd)
+		require.Equal(t, tc.expectedErrFunc == nil, err == nil, tc.name)
+		if err != nil {
+			tc.expectedErrFunc(t, err)
+			continue
+		}
+
+		require.Len(t, evs, 1, tc.name)
+		require.Equal(t, domain.CarDrainingEventName, evs[0].Name(), tc.name)
+		require.Len(t, tc.cr.UpdateCalls(), 1, tc.name)
+		car := tc.cr.UpdateCalls()[0].Car
+		require.Equal(t, tc.expectedStatus, car.Status(), tc.name)
+		require.Contains(t, car.Journeys(), gID, tc.name)
+	}
+}
+
+func TestPutCarBackInService(t *testing.T) {
+	var (
+		randomErr = errors.New("")
+
+		gID          = uuid.New()
+		outOfService = domain.CarOutOfService
+	)
+	testCases := []struct {
+		name            string
+		cmd             cqrs.Command
+		gr              *GroupsRepositoryMock
+		cr              *CarsRepositoryMock
+		expectedErrFunc func(*testing.T, error)
+	}{
+		{
+			name: `Given an invalid command, when it's called, then an error is returned`,
+			cmd:  newInvalidCommand(),
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorAs(t, err, &app.InvalidCommandError{})
+			},
+		},
+		{
+			name: `Given a cars repository that returns an error on FindByID method,
+				when it's called, then an error is returned`,
+			cmd: app.PutCarBackInServiceCmd{},
+			cr: &CarsRepositoryMock{
+				FindByIDFunc: func(_ context.Context, _ uuid.UUID) (domain.Car, error) {
+					return domain.Car{}, randomErr
+				},
+			},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, randomErr)
+			},
+		},
+		{
+			name: `Given an available car, when it's called, then an error is returned`,
+			cmd:  app.PutCarBackInServiceCmd{},
+			gr:   &GroupsRepositoryMock{},
+			cr: &CarsRepositoryMock{
+				FindByIDFunc: func(_ context.Context, _ uuid.UUID) (domain.Car, error) {
+					return fixtures.Car{}.Build(), nil
+				},
+			},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, domain.ErrCarInService)
+			},
+		},
+		{
+			name: `Given a waiting group and an out of service car,
+				when it's called, then the car is available and the group gets on it`,
+			cmd: app.PutCarBackInServiceCmd{},
+			gr: &GroupsRepositoryMock{
+				FindGroupsWithoutCarFunc: func(_ context.Context) ([]domain.Group, error) {
+					return []domain.Group{fixtures.Group{ID: helpers.UUIDPtr(gID), People: helpers.IntPtr(4)}.Build()}, nil
+				},
+			},
+			cr: &CarsRepositoryMock{
+				FindByIDFunc: func(_ context.Context, _ uuid.UUID) (domain.Car, error) {
+					return fixtures.Car{Status: &outOfService}.Build(), nil
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		ch := app.NewPutCarBackInService(tc.gr, tc.cr, &ReservationsRepositoryMock{})
+		_, err := ch.Handle(context.Background(), tc.cmd)
+		require.Equal(t, tc.expectedErrFunc == nil, err == nil, tc.name)
+		if err != nil {
+			tc.expectedErrFunc(t, err)
+			continue
+		}
+
+		require.Len(t, tc.cr.UpdateCalls(), 1, tc.name)
+		car := tc.cr.UpdateCalls()[0].Car
+		require.Equal(t, domain.CarAvailable, car.Status(), tc.name)
+		require.Contains(t, car.Journeys(), gID, tc.name)
+		require.Len(t, tc.gr.UpdateCalls(), 1, tc.name)
+	}
+}
+
+func TestReportCarPosition(t *testing.T) {
+	var (
+		randomErr = errors.New("")
+
+		carID    = uuid.New()
+		gID      = uuid.New()
+		position = domain.Location{Lat: 41.3874, Lng: 2.1686}
+	)
+	testCases := []struct {
+		name            string
+		cmd             cqrs.Command
+		gr              *GroupsRepositoryMock
+		cr              *CarsRepositoryMock
+		expectedErrFunc func(*testing.T, error)
+	}{
+		{
+			name: `Given an invalid command, when it's called, then an error is returned`,
+			cmd:  newInvalidCommand(),
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorAs(t, err, &app.InvalidCommandError{})
+			},
+		},
+		{
+			name: `Given a wrong position, when it's called, then an error is returned`,
+			cmd:  app.ReportCarPositionCmd{CarID: carID, Position: domain.Location{Lat: 91}},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, domain.ErrWrongLocation)
+			},
+		},
+		{
+			name: `Given a cars repository that returns an error on FindByID method,
+				when it's called, then an error is returned`,
+			cmd: app.ReportCarPositionCmd{CarID: carID, Position: position},
+			cr: &CarsRepositoryMock{
+				FindByIDFunc: func(_ context.Context, _ uuid.UUID) (domain.Car, error) {
+					return domain.Car{}, randomErr
+				},
+			},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, randomErr)
+			},
+		},
+		{
+			name: `Given a cars repository that returns an error on Update method,
+				when it's called, then an error is returned`,
+			cmd: app.ReportCarPositionCmd{CarID: carID, Position: position},
+			gr:  &GroupsRepositoryMock{},
+			cr: &CarsRepositoryMock{
+				FindByIDFunc: func(_ context.Context, _ uuid.UUID) (domain.Car, error) {
+					return fixtures.Car{ID: helpers.UUIDPtr(carID)}.Build(), nil
+				},
+				UpdateFunc: func(_ context.Context, _ domain.Car) error {
+					return randomErr
+				},
+			},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, randomErr)
+			},
+		},
+		{
+			name: `Given a waiting group, when the car reports its position, then it's updated and the group gets on it`,
+			cmd:  app.ReportCarPositionCmd{CarID: carID, Position: position},
+			gr: &GroupsRepositoryMock{
+				FindGroupsWithoutCarFunc: func(_ context.Context) ([]domain.Group, error) {
+					return []domain.Group{fixtures.Group{ID: helpers.UUIDPtr(gID), People: helpers.IntPtr(2), Pickup: &position}.Build()}, nil
+				},
+			},
+			cr: &CarsRepositoryMock{
+				FindByIDFunc: func(_ context.Context, _ uuid.UUID) (domain.Car, error) {
+					return fixtures.Car{ID: helpers.UUIDPtr(carID)}.Build(), nil
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		ch := app.NewReportCarPosition(tc.gr, tc.cr, &ReservationsRepositoryMock{}, domain.WithMaxPickupRadius(5))
+		evs, err := ch.Handle(context.Background(), tc.cmd)
+		require.Equal(t, tc.expectedErrFunc == nil, err == nil, tc.name)
+		if err != nil {
+			tc.expectedErrFunc(t, err)
+			continue
+		}
+
+		require.Len(t, tc.cr.UpdateCalls(), 1, tc.name)
+		require.Equal(t, &position, tc.cr.UpdateCalls()[0].Car.Position(), tc.name)
+		require.Len(t, tc.gr.UpdateCalls(), 1, tc.name)
+		require.Equal(t, gID, tc.gr.UpdateCalls()[0].G.ID(), tc.name)
+		require.IsType(t, domain.CarPositionReportedEvent{}, evs[0], tc.name)
+	}
+}
@@ -1,33 +1,144 @@
 package app
 
 import (
-	"theskyinflames/car-sharing/internal/infra/repository"
+	"theskyinflames/car-sharing/internal/domain"
 
 	"github.com/theskyinflames/cqrs-eda/pkg/bus"
 	"github.com/theskyinflames/cqrs-eda/pkg/cqrs"
 	"github.com/theskyinflames/cqrs-eda/pkg/helpers"
 )
 
-// BuildCommandQueryBus returns the command/query bus
-func BuildCommandQueryBus(log cqrs.Logger, eventsBus bus.Bus) bus.Bus {
-	gr := repository.NewGroupsRepository()
-	evr := repository.NewCarRepository()
+// commandAttempts is the number of times that a command is tried when it finds a version conflict
+const commandAttempts = 3
 
-	chMw := cqrs.CommandHandlerMultiMiddleware(
+type busConfig struct {
+	clock     Clock
+	fleetOpts []domain.FleetOption
+	chMws     []cqrs.CommandHandlerMiddleware
+	qhMws     []cqrs.QueryHandlerMiddleware
+	outbox    Outbox
+}
+
+// BusOption customizes the command/query bus
+type BusOption func(*busConfig)
+
+// WithFleetOptions sets the fleet options passed to the command handlers
+func WithFleetOptions(opts ...domain.FleetOption) BusOption {
+	return func(c *busConfig) {
+		c.fleetOpts = append(c.fleetOpts, opts...)
+	}
+}
+
+// WithClock sets the clock of the handlers and the fleet. By default, the SystemClock
+func WithClock(clock Clock) BusOption {
+	return func(c *busConfig) {
+		c.clock = clock
+	}
+}
+
+// WithCommandHandlerMiddleware adds a middleware that wraps every command handler, after the built-in ones
+func WithCommandHandlerMiddleware(mw cqrs.CommandHandlerMiddleware) BusOption {
+	return func(c *busConfig) {
+		c.chMws = append(c.chMws, mw)
+	}
+}
+
+// WithQueryHandlerMiddleware adds a middleware that wraps every query handler, after the built-in ones
+func WithQueryHandlerMiddleware(mw cqrs.QueryHandlerMiddleware) BusOption {
+	return func(c *busConfig) {
+		c.qhMws = append(c.qhMws, mw)
+	}
+}
+
+// WithOutbox makes the events of the commands be added to the outbox, in the same unit of work than their changes,
+// instead of being dispatched to the events bus. They are delivered by an OutboxRelay
+func WithOutbox(ob Outbox) BusOption {
+	return func(c *busConfig) {
+		c.outbox = ob
+	}
+}
+
+// BuildCommandQueryBus returns the command/query bus.
+// The commands are run one at a time, each one in its own unit of work, and its events are dispatched once it's committed,
+// or added to the outbox if there is one. If a command fails because of a version conflict, it's retried.
+func BuildCommandQueryBus(
+	log cqrs.Logger,
+	eventsBus bus.Bus,
+	gr GroupsRepository,
+	evr CarsRepository,
+	uow UnitOfWork,
+	hr JourneysHistoryRepository,
+	wr WebhooksRepository,
+	rr ReservationsRepository,
+	opts ...BusOption,
+) bus.Bus {
+	cfg := busConfig{clock: SystemClock{}}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	cfg.fleetOpts = append([]domain.FleetOption{domain.WithClock(cfg.clock)}, cfg.fleetOpts...)
+
+	chMws := []cqrs.CommandHandlerMiddleware{
+		ChUnitOfWorkMw(uow),
+		ChRetryMw(commandAttempts),
+		ChSingleWriterMw(),
 		cqrs.ChEventMw(eventsBus),
 		cqrs.ChErrMw(log),
-	)
+	}
+	if cfg.outbox != nil {
+		chMws = []cqrs.CommandHandlerMiddleware{
+			ChOutboxMw(cfg.outbox),
+			ChUnitOfWorkMw(uow),
+			ChRetryMw(commandAttempts),
+			ChSingleWriterMw(),
+			cqrs.ChErrMw(log),
+		}
+	}
+	chMw := cqrs.CommandHandlerMultiMiddleware(append(chMws, cfg.chMws...)...)
+	qhMw := cqrs.QueryHandlerMultiMiddleware(append([]cqrs.QueryHandlerMiddleware{
+		cqrs.QhErrMw(log),
+	}, cfg.qhMws...)...)
 
-	initializeFleetCh := chMw(NewInitializeFleet(&gr, &evr))
-	journeyCh := chMw(NewJourney(&gr, &evr))
-	dropOffCh := chMw(NewDropOff(&gr, &evr))
+	initializeFleetCh := chMw(NewInitializeFleet(gr, evr, cfg.fleetOpts...))
+	journeyCh := chMw(NewJourney(gr, evr, rr, cfg.fleetOpts...))
+	dropOffCh := chMw(NewDropOff(gr, evr, rr, cfg.fleetOpts...))
+	cancelJourneyCh := chMw(NewCancelJourney(gr, evr, rr, cfg.fleetOpts...))
+	expireGroupCh := chMw(NewExpireGroup(gr, evr, rr, cfg.fleetOpts...))
+	scheduleJourneyCh := chMw(NewScheduleJourney(gr, evr, rr, cfg.fleetOpts...))
+	startReservationCh := chMw(NewStartReservation(gr, evr, rr, cfg.fleetOpts...))
+	addCarsCh := chMw(NewAddCars(gr, evr, rr, cfg.fleetOpts...))
+	retireCarCh := chMw(NewRetireCar(gr, evr, rr, cfg.fleetOpts...))
+	updateCarCh := chMw(NewUpdateCar(gr, evr, rr, cfg.fleetOpts...))
+	takeCarOutOfServiceCh := chMw(NewTakeCarOutOfService(gr, evr, rr, cfg.fleetOpts...))
+	putCarBackInServiceCh := chMw(NewPutCarBackInService(gr, evr, rr, cfg.fleetOpts...))
+	reportCarPositionCh := chMw(NewReportCarPosition(gr, evr, rr, cfg.fleetOpts...))
+	registerWebhookCh := chMw(NewRegisterWebhook(wr))
 
-	localeQh := cqrs.QhErrMw(log)(NewLocate(&gr, &evr))
+	localeQh := qhMw(NewLocate(gr, evr))
+	fleetStatusQh := qhMw(NewFleetStatus(gr, evr))
+	sitesOccupancyQh := qhMw(NewSitesOccupancy(gr, evr))
+	waitTimesQh := qhMw(NewWaitTimes(hr, cfg.clock))
+	deadLettersQh := qhMw(NewDeadLetters(wr))
 
 	bus := bus.New()
 	bus.Register(InitializeFleetName, helpers.BusChHandler(initializeFleetCh))
 	bus.Register(JourneyName, helpers.BusChHandler(journeyCh))
 	bus.Register(DropOffName, helpers.BusChHandler(dropOffCh))
+	bus.Register(CancelJourneyName, helpers.BusChHandler(cancelJourneyCh))
+	bus.Register(ExpireGroupName, helpers.BusChHandler(expireGroupCh))
+	bus.Register(ScheduleJourneyName, helpers.BusChHandler(scheduleJourneyCh))
+	bus.Register(StartReservationName, helpers.BusChHandler(startReservationCh))
+	bus.Register(AddCarsName, helpers.BusChHandler(addCarsCh))
+	bus.Register(RetireCarName, helpers.BusChHandler(retireCarCh))
+	bus.Register(UpdateCarName, helpers.BusChHandler(updateCarCh))
+	bus.Register(TakeCarOutOfServiceName, helpers.BusChHandler(takeCarOutOfServiceCh))
+	bus.Register(PutCarBackInServiceName, helpers.BusChHandler(putCarBackInServiceCh))
+	bus.Register(ReportCarPositionName, helpers.BusChHandler(reportCarPositionCh))
+	bus.Register(RegisterWebhookName, helpers.BusChHandler(registerWebhookCh))
 	bus.Register(LocateName, helpers.BusQhHandler(localeQh))
+	bus.Register(FleetStatusName, helpers.BusQhHandler(fleetStatusQh))
+	bus.Register(SitesOccupancyName, helpers.BusQhHandler(sitesOccupancyQh))
+	bus.Register(WaitTimesName, helpers.BusQhHandler(waitTimesQh))
+	bus.Register(DeadLettersName, helpers.BusQhHandler(deadLettersQh))
 	return bus
 }
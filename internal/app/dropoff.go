@@ -27,11 +27,19 @@ func (cmd DropOffCmd) Name() string {
 type DropOff struct {
 	gr  GroupsRepository
 	evr CarsRepository
+	rr  ReservationsRepository
+
+	fleetOpts []domain.FleetOption
 }
 
 // NewDropOff is a constructor
-func NewDropOff(gr GroupsRepository, evr CarsRepository) DropOff {
-	return DropOff{gr: gr, evr: evr}
+func NewDropOff(
+	gr GroupsRepository,
+	evr CarsRepository,
+	rr ReservationsRepository,
+	fleetOpts ...domain.FleetOption,
+) DropOff {
+	return DropOff{gr: gr, evr: evr, rr: rr, fleetOpts: fleetOpts}
 }
 
 // Handle implements CommandHandler interface
@@ -51,6 +59,11 @@ func (ch DropOff) Handle(ctx context.Context, cmd cqrs.Command) ([]events.Event,
 		return nil, err
 	}
 
+	cars, err := ch.evr.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
 	var ev *domain.Car
 	if g.Car() != nil {
 		gev, err := ch.evr.FindByID(ctx, g.Car().ID())
@@ -60,28 +73,44 @@ func (ch DropOff) Handle(ctx context.Context, cmd cqrs.Command) ([]events.Event,
 		ev = &gev
 	}
 
-	// here we don't need all the list of evs. We only need the dropping group ev
-	fleet := domain.NewFleet(nil, wg)
+	fleetOpts, err := withReservations(ctx, ch.rr, ch.fleetOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	// the whole list of cars is needed to know which ones are already reserved by starving groups
+	fleet := domain.NewFleet(cars, wg, fleetOpts...)
 	resultEv, onJourney, err := fleet.DropOff(&g, ev)
 	if err != nil {
 		return nil, err
 	}
 
+	evs := g.Events()
+
 	if resultEv != nil {
-		if err := ch.evr.Update(ctx, *resultEv); err != nil {
+		evs = append(evs, resultEv.Events()...)
+		// a retiring car leaves the fleet with its last group
+		if err := saveCar(ctx, ch.evr, *resultEv); err != nil {
 			return nil, err
 		}
 	}
 
 	for _, oj := range onJourney {
+		evs = append(evs, oj.Events()...)
 		if err := ch.gr.Update(ctx, oj); err != nil {
 			return nil, err
 		}
 	}
 
+	for _, og := range fleet.OvertakenGroups() {
+		if err := ch.gr.Update(ctx, og); err != nil {
+			return nil, err
+		}
+	}
+
 	if err := ch.gr.RemoveByID(ctx, g.ID()); err != nil {
 		return nil, err
 	}
 
-	return g.Events(), nil
+	return evs, nil
 }
@@ -182,7 +182,7 @@ func TestDropOff(t *testing.T) {
 	}
 
 	for _, tc := range testCases {
-		ch := app.NewDropOff(tc.gr, tc.cr)
+		ch := app.NewDropOff(tc.gr, tc.cr, &ReservationsRepositoryMock{})
 		_, err := ch.Handle(context.Background(), tc.cmd)
 		require.Equal(t, tc.expectedErrFunc == nil, err == nil)
 		if err != nil {
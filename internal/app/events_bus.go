@@ -8,15 +8,30 @@ import (
 	"theskyinflames/car-sharing/internal/domain"
 
 	"github.com/theskyinflames/cqrs-eda/pkg/bus"
+	"github.com/theskyinflames/cqrs-eda/pkg/cqrs"
 	"github.com/theskyinflames/cqrs-eda/pkg/events"
 )
 
-// BuildEventsBus returns a generic events bus
-func BuildEventsBus() bus.Bus {
+// BuildEventsBus returns the events bus. The journeys of the groups, and the waits they abandon, are recorded in the journeys history,
+// the events are sent to the webhooks subscribed to them, and all of them are fanned out by the hub
+func BuildEventsBus(log cqrs.Logger, hr JourneysHistoryRepository, wn WebhooksNotifier, hub EventsHub) bus.Bus {
 	eventsBus := bus.New()
-	eventsBus.Register(domain.CarCreatedEventName, busHandler(eventHandler()))
-	eventsBus.Register(domain.GroupSetOnJourneyEventName, busHandler(eventHandler()))
-	eventsBus.Register(domain.GroupDroppedOffEventName, busHandler(eventHandler()))
+	eventsBus.Register(domain.CarCreatedEventName, busHandler(eventHandler(), wn.Handler(), hub.Handler()))
+	eventsBus.Register(domain.CarUpdatedEventName, busHandler(eventHandler(), hub.Handler()))
+	eventsBus.Register(domain.CarRetiringEventName, busHandler(eventHandler(), hub.Handler()))
+	eventsBus.Register(domain.CarRetiredEventName, busHandler(eventHandler(), hub.Handler()))
+	eventsBus.Register(domain.CarOutOfServiceEventName, busHandler(eventHandler(), hub.Handler()))
+	eventsBus.Register(domain.CarDrainingEventName, busHandler(eventHandler(), hub.Handler()))
+	eventsBus.Register(domain.CarBackInServiceEventName, busHandler(eventHandler(), hub.Handler()))
+	eventsBus.Register(domain.CarReservedEventName, busHandler(eventHandler(), hub.Handler()))
+	eventsBus.Register(domain.CarReservedForScheduledJourneyEventName, busHandler(eventHandler(), hub.Handler()))
+	eventsBus.Register(domain.CarPositionReportedEventName, busHandler(eventHandler(), hub.Handler()))
+	eventsBus.Register(domain.GroupSetOnJourneyEventName, busHandler(eventHandler(), RecordBoardingHandler(hr, log), wn.Handler(), hub.Handler()))
+	eventsBus.Register(domain.GroupDroppedOffEventName, busHandler(eventHandler(), RecordDropOffHandler(hr, log), wn.Handler(), hub.Handler()))
+	eventsBus.Register(domain.GroupCancelledEventName, busHandler(eventHandler(), RecordAbandonHandler(hr, log), wn.Handler(), hub.Handler()))
+	eventsBus.Register(domain.GroupExpiredEventName, busHandler(eventHandler(), RecordAbandonHandler(hr, log), wn.Handler(), hub.Handler()))
+	eventsBus.Register(domain.GroupUnservableEventName, busHandler(eventHandler(), hub.Handler()))
+	eventsBus.Register(domain.ReservationScheduledEventName, busHandler(eventHandler(), hub.Handler()))
 	return eventsBus
 }
 
@@ -26,13 +41,16 @@ func eventHandler() events.Handler {
 	})
 }
 
-func busHandler(evh events.Handler) bus.Handler {
+// busHandler adapts a list of events handlers to the bus. They are called in order
+func busHandler(evhs ...events.Handler) bus.Handler {
 	return bus.Handler(func(_ context.Context, d bus.Dispatchable) (interface{}, error) {
 		ev, ok := d.(events.Event)
 		if !ok {
 			return nil, errors.New("is not an event")
 		}
-		evh(ev)
+		for _, evh := range evhs {
+			evh(ev)
+		}
 		return nil, nil
 	})
 }
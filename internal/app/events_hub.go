@@ -0,0 +1,64 @@
+package app
+
+import (
+	"sync"
+
+	"github.com/theskyinflames/cqrs-eda/pkg/events"
+)
+
+// EventsHub fans out the events of the events bus to the subscribers added while the service runs, like the streams
+// of the API. An event is never blocked by a subscriber: the one that doesn't keep up, because its buffer is full,
+// is unsubscribed and its channel is closed.
+type EventsHub struct {
+	subs map[uint64]chan events.Event
+	next *uint64
+
+	mux *sync.Mutex
+}
+
+// NewEventsHub is a constructor
+func NewEventsHub() EventsHub {
+	return EventsHub{subs: make(map[uint64]chan events.Event), next: new(uint64), mux: &sync.Mutex{}}
+}
+
+// Subscribe returns a channel with the events that happen from now on, with room for size events, and the function
+// to unsubscribe. The channel is closed once it's unsubscribed
+func (h EventsHub) Subscribe(size int) (<-chan events.Event, func()) {
+	h.mux.Lock()
+	defer h.mux.Unlock()
+
+	id := *h.next
+	*h.next++
+	ch := make(chan events.Event, size)
+	h.subs[id] = ch
+
+	return ch, func() {
+		h.mux.Lock()
+		defer h.mux.Unlock()
+		h.unsubscribe(id)
+	}
+}
+
+// Handler returns the events handler that sends the events to the subscribers
+func (h EventsHub) Handler() events.Handler {
+	return func(ev events.Event) {
+		h.mux.Lock()
+		defer h.mux.Unlock()
+
+		for id, ch := range h.subs {
+			select {
+			case ch <- ev:
+			default:
+				h.unsubscribe(id)
+			}
+		}
+	}
+}
+
+// unsubscribe must be called with the lock held
+func (h EventsHub) unsubscribe(id uint64) {
+	if ch, ok := h.subs[id]; ok {
+		close(ch)
+		delete(h.subs, id)
+	}
+}
@@ -0,0 +1,54 @@
+package app_test
+
+import (
+	"testing"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/domain"
+	"theskyinflames/car-sharing/internal/fixtures"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventsHub(t *testing.T) {
+	ev := domain.NewCarCreatedEvent(fixtures.Car{}.Build())
+
+	t.Run(`Given two subscribers, when an event is handled, then both of them receive it`, func(t *testing.T) {
+		hub := app.NewEventsHub()
+		ch1, unsubscribe1 := hub.Subscribe(1)
+		defer unsubscribe1()
+		ch2, unsubscribe2 := hub.Subscribe(1)
+		defer unsubscribe2()
+
+		hub.Handler()(ev)
+		require.Equal(t, ev, <-ch1)
+		require.Equal(t, ev, <-ch2)
+	})
+
+	t.Run(`Given a subscriber that unsubscribes, when an event is handled, then it doesn't receive it`, func(t *testing.T) {
+		hub := app.NewEventsHub()
+		ch, unsubscribe := hub.Subscribe(1)
+		unsubscribe()
+		unsubscribe()
+
+		hub.Handler()(ev)
+		_, ok := <-ch
+		require.False(t, ok)
+	})
+
+	t.Run(`Given a subscriber with its buffer full, when an event is handled, then it's unsubscribed without blocking the others`, func(t *testing.T) {
+		hub := app.NewEventsHub()
+		slow, unsubscribeSlow := hub.Subscribe(1)
+		defer unsubscribeSlow()
+		fast, unsubscribeFast := hub.Subscribe(2)
+		defer unsubscribeFast()
+
+		hub.Handler()(ev)
+		hub.Handler()(ev)
+
+		require.Equal(t, ev, <-slow)
+		_, ok := <-slow
+		require.False(t, ok)
+		require.Len(t, fast, 2)
+	})
+}
@@ -0,0 +1,53 @@
+package app
+
+import (
+	"context"
+
+	"theskyinflames/car-sharing/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/theskyinflames/cqrs-eda/pkg/cqrs"
+	"github.com/theskyinflames/cqrs-eda/pkg/events"
+)
+
+// ExpireGroupCmd is a command
+type ExpireGroupCmd struct {
+	GroupID uuid.UUID
+}
+
+// ExpireGroupName is self-described
+var ExpireGroupName = "expire.group"
+
+// Name implements the Command interface
+func (cmd ExpireGroupCmd) Name() string {
+	return ExpireGroupName
+}
+
+// ExpireGroup is a command handler. It removes a group that has been waiting too long, see ExpiryScheduler
+type ExpireGroup struct {
+	gr  GroupsRepository
+	evr CarsRepository
+	rr  ReservationsRepository
+
+	fleetOpts []domain.FleetOption
+}
+
+// NewExpireGroup is a constructor
+func NewExpireGroup(
+	gr GroupsRepository,
+	evr CarsRepository,
+	rr ReservationsRepository,
+	fleetOpts ...domain.FleetOption,
+) ExpireGroup {
+	return ExpireGroup{gr: gr, evr: evr, rr: rr, fleetOpts: fleetOpts}
+}
+
+// Handle implements CommandHandler interface
+func (ch ExpireGroup) Handle(ctx context.Context, cmd cqrs.Command) ([]events.Event, error) {
+	co, ok := cmd.(ExpireGroupCmd)
+	if !ok {
+		return nil, NewInvalidCommandError(ExpireGroupName, cmd.Name())
+	}
+
+	return leaveWaitingList(ctx, ch.gr, ch.evr, ch.rr, ch.fleetOpts, co.GroupID, (*domain.Fleet).Expire)
+}
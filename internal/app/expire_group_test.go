@@ -0,0 +1,52 @@
+package app_test
+
+import (
+	"context"
+	"testing"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/domain"
+	"theskyinflames/car-sharing/internal/fixtures"
+	"theskyinflames/car-sharing/internal/helpers"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpireGroup(t *testing.T) {
+	var (
+		gID     = uuid.New()
+		waiting = fixtures.Group{ID: helpers.UUIDPtr(gID)}.Build()
+	)
+
+	t.Run(`Given an invalid command, when it's called, then an error is returned`, func(t *testing.T) {
+		_, err := app.NewExpireGroup(nil, nil, nil).Handle(context.Background(), newInvalidCommand())
+		require.ErrorAs(t, err, &app.InvalidCommandError{})
+	})
+
+	t.Run(`Given a waiting group, when it's expired, then it's removed and a group expired event is returned`, func(t *testing.T) {
+		gr := &GroupsRepositoryMock{
+			FindByIDFunc: func(_ context.Context, _ uuid.UUID) (domain.Group, error) {
+				return waiting, nil
+			},
+			FindGroupsWithoutCarFunc: func(_ context.Context) ([]domain.Group, error) {
+				return []domain.Group{waiting}, nil
+			},
+		}
+		evs, err := app.NewExpireGroup(gr, &CarsRepositoryMock{}, &ReservationsRepositoryMock{}).Handle(context.Background(), app.ExpireGroupCmd{GroupID: gID})
+		require.NoError(t, err)
+		require.Len(t, gr.RemoveByIDCalls(), 1)
+		require.Len(t, evs, 1)
+		require.Equal(t, domain.GroupExpiredEventName, evs[0].Name())
+	})
+
+	t.Run(`Given a group on journey, when it's expired, then an error is returned`, func(t *testing.T) {
+		gr := &GroupsRepositoryMock{
+			FindByIDFunc: func(_ context.Context, _ uuid.UUID) (domain.Group, error) {
+				return fixtures.Group{ID: helpers.UUIDPtr(gID), Car: helpers.EvPtr(fixtures.Car{}.Build())}.Build(), nil
+			},
+		}
+		_, err := app.NewExpireGroup(gr, &CarsRepositoryMock{}, &ReservationsRepositoryMock{}).Handle(context.Background(), app.ExpireGroupCmd{GroupID: gID})
+		require.ErrorIs(t, err, domain.ErrGroupOnJourney)
+	})
+}
@@ -0,0 +1,95 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"theskyinflames/car-sharing/internal/domain"
+
+	"github.com/theskyinflames/cqrs-eda/pkg/bus"
+	"github.com/theskyinflames/cqrs-eda/pkg/cqrs"
+)
+
+// Clock tells the current time. It's injected into the schedulers and the fleet, so they can be tested without waiting
+type Clock = domain.Clock
+
+// SystemClock is the Clock of the system
+type SystemClock struct{}
+
+// Now implements the Clock interface
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// ExpiryScheduler expires the groups that have been waiting for a car longer than the max waiting time.
+// Each stale group is expired through the command bus, so it's done with the same middlewares as any other command
+type ExpiryScheduler struct {
+	gr         GroupsRepository
+	commandBus bus.Bus
+	log        cqrs.Logger
+	clock      Clock
+
+	maxWait  time.Duration
+	interval time.Duration
+}
+
+// NewExpiryScheduler is a constructor. The interval is the time between two scans of the waiting groups
+func NewExpiryScheduler(
+	gr GroupsRepository,
+	commandBus bus.Bus,
+	log cqrs.Logger,
+	clock Clock,
+	maxWait, interval time.Duration,
+) ExpiryScheduler {
+	return ExpiryScheduler{
+		gr:         gr,
+		commandBus: commandBus,
+		log:        log,
+		clock:      clock,
+		maxWait:    maxWait,
+		interval:   interval,
+	}
+}
+
+// Expire expires the stale groups, and returns how many of them have been expired. A group that gets on a car,
+// or leaves, between the scan and its expiry is skipped silently. The other failures are logged, and the next groups are expired anyway
+func (s ExpiryScheduler) Expire(ctx context.Context) (int, error) {
+	wg, err := s.gr.FindGroupsWithoutCar(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	now := s.clock.Now()
+	var expired int
+	for _, g := range wg {
+		if !g.HasWaitedLongerThan(s.maxWait, now) {
+			continue
+		}
+		if _, err := s.commandBus.Dispatch(ctx, ExpireGroupCmd{GroupID: g.ID()}); err != nil {
+			if !errors.Is(err, domain.ErrGroupOnJourney) && !errors.Is(err, domain.ErrNotFound) {
+				s.log.Printf("expiry scheduler, group %s: %s\n", g.ID().String(), err.Error())
+			}
+			continue
+		}
+		expired++
+	}
+	return expired, nil
+}
+
+// Run expires the stale groups periodically, until the context is cancelled
+func (s ExpiryScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := s.Expire(ctx); err != nil {
+			s.log.Printf("expiry scheduler: %s\n", err.Error())
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package app_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/domain"
+	"theskyinflames/car-sharing/internal/fixtures"
+	"theskyinflames/car-sharing/internal/helpers"
+	"theskyinflames/car-sharing/internal/infra/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/theskyinflames/cqrs-eda/pkg/bus"
+)
+
+type fixedClock struct {
+	now time.Time
+}
+
+func (c fixedClock) Now() time.Time {
+	return c.now
+}
+
+func TestExpiryScheduler(t *testing.T) {
+	var (
+		randomErr = errors.New("")
+		logger    = log.New(io.Discard, "", 0)
+
+		now      = time.Now()
+		maxWait  = 10 * time.Minute
+		stale    = now.Add(-maxWait - time.Second)
+		recent   = now.Add(-maxWait + time.Second)
+		staleID1 = uuid.New()
+		staleID2 = uuid.New()
+		waiting  = []domain.Group{
+			fixtures.Group{ID: helpers.UUIDPtr(staleID1), RequestedAt: &stale}.Build(),
+			fixtures.Group{ID: helpers.UUIDPtr(uuid.New()), RequestedAt: &recent}.Build(),
+			fixtures.Group{ID: helpers.UUIDPtr(staleID2), RequestedAt: &stale}.Build(),
+		}
+	)
+
+	commandBus := func(handle func(app.ExpireGroupCmd) error) (bus.Bus, *[]uuid.UUID) {
+		var expired []uuid.UUID
+		b := bus.New()
+		b.Register(app.ExpireGroupName, func(_ context.Context, d bus.Dispatchable) (interface{}, error) {
+			cmd := d.(app.ExpireGroupCmd)
+			if err := handle(cmd); err != nil {
+				return nil, err
+			}
+			expired = append(expired, cmd.GroupID)
+			return nil, nil
+		})
+		return b, &expired
+	}
+
+	gr := &GroupsRepositoryMock{
+		FindGroupsWithoutCarFunc: func(_ context.Context) ([]domain.Group, error) {
+			return waiting, nil
+		},
+	}
+
+	t.Run(`Given some groups waiting longer than the max waiting time,
+		when the scheduler runs, then only those groups are expired through the command bus`, func(t *testing.T) {
+		b, expired := commandBus(func(app.ExpireGroupCmd) error { return nil })
+		s := app.NewExpiryScheduler(gr, b, logger, fixedClock{now: now}, maxWait, time.Hour)
+
+		n, err := s.Expire(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 2, n)
+		require.Equal(t, []uuid.UUID{staleID1, staleID2}, *expired)
+	})
+
+	t.Run(`Given a clock moved forward, when the scheduler runs, then all the groups are expired`, func(t *testing.T) {
+		b, expired := commandBus(func(app.ExpireGroupCmd) error { return nil })
+		s := app.NewExpiryScheduler(gr, b, logger, fixedClock{now: now.Add(time.Minute)}, maxWait, time.Hour)
+
+		n, err := s.Expire(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 3, n)
+		require.Len(t, *expired, 3)
+	})
+
+	t.Run(`Given a group that fails to be expired, when the scheduler runs, then the next ones are expired anyway`, func(t *testing.T) {
+		b, expired := commandBus(func(cmd app.ExpireGroupCmd) error {
+			if cmd.GroupID == staleID1 {
+				return domain.ErrGroupOnJourney
+			}
+			return nil
+		})
+		s := app.NewExpiryScheduler(gr, b, logger, fixedClock{now: now}, maxWait, time.Hour)
+
+		n, err := s.Expire(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+		require.Equal(t, []uuid.UUID{staleID2}, *expired)
+	})
+
+	t.Run(`Given groups that get on a car or leave before being expired, when the scheduler runs,
+		then they're skipped without logging it`, func(t *testing.T) {
+		b, expired := commandBus(func(cmd app.ExpireGroupCmd) error {
+			if cmd.GroupID == staleID1 {
+				return domain.ErrGroupOnJourney
+			}
+			return repository.ErrNotFound
+		})
+		var logged bytes.Buffer
+		s := app.NewExpiryScheduler(gr, b, log.New(&logged, "", 0), fixedClock{now: now}, maxWait, time.Hour)
+
+		n, err := s.Expire(context.Background())
+		require.NoError(t, err)
+		require.Zero(t, n)
+		require.Empty(t, *expired)
+		require.Empty(t, logged.String())
+	})
+
+	t.Run(`Given a groups repository that fails, when the scheduler runs, then an error is returned`, func(t *testing.T) {
+		gr := &GroupsRepositoryMock{
+			FindGroupsWithoutCarFunc: func(_ context.Context) ([]domain.Group, error) {
+				return nil, randomErr
+			},
+		}
+		s := app.NewExpiryScheduler(gr, bus.New(), logger, fixedClock{now: now}, maxWait, time.Hour)
+
+		_, err := s.Expire(context.Background())
+		require.ErrorIs(t, err, randomErr)
+	})
+}
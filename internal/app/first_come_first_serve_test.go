@@ -0,0 +1,112 @@
+package app_test
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/domain"
+	"theskyinflames/car-sharing/internal/infra/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFirstComeFirstServe(t *testing.T) {
+	t.Run(`Given a fleet and a random sequence of journeys and drop offs,
+		when they are handled,
+		then the waiting groups are always served in arrival order`, func(t *testing.T) {
+		var (
+			ctx = context.Background()
+			rnd = rand.New(rand.NewSource(1))
+
+			gr  = repository.NewGroupsRepository()
+			evr = repository.NewCarRepository()
+			rr  = repository.NewReservationsRepository()
+
+			journeyCh = app.NewJourney(&gr, &evr, rr)
+			dropOffCh = app.NewDropOff(&gr, &evr, rr)
+
+			groups []uuid.UUID
+		)
+
+		_, err := app.NewInitializeFleet(&gr, &evr).Handle(ctx, app.InitializeFleetCmd{
+			Cars: []app.Car{
+				{ID: uuid.New(), Seats: domain.CarCapacity4},
+				{ID: uuid.New(), Seats: domain.CarCapacity5},
+				{ID: uuid.New(), Seats: domain.CarCapacity6},
+			},
+		})
+		require.NoError(t, err)
+
+		for i := 0; i < 2000; i++ {
+			if len(groups) == 0 || rnd.Intn(2) == 0 {
+				gID := uuid.New()
+				_, err := journeyCh.Handle(ctx, app.JourneyCmd{ID: gID, People: rnd.Intn(6) + 1})
+				require.NoError(t, err)
+				groups = append(groups, gID)
+				requireNoWaitingGroupFits(t, &gr, &evr)
+				continue
+			}
+
+			idx := rnd.Intn(len(groups))
+			g, err := gr.FindByID(ctx, groups[idx])
+			require.NoError(t, err)
+
+			expected := expectedToGetOn(t, &gr, &evr, g)
+
+			_, err = dropOffCh.Handle(ctx, app.DropOffCmd{GroupID: g.ID()})
+			require.NoError(t, err)
+			groups = append(groups[:idx], groups[idx+1:]...)
+
+			for _, gID := range expected {
+				got, err := gr.FindByID(ctx, gID)
+				require.NoError(t, err)
+				require.True(t, got.IsOnJourney())
+			}
+			requireNoWaitingGroupFits(t, &gr, &evr)
+		}
+	})
+}
+
+// expectedToGetOn returns the waiting groups that should get on the car freed by the dropped off group,
+// by serving them in arrival order
+func expectedToGetOn(t *testing.T, gr app.GroupsRepository, evr app.CarsRepository, g domain.Group) []uuid.UUID {
+	if !g.IsOnJourney() {
+		return nil
+	}
+
+	ctx := context.Background()
+	car, err := evr.FindByID(ctx, g.Car().ID())
+	require.NoError(t, err)
+	wg, err := gr.FindGroupsWithoutCar(ctx)
+	require.NoError(t, err)
+
+	var expected []uuid.UUID
+	available := car.Availability() + g.People()
+	for i, w := range wg {
+		if i > 0 {
+			require.False(t, w.ArrivedBefore(wg[i-1]), "waiting groups are not sorted by arrival")
+		}
+		if w.People() <= available {
+			available -= w.People()
+			expected = append(expected, w.ID())
+		}
+	}
+	return expected
+}
+
+func requireNoWaitingGroupFits(t *testing.T, gr app.GroupsRepository, evr app.CarsRepository) {
+	ctx := context.Background()
+	cars, err := evr.FindAll(ctx)
+	require.NoError(t, err)
+	wg, err := gr.FindGroupsWithoutCar(ctx)
+	require.NoError(t, err)
+
+	for _, w := range wg {
+		for _, car := range cars {
+			require.Less(t, car.Availability(), w.People(), "a waiting group fits a car with free seats")
+		}
+	}
+}
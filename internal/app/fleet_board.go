@@ -0,0 +1,211 @@
+package app
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"theskyinflames/car-sharing/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/theskyinflames/cqrs-eda/pkg/bus"
+	"github.com/theskyinflames/cqrs-eda/pkg/cqrs"
+	"github.com/theskyinflames/cqrs-eda/pkg/events"
+)
+
+// fleetBoardEventsSize is the number of events that the board can have pending to refresh itself
+const fleetBoardEventsSize = 256
+
+// BoardGroup is a DTO
+type BoardGroup struct {
+	ID     uuid.UUID
+	People int
+}
+
+// BoardCar is a DTO. Its groups are the ones on journey, sorted by ID
+type BoardCar struct {
+	ID           uuid.UUID
+	Site         domain.Site
+	Capacity     domain.CarCapacity
+	Availability int
+	Status       domain.CarStatus
+	Groups       []BoardGroup
+}
+
+func (c BoardCar) equal(o BoardCar) bool {
+	if c.ID != o.ID || c.Site != o.Site || c.Capacity != o.Capacity || c.Availability != o.Availability ||
+		c.Status != o.Status || len(c.Groups) != len(o.Groups) {
+		return false
+	}
+	for i := range c.Groups {
+		if c.Groups[i] != o.Groups[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// FleetBoardDiff is a DTO with the changes of the board: the cars added or changed, and the IDs of the cars removed
+type FleetBoardDiff struct {
+	Updated []BoardCar
+	Removed []uuid.UUID
+}
+
+// FleetBoard is a live board of the occupancy of the cars. Each time that there is an event, the board is refreshed
+// and the changes are sent to its subscribers. The events that happen while it's refreshed are merged into the next refresh.
+// A subscriber that doesn't keep up, because its buffer is full, is unsubscribed and its channel is closed,
+// so it has to subscribe again to get a new snapshot.
+type FleetBoard struct {
+	queryBus bus.Bus
+	hub      EventsHub
+	log      cqrs.Logger
+
+	bufferSize int
+
+	// st is shared by the copies of the board
+	st *fleetBoardState
+}
+
+type fleetBoardState struct {
+	mux  sync.Mutex
+	cars []BoardCar
+	subs map[uint64]chan FleetBoardDiff
+	next uint64
+}
+
+// NewFleetBoard is a constructor. The buffer size is the number of diffs that a subscriber can have pending
+func NewFleetBoard(queryBus bus.Bus, hub EventsHub, log cqrs.Logger, bufferSize int) FleetBoard {
+	return FleetBoard{
+		queryBus:   queryBus,
+		hub:        hub,
+		log:        log,
+		bufferSize: bufferSize,
+		st:         &fleetBoardState{subs: make(map[uint64]chan FleetBoardDiff)},
+	}
+}
+
+// Subscribe returns the current cars of the board, the channel with its changes from now on, and the function
+// to unsubscribe. The channel is closed once it's unsubscribed
+func (b FleetBoard) Subscribe() ([]BoardCar, <-chan FleetBoardDiff, func()) {
+	b.st.mux.Lock()
+	defer b.st.mux.Unlock()
+
+	id := b.st.next
+	b.st.next++
+	ch := make(chan FleetBoardDiff, b.bufferSize)
+	b.st.subs[id] = ch
+
+	return append([]BoardCar{}, b.st.cars...), ch, func() {
+		b.st.mux.Lock()
+		defer b.st.mux.Unlock()
+		b.unsubscribe(id)
+	}
+}
+
+// Run refreshes the board with each event until the context is cancelled
+func (b FleetBoard) Run(ctx context.Context) {
+	evs, unsubscribe := b.hub.Subscribe(fleetBoardEventsSize)
+	defer func() { unsubscribe() }()
+
+	b.refresh(ctx)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case _, ok := <-evs:
+			if !ok { // the board didn't keep up with the events, so it's subscribed again
+				evs, unsubscribe = b.hub.Subscribe(fleetBoardEventsSize)
+			}
+			drain(evs)
+			b.refresh(ctx)
+		}
+	}
+}
+
+// drain discards the pending events, they are all covered by the next refresh
+func drain(ch <-chan events.Event) {
+	for {
+		select {
+		case _, ok := <-ch:
+			if !ok {
+				return
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (b FleetBoard) refresh(ctx context.Context) {
+	rs, err := b.queryBus.Dispatch(ctx, FleetStatusQuery{})
+	if err != nil {
+		b.log.Printf("fleet board: %s\n", err.Error())
+		return
+	}
+	cars := boardCars(rs.(FleetStatusResponse).Cars)
+
+	b.st.mux.Lock()
+	defer b.st.mux.Unlock()
+
+	diff := boardDiff(b.st.cars, cars)
+	b.st.cars = cars
+	if len(diff.Updated) == 0 && len(diff.Removed) == 0 {
+		return
+	}
+	for id, ch := range b.st.subs {
+		select {
+		case ch <- diff:
+		default:
+			b.unsubscribe(id)
+		}
+	}
+}
+
+// unsubscribe must be called with the lock held
+func (b FleetBoard) unsubscribe(id uint64) {
+	if ch, ok := b.st.subs[id]; ok {
+		close(ch)
+		delete(b.st.subs, id)
+	}
+}
+
+func boardCars(cars []domain.Car) []BoardCar {
+	bcs := make([]BoardCar, 0, len(cars))
+	for _, car := range cars {
+		bc := BoardCar{
+			ID:           car.ID(),
+			Site:         car.Site(),
+			Capacity:     car.Capacity(),
+			Availability: car.Availability(),
+			Status:       car.Status(),
+			Groups:       make([]BoardGroup, 0, len(car.Journeys())),
+		}
+		for _, g := range car.Journeys() {
+			bc.Groups = append(bc.Groups, BoardGroup{ID: g.ID(), People: g.People()})
+		}
+		sort.Slice(bc.Groups, func(i, j int) bool { return bc.Groups[i].ID.String() < bc.Groups[j].ID.String() })
+		bcs = append(bcs, bc)
+	}
+	return bcs
+}
+
+// boardDiff returns the changes from the cars to the new ones. The updated cars keep the order of the new ones
+func boardDiff(from, to []BoardCar) FleetBoardDiff {
+	var diff FleetBoardDiff
+	old := make(map[uuid.UUID]BoardCar, len(from))
+	for _, c := range from {
+		old[c.ID] = c
+	}
+	for _, c := range to {
+		if o, ok := old[c.ID]; !ok || !o.equal(c) {
+			diff.Updated = append(diff.Updated, c)
+		}
+		delete(old, c.ID)
+	}
+	for _, c := range from {
+		if _, ok := old[c.ID]; ok {
+			diff.Removed = append(diff.Removed, c.ID)
+		}
+	}
+	return diff
+}
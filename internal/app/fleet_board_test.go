@@ -0,0 +1,116 @@
+package app_test
+
+import (
+	"context"
+	"io"
+	"log"
+	"sync"
+	"testing"
+	"time"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/domain"
+	"theskyinflames/car-sharing/internal/fixtures"
+	"theskyinflames/car-sharing/internal/helpers"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/theskyinflames/cqrs-eda/pkg/bus"
+	"github.com/theskyinflames/cqrs-eda/pkg/cqrs"
+	cqrshelpers "github.com/theskyinflames/cqrs-eda/pkg/helpers"
+)
+
+func TestFleetBoard(t *testing.T) {
+	var (
+		g      = fixtures.Group{People: helpers.IntPtr(2)}.Build()
+		car1   = fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity4)}.Build()
+		car2   = fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity6)}.Build()
+		car1On = fixtures.Car{ID: helpers.UUIDPtr(car1.ID()), Capacity: helpers.CarCapacityPtr(domain.CarCapacity4), Journeys: domain.Journeys{g.ID(): g}}.Build()
+		ev     = domain.NewCarCreatedEvent(car1)
+
+		mux  sync.Mutex
+		cars = []domain.Car{car1, car2}
+		qh   = &QueryHandlerMock{
+			HandleFunc: func(_ context.Context, _ cqrs.Query) (cqrs.QueryResult, error) {
+				mux.Lock()
+				defer mux.Unlock()
+				return app.FleetStatusResponse{Cars: cars}, nil
+			},
+		}
+		setCars = func(cs ...domain.Car) {
+			mux.Lock()
+			defer mux.Unlock()
+			cars = cs
+		}
+	)
+	queryBus := bus.New()
+	queryBus.Register(app.FleetStatusName, cqrshelpers.BusQhHandler(qh))
+	hub := app.NewEventsHub()
+	board := app.NewFleetBoard(queryBus, hub, log.New(io.Discard, "", 0), 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go board.Run(ctx)
+	require.Eventually(t, func() bool {
+		snapshot, _, unsubscribe := board.Subscribe()
+		unsubscribe()
+		return len(snapshot) == 2
+	}, time.Second, time.Millisecond)
+
+	snapshot, diffs, unsubscribe := board.Subscribe()
+	defer unsubscribe()
+	require.Equal(t, []app.BoardCar{
+		{ID: car1.ID(), Site: domain.DefaultSite, Capacity: domain.CarCapacity4, Availability: 4, Status: domain.CarAvailable, Groups: []app.BoardGroup{}},
+		{ID: car2.ID(), Site: domain.DefaultSite, Capacity: domain.CarCapacity6, Availability: 6, Status: domain.CarAvailable, Groups: []app.BoardGroup{}},
+	}, snapshot)
+
+	t.Run(`Given a subscriber, when a group gets on a car, then the car is sent as updated`, func(t *testing.T) {
+		setCars(car1On, car2)
+		hub.Handler()(ev)
+		require.Equal(t, app.FleetBoardDiff{Updated: []app.BoardCar{
+			{ID: car1.ID(), Site: domain.DefaultSite, Capacity: domain.CarCapacity4, Availability: 2, Status: domain.CarAvailable, Groups: []app.BoardGroup{{ID: g.ID(), People: 2}}},
+		}}, receiveDiff(t, diffs))
+	})
+
+	t.Run(`Given a subscriber, when a car is removed, then its id is sent as removed`, func(t *testing.T) {
+		setCars(car1On)
+		hub.Handler()(ev)
+		require.Equal(t, app.FleetBoardDiff{Removed: []uuid.UUID{car2.ID()}}, receiveDiff(t, diffs))
+	})
+
+	t.Run(`Given a subscriber, when there is an event without changes, then nothing is sent`, func(t *testing.T) {
+		hub.Handler()(ev)
+		setCars(car1)
+		hub.Handler()(ev)
+		require.Equal(t, app.FleetBoardDiff{Updated: []app.BoardCar{
+			{ID: car1.ID(), Site: domain.DefaultSite, Capacity: domain.CarCapacity4, Availability: 4, Status: domain.CarAvailable, Groups: []app.BoardGroup{}},
+		}}, receiveDiff(t, diffs))
+	})
+
+	t.Run(`Given a subscriber that doesn't keep up, when its buffer is full, then it's unsubscribed`, func(t *testing.T) {
+		_, slow, unsubscribeSlow := board.Subscribe()
+		defer unsubscribeSlow()
+
+		setCars(car1On)
+		hub.Handler()(ev)
+		receiveDiff(t, diffs)
+		setCars(car1)
+		hub.Handler()(ev)
+		receiveDiff(t, diffs)
+
+		require.Len(t, slow, 1)
+		<-slow
+		_, ok := <-slow
+		require.False(t, ok)
+	})
+}
+
+func receiveDiff(t *testing.T, diffs <-chan app.FleetBoardDiff) app.FleetBoardDiff {
+	select {
+	case diff := <-diffs:
+		return diff
+	case <-time.After(time.Second):
+		t.Fatal("expected a diff")
+		return app.FleetBoardDiff{}
+	}
+}
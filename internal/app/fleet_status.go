@@ -0,0 +1,56 @@
+package app
+
+import (
+	"context"
+
+	"theskyinflames/car-sharing/internal/domain"
+
+	"github.com/theskyinflames/cqrs-eda/pkg/cqrs"
+)
+
+// FleetStatusResponse is a DTO
+type FleetStatusResponse struct {
+	Cars          []domain.Car
+	WaitingGroups []domain.Group
+}
+
+// FleetStatusQuery is a query
+type FleetStatusQuery struct{}
+
+// FleetStatusName is self-described
+var FleetStatusName = "fleet.status"
+
+// Name implements Query interface
+func (q FleetStatusQuery) Name() string {
+	return FleetStatusName
+}
+
+// FleetStatus is a query handler. It returns the cars, with their groups on journey, and the waiting groups
+type FleetStatus struct {
+	gr  GroupsRepository
+	evr CarsRepository
+}
+
+// NewFleetStatus is a constructor
+func NewFleetStatus(gr GroupsRepository, evr CarsRepository) FleetStatus {
+	return FleetStatus{gr: gr, evr: evr}
+}
+
+// Handle implements the QueryHandler interface
+func (qh FleetStatus) Handle(ctx context.Context, query cqrs.Query) (cqrs.QueryResult, error) {
+	if _, ok := query.(FleetStatusQuery); !ok {
+		return nil, NewInvalidQueryError(FleetStatusName, query.Name())
+	}
+
+	cars, err := qh.evr.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	wg, err := qh.gr.FindGroupsWithoutCar(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return FleetStatusResponse{Cars: cars, WaitingGroups: wg}, nil
+}
@@ -0,0 +1,97 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/domain"
+	"theskyinflames/car-sharing/internal/fixtures"
+
+	"github.com/stretchr/testify/require"
+	"github.com/theskyinflames/cqrs-eda/pkg/cqrs"
+)
+
+func TestFleetStatus(t *testing.T) {
+	var (
+		randomErr = errors.New("")
+		cars      = []domain.Car{fixtures.Car{}.Build(), fixtures.Car{}.Build()}
+		wg        = []domain.Group{fixtures.Group{}.Build()}
+	)
+	testCases := []struct {
+		name            string
+		q               cqrs.Query
+		gr              *GroupsRepositoryMock
+		evr             *CarsRepositoryMock
+		expectedRs      app.FleetStatusResponse
+		expectedErrFunc func(*testing.T, error)
+	}{
+		{
+			name: `Given an invalid query, when it's called, then an error is returned`,
+			q:    newInvalidQuery(),
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorAs(t, err, &app.InvalidQueryError{})
+			},
+		},
+		{
+			name: `Given a cars repository that returns an error on FindAll method,
+				when it's called, then an error is returned`,
+			q: app.FleetStatusQuery{},
+			evr: &CarsRepositoryMock{
+				FindAllFunc: func(_ context.Context) ([]domain.Car, error) {
+					return nil, randomErr
+				},
+			},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, randomErr)
+			},
+		},
+		{
+			name: `Given a groups repository that returns an error on FindGroupsWithoutCar method,
+				when it's called, then an error is returned`,
+			q: app.FleetStatusQuery{},
+			evr: &CarsRepositoryMock{
+				FindAllFunc: func(_ context.Context) ([]domain.Car, error) {
+					return cars, nil
+				},
+			},
+			gr: &GroupsRepositoryMock{
+				FindGroupsWithoutCarFunc: func(_ context.Context) ([]domain.Group, error) {
+					return nil, randomErr
+				},
+			},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, randomErr)
+			},
+		},
+		{
+			name: `Given a fleet with waiting groups, when it's called, then the cars and the waiting groups are returned`,
+			q:    app.FleetStatusQuery{},
+			evr: &CarsRepositoryMock{
+				FindAllFunc: func(_ context.Context) ([]domain.Car, error) {
+					return cars, nil
+				},
+			},
+			gr: &GroupsRepositoryMock{
+				FindGroupsWithoutCarFunc: func(_ context.Context) ([]domain.Group, error) {
+					return wg, nil
+				},
+			},
+			expectedRs: app.FleetStatusResponse{Cars: cars, WaitingGroups: wg},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			qh := app.NewFleetStatus(tc.gr, tc.evr)
+			rs, err := qh.Handle(context.Background(), tc.q)
+			require.Equal(t, tc.expectedErrFunc == nil, err == nil)
+			if err != nil {
+				tc.expectedErrFunc(t, err)
+				return
+			}
+			require.Equal(t, tc.expectedRs, rs)
+		})
+	}
+}
@@ -0,0 +1,52 @@
+package app
+
+import (
+	"theskyinflames/car-sharing/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/theskyinflames/cqrs-eda/pkg/events"
+)
+
+// Group statuses, in the order a group goes through them. A waiting group can be cancelled or expire instead
+const (
+	GroupWaiting    = "waiting"
+	GroupOnJourney  = "on_journey"
+	GroupDroppedOff = "dropped_off"
+	GroupCancelled  = "cancelled"
+	GroupExpired    = "expired"
+)
+
+// GroupStatus is a DTO. The car is only set while the group is on journey
+type GroupStatus struct {
+	Status string
+	CarID  uuid.UUID
+	Seats  domain.CarCapacity
+}
+
+// NewGroupStatus returns the status of a located group
+func NewGroupStatus(rs LocateResponse) GroupStatus {
+	if !rs.IsInJourney {
+		return GroupStatus{Status: GroupWaiting}
+	}
+	return GroupStatus{Status: GroupOnJourney, CarID: rs.Car.ID(), Seats: rs.Car.Capacity()}
+}
+
+// GroupStatusFromEvent returns the status that the group gets with the event.
+// It returns FALSE if the event doesn't change the status of the group
+func GroupStatusFromEvent(groupID uuid.UUID, ev events.Event) (GroupStatus, bool) {
+	if ev.AggregateID() != groupID {
+		return GroupStatus{}, false
+	}
+	switch e := ev.(type) {
+	case domain.GroupSetOnJourneyEvent:
+		return GroupStatus{Status: GroupOnJourney, CarID: e.CarID(), Seats: e.Seats()}, true
+	case domain.GroupDroppedOffEvent:
+		return GroupStatus{Status: GroupDroppedOff}, true
+	case domain.GroupCancelledEvent:
+		return GroupStatus{Status: GroupCancelled}, true
+	case domain.GroupExpiredEvent:
+		return GroupStatus{Status: GroupExpired}, true
+	default:
+		return GroupStatus{}, false
+	}
+}
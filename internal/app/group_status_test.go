@@ -0,0 +1,81 @@
+package app_test
+
+import (
+	"testing"
+	"time"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/domain"
+	"theskyinflames/car-sharing/internal/fixtures"
+	"theskyinflames/car-sharing/internal/helpers"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/theskyinflames/cqrs-eda/pkg/events"
+)
+
+func TestGroupStatusFromEvent(t *testing.T) {
+	var (
+		car = fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity5)}.Build()
+		g   = fixtures.Group{}.Build()
+	)
+	g.GetOn(&car)
+	onJourney := domain.NewGroupSetOnJourneyEvent(g)
+	g.DropOff()
+	droppedOff := domain.NewGroupDroppedOff(g)
+
+	testCases := []struct {
+		name           string
+		groupID        uuid.UUID
+		ev             events.Event
+		expectedStatus app.GroupStatus
+		expectedOk     bool
+	}{
+		{
+			name:    `Given an event of another group, when it's mapped, then it doesn't change the status`,
+			groupID: uuid.New(),
+			ev:      onJourney,
+		},
+		{
+			name:    `Given an event that is not of a group, when it's mapped, then it doesn't change the status`,
+			groupID: car.ID(),
+			ev:      domain.NewCarCreatedEvent(car),
+		},
+		{
+			name:           `Given a group set on journey event, when it's mapped, then the group is on journey with the car`,
+			groupID:        g.ID(),
+			ev:             onJourney,
+			expectedStatus: app.GroupStatus{Status: app.GroupOnJourney, CarID: car.ID(), Seats: domain.CarCapacity5},
+			expectedOk:     true,
+		},
+		{
+			name:           `Given a group dropped off event, when it's mapped, then the group is dropped off`,
+			groupID:        g.ID(),
+			ev:             droppedOff,
+			expectedStatus: app.GroupStatus{Status: app.GroupDroppedOff},
+			expectedOk:     true,
+		},
+		{
+			name:           `Given a group cancelled event, when it's mapped, then the group is cancelled`,
+			groupID:        g.ID(),
+			ev:             domain.NewGroupCancelledEvent(g, domain.CancelReasonGaveUp, time.Now()),
+			expectedStatus: app.GroupStatus{Status: app.GroupCancelled},
+			expectedOk:     true,
+		},
+		{
+			name:           `Given a group expired event, when it's mapped, then the group is expired`,
+			groupID:        g.ID(),
+			ev:             domain.NewGroupExpiredEvent(g, time.Now()),
+			expectedStatus: app.GroupStatus{Status: app.GroupExpired},
+			expectedOk:     true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			status, ok := app.GroupStatusFromEvent(tc.groupID, tc.ev)
+			require.Equal(t, tc.expectedOk, ok)
+			require.Equal(t, tc.expectedStatus, status)
+		})
+	}
+}
@@ -10,10 +10,12 @@ import (
 	"github.com/theskyinflames/cqrs-eda/pkg/events"
 )
 
-// Car is a DTO
+// Car is a DTO. The site is where the car is based, by default the domain.DefaultSite
 type Car struct {
-	ID    uuid.UUID
-	Seats domain.CarCapacity
+	ID       uuid.UUID
+	Seats    domain.CarCapacity
+	Site     domain.Site
+	Features domain.Features
 }
 
 // InitializeFleetCmd is a Command
@@ -33,11 +35,13 @@ func (cmd InitializeFleetCmd) Name() string {
 type InitializeFleet struct {
 	gr  GroupsRepository
 	evr CarsRepository
+
+	fleetOpts []domain.FleetOption
 }
 
-// NewInitializeFleet is constructor
-func NewInitializeFleet(gr GroupsRepository, evr CarsRepository) InitializeFleet {
-	return InitializeFleet{gr: gr, evr: evr}
+// NewInitializeFleet is constructor. The fleet options set the seats that a car can have
+func NewInitializeFleet(gr GroupsRepository, evr CarsRepository, fleetOpts ...domain.FleetOption) InitializeFleet {
+	return InitializeFleet{gr: gr, evr: evr, fleetOpts: fleetOpts}
 }
 
 // Handle implements the CommandHandler constructor
@@ -47,14 +51,21 @@ func (ch InitializeFleet) Handle(ctx context.Context, cmd cqrs.Command) ([]event
 		return nil, NewInvalidCommandError(InitializeFleetName, cmd.Name())
 	}
 
+	limits := capacityLimits(ch.fleetOpts)
 	var cars []domain.Car
 	for _, car := range co.Cars {
-		seats, err := domain.ParseCarCapacityFromInt(int(car.Seats))
+		seats, err := limits.ParseCarCapacity(car.Seats.Int())
 		if err != nil {
 			return nil, err
 		}
-		cars = append(cars, domain.NewCar(car.ID, seats))
+		cars = append(cars, domain.NewCar(car.ID, seats, domain.WithSite(car.Site), domain.WithFeatures(car.Features...)))
+	}
+
+	var evs []events.Event
+	for i := range cars {
+		evs = append(evs, cars[i].Events()...)
 	}
+
 	if err := ch.gr.RemoveAll(ctx); err != nil {
 		return nil, err
 	}
@@ -65,9 +76,5 @@ func (ch InitializeFleet) Handle(ctx context.Context, cmd cqrs.Command) ([]event
 		return nil, err
 	}
 
-	var evs []events.Event
-	for _, car := range cars {
-		evs = append(evs, car.Events()...)
-	}
 	return evs, nil
 }
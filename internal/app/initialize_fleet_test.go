@@ -82,6 +82,17 @@ func TestInitializeFleet(t *testing.T) {
 				require.ErrorIs(t, err, randomErr)
 			},
 		},
+		{
+			name: `Given a car with more seats than the allowed ones,
+				when it's called,
+				then an error is returned`,
+			cmd: app.InitializeFleetCmd{
+				Cars: []app.Car{{ID: uuid.New(), Seats: 9}},
+			},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, domain.ErrCapacityNotSupported)
+			},
+		},
 		{
 			name: `Given a list of Evs to be added,
 				when it's called,
@@ -113,4 +124,19 @@ func TestInitializeFleet(t *testing.T) {
 		cars := tc.cmd.(app.InitializeFleetCmd).Cars
 		require.Equal(t, len(tc.evr.AddAllCalls()[0].Cars), len(cars))
 	}
+
+	t.Run(`Given capacity limits up to 15 seats,
+		when minibuses are added, then no error is returned`, func(t *testing.T) {
+		var (
+			gr  = &GroupsRepositoryMock{}
+			evr = &CarsRepositoryMock{}
+			ch  = app.NewInitializeFleet(gr, evr, domain.WithCapacityLimits(domain.CapacityLimits{MinSeats: 4, MaxSeats: 15}))
+		)
+		_, err := ch.Handle(context.Background(), app.InitializeFleetCmd{
+			Cars: []app.Car{{ID: uuid.New(), Seats: 9}, {ID: uuid.New(), Seats: 15}},
+		})
+		require.NoError(t, err)
+		require.Len(t, evr.AddAllCalls()[0].Cars, 2)
+		require.Equal(t, domain.CarCapacity(15), evr.AddAllCalls()[0].Cars[1].Capacity())
+	})
 }
@@ -2,6 +2,7 @@ package app
 
 import (
 	"context"
+	"errors"
 
 	"theskyinflames/car-sharing/internal/domain"
 
@@ -10,12 +11,21 @@ import (
 	"github.com/theskyinflames/cqrs-eda/pkg/events"
 )
 
-// JourneyCmd is a command
+// JourneyCmd is a command. The site is the origin site of the group, by default the domain.DefaultSite.
+// The destination, the members roster, the requirements and the pickup location are optional
 type JourneyCmd struct {
-	ID     uuid.UUID
-	People int
+	ID           uuid.UUID
+	People       int
+	Site         domain.Site
+	Destination  domain.Site
+	Members      []string
+	Requirements domain.Features
+	Pickup       *domain.Location
 }
 
+// ErrGroupIDTaken is self-described
+var ErrGroupIDTaken = errors.New("the id of the group belongs to a reservation already")
+
 // JourneyName is self-described
 var JourneyName = "journey"
 
@@ -24,15 +34,24 @@ func (cmd JourneyCmd) Name() string {
 	return JourneyName
 }
 
-// Journey is a command handler
+// Journey is a command handler. A group that doesn't fit in any car of its site that meets its requirements is rejected,
+// as well as a group whose ID belongs to a reservation, because the group of the reservation couldn't be added once it starts
 type Journey struct {
 	gr  GroupsRepository
 	evr CarsRepository
+	rr  ReservationsRepository
+
+	fleetOpts []domain.FleetOption
 }
 
 // NewJourney is a constructor
-func NewJourney(gr GroupsRepository, evr CarsRepository) Journey {
-	return Journey{gr: gr, evr: evr}
+func NewJourney(
+	gr GroupsRepository,
+	evr CarsRepository,
+	rr ReservationsRepository,
+	fleetOpts ...domain.FleetOption,
+) Journey {
+	return Journey{gr: gr, evr: evr, rr: rr, fleetOpts: fleetOpts}
 }
 
 // Handle implements CommandHandler interface
@@ -47,12 +66,24 @@ func (ch Journey) Handle(ctx context.Context, cmd cqrs.Command) ([]events.Event,
 		return nil, err
 	}
 
-	g, err := domain.NewGroup(co.ID, co.People)
+	groupOpts := []domain.GroupOption{
+		domain.WithOrigin(co.Site),
+		domain.WithDestination(co.Destination),
+		domain.WithMembers(co.Members...),
+		domain.WithRequirements(co.Requirements...),
+	}
+	if co.Pickup != nil {
+		pickup, err := domain.NewLocation(co.Pickup.Lat, co.Pickup.Lng)
+		if err != nil {
+			return nil, err
+		}
+		groupOpts = append(groupOpts, domain.WithPickup(pickup))
+	}
+	g, err := domain.NewGroup(co.ID, co.People, groupOpts...)
 	if err != nil {
 		return nil, err
 	}
-
-	if err := ch.gr.Add(ctx, g); err != nil {
+	if err := capacityLimits(ch.fleetOpts).CheckGroupSize(g.People()); err != nil {
 		return nil, err
 	}
 
@@ -61,13 +92,31 @@ func (ch Journey) Handle(ctx context.Context, cmd cqrs.Command) ([]events.Event,
 		return nil, err
 	}
 
-	fleet := domain.NewFleet(evs, wg)
+	fleetOpts, err := withReservations(ctx, ch.rr, ch.fleetOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	fleet := domain.NewFleet(evs, wg, fleetOpts...)
+	if fleet.HasReservation(g.ID()) {
+		return nil, ErrGroupIDTaken
+	}
+	if !fleet.CanServe(g) { // otherwise, it would wait forever
+		return nil, domain.ErrGroupNotServable
+	}
+
+	if err := ch.gr.Add(ctx, g); err != nil {
+		return nil, err
+	}
+
 	g, ev := fleet.Journey(g) // try to get the group on a ev
 
 	if !g.IsOnJourney() { // if the g is not in journey, there is not ev to be updated. Otherwise, its list of groups is updated
 		return nil, nil
 	}
 
+	groupEvs := g.Events()
+
 	if err := ch.gr.Update(ctx, g); err != nil {
 		return nil, err
 	}
@@ -76,5 +125,11 @@ func (ch Journey) Handle(ctx context.Context, cmd cqrs.Command) ([]events.Event,
 		return nil, err
 	}
 
-	return g.Events(), nil
+	for _, og := range fleet.OvertakenGroups() {
+		if err := ch.gr.Update(ctx, og); err != nil {
+			return nil, err
+		}
+	}
+
+	return groupEvs, nil
 }
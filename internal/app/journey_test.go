@@ -52,6 +52,105 @@ func TestJourney(t *testing.T) {
 				require.ErrorIs(t, err, randomErr)
 			},
 		},
+		{
+			name: `Given a group bigger than the largest car allowed, when it's called, then an error is returned`,
+			cmd: app.JourneyCmd{
+				ID:     jID1,
+				People: 7,
+			},
+			gr: &GroupsRepositoryMock{},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, domain.ErrGroupTooBig)
+			},
+		},
+		{
+			name: `Given a fleet of four and five seats cars, when a group of 6 people asks for a journey,
+				then it's rejected because it doesn't fit in any car`,
+			cmd: app.JourneyCmd{
+				ID:     jID1,
+				People: 6,
+			},
+			gr: &GroupsRepositoryMock{},
+			cr: &CarsRepositoryMock{
+				FindAllFunc: func(_ context.Context) ([]domain.Car, error) {
+					return []domain.Car{
+						fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity4)}.Build(),
+						fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity5)}.Build(),
+					}, nil
+				},
+			},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, domain.ErrGroupNotServable)
+			},
+		},
+		{
+			name: `Given a six seats car that is retiring, when a group of 6 people asks for a journey,
+				then it's rejected because it doesn't fit in any car`,
+			cmd: app.JourneyCmd{
+				ID:     jID1,
+				People: 6,
+			},
+			gr: &GroupsRepositoryMock{},
+			cr: &CarsRepositoryMock{
+				FindAllFunc: func(_ context.Context) ([]domain.Car, error) {
+					return []domain.Car{
+						fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity4)}.Build(),
+						fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity6), Retiring: true}.Build(),
+					}, nil
+				},
+			},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, domain.ErrGroupNotServable)
+			},
+		},
+		{
+			name: `Given a fleet without cars in the origin site of the group, when it asks for a journey,
+				then it's rejected because it doesn't fit in any car of its site`,
+			cmd: app.JourneyCmd{
+				ID:     jID1,
+				People: 2,
+				Site:   "factory",
+			},
+			gr: &GroupsRepositoryMock{},
+			cr: &CarsRepositoryMock{
+				FindAllFunc: func(_ context.Context) ([]domain.Car, error) {
+					return []domain.Car{fixtures.Car{Site: "warehouse"}.Build()}, nil
+				},
+			},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, domain.ErrGroupNotServable)
+			},
+		},
+		{
+			name: `Given a fleet without cars that meet the requirements of the group, when it asks for a journey,
+				then it's rejected because it doesn't fit in any car that meets them`,
+			cmd: app.JourneyCmd{
+				ID:           jID1,
+				People:       2,
+				Requirements: domain.NewFeatures(domain.FeatureWheelchair),
+			},
+			gr: &GroupsRepositoryMock{},
+			cr: &CarsRepositoryMock{
+				FindAllFunc: func(_ context.Context) ([]domain.Car, error) {
+					return []domain.Car{fixtures.Car{Features: domain.NewFeatures(domain.FeatureLuggage)}.Build()}, nil
+				},
+			},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, domain.ErrGroupNotServable)
+			},
+		},
+		{
+			name: `Given an empty fleet, when a group asks for a journey, then it's rejected`,
+			cmd: app.JourneyCmd{
+				ID:     jID1,
+				People: 1,
+			},
+			gr: &GroupsRepositoryMock{},
+			cr: &CarsRepositoryMock{},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, domain.ErrGroupNotServable)
+			},
+		},
 		{
 			name: `Given an gr repository that returns an error on Add method, when it's called, then an error is returned`,
 			cmd: app.JourneyCmd{
@@ -63,6 +162,11 @@ func TestJourney(t *testing.T) {
 					return randomErr
 				},
 			},
+			cr: &CarsRepositoryMock{
+				FindAllFunc: func(_ context.Context) ([]domain.Car, error) {
+					return []domain.Car{fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity4)}.Build()}, nil
+				},
+			},
 			expectedErrFunc: func(t *testing.T, err error) {
 				require.ErrorIs(t, err, randomErr)
 			},
@@ -162,14 +266,25 @@ func TestJourney(t *testing.T) {
 				ID:     jID1,
 				People: 1,
 			},
-			gr:                &GroupsRepositoryMock{},
-			cr:                &CarsRepositoryMock{},
+			gr: &GroupsRepositoryMock{},
+			cr: &CarsRepositoryMock{
+				FindAllFunc: func(_ context.Context) ([]domain.Car, error) {
+					return []domain.Car{
+						fixtures.Car{
+							Capacity: helpers.CarCapacityPtr(domain.CarCapacity4),
+							Journeys: domain.Journeys{
+								jID2: fixtures.Group{ID: helpers.UUIDPtr(jID2), People: helpers.IntPtr(4)}.Build(),
+							},
+						}.Build(),
+					}, nil
+				},
+			},
 			expectedOnJourney: false,
 		},
 	}
 
 	for _, tc := range testCases {
-		ch := app.NewJourney(tc.gr, tc.cr)
+		ch := app.NewJourney(tc.gr, tc.cr, &ReservationsRepositoryMock{})
 		_, err := ch.Handle(context.Background(), tc.cmd)
 		require.Equal(t, tc.expectedErrFunc == nil, err == nil)
 		if err != nil {
@@ -187,4 +302,42 @@ func TestJourney(t *testing.T) {
 			require.Len(t, tc.cr.UpdateCalls(), 1)
 		}
 	}
+
+	t.Run(`Given capacity limits up to 15 seats and a minibus,
+		when a group of 12 people asks for a journey, then it gets on the minibus`, func(t *testing.T) {
+		var (
+			gr = &GroupsRepositoryMock{}
+			cr = &CarsRepositoryMock{
+				FindAllFunc: func(_ context.Context) ([]domain.Car, error) {
+					return []domain.Car{fixtures.Car{Capacity: helpers.CarCapacityPtr(15)}.Build()}, nil
+				},
+			}
+			ch = app.NewJourney(gr, cr, &ReservationsRepositoryMock{}, domain.WithCapacityLimits(domain.CapacityLimits{MinSeats: 4, MaxSeats: 15}))
+		)
+		_, err := ch.Handle(context.Background(), app.JourneyCmd{ID: uuid.New(), People: 12})
+		require.NoError(t, err)
+		require.Len(t, cr.UpdateCalls(), 1)
+		require.Equal(t, 3, cr.UpdateCalls()[0].Car.Availability())
+	})
+
+	t.Run(`Given a pending reservation, when a group asks for a journey with its id, then it's rejected`, func(t *testing.T) {
+		var (
+			car = fixtures.Car{}.Build()
+			r   = fixtures.Reservation{ID: &jID1, People: helpers.IntPtr(2), CarID: helpers.UUIDPtr(car.ID())}.Build()
+			gr  = &GroupsRepositoryMock{}
+			cr  = &CarsRepositoryMock{
+				FindAllFunc: func(_ context.Context) ([]domain.Car, error) {
+					return []domain.Car{car}, nil
+				},
+			}
+			rr = &ReservationsRepositoryMock{
+				FindAllFunc: func(_ context.Context) ([]domain.Reservation, error) {
+					return []domain.Reservation{r}, nil
+				},
+			}
+		)
+		_, err := app.NewJourney(gr, cr, rr).Handle(context.Background(), app.JourneyCmd{ID: jID1, People: 2})
+		require.ErrorIs(t, err, app.ErrGroupIDTaken)
+		require.Empty(t, gr.AddCalls())
+	})
 }
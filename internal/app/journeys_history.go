@@ -0,0 +1,100 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"theskyinflames/car-sharing/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/theskyinflames/cqrs-eda/pkg/cqrs"
+	"github.com/theskyinflames/cqrs-eda/pkg/events"
+)
+
+// JourneyRecord is the history of the journey of a group. AbandonedAt is set instead of BoardedAt
+// if the group left the waiting list, cancelled or expired, without getting on a car
+type JourneyRecord struct {
+	GroupID      uuid.UUID
+	People       int
+	RequestedAt  time.Time
+	BoardedAt    time.Time
+	DroppedOffAt time.Time
+	AbandonedAt  time.Time
+}
+
+// IsAbandoned returns TRUE if the group left without getting on a car
+func (r JourneyRecord) IsAbandoned() bool {
+	return r.BoardedAt.IsZero()
+}
+
+// WaitEndedAt returns when the group got on a car, or when it left without it
+func (r JourneyRecord) WaitEndedAt() time.Time {
+	if r.IsAbandoned() {
+		return r.AbandonedAt
+	}
+	return r.BoardedAt
+}
+
+// WaitingTime is self-described. For an abandoned wait, it's the time until the group left
+func (r JourneyRecord) WaitingTime() time.Duration {
+	return r.WaitEndedAt().Sub(r.RequestedAt)
+}
+
+// RecordBoardingHandler is an events handler that records in the journeys history when a group gets on a car
+func RecordBoardingHandler(hr JourneysHistoryRepository, log cqrs.Logger) events.Handler {
+	return func(ev events.Event) {
+		e, ok := ev.(domain.GroupSetOnJourneyEvent)
+		if !ok {
+			return
+		}
+		r := JourneyRecord{
+			GroupID:     e.AggregateID(),
+			People:      e.People(),
+			RequestedAt: e.RequestedAt(),
+			BoardedAt:   e.BoardedAt(),
+		}
+		if err := hr.Save(context.Background(), r); err != nil {
+			log.Printf("journeys history, group %s: %s\n", e.AggregateID().String(), err.Error())
+		}
+	}
+}
+
+// RecordAbandonHandler is an events handler that records in the journeys history when a waiting group is cancelled
+// or expires, so the waits that end without a car are taken into account too
+func RecordAbandonHandler(hr JourneysHistoryRepository, log cqrs.Logger) events.Handler {
+	return func(ev events.Event) {
+		var r JourneyRecord
+		switch e := ev.(type) {
+		case domain.GroupCancelledEvent:
+			r = JourneyRecord{GroupID: e.AggregateID(), People: e.People(), RequestedAt: e.RequestedAt(), AbandonedAt: e.CancelledAt()}
+		case domain.GroupExpiredEvent:
+			r = JourneyRecord{GroupID: e.AggregateID(), People: e.People(), RequestedAt: e.RequestedAt(), AbandonedAt: e.ExpiredAt()}
+		default:
+			return
+		}
+		if err := hr.Save(context.Background(), r); err != nil {
+			log.Printf("journeys history, group %s: %s\n", r.GroupID.String(), err.Error())
+		}
+	}
+}
+
+// RecordDropOffHandler is an events handler that records in the journeys history when a group is dropped off.
+// The groups that are dropped off before getting on a car have no journey, so they are not recorded
+func RecordDropOffHandler(hr JourneysHistoryRepository, log cqrs.Logger) events.Handler {
+	return func(ev events.Event) {
+		e, ok := ev.(domain.GroupDroppedOffEvent)
+		if !ok || e.BoardedAt().IsZero() {
+			return
+		}
+		ctx := context.Background()
+		r, err := hr.FindByGroupID(ctx, e.AggregateID())
+		if err != nil {
+			log.Printf("journeys history, group %s: %s\n", e.AggregateID().String(), err.Error())
+			return
+		}
+		r.DroppedOffAt = e.DroppedOffAt()
+		if err := hr.Save(ctx, r); err != nil {
+			log.Printf("journeys history, group %s: %s\n", e.AggregateID().String(), err.Error())
+		}
+	}
+}
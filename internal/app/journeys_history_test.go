@@ -0,0 +1,77 @@
+package app_test
+
+import (
+	"context"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/domain"
+	"theskyinflames/car-sharing/internal/fixtures"
+	"theskyinflames/car-sharing/internal/infra/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJourneysHistoryHandlers(t *testing.T) {
+	var (
+		logger      = log.New(io.Discard, "", 0)
+		requestedAt = time.Now().Add(-time.Minute)
+		people      = 4
+		car         = fixtures.Car{}.Build()
+	)
+
+	t.Run(`Given a group that gets on a car and then is dropped off,
+		when the events are handled, then its journey is recorded`, func(t *testing.T) {
+		hr := repository.NewJourneysHistoryRepository()
+		g := fixtures.Group{People: &people, RequestedAt: &requestedAt}.Build()
+
+		g.GetOn(&car)
+		app.RecordBoardingHandler(hr, logger)(domain.NewGroupSetOnJourneyEvent(g))
+		r, err := hr.FindByGroupID(context.Background(), g.ID())
+		require.NoError(t, err)
+		require.Equal(t, people, r.People)
+		require.Equal(t, g.WaitingTime(), r.WaitingTime())
+		require.True(t, r.DroppedOffAt.IsZero())
+
+		g.DropOff()
+		app.RecordDropOffHandler(hr, logger)(domain.NewGroupDroppedOff(g))
+		r, err = hr.FindByGroupID(context.Background(), g.ID())
+		require.NoError(t, err)
+		require.Equal(t, g.DroppedOffAt(), r.DroppedOffAt)
+	})
+
+	t.Run(`Given waiting groups that are cancelled or expire, when the events are handled,
+		then their waits are recorded as abandoned`, func(t *testing.T) {
+		hr := repository.NewJourneysHistoryRepository()
+		var (
+			cancelled = fixtures.Group{People: &people, RequestedAt: &requestedAt}.Build()
+			expired   = fixtures.Group{People: &people, RequestedAt: &requestedAt}.Build()
+			leftAt    = time.Now()
+		)
+
+		app.RecordAbandonHandler(hr, logger)(domain.NewGroupCancelledEvent(cancelled, domain.CancelReasonPlansChanged, leftAt))
+		app.RecordAbandonHandler(hr, logger)(domain.NewGroupExpiredEvent(expired, leftAt))
+		for _, id := range []uuid.UUID{cancelled.ID(), expired.ID()} {
+			r, err := hr.FindByGroupID(context.Background(), id)
+			require.NoError(t, err)
+			require.True(t, r.IsAbandoned())
+			require.Equal(t, people, r.People)
+			require.Equal(t, leftAt.Sub(requestedAt), r.WaitingTime())
+		}
+	})
+
+	t.Run(`Given a group that is dropped off before getting on a car,
+		when the event is handled, then nothing is recorded`, func(t *testing.T) {
+		hr := &JourneysHistoryRepositoryMock{}
+		g := fixtures.Group{People: &people, RequestedAt: &requestedAt}.Build()
+
+		g.DropOff()
+		app.RecordDropOffHandler(hr, logger)(domain.NewGroupDroppedOff(g))
+		require.Empty(t, hr.FindByGroupIDCalls())
+		require.Empty(t, hr.SaveCalls())
+	})
+}
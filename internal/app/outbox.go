@@ -0,0 +1,146 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/theskyinflames/cqrs-eda/pkg/bus"
+	"github.com/theskyinflames/cqrs-eda/pkg/cqrs"
+	"github.com/theskyinflames/cqrs-eda/pkg/events"
+)
+
+//go:generate moq -stub -out zmock_outbox_test.go -pkg app_test . Outbox
+
+// OutboxMessage is an event waiting in the outbox to be delivered
+type OutboxMessage struct {
+	ID    uuid.UUID
+	Event events.Event
+}
+
+// Outbox keeps the events of the commands until they are delivered to the events bus.
+// The events are added with the context of the unit of work of the command, so they are stored with its changes.
+type Outbox interface {
+	Add(ctx context.Context, evs []events.Event) error
+	// Pending returns the oldest messages not delivered yet, in the order they were added
+	Pending(ctx context.Context, limit int) ([]OutboxMessage, error)
+	MarkDelivered(ctx context.Context, id uuid.UUID) error
+}
+
+// ChOutboxMw is a command handler middleware that adds the events of each command to the outbox.
+// It has to be wrapped by the unit of work, so the events are only kept if the command changes are committed.
+func ChOutboxMw(ob Outbox) cqrs.CommandHandlerMiddleware {
+	return func(ch cqrs.CommandHandler) cqrs.CommandHandler {
+		return cqrs.CommandHandlerFunc(func(ctx context.Context, cmd cqrs.Command) ([]events.Event, error) {
+			evs, err := ch.Handle(ctx, cmd)
+			if err != nil {
+				return nil, err
+			}
+			if len(evs) == 0 {
+				return evs, nil
+			}
+			if err := ob.Add(ctx, evs); err != nil {
+				return nil, err
+			}
+			return evs, nil
+		})
+	}
+}
+
+// relayBatchSize is the number of messages read from the outbox at once
+const relayBatchSize = 100
+
+// OutboxRelay delivers the events of the outbox to the events bus, in order. An event is removed from the outbox
+// only once it has been delivered, so it can be delivered more than once if it fails in between (at-least-once).
+type OutboxRelay struct {
+	ob        Outbox
+	eventsBus bus.Bus
+	log       cqrs.Logger
+
+	// interval is the time between two deliveries, and maxBackoff the maximum time between them while they fail
+	interval   time.Duration
+	maxBackoff time.Duration
+
+	// mux keeps the deliveries one at a time, so the events are delivered in order
+	mux *sync.Mutex
+}
+
+// NewOutboxRelay is a constructor
+func NewOutboxRelay(ob Outbox, eventsBus bus.Bus, log cqrs.Logger, interval, maxBackoff time.Duration) OutboxRelay {
+	return OutboxRelay{
+		ob:         ob,
+		eventsBus:  eventsBus,
+		log:        log,
+		interval:   interval,
+		maxBackoff: maxBackoff,
+		mux:        &sync.Mutex{},
+	}
+}
+
+// Deliver delivers all the pending events. It stops at the first one that can't be delivered
+func (r OutboxRelay) Deliver(ctx context.Context) error {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+
+	for {
+		msgs, err := r.ob.Pending(ctx, relayBatchSize)
+		if err != nil {
+			return err
+		}
+		if len(msgs) == 0 {
+			return nil
+		}
+		for _, m := range msgs {
+			// an event without handlers has nobody to be delivered to
+			if _, err := r.eventsBus.Dispatch(ctx, m.Event); err != nil && !errors.Is(err, bus.ErrNotDispatchable) {
+				return fmt.Errorf("outbox message %s, event %s: %w", m.ID, m.Event.Name(), err)
+			}
+			if err := r.ob.MarkDelivered(ctx, m.ID); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// Run delivers the pending events periodically, until the context is cancelled.
+// While the deliveries fail, the time between them is doubled up to the maximum backoff
+func (r OutboxRelay) Run(ctx context.Context) {
+	wait := r.interval
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(wait):
+		}
+
+		if err := r.Deliver(ctx); err != nil {
+			r.log.Printf("outbox relay: %s\n", err.Error())
+			wait *= 2
+			if wait > r.maxBackoff {
+				wait = r.maxBackoff
+			}
+			continue
+		}
+		wait = r.interval
+	}
+}
+
+// ChMw is a command handler middleware that delivers the pending events once the command has finished,
+// so they don't wait for the next periodic delivery. It has to wrap the unit of work.
+// If the delivery fails, the events stay in the outbox and they are retried by Run
+func (r OutboxRelay) ChMw() cqrs.CommandHandlerMiddleware {
+	return func(ch cqrs.CommandHandler) cqrs.CommandHandler {
+		return cqrs.CommandHandlerFunc(func(ctx context.Context, cmd cqrs.Command) ([]events.Event, error) {
+			evs, err := ch.Handle(ctx, cmd)
+			if err == nil && len(evs) > 0 {
+				if err := r.Deliver(ctx); err != nil {
+					r.log.Printf("outbox relay: %s\n", err.Error())
+				}
+			}
+			return evs, err
+		})
+	}
+}
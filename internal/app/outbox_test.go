@@ -0,0 +1,240 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/domain"
+	"theskyinflames/car-sharing/internal/fixtures"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/theskyinflames/cqrs-eda/pkg/bus"
+	"github.com/theskyinflames/cqrs-eda/pkg/cqrs"
+	"github.com/theskyinflames/cqrs-eda/pkg/events"
+)
+
+func TestChOutboxMw(t *testing.T) {
+	var (
+		randomErr = errors.New("")
+		evs       = []events.Event{domain.NewCarCreatedEvent(fixtures.Car{}.Build())}
+	)
+	testCases := []struct {
+		name          string
+		ch            *CommandHandlerMock
+		ob            *OutboxMock
+		expectedAdded int
+		expectedEvs   []events.Event
+		expectedErr   error
+	}{
+		{
+			name: `Given a command handler that fails, when a command is handled, then nothing is added to the outbox`,
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return evs, randomErr
+				},
+			},
+			ob:          &OutboxMock{},
+			expectedErr: randomErr,
+		},
+		{
+			name: `Given a command handler without events, when a command is handled, then nothing is added to the outbox`,
+			ch:   &CommandHandlerMock{},
+			ob:   &OutboxMock{},
+		},
+		{
+			name: `Given an outbox that fails, when a command with events is handled, then an error is returned`,
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return evs, nil
+				},
+			},
+			ob: &OutboxMock{
+				AddFunc: func(_ context.Context, _ []events.Event) error {
+					return randomErr
+				},
+			},
+			expectedAdded: 1,
+			expectedErr:   randomErr,
+		},
+		{
+			name: `Given a command handler with events, when a command is handled, then its events are added to the outbox`,
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return evs, nil
+				},
+			},
+			ob:            &OutboxMock{},
+			expectedAdded: 1,
+			expectedEvs:   evs,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := app.ChOutboxMw(tc.ob)(tc.ch).Handle(context.Background(), &CommandMock{})
+			require.ErrorIs(t, err, tc.expectedErr)
+			require.Equal(t, tc.expectedEvs, got)
+			require.Len(t, tc.ob.AddCalls(), tc.expectedAdded)
+			if tc.expectedAdded > 0 {
+				require.Equal(t, evs, tc.ob.AddCalls()[0].Evs)
+			}
+		})
+	}
+}
+
+// inMemoryOutbox is an outbox for the relay tests, with the pending messages in order
+type inMemoryOutbox struct {
+	msgs []app.OutboxMessage
+}
+
+func (ob *inMemoryOutbox) mock() *OutboxMock {
+	return &OutboxMock{
+		PendingFunc: func(_ context.Context, limit int) ([]app.OutboxMessage, error) {
+			if len(ob.msgs) < limit {
+				limit = len(ob.msgs)
+			}
+			return append([]app.OutboxMessage{}, ob.msgs[:limit]...), nil
+		},
+		MarkDeliveredFunc: func(_ context.Context, id uuid.UUID) error {
+			for i, m := range ob.msgs {
+				if m.ID == id {
+					ob.msgs = append(ob.msgs[:i], ob.msgs[i+1:]...)
+					return nil
+				}
+			}
+			return errors.New("not found")
+		},
+	}
+}
+
+func TestOutboxRelay(t *testing.T) {
+	var (
+		logger    = log.New(io.Discard, "", 0)
+		randomErr = errors.New("")
+		car       = fixtures.Car{}.Build()
+		g         = fixtures.Group{}.Build()
+		newMsgs   = func() []app.OutboxMessage {
+			return []app.OutboxMessage{
+				{ID: uuid.New(), Event: domain.NewCarCreatedEvent(car)},
+				{ID: uuid.New(), Event: domain.NewCarReservedEvent(car, g)},
+				{ID: uuid.New(), Event: domain.NewGroupSetOnJourneyEvent(g)},
+			}
+		}
+	)
+
+	t.Run(`Given some pending events, when they are delivered, then they are dispatched in order
+		and removed from the outbox, skipping those without handlers`, func(t *testing.T) {
+		ob := &inMemoryOutbox{msgs: newMsgs()}
+		var delivered []string
+		eventsBus := bus.New()
+		for _, name := range []string{domain.CarCreatedEventName, domain.GroupSetOnJourneyEventName} {
+			eventsBus.Register(name, func(_ context.Context, d bus.Dispatchable) (interface{}, error) {
+				delivered = append(delivered, d.Name())
+				return nil, nil
+			})
+		}
+
+		relay := app.NewOutboxRelay(ob.mock(), eventsBus, logger, time.Millisecond, time.Millisecond)
+		require.NoError(t, relay.Deliver(context.Background()))
+		require.Equal(t, []string{domain.CarCreatedEventName, domain.GroupSetOnJourneyEventName}, delivered)
+		require.Empty(t, ob.msgs)
+	})
+
+	t.Run(`Given an event that fails to be delivered, when the pending events are delivered,
+		then it and the next ones are kept in the outbox`, func(t *testing.T) {
+		msgs := newMsgs()
+		ob := &inMemoryOutbox{msgs: append([]app.OutboxMessage{}, msgs...)}
+		eventsBus := bus.New()
+		eventsBus.Register(domain.CarCreatedEventName, func(_ context.Context, _ bus.Dispatchable) (interface{}, error) {
+			return nil, nil
+		})
+		eventsBus.Register(domain.CarReservedEventName, func(_ context.Context, _ bus.Dispatchable) (interface{}, error) {
+			return nil, randomErr
+		})
+
+		relay := app.NewOutboxRelay(ob.mock(), eventsBus, logger, time.Millisecond, time.Millisecond)
+		require.ErrorIs(t, relay.Deliver(context.Background()), randomErr)
+		require.Len(t, ob.msgs, 2)
+		require.Equal(t, msgs[1].ID, ob.msgs[0].ID)
+		require.Equal(t, msgs[2].ID, ob.msgs[1].ID)
+	})
+
+	t.Run(`Given an outbox that fails, when the pending events are delivered, then an error is returned`, func(t *testing.T) {
+		ob := &OutboxMock{
+			PendingFunc: func(_ context.Context, _ int) ([]app.OutboxMessage, error) {
+				return nil, randomErr
+			},
+		}
+		relay := app.NewOutboxRelay(ob, bus.New(), logger, time.Millisecond, time.Millisecond)
+		require.ErrorIs(t, relay.Deliver(context.Background()), randomErr)
+	})
+
+	t.Run(`Given an events bus that fails once, when the relay is run, then the events are delivered when it's retried`, func(t *testing.T) {
+		ob := &inMemoryOutbox{msgs: newMsgs()[:1]}
+		var (
+			calls     int
+			delivered = make(chan struct{})
+		)
+		eventsBus := bus.New()
+		eventsBus.Register(domain.CarCreatedEventName, func(_ context.Context, _ bus.Dispatchable) (interface{}, error) {
+			calls++
+			if calls == 1 {
+				return nil, randomErr
+			}
+			close(delivered)
+			return nil, nil
+		})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		relay := app.NewOutboxRelay(ob.mock(), eventsBus, logger, time.Millisecond, 2*time.Millisecond)
+		done := make(chan struct{})
+		go func() {
+			relay.Run(ctx)
+			close(done)
+		}()
+
+		select {
+		case <-delivered:
+		case <-time.After(time.Second):
+			t.Fatal("the event has not been delivered")
+		}
+		cancel()
+		<-done
+		require.Empty(t, ob.msgs)
+	})
+
+	t.Run(`Given a command with events, when it's handled through the relay middleware, then they are delivered`, func(t *testing.T) {
+		ob := &inMemoryOutbox{}
+		obMock := ob.mock()
+		obMock.AddFunc = func(_ context.Context, evs []events.Event) error {
+			for _, e := range evs {
+				ob.msgs = append(ob.msgs, app.OutboxMessage{ID: uuid.New(), Event: e})
+			}
+			return nil
+		}
+		var delivered int
+		eventsBus := bus.New()
+		eventsBus.Register(domain.CarCreatedEventName, func(_ context.Context, _ bus.Dispatchable) (interface{}, error) {
+			delivered++
+			return nil, nil
+		})
+		ch := &CommandHandlerMock{
+			HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+				return []events.Event{domain.NewCarCreatedEvent(car)}, nil
+			},
+		}
+
+		relay := app.NewOutboxRelay(obMock, eventsBus, logger, time.Hour, time.Hour)
+		_, err := relay.ChMw()(app.ChOutboxMw(obMock)(ch)).Handle(context.Background(), &CommandMock{})
+		require.NoError(t, err)
+		require.Equal(t, 1, delivered)
+		require.Empty(t, ob.msgs)
+	})
+}
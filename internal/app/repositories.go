@@ -2,13 +2,19 @@ package app
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	"theskyinflames/car-sharing/internal/domain"
 
 	"github.com/google/uuid"
 )
 
-//go:generate moq -stub -out zmock_app_repositories_test.go -pkg app_test . GroupsRepository CarsRepository
+//go:generate moq -stub -out zmock_app_repositories_test.go -pkg app_test . GroupsRepository CarsRepository JourneysHistoryRepository ReservationsRepository
+
+// ErrVersionConflict is returned by the repositories when an aggregate is updated from a stale version,
+// because it has been changed by another command since it was read
+var ErrVersionConflict = errors.New("version conflict")
 
 // GroupsRepository is self-described
 type GroupsRepository interface {
@@ -27,4 +33,21 @@ type CarsRepository interface {
 	AddAll(ctx context.Context, cars []domain.Car) error
 	FindAll(ctx context.Context) ([]domain.Car, error)
 	FindByID(ctx context.Context, ID uuid.UUID) (domain.Car, error)
+	RemoveByID(ctx context.Context, ID uuid.UUID) error
+}
+
+// ReservationsRepository keeps the reservations until they start
+type ReservationsRepository interface {
+	Add(ctx context.Context, r domain.Reservation) error
+	FindAll(ctx context.Context) ([]domain.Reservation, error)
+	FindByID(ctx context.Context, ID uuid.UUID) (domain.Reservation, error)
+	RemoveByID(ctx context.Context, ID uuid.UUID) error
+}
+
+// JourneysHistoryRepository keeps the journeys of the groups, also after they have been dropped off,
+// and the waits of the groups that left without getting on a car
+type JourneysHistoryRepository interface {
+	Save(ctx context.Context, r JourneyRecord) error
+	FindByGroupID(ctx context.Context, groupID uuid.UUID) (JourneyRecord, error)
+	FindWaitEndedSince(ctx context.Context, since time.Time) ([]JourneyRecord, error)
 }
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"context"
+	"time"
+
+	"github.com/theskyinflames/cqrs-eda/pkg/bus"
+	"github.com/theskyinflames/cqrs-eda/pkg/cqrs"
+)
+
+// ReservationScheduler turns the reservations into journeys once they start.
+// Each reservation is started through the command bus, so it's done with the same middlewares as any other command
+type ReservationScheduler struct {
+	rr         ReservationsRepository
+	commandBus bus.Bus
+	log        cqrs.Logger
+	clock      Clock
+
+	interval time.Duration
+}
+
+// NewReservationScheduler is a constructor. The interval is the time between two scans of the reservations
+func NewReservationScheduler(
+	rr ReservationsRepository,
+	commandBus bus.Bus,
+	log cqrs.Logger,
+	clock Clock,
+	interval time.Duration,
+) ReservationScheduler {
+	return ReservationScheduler{
+		rr:         rr,
+		commandBus: commandBus,
+		log:        log,
+		clock:      clock,
+		interval:   interval,
+	}
+}
+
+// Start starts the reservations that have started, and returns how many of them have been started.
+// The failures are logged, and the next reservations are started anyway
+func (s ReservationScheduler) Start(ctx context.Context) (int, error) {
+	rs, err := s.rr.FindAll(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	now := s.clock.Now()
+	var started int
+	for _, r := range rs {
+		if !r.HasStarted(now) {
+			continue
+		}
+		if _, err := s.commandBus.Dispatch(ctx, StartReservationCmd{ReservationID: r.ID()}); err != nil {
+			s.log.Printf("reservation scheduler, reservation %s: %s\n", r.ID().String(), err.Error())
+			continue
+		}
+		started++
+	}
+	return started, nil
+}
+
+// Run starts the reservations periodically, until the context is cancelled
+func (s ReservationScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		if _, err := s.Start(ctx); err != nil {
+			s.log.Printf("reservation scheduler: %s\n", err.Error())
+		}
+	}
+}
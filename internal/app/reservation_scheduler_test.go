@@ -0,0 +1,81 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/domain"
+	"theskyinflames/car-sharing/internal/fixtures"
+	"theskyinflames/car-sharing/internal/helpers"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/theskyinflames/cqrs-eda/pkg/bus"
+)
+
+func TestReservationScheduler(t *testing.T) {
+	var (
+		randomErr = errors.New("")
+		logger    = log.New(io.Discard, "", 0)
+
+		now      = time.Now()
+		started  = fixtures.Reservation{StartAt: helpers.TimePtr(now.Add(-time.Second))}.Build()
+		upcoming = fixtures.Reservation{StartAt: helpers.TimePtr(now.Add(time.Hour))}.Build()
+		rr       = &ReservationsRepositoryMock{
+			FindAllFunc: func(_ context.Context) ([]domain.Reservation, error) {
+				return []domain.Reservation{started, upcoming}, nil
+			},
+		}
+	)
+
+	commandBus := func(handle func(app.StartReservationCmd) error) (bus.Bus, *[]uuid.UUID) {
+		var startedIDs []uuid.UUID
+		b := bus.New()
+		b.Register(app.StartReservationName, func(_ context.Context, d bus.Dispatchable) (interface{}, error) {
+			cmd := d.(app.StartReservationCmd)
+			if err := handle(cmd); err != nil {
+				return nil, err
+			}
+			startedIDs = append(startedIDs, cmd.ReservationID)
+			return nil, nil
+		})
+		return b, &startedIDs
+	}
+
+	t.Run(`Given a reservation that has started, when the scheduler runs,
+		then only that reservation is started through the command bus`, func(t *testing.T) {
+		b, startedIDs := commandBus(func(app.StartReservationCmd) error { return nil })
+		s := app.NewReservationScheduler(rr, b, logger, fixedClock{now: now}, time.Hour)
+
+		n, err := s.Start(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, 1, n)
+		require.Equal(t, []uuid.UUID{started.ID()}, *startedIDs)
+	})
+
+	t.Run(`Given a reservation that fails to be started, when the scheduler runs, then it's not counted`, func(t *testing.T) {
+		b, _ := commandBus(func(app.StartReservationCmd) error { return randomErr })
+		s := app.NewReservationScheduler(rr, b, logger, fixedClock{now: now.Add(2 * time.Hour)}, time.Hour)
+
+		n, err := s.Start(context.Background())
+		require.NoError(t, err)
+		require.Zero(t, n)
+	})
+
+	t.Run(`Given a reservations repository that fails, when the scheduler runs, then an error is returned`, func(t *testing.T) {
+		rr := &ReservationsRepositoryMock{
+			FindAllFunc: func(_ context.Context) ([]domain.Reservation, error) {
+				return nil, randomErr
+			},
+		}
+		s := app.NewReservationScheduler(rr, bus.New(), logger, fixedClock{now: now}, time.Hour)
+
+		_, err := s.Start(context.Background())
+		require.ErrorIs(t, err, randomErr)
+	})
+}
@@ -0,0 +1,29 @@
+package app
+
+import (
+	"context"
+	"errors"
+
+	"github.com/theskyinflames/cqrs-eda/pkg/cqrs"
+	"github.com/theskyinflames/cqrs-eda/pkg/events"
+)
+
+// ChRetryMw is a command handler middleware that retries the command when it fails because of a version conflict,
+// up to the given number of attempts. It has to wrap the unit of work, so each attempt reads the fleet again.
+func ChRetryMw(attempts int) cqrs.CommandHandlerMiddleware {
+	return func(ch cqrs.CommandHandler) cqrs.CommandHandler {
+		return cqrs.CommandHandlerFunc(func(ctx context.Context, cmd cqrs.Command) ([]events.Event, error) {
+			var (
+				evs []events.Event
+				err error
+			)
+			for i := 0; i < attempts; i++ {
+				evs, err = ch.Handle(ctx, cmd)
+				if !errors.Is(err, ErrVersionConflict) {
+					return evs, err
+				}
+			}
+			return nil, err
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"theskyinflames/car-sharing/internal/app"
+
+	"github.com/stretchr/testify/require"
+	"github.com/theskyinflames/cqrs-eda/pkg/cqrs"
+	"github.com/theskyinflames/cqrs-eda/pkg/events"
+)
+
+func TestChRetryMw(t *testing.T) {
+	const attempts = 3
+	randomErr := errors.New("")
+	testCases := []struct {
+		name              string
+		errs              []error
+		expectedCallsToCh int
+		expectedErr       error
+	}{
+		{
+			name:              `Given a command handler that succeeds, when a command is handled, then it's not retried`,
+			errs:              []error{nil},
+			expectedCallsToCh: 1,
+		},
+		{
+			name: `Given a command handler that returns an error that is not a version conflict, 
+				when a command is handled, then it's not retried`,
+			errs:              []error{randomErr},
+			expectedCallsToCh: 1,
+			expectedErr:       randomErr,
+		},
+		{
+			name: `Given a command handler that returns a version conflict and then succeeds, 
+				when a command is handled, then it's retried until it succeeds`,
+			errs:              []error{app.ErrVersionConflict, app.ErrVersionConflict, nil},
+			expectedCallsToCh: 3,
+		},
+		{
+			name: `Given a command handler that always returns a version conflict, 
+				when a command is handled, then it's retried up to the number of attempts and the conflict is returned`,
+			errs:              []error{app.ErrVersionConflict, app.ErrVersionConflict, app.ErrVersionConflict, nil},
+			expectedCallsToCh: attempts,
+			expectedErr:       app.ErrVersionConflict,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var calls int
+			ch := &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					err := tc.errs[calls]
+					calls++
+					return nil, err
+				},
+			}
+			_, err := app.ChRetryMw(attempts)(ch).Handle(context.Background(), &CommandMock{})
+			require.ErrorIs(t, err, tc.expectedErr)
+			require.Len(t, ch.HandleCalls(), tc.expectedCallsToCh)
+		})
+	}
+}
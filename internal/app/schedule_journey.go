@@ -0,0 +1,175 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"theskyinflames/car-sharing/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/theskyinflames/cqrs-eda/pkg/cqrs"
+	"github.com/theskyinflames/cqrs-eda/pkg/events"
+)
+
+// ScheduleJourneyCmd is a command. The site is the origin site of the group, by default the domain.DefaultSite.
+// The requirements are the features that the booked car must have
+type ScheduleJourneyCmd struct {
+	ID           uuid.UUID
+	People       int
+	Site         domain.Site
+	Requirements domain.Features
+	StartAt      time.Time
+	EndAt        time.Time
+}
+
+// ErrReservationIDTaken is self-described
+var ErrReservationIDTaken = errors.New("the id of the reservation belongs to a group already")
+
+// ScheduleJourneyName is self-described
+var ScheduleJourneyName = "schedule.journey"
+
+// Name implements the Command interface
+func (cmd ScheduleJourneyCmd) Name() string {
+	return ScheduleJourneyName
+}
+
+// ScheduleJourney is a command handler. It books the seats of a car for a group in a future window.
+// The reservation is rejected if no car can hold it besides the other reservations of its window, or if its ID
+// belongs to a group already, because its group couldn't be added once it starts
+type ScheduleJourney struct {
+	gr  GroupsRepository
+	evr CarsRepository
+	rr  ReservationsRepository
+
+	fleetOpts []domain.FleetOption
+}
+
+// NewScheduleJourney is a constructor
+func NewScheduleJourney(
+	gr GroupsRepository,
+	evr CarsRepository,
+	rr ReservationsRepository,
+	fleetOpts ...domain.FleetOption,
+) ScheduleJourney {
+	return ScheduleJourney{gr: gr, evr: evr, rr: rr, fleetOpts: fleetOpts}
+}
+
+// Handle implements CommandHandler interface
+func (ch ScheduleJourney) Handle(ctx context.Context, cmd cqrs.Command) ([]events.Event, error) {
+	co, ok := cmd.(ScheduleJourneyCmd)
+	if !ok {
+		return nil, NewInvalidCommandError(ScheduleJourneyName, cmd.Name())
+	}
+
+	r, err := domain.NewReservation(co.ID, co.People, co.Site, co.StartAt, co.EndAt, fleetNow(ch.fleetOpts),
+		domain.WithRequiredFeatures(co.Requirements...))
+	if err != nil {
+		return nil, err
+	}
+	if err := capacityLimits(ch.fleetOpts).CheckGroupSize(r.People()); err != nil {
+		return nil, err
+	}
+
+	fleet, err := loadFleet(ctx, ch.gr, ch.evr, ch.rr, ch.fleetOpts)
+	if err != nil {
+		return nil, err
+	}
+	if fleet.HasGroup(r.ID()) {
+		return nil, ErrReservationIDTaken
+	}
+	if err := fleet.Schedule(&r); err != nil {
+		return nil, err
+	}
+
+	evs := r.Events()
+	if err := ch.rr.Add(ctx, r); err != nil {
+		return nil, err
+	}
+	return evs, nil
+}
+
+// StartReservationCmd is a command
+type StartReservationCmd struct {
+	ReservationID uuid.UUID
+}
+
+// StartReservationName is self-described
+var StartReservationName = "start.reservation"
+
+// Name implements the Command interface
+func (cmd StartReservationCmd) Name() string {
+	return StartReservationName
+}
+
+// StartReservation is a command handler. It turns a reservation that has started into the journey of its group,
+// see ReservationScheduler
+type StartReservation struct {
+	gr  GroupsRepository
+	evr CarsRepository
+	rr  ReservationsRepository
+
+	fleetOpts []domain.FleetOption
+}
+
+// NewStartReservation is a constructor
+func NewStartReservation(
+	gr GroupsRepository,
+	evr CarsRepository,
+	rr ReservationsRepository,
+	fleetOpts ...domain.FleetOption,
+) StartReservation {
+	return StartReservation{gr: gr, evr: evr, rr: rr, fleetOpts: fleetOpts}
+}
+
+// Handle implements CommandHandler interface
+func (ch StartReservation) Handle(ctx context.Context, cmd cqrs.Command) ([]events.Event, error) {
+	co, ok := cmd.(StartReservationCmd)
+	if !ok {
+		return nil, NewInvalidCommandError(StartReservationName, cmd.Name())
+	}
+
+	r, err := ch.rr.FindByID(ctx, co.ReservationID)
+	if err != nil {
+		return nil, err
+	}
+	g, err := domain.NewGroup(r.ID(), r.People(),
+		domain.WithOrigin(r.Site()),
+		domain.WithRequirements(r.Requirements()...),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	fleet, err := loadFleet(ctx, ch.gr, ch.evr, ch.rr, ch.fleetOpts)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ch.gr.Add(ctx, g); err != nil {
+		return nil, err
+	}
+
+	g, car := fleet.StartReservation(r, g)
+	if car == nil { // the group waits for a car as the walk-in ones
+		return nil, ch.rr.RemoveByID(ctx, r.ID())
+	}
+
+	evs := g.Events()
+	if g.IsOnJourney() {
+		if err := ch.gr.Update(ctx, g); err != nil {
+			return nil, err
+		}
+	}
+
+	reassignedEvs, err := saveReassignment(ctx, ch.gr, ch.evr, fleet, []domain.Car{*car}, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// the reservation is only removed once its group and its car are persisted, so it's not lost if they fail
+	if err := ch.rr.RemoveByID(ctx, r.ID()); err != nil {
+		return nil, err
+	}
+	return append(evs, reassignedEvs...), nil
+}
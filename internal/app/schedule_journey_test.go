@@ -0,0 +1,253 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/domain"
+	"theskyinflames/car-sharing/internal/fixtures"
+	"theskyinflames/car-sharing/internal/helpers"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/theskyinflames/cqrs-eda/pkg/cqrs"
+)
+
+func TestScheduleJourney(t *testing.T) {
+	var (
+		randomErr = errors.New("")
+
+		gID     = uuid.New()
+		startAt = time.Now().Add(time.Hour)
+		endAt   = startAt.Add(time.Hour)
+		car     = fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity4)}.Build()
+		booked  = fixtures.Reservation{People: helpers.IntPtr(2), CarID: helpers.UUIDPtr(car.ID()), StartAt: &startAt}.Build()
+	)
+	testCases := []struct {
+		name            string
+		cmd             cqrs.Command
+		gr              *GroupsRepositoryMock
+		cr              *CarsRepositoryMock
+		rr              *ReservationsRepositoryMock
+		fleetOpts       []domain.FleetOption
+		expectedErrFunc func(*testing.T, error)
+	}{
+		{
+			name: `Given an invalid command, when it's called, then an error is returned`,
+			cmd:  newInvalidCommand(),
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorAs(t, err, &app.InvalidCommandError{})
+			},
+		},
+		{
+			name: `Given a window in the past, when it's called, then an error is returned`,
+			cmd:  app.ScheduleJourneyCmd{ID: gID, People: 2, StartAt: startAt.Add(-2 * time.Hour), EndAt: endAt},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, domain.ErrWrongWindow)
+			},
+		},
+		{
+			name:      `Given a clock that has passed the start of the window, when it's called, then an error is returned`,
+			cmd:       app.ScheduleJourneyCmd{ID: gID, People: 2, StartAt: startAt, EndAt: endAt},
+			fleetOpts: []domain.FleetOption{domain.WithClock(fixedClock{now: startAt.Add(time.Minute)})},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, domain.ErrWrongWindow)
+			},
+		},
+		{
+			name: `Given a group bigger than the largest car allowed, when it's called, then an error is returned`,
+			cmd:  app.ScheduleJourneyCmd{ID: gID, People: 7, StartAt: startAt, EndAt: endAt},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, domain.ErrGroupTooBig)
+			},
+		},
+		{
+			name: `Given reservations repository that returns an error on FindAll method,
+				when it's called, then an error is returned`,
+			cmd: app.ScheduleJourneyCmd{ID: gID, People: 2, StartAt: startAt, EndAt: endAt},
+			cr:  &CarsRepositoryMock{},
+			rr: &ReservationsRepositoryMock{
+				FindAllFunc: func(_ context.Context) ([]domain.Reservation, error) {
+					return nil, randomErr
+				},
+			},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, randomErr)
+			},
+		},
+		{
+			name: `Given a waiting group with the id of the reservation, when it's called, then an error is returned`,
+			cmd:  app.ScheduleJourneyCmd{ID: gID, People: 2, StartAt: startAt, EndAt: endAt},
+			gr: &GroupsRepositoryMock{
+				FindGroupsWithoutCarFunc: func(_ context.Context) ([]domain.Group, error) {
+					return []domain.Group{fixtures.Group{ID: &gID}.Build()}, nil
+				},
+			},
+			cr: &CarsRepositoryMock{
+				FindAllFunc: func(_ context.Context) ([]domain.Car, error) {
+					return []domain.Car{car}, nil
+				},
+			},
+			rr: &ReservationsRepositoryMock{},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, app.ErrReservationIDTaken)
+			},
+		},
+		{
+			name: `Given a car fully booked in the window, when it's called, then an error is returned`,
+			cmd:  app.ScheduleJourneyCmd{ID: gID, People: 3, StartAt: startAt, EndAt: endAt},
+			cr: &CarsRepositoryMock{
+				FindAllFunc: func(_ context.Context) ([]domain.Car, error) {
+					return []domain.Car{car}, nil
+				},
+			},
+			rr: &ReservationsRepositoryMock{
+				FindAllFunc: func(_ context.Context) ([]domain.Reservation, error) {
+					return []domain.Reservation{booked}, nil
+				},
+			},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, domain.ErrReservationNotHonourable)
+			},
+		},
+		{
+			name: `Given a car without the features required, when it's called, then an error is returned`,
+			cmd: app.ScheduleJourneyCmd{
+				ID:           gID,
+				People:       2,
+				Requirements: domain.NewFeatures(domain.FeatureWheelchair),
+				StartAt:      startAt,
+				EndAt:        endAt,
+			},
+			cr: &CarsRepositoryMock{
+				FindAllFunc: func(_ context.Context) ([]domain.Car, error) {
+					return []domain.Car{car}, nil
+				},
+			},
+			rr: &ReservationsRepositoryMock{},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, domain.ErrReservationNotHonourable)
+			},
+		},
+		{
+			name: `Given a car with seats enough in the window, when it's called,
+				then the reservation is added and a reservation scheduled event is returned`,
+			cmd: app.ScheduleJourneyCmd{ID: gID, People: 2, StartAt: startAt, EndAt: endAt},
+			cr: &CarsRepositoryMock{
+				FindAllFunc: func(_ context.Context) ([]domain.Car, error) {
+					return []domain.Car{car}, nil
+				},
+			},
+			rr: &ReservationsRepositoryMock{
+				FindAllFunc: func(_ context.Context) ([]domain.Reservation, error) {
+					return []domain.Reservation{booked}, nil
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		gr := tc.gr
+		if gr == nil {
+			gr = &GroupsRepositoryMock{}
+		}
+		ch := app.NewScheduleJourney(gr, tc.cr, tc.rr, tc.fleetOpts...)
+		evs, err := ch.Handle(context.Background(), tc.cmd)
+		require.Equal(t, tc.expectedErrFunc == nil, err == nil, tc.name)
+		if err != nil {
+			tc.expectedErrFunc(t, err)
+			continue
+		}
+
+		require.Len(t, tc.rr.AddCalls(), 1, tc.name)
+		require.Equal(t, gID, tc.rr.AddCalls()[0].R.ID(), tc.name)
+		require.Equal(t, car.ID(), tc.rr.AddCalls()[0].R.CarID(), tc.name)
+		require.Len(t, evs, 1, tc.name)
+		require.Equal(t, domain.ReservationScheduledEventName, evs[0].Name(), tc.name)
+	}
+}
+
+func TestStartReservation(t *testing.T) {
+	var (
+		car = fixtures.Car{}.Build()
+		r   = fixtures.Reservation{People: helpers.IntPtr(4), CarID: helpers.UUIDPtr(car.ID()), StartAt: helpers.TimePtr(time.Now())}.Build()
+	)
+
+	t.Run(`Given an invalid command, when it's called, then an error is returned`, func(t *testing.T) {
+		_, err := app.NewStartReservation(nil, nil, nil).Handle(context.Background(), newInvalidCommand())
+		require.ErrorAs(t, err, &app.InvalidCommandError{})
+	})
+
+	t.Run(`Given a reservation that has started, when it's called, then its group gets on the booked car`, func(t *testing.T) {
+		var (
+			gr = &GroupsRepositoryMock{}
+			cr = &CarsRepositoryMock{
+				FindAllFunc: func(_ context.Context) ([]domain.Car, error) {
+					return []domain.Car{fixtures.Car{}.Build(), car}, nil
+				},
+			}
+			rr = &ReservationsRepositoryMock{
+				FindByIDFunc: func(_ context.Context, _ uuid.UUID) (domain.Reservation, error) {
+					return r, nil
+				},
+				FindAllFunc: func(_ context.Context) ([]domain.Reservation, error) {
+					return []domain.Reservation{r}, nil
+				},
+			}
+		)
+
+		evs, err := app.NewStartReservation(gr, cr, rr).Handle(context.Background(), app.StartReservationCmd{ReservationID: r.ID()})
+		require.NoError(t, err)
+		require.Len(t, rr.RemoveByIDCalls(), 1)
+		require.Len(t, gr.AddCalls(), 1)
+		require.Len(t, gr.UpdateCalls(), 1)
+		require.Equal(t, r.ID(), gr.UpdateCalls()[0].G.ID())
+		require.Len(t, cr.UpdateCalls(), 1)
+		require.Equal(t, car.ID(), cr.UpdateCalls()[0].Car.ID())
+		require.Len(t, evs, 1)
+		require.Equal(t, domain.GroupSetOnJourneyEventName, evs[0].Name())
+	})
+
+	t.Run(`Given cars repository that returns an error on Update method, when it's called,
+		then an error is returned and the reservation is kept`, func(t *testing.T) {
+		var (
+			randomErr = errors.New("")
+			cr        = &CarsRepositoryMock{
+				FindAllFunc: func(_ context.Context) ([]domain.Car, error) {
+					return []domain.Car{car}, nil
+				},
+				UpdateFunc: func(_ context.Context, _ domain.Car) error {
+					return randomErr
+				},
+			}
+			rr = &ReservationsRepositoryMock{
+				FindByIDFunc: func(_ context.Context, _ uuid.UUID) (domain.Reservation, error) {
+					return r, nil
+				},
+				FindAllFunc: func(_ context.Context) ([]domain.Reservation, error) {
+					return []domain.Reservation{r}, nil
+				},
+			}
+		)
+
+		_, err := app.NewStartReservation(&GroupsRepositoryMock{}, cr, rr).
+			Handle(context.Background(), app.StartReservationCmd{ReservationID: r.ID()})
+		require.ErrorIs(t, err, randomErr)
+		require.Empty(t, rr.RemoveByIDCalls())
+	})
+
+	t.Run(`Given reservations repository that returns an error on FindByID method,
+		when it's called, then an error is returned`, func(t *testing.T) {
+		rr := &ReservationsRepositoryMock{
+			FindByIDFunc: func(_ context.Context, _ uuid.UUID) (domain.Reservation, error) {
+				return domain.Reservation{}, domain.ErrNotFound
+			},
+		}
+		_, err := app.NewStartReservation(&GroupsRepositoryMock{}, &CarsRepositoryMock{}, rr).
+			Handle(context.Background(), app.StartReservationCmd{ReservationID: r.ID()})
+		require.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
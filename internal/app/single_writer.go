@@ -0,0 +1,24 @@
+package app
+
+import (
+	"context"
+	"sync"
+
+	"github.com/theskyinflames/cqrs-eda/pkg/cqrs"
+	"github.com/theskyinflames/cqrs-eda/pkg/events"
+)
+
+// ChSingleWriterMw is a command handler middleware that serializes the commands of all the handlers it wraps.
+// The command handlers read the fleet, decide in memory and write it back. Running them one at a time ensures
+// that two concurrent commands never decide on the same fleet state, so the seats can't be double-booked.
+func ChSingleWriterMw() cqrs.CommandHandlerMiddleware {
+	mux := &sync.Mutex{}
+	return func(ch cqrs.CommandHandler) cqrs.CommandHandler {
+		return cqrs.CommandHandlerFunc(func(ctx context.Context, cmd cqrs.Command) ([]events.Event, error) {
+			mux.Lock()
+			defer mux.Unlock()
+
+			return ch.Handle(ctx, cmd)
+		})
+	}
+}
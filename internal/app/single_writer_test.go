@@ -0,0 +1,133 @@
+package app_test
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"runtime"
+	"sync"
+	"testing"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/domain"
+	"theskyinflames/car-sharing/internal/infra/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/theskyinflames/cqrs-eda/pkg/bus"
+)
+
+func TestConcurrentJourneysAndDropOffs(t *testing.T) {
+	t.Run(`Given a fleet and a storage that doesn't serialize the changes,
+		when thousands of journeys and drop offs are handled concurrently,
+		then no car ever exceeds its capacity`, func(t *testing.T) {
+		const groupsNumber = 2000
+
+		var (
+			ctx = context.Background()
+
+			gr  = repository.NewGroupsRepository()
+			evr = repository.NewCarRepository()
+
+			cr  = &capacityCheckingCarsRepository{CarRepository: &evr}
+			uow = &UnitOfWorkMock{
+				DoFunc: func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				},
+			}
+
+			commandBus = app.BuildCommandQueryBus(log.New(io.Discard, "", 0), bus.New(), &gr, cr, uow, repository.NewJourneysHistoryRepository(), repository.NewWebhooksRepository(), repository.NewReservationsRepository())
+		)
+
+		cars := make([]app.Car, 0)
+		for _, capacity := range []domain.CarCapacity{domain.CarCapacity4, domain.CarCapacity5, domain.CarCapacity6} {
+			for i := 0; i < 10; i++ {
+				cars = append(cars, app.Car{ID: uuid.New(), Seats: capacity})
+			}
+		}
+		_, err := commandBus.Dispatch(ctx, app.InitializeFleetCmd{Cars: cars})
+		require.NoError(t, err)
+
+		var (
+			wg     sync.WaitGroup
+			groups = make([]uuid.UUID, groupsNumber)
+			errs   = make(chan error, 3*groupsNumber)
+		)
+		for i := range groups {
+			groups[i] = uuid.New()
+			wg.Add(1)
+			go func(i int, gID uuid.UUID) {
+				defer wg.Done()
+				if _, err := commandBus.Dispatch(ctx, app.JourneyCmd{ID: gID, People: i%6 + 1}); err != nil {
+					errs <- err
+					return
+				}
+				if _, err := commandBus.Dispatch(ctx, app.LocateQuery{GroupID: gID}); err != nil {
+					errs <- fmt.Errorf("locate: %w", err)
+				}
+				if i%2 == 0 {
+					if _, err := commandBus.Dispatch(ctx, app.DropOffCmd{GroupID: gID}); err != nil {
+						errs <- fmt.Errorf("dropoff: %w", err)
+					}
+				}
+			}(i, groups[i])
+		}
+		wg.Wait()
+		close(errs)
+		for err := range errs {
+			require.NoError(t, err)
+		}
+		require.Empty(t, cr.overbooked)
+
+		// The groups that are on journey have to be in their car, and their seats can't exceed its capacity
+		fleet, err := evr.FindAll(ctx)
+		require.NoError(t, err)
+		occupied := make(map[uuid.UUID]int)
+		for i, gID := range groups {
+			g, err := gr.FindByID(ctx, gID)
+			if i%2 == 0 {
+				require.ErrorIs(t, err, repository.ErrNotFound)
+				continue
+			}
+			require.NoError(t, err)
+			if g.IsOnJourney() {
+				occupied[g.Car().ID()] += g.People()
+			}
+		}
+		for _, car := range fleet {
+			require.GreaterOrEqual(t, car.Availability(), 0)
+			require.LessOrEqual(t, occupied[car.ID()], car.Capacity().Int())
+			require.Equal(t, car.Capacity().Int()-car.Availability(), occupied[car.ID()])
+			for gID := range car.Journeys() {
+				g, err := gr.FindByID(ctx, gID)
+				require.NoError(t, err)
+				require.Equal(t, car.ID(), g.Car().ID())
+			}
+		}
+	})
+}
+
+// capacityCheckingCarsRepository records the cars that are stored with more people than seats.
+// It yields after reading the fleet, to widen the window between the read and the write of the command handlers
+type capacityCheckingCarsRepository struct {
+	*repository.CarRepository
+
+	mux        sync.Mutex
+	overbooked []domain.Car
+}
+
+func (cr *capacityCheckingCarsRepository) FindAll(ctx context.Context) ([]domain.Car, error) {
+	cars, err := cr.CarRepository.FindAll(ctx)
+	runtime.Gosched()
+	return cars, err
+}
+
+func (cr *capacityCheckingCarsRepository) Update(ctx context.Context, car domain.Car) error {
+	if car.Availability() < 0 {
+		cr.mux.Lock()
+		cr.overbooked = append(cr.overbooked, car)
+		cr.mux.Unlock()
+	}
+	return cr.CarRepository.Update(ctx, car)
+}
@@ -0,0 +1,89 @@
+package app
+
+import (
+	"context"
+	"sort"
+
+	"theskyinflames/car-sharing/internal/domain"
+
+	"github.com/theskyinflames/cqrs-eda/pkg/cqrs"
+)
+
+// SiteOccupancy is a DTO. It has the occupancy of the cars of a site, and its queue of waiting groups
+type SiteOccupancy struct {
+	Site          domain.Site
+	Cars          int
+	Seats         int
+	OccupiedSeats int
+	WaitingGroups int
+	WaitingPeople int
+}
+
+// SitesOccupancyResponse is a DTO. The sites are sorted by name
+type SitesOccupancyResponse struct {
+	Sites []SiteOccupancy
+}
+
+// SitesOccupancyQuery is a query
+type SitesOccupancyQuery struct{}
+
+// SitesOccupancyName is self-described
+var SitesOccupancyName = "sites.occupancy"
+
+// Name implements Query interface
+func (q SitesOccupancyQuery) Name() string {
+	return SitesOccupancyName
+}
+
+// SitesOccupancy is a query handler. It returns the occupancy of each site that has cars or waiting groups
+type SitesOccupancy struct {
+	gr  GroupsRepository
+	evr CarsRepository
+}
+
+// NewSitesOccupancy is a constructor
+func NewSitesOccupancy(gr GroupsRepository, evr CarsRepository) SitesOccupancy {
+	return SitesOccupancy{gr: gr, evr: evr}
+}
+
+// Handle implements the QueryHandler interface
+func (qh SitesOccupancy) Handle(ctx context.Context, query cqrs.Query) (cqrs.QueryResult, error) {
+	if _, ok := query.(SitesOccupancyQuery); !ok {
+		return nil, NewInvalidQueryError(SitesOccupancyName, query.Name())
+	}
+
+	cars, err := qh.evr.FindAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	wg, err := qh.gr.FindGroupsWithoutCar(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	bySite := make(map[domain.Site]*SiteOccupancy)
+	site := func(s domain.Site) *SiteOccupancy {
+		if _, ok := bySite[s]; !ok {
+			bySite[s] = &SiteOccupancy{Site: s}
+		}
+		return bySite[s]
+	}
+	for _, car := range cars {
+		so := site(car.Site())
+		so.Cars++
+		so.Seats += car.Capacity().Int()
+		so.OccupiedSeats += car.Occupancy()
+	}
+	for _, g := range wg {
+		so := site(g.Site())
+		so.WaitingGroups++
+		so.WaitingPeople += g.People()
+	}
+
+	rs := SitesOccupancyResponse{Sites: make([]SiteOccupancy, 0, len(bySite))}
+	for _, so := range bySite {
+		rs.Sites = append(rs.Sites, *so)
+	}
+	sort.Slice(rs.Sites, func(i, j int) bool { return rs.Sites[i].Site < rs.Sites[j].Site })
+	return rs, nil
+}
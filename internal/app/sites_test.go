@@ -0,0 +1,113 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/domain"
+	"theskyinflames/car-sharing/internal/fixtures"
+	"theskyinflames/car-sharing/internal/helpers"
+
+	"github.com/stretchr/testify/require"
+	"github.com/theskyinflames/cqrs-eda/pkg/cqrs"
+)
+
+func TestSitesOccupancy(t *testing.T) {
+	var (
+		randomErr = errors.New("")
+
+		onJourney = fixtures.Group{People: helpers.IntPtr(3)}.Build()
+		cars      = []domain.Car{
+			fixtures.Car{Site: "warehouse"}.Build(),
+			fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity6), Journeys: domain.Journeys{onJourney.ID(): onJourney}}.Build(),
+			fixtures.Car{Site: "warehouse"}.Build(),
+		}
+		wg = []domain.Group{
+			fixtures.Group{People: helpers.IntPtr(2), Site: "warehouse"}.Build(),
+			fixtures.Group{People: helpers.IntPtr(4), Site: "factory"}.Build(),
+			fixtures.Group{People: helpers.IntPtr(1), Site: "warehouse"}.Build(),
+		}
+	)
+	testCases := []struct {
+		name            string
+		q               cqrs.Query
+		gr              *GroupsRepositoryMock
+		evr             *CarsRepositoryMock
+		expectedRs      app.SitesOccupancyResponse
+		expectedErrFunc func(*testing.T, error)
+	}{
+		{
+			name: `Given an invalid query, when it's called, then an error is returned`,
+			q:    newInvalidQuery(),
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorAs(t, err, &app.InvalidQueryError{})
+			},
+		},
+		{
+			name: `Given a cars repository that returns an error on FindAll method,
+				when it's called, then an error is returned`,
+			q: app.SitesOccupancyQuery{},
+			evr: &CarsRepositoryMock{
+				FindAllFunc: func(_ context.Context) ([]domain.Car, error) {
+					return nil, randomErr
+				},
+			},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, randomErr)
+			},
+		},
+		{
+			name: `Given a groups repository that returns an error on FindGroupsWithoutCar method,
+				when it's called, then an error is returned`,
+			q: app.SitesOccupancyQuery{},
+			evr: &CarsRepositoryMock{
+				FindAllFunc: func(_ context.Context) ([]domain.Car, error) {
+					return cars, nil
+				},
+			},
+			gr: &GroupsRepositoryMock{
+				FindGroupsWithoutCarFunc: func(_ context.Context) ([]domain.Group, error) {
+					return nil, randomErr
+				},
+			},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, randomErr)
+			},
+		},
+		{
+			name: `Given cars and waiting groups of several sites, when it's called,
+				then the occupancy of each site is returned, sorted by site`,
+			q: app.SitesOccupancyQuery{},
+			evr: &CarsRepositoryMock{
+				FindAllFunc: func(_ context.Context) ([]domain.Car, error) {
+					return cars, nil
+				},
+			},
+			gr: &GroupsRepositoryMock{
+				FindGroupsWithoutCarFunc: func(_ context.Context) ([]domain.Group, error) {
+					return wg, nil
+				},
+			},
+			expectedRs: app.SitesOccupancyResponse{Sites: []app.SiteOccupancy{
+				{Site: domain.DefaultSite, Cars: 1, Seats: 6, OccupiedSeats: 3},
+				{Site: "factory", WaitingGroups: 1, WaitingPeople: 4},
+				{Site: "warehouse", Cars: 2, Seats: 8, WaitingGroups: 2, WaitingPeople: 3},
+			}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			qh := app.NewSitesOccupancy(tc.gr, tc.evr)
+			rs, err := qh.Handle(context.Background(), tc.q)
+			require.Equal(t, tc.expectedErrFunc == nil, err == nil)
+			if err != nil {
+				tc.expectedErrFunc(t, err)
+				return
+			}
+			require.Equal(t, tc.expectedRs, rs)
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package app
+
+import (
+	"context"
+
+	"github.com/theskyinflames/cqrs-eda/pkg/cqrs"
+	"github.com/theskyinflames/cqrs-eda/pkg/events"
+)
+
+//go:generate moq -stub -out zmock_unit_of_work_test.go -pkg app_test . UnitOfWork
+
+// UnitOfWork runs a function atomically. All the changes done through the repositories
+// with the context received by the function are committed if it succeeds, or rolled back otherwise.
+type UnitOfWork interface {
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// ChUnitOfWorkMw is a command handler middleware that runs each command in its own unit of work
+func ChUnitOfWorkMw(uow UnitOfWork) cqrs.CommandHandlerMiddleware {
+	return func(ch cqrs.CommandHandler) cqrs.CommandHandler {
+		return cqrs.CommandHandlerFunc(func(ctx context.Context, cmd cqrs.Command) ([]events.Event, error) {
+			var evs []events.Event
+			err := uow.Do(ctx, func(ctx context.Context) error {
+				var err error
+				evs, err = ch.Handle(ctx, cmd)
+				return err
+			})
+			if err != nil {
+				return nil, err
+			}
+			return evs, nil
+		})
+	}
+}
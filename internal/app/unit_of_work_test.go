@@ -0,0 +1,83 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/domain"
+	"theskyinflames/car-sharing/internal/fixtures"
+
+	"github.com/stretchr/testify/require"
+	"github.com/theskyinflames/cqrs-eda/pkg/cqrs"
+	"github.com/theskyinflames/cqrs-eda/pkg/events"
+)
+
+func TestChUnitOfWorkMw(t *testing.T) {
+	randomErr := errors.New("")
+	evs := []events.Event{domain.NewCarCreatedEvent(fixtures.Car{}.Build())}
+	testCases := []struct {
+		name              string
+		uow               *UnitOfWorkMock
+		ch                *CommandHandlerMock
+		expectedCallsToCh int
+		expectedEvs       []events.Event
+		expectedErr       error
+	}{
+		{
+			name: `Given a unit of work that fails to start, when a command is handled, 
+				then the command handler is not called and an error is returned`,
+			uow: &UnitOfWorkMock{
+				DoFunc: func(_ context.Context, _ func(ctx context.Context) error) error {
+					return randomErr
+				},
+			},
+			ch:          &CommandHandlerMock{},
+			expectedErr: randomErr,
+		},
+		{
+			name: `Given a command handler that returns an error, when a command is handled, 
+				then the error is returned to the unit of work and no events are returned`,
+			uow: &UnitOfWorkMock{
+				DoFunc: func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				},
+			},
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return evs, randomErr
+				},
+			},
+			expectedCallsToCh: 1,
+			expectedErr:       randomErr,
+		},
+		{
+			name: `Given a command handler that succeeds, when a command is handled, 
+				then its events are returned`,
+			uow: &UnitOfWorkMock{
+				DoFunc: func(ctx context.Context, fn func(ctx context.Context) error) error {
+					return fn(ctx)
+				},
+			},
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return evs, nil
+				},
+			},
+			expectedCallsToCh: 1,
+			expectedEvs:       evs,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ch := app.ChUnitOfWorkMw(tc.uow)(tc.ch)
+			got, err := ch.Handle(context.Background(), &CommandMock{})
+			require.ErrorIs(t, err, tc.expectedErr)
+			require.Equal(t, tc.expectedEvs, got)
+			require.Len(t, tc.uow.DoCalls(), 1)
+			require.Len(t, tc.ch.HandleCalls(), tc.expectedCallsToCh)
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package app
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/theskyinflames/cqrs-eda/pkg/cqrs"
+)
+
+// GroupSizeWaitTimes is a DTO. It has the waiting time percentiles of the groups of a size,
+// both the ones that got on a car and the ones that abandoned
+type GroupSizeWaitTimes struct {
+	People    int
+	Journeys  int
+	Abandoned int
+	P50       time.Duration
+	P90       time.Duration
+	P99       time.Duration
+}
+
+// WaitTimesResponse is a DTO. The groups sizes are sorted in ascending order
+type WaitTimesResponse struct {
+	Window time.Duration
+	BySize []GroupSizeWaitTimes
+}
+
+// WaitTimesQuery is a query. It asks for the waiting times of the groups that got on a car, or that left without it,
+// during the last window
+type WaitTimesQuery struct {
+	Window time.Duration
+}
+
+// WaitTimesName is self-described
+var WaitTimesName = "wait.times"
+
+// Name implements Query interface
+func (q WaitTimesQuery) Name() string {
+	return WaitTimesName
+}
+
+// ErrWrongWindow is self-described
+var ErrWrongWindow = errors.New("wrong window, it has to be greater than zero")
+
+// WaitTimes is a query handler
+type WaitTimes struct {
+	hr    JourneysHistoryRepository
+	clock Clock
+}
+
+// NewWaitTimes is a constructor
+func NewWaitTimes(hr JourneysHistoryRepository, clock Clock) WaitTimes {
+	return WaitTimes{hr: hr, clock: clock}
+}
+
+// Handle implements the QueryHandler interface
+func (qh WaitTimes) Handle(ctx context.Context, query cqrs.Query) (cqrs.QueryResult, error) {
+	q, ok := query.(WaitTimesQuery)
+	if !ok {
+		return nil, NewInvalidQueryError(WaitTimesName, query.Name())
+	}
+	if q.Window <= 0 {
+		return nil, ErrWrongWindow
+	}
+
+	records, err := qh.hr.FindWaitEndedSince(ctx, qh.clock.Now().Add(-q.Window))
+	if err != nil {
+		return nil, err
+	}
+
+	bySize := make(map[int][]time.Duration)
+	abandoned := make(map[int]int)
+	for _, r := range records {
+		bySize[r.People] = append(bySize[r.People], r.WaitingTime())
+		if r.IsAbandoned() {
+			abandoned[r.People]++
+		}
+	}
+
+	rs := WaitTimesResponse{Window: q.Window, BySize: make([]GroupSizeWaitTimes, 0, len(bySize))}
+	for people, waits := range bySize {
+		sort.Slice(waits, func(i, j int) bool { return waits[i] < waits[j] })
+		rs.BySize = append(rs.BySize, GroupSizeWaitTimes{
+			People:    people,
+			Journeys:  len(waits) - abandoned[people],
+			Abandoned: abandoned[people],
+			P50:       percentile(waits, 50),
+			P90:       percentile(waits, 90),
+			P99:       percentile(waits, 99),
+		})
+	}
+	sort.Slice(rs.BySize, func(i, j int) bool { return rs.BySize[i].People < rs.BySize[j].People })
+	return rs, nil
+}
+
+// percentile returns the nearest-rank percentile p of the sorted durations
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,127 @@
+package app_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"theskyinflames/car-sharing/internal/app"
+
+	"github.com/stretchr/testify/require"
+	"github.com/theskyinflames/cqrs-eda/pkg/cqrs"
+)
+
+func TestWaitTimes(t *testing.T) {
+	var (
+		randomErr = errors.New("")
+		now       = time.Now()
+		record    = func(people int, wait time.Duration) app.JourneyRecord {
+			return app.JourneyRecord{People: people, RequestedAt: now.Add(-wait), BoardedAt: now}
+		}
+		abandoned = func(people int, wait time.Duration) app.JourneyRecord {
+			return app.JourneyRecord{People: people, RequestedAt: now.Add(-wait), AbandonedAt: now}
+		}
+	)
+	testCases := []struct {
+		name            string
+		q               cqrs.Query
+		hr              *JourneysHistoryRepositoryMock
+		expectedRs      app.WaitTimesResponse
+		expectedErrFunc func(*testing.T, error)
+	}{
+		{
+			name: `Given an invalid query, when it's called, then an error is returned`,
+			q:    newInvalidQuery(),
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorAs(t, err, &app.InvalidQueryError{})
+			},
+		},
+		{
+			name: `Given a query without window, when it's called, then an error is returned`,
+			q:    app.WaitTimesQuery{},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, app.ErrWrongWindow)
+			},
+		},
+		{
+			name: `Given a journeys history repository that returns an error on FindWaitEndedSince method,
+				when it's called, then an error is returned`,
+			q: app.WaitTimesQuery{Window: time.Hour},
+			hr: &JourneysHistoryRepositoryMock{
+				FindWaitEndedSinceFunc: func(_ context.Context, _ time.Time) ([]app.JourneyRecord, error) {
+					return nil, randomErr
+				},
+			},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, randomErr)
+			},
+		},
+		{
+			name: `Given an empty journeys history, when it's called, then no group sizes are returned`,
+			q:    app.WaitTimesQuery{Window: time.Hour},
+			hr:   &JourneysHistoryRepositoryMock{},
+			expectedRs: app.WaitTimesResponse{
+				Window: time.Hour,
+				BySize: []app.GroupSizeWaitTimes{},
+			},
+		},
+		{
+			name: `Given a journeys history with several group sizes,
+				when it's called, then the waiting time percentiles by group size are returned`,
+			q: app.WaitTimesQuery{Window: time.Hour},
+			hr: &JourneysHistoryRepositoryMock{
+				FindWaitEndedSinceFunc: func(_ context.Context, _ time.Time) ([]app.JourneyRecord, error) {
+					rs := []app.JourneyRecord{record(6, time.Minute)}
+					for i := 10; i > 0; i-- {
+						rs = append(rs, record(2, time.Duration(i)*time.Second))
+					}
+					return rs, nil
+				},
+			},
+			expectedRs: app.WaitTimesResponse{
+				Window: time.Hour,
+				BySize: []app.GroupSizeWaitTimes{
+					{People: 2, Journeys: 10, P50: 5 * time.Second, P90: 9 * time.Second, P99: 10 * time.Second},
+					{People: 6, Journeys: 1, P50: time.Minute, P90: time.Minute, P99: time.Minute},
+				},
+			},
+		},
+		{
+			name: `Given a journeys history with groups that abandoned after a long wait,
+				when it's called, then their waits are in the percentiles`,
+			q: app.WaitTimesQuery{Window: time.Hour},
+			hr: &JourneysHistoryRepositoryMock{
+				FindWaitEndedSinceFunc: func(_ context.Context, since time.Time) ([]app.JourneyRecord, error) {
+					if !since.Equal(now.Add(-time.Hour)) {
+						return nil, errors.New("the window doesn't end at the time of the clock")
+					}
+					var rs []app.JourneyRecord
+					for i := 1; i <= 8; i++ {
+						rs = append(rs, record(4, time.Duration(i)*time.Second))
+					}
+					return append(rs, abandoned(4, 10*time.Minute), abandoned(4, 20*time.Minute)), nil
+				},
+			},
+			expectedRs: app.WaitTimesResponse{
+				Window: time.Hour,
+				BySize: []app.GroupSizeWaitTimes{
+					{People: 4, Journeys: 8, Abandoned: 2, P50: 5 * time.Second, P90: 10 * time.Minute, P99: 20 * time.Minute},
+				},
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			qh := app.NewWaitTimes(tc.hr, fixedClock{now: now})
+			rs, err := qh.Handle(context.Background(), tc.q)
+			require.Equal(t, tc.expectedErrFunc == nil, err == nil)
+			if err != nil {
+				tc.expectedErrFunc(t, err)
+				return
+			}
+			require.Equal(t, tc.expectedRs, rs)
+		})
+	}
+}
@@ -0,0 +1,379 @@
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"theskyinflames/car-sharing/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/theskyinflames/cqrs-eda/pkg/cqrs"
+	"github.com/theskyinflames/cqrs-eda/pkg/events"
+)
+
+//go:generate moq -stub -out zmock_webhooks_test.go -pkg app_test . WebhooksRepository WebhookSender
+
+// WebhookEvents are the events that a webhook can subscribe to
+var WebhookEvents = []string{
+	domain.CarCreatedEventName,
+	domain.GroupSetOnJourneyEventName,
+	domain.GroupDroppedOffEventName,
+	domain.GroupCancelledEventName,
+	domain.GroupExpiredEventName,
+}
+
+// webhookEventTimes has the field of the body of each webhook event with the time it happened
+var webhookEventTimes = map[string]string{
+	domain.CarCreatedEventName:        "added_at",
+	domain.GroupSetOnJourneyEventName: "boarded_at",
+	domain.GroupDroppedOffEventName:   "dropped_off_at",
+	domain.GroupCancelledEventName:    "cancelled_at",
+	domain.GroupExpiredEventName:      "expired_at",
+}
+
+// Webhook is a subscription of an URL to some events. The payloads sent to it are signed with its secret
+type Webhook struct {
+	ID     uuid.UUID
+	URL    string
+	Events []string
+	Secret string
+}
+
+// Subscribes returns TRUE if the webhook is subscribed to the event
+func (wh Webhook) Subscribes(name string) bool {
+	for _, e := range wh.Events {
+		if e == name {
+			return true
+		}
+	}
+	return false
+}
+
+// DeadLetter is a payload that could not be delivered to a webhook after all the attempts
+type DeadLetter struct {
+	WebhookID uuid.UUID
+	URL       string
+	Event     string
+	Payload   []byte
+	Attempts  int
+	Err       string
+	FailedAt  time.Time
+}
+
+// WebhooksRepository keeps the webhooks and their dead letters
+type WebhooksRepository interface {
+	Add(ctx context.Context, wh Webhook) error
+	FindByEvent(ctx context.Context, name string) ([]Webhook, error)
+	AddDeadLetter(ctx context.Context, dl DeadLetter) error
+	FindDeadLetters(ctx context.Context) ([]DeadLetter, error)
+}
+
+// WebhookSender sends a payload to a webhook. It's tried once, the retries are done by the WebhooksNotifier
+type WebhookSender interface {
+	Send(ctx context.Context, wh Webhook, event string, payload []byte) error
+}
+
+// RegisterWebhookCmd is a command
+type RegisterWebhookCmd struct {
+	ID     uuid.UUID
+	URL    string
+	Events []string
+	Secret string
+}
+
+// RegisterWebhookName is self-described
+var RegisterWebhookName = "register.webhook"
+
+// Name implements the Command interface
+func (cmd RegisterWebhookCmd) Name() string {
+	return RegisterWebhookName
+}
+
+var (
+	// ErrWrongWebhookURL is self-described
+	ErrWrongWebhookURL = errors.New("wrong webhook URL, it has to be an absolute http or https URL")
+	// ErrWrongWebhookEvents is self-described
+	ErrWrongWebhookEvents = fmt.Errorf("wrong webhook events, at least one of %s is required", strings.Join(WebhookEvents, ", "))
+	// ErrWrongWebhookSecret is self-described
+	ErrWrongWebhookSecret = errors.New("wrong webhook secret, it can't be empty")
+)
+
+// RegisterWebhook is a command handler
+type RegisterWebhook struct {
+	wr WebhooksRepository
+}
+
+// NewRegisterWebhook is a constructor
+func NewRegisterWebhook(wr WebhooksRepository) RegisterWebhook {
+	return RegisterWebhook{wr: wr}
+}
+
+// Handle implements CommandHandler interface
+func (ch RegisterWebhook) Handle(ctx context.Context, cmd cqrs.Command) ([]events.Event, error) {
+	co, ok := cmd.(RegisterWebhookCmd)
+	if !ok {
+		return nil, NewInvalidCommandError(RegisterWebhookName, cmd.Name())
+	}
+
+	u, err := url.Parse(co.URL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, ErrWrongWebhookURL
+	}
+	if len(co.Events) == 0 {
+		return nil, ErrWrongWebhookEvents
+	}
+	for _, e := range co.Events {
+		if !isWebhookEvent(e) {
+			return nil, fmt.Errorf("%w: %s", ErrWrongWebhookEvents, e)
+		}
+	}
+	if co.Secret == "" {
+		return nil, ErrWrongWebhookSecret
+	}
+
+	return nil, ch.wr.Add(ctx, Webhook{ID: co.ID, URL: co.URL, Events: co.Events, Secret: co.Secret})
+}
+
+func isWebhookEvent(name string) bool {
+	for _, e := range WebhookEvents {
+		if e == name {
+			return true
+		}
+	}
+	return false
+}
+
+// DeadLettersQuery is a query
+type DeadLettersQuery struct{}
+
+// DeadLettersName is self-described
+var DeadLettersName = "webhooks.dead.letters"
+
+// Name implements Query interface
+func (q DeadLettersQuery) Name() string {
+	return DeadLettersName
+}
+
+// DeadLetters is a query handler
+type DeadLetters struct {
+	wr WebhooksRepository
+}
+
+// NewDeadLetters is a constructor
+func NewDeadLetters(wr WebhooksRepository) DeadLetters {
+	return DeadLetters{wr: wr}
+}
+
+// Handle implements the QueryHandler interface
+func (qh DeadLetters) Handle(ctx context.Context, query cqrs.Query) (cqrs.QueryResult, error) {
+	if _, ok := query.(DeadLettersQuery); !ok {
+		return nil, NewInvalidQueryError(DeadLettersName, query.Name())
+	}
+	return qh.wr.FindDeadLetters(ctx)
+}
+
+// WebhookPayload is the JSON payload sent to the webhooks. Its ID identifies the event, so it's kept when the event is
+// delivered more than once. OccurredAt is the time the event happened, taken from its body, not the time it was sent
+type WebhookPayload struct {
+	ID          uuid.UUID       `json:"id"`
+	Event       string          `json:"event"`
+	AggregateID uuid.UUID       `json:"aggregate_id"`
+	OccurredAt  time.Time       `json:"occurred_at"`
+	Data        json.RawMessage `json:"data"`
+}
+
+const (
+	// webhookDeliveriesSize is the number of deliveries that can be waiting to be sent
+	webhookDeliveriesSize = 1000
+	// webhookQueueSize is the number of deliveries that can be waiting to be sent to a single webhook
+	webhookQueueSize = 100
+)
+
+type webhookDelivery struct {
+	wh      Webhook
+	event   string
+	payload []byte
+}
+
+// WebhooksNotifier sends the events to the webhooks subscribed to them. The deliveries are queued by its events handler,
+// so the events bus is not blocked, and Run sends them with a worker per webhook, so a webhook that is slow or
+// being retried doesn't hold back the other ones. Each webhook gets its deliveries in order.
+// A delivery that still fails after all the attempts is added to the dead letters
+type WebhooksNotifier struct {
+	wr     WebhooksRepository
+	sender WebhookSender
+	log    cqrs.Logger
+
+	attempts int
+	backoff  time.Duration
+
+	deliveries chan webhookDelivery
+}
+
+// NewWebhooksNotifier is a constructor. The time between two attempts is doubled each time, starting from the backoff
+func NewWebhooksNotifier(wr WebhooksRepository, sender WebhookSender, log cqrs.Logger, attempts int, backoff time.Duration) WebhooksNotifier {
+	return WebhooksNotifier{
+		wr:         wr,
+		sender:     sender,
+		log:        log,
+		attempts:   attempts,
+		backoff:    backoff,
+		deliveries: make(chan webhookDelivery, webhookDeliveriesSize),
+	}
+}
+
+// Handler returns the events handler that queues a delivery for each webhook subscribed to the event
+func (wn WebhooksNotifier) Handler() events.Handler {
+	return func(ev events.Event) {
+		ctx := context.Background()
+		whs, err := wn.wr.FindByEvent(ctx, ev.Name())
+		if err != nil {
+			wn.log.Printf("webhooks, event %s: %s\n", ev.Name(), err.Error())
+			return
+		}
+		if len(whs) == 0 {
+			return
+		}
+
+		data, err := domain.EventBody(ev)
+		if err != nil {
+			wn.log.Printf("webhooks, event %s: %s\n", ev.Name(), err.Error())
+			return
+		}
+		occurredAt, err := webhookEventTime(ev.Name(), data)
+		if err != nil {
+			wn.log.Printf("webhooks, event %s: %s\n", ev.Name(), err.Error())
+			return
+		}
+		payload, _ := json.Marshal(WebhookPayload{
+			ID:          webhookPayloadID(ev, data),
+			Event:       ev.Name(),
+			AggregateID: ev.AggregateID(),
+			OccurredAt:  occurredAt,
+			Data:        data,
+		})
+
+		for _, wh := range whs {
+			d := webhookDelivery{wh: wh, event: ev.Name(), payload: payload}
+			select {
+			case wn.deliveries <- d:
+			default:
+				wn.deadLetter(ctx, d, 0, errors.New("too many pending deliveries"))
+			}
+		}
+	}
+}
+
+// webhookPayloadID derives the ID of the payload from the event, so it's the same each time the event is delivered,
+// i.e. again by the outbox relay after a failure, and the webhooks can discard the duplicates.
+// The body of the events sent to the webhooks has the time they happened, so two events don't get the same ID
+func webhookPayloadID(ev events.Event, data []byte) uuid.UUID {
+	return uuid.NewSHA1(ev.AggregateID(), append([]byte(ev.Name()), data...))
+}
+
+// webhookEventTime returns the time the event happened, read from its body
+func webhookEventTime(name string, data []byte) (time.Time, error) {
+	field, ok := webhookEventTimes[name]
+	if !ok {
+		return time.Time{}, fmt.Errorf("%w: %s", domain.ErrUnknownEvent, name)
+	}
+	var body map[string]json.RawMessage
+	if err := json.Unmarshal(data, &body); err != nil {
+		return time.Time{}, err
+	}
+	var at time.Time
+	if err := json.Unmarshal(body[field], &at); err != nil {
+		return time.Time{}, fmt.Errorf("%s of %s: %w", field, name, err)
+	}
+	return at, nil
+}
+
+// Run sends the queued deliveries until the context is cancelled. The worker of a webhook is started with its first
+// delivery, and Run returns once all the workers have stopped
+func (wn WebhooksNotifier) Run(ctx context.Context) {
+	var (
+		wg     sync.WaitGroup
+		queues = make(map[uuid.UUID]chan webhookDelivery)
+	)
+	defer wg.Wait()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case d := <-wn.deliveries:
+			queue, ok := queues[d.wh.ID]
+			if !ok {
+				queue = make(chan webhookDelivery, webhookQueueSize)
+				queues[d.wh.ID] = queue
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					wn.work(ctx, queue)
+				}()
+			}
+			select {
+			case queue <- d:
+			default:
+				wn.deadLetter(ctx, d, 0, errors.New("too many pending deliveries to the webhook"))
+			}
+		}
+	}
+}
+
+// work sends the deliveries of a webhook one by one, until the context is cancelled
+func (wn WebhooksNotifier) work(ctx context.Context, queue <-chan webhookDelivery) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case d := <-queue:
+			wn.deliver(ctx, d)
+		}
+	}
+}
+
+func (wn WebhooksNotifier) deliver(ctx context.Context, d webhookDelivery) {
+	var (
+		err  error
+		wait = wn.backoff
+	)
+	for i := 1; i <= wn.attempts; i++ {
+		if err = wn.sender.Send(ctx, d.wh, d.event, d.payload); err == nil {
+			return
+		}
+		if i == wn.attempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			wn.deadLetter(context.Background(), d, i, ctx.Err())
+			return
+		case <-time.After(wait):
+		}
+		wait *= 2
+	}
+	wn.deadLetter(ctx, d, wn.attempts, err)
+}
+
+func (wn WebhooksNotifier) deadLetter(ctx context.Context, d webhookDelivery, attempts int, err error) {
+	wn.log.Printf("webhooks, event %s to %s: %s\n", d.event, d.wh.URL, err.Error())
+	dl := DeadLetter{
+		WebhookID: d.wh.ID,
+		URL:       d.wh.URL,
+		Event:     d.event,
+		Payload:   d.payload,
+		Attempts:  attempts,
+		Err:       err.Error(),
+		FailedAt:  time.Now(),
+	}
+	if err := wn.wr.AddDeadLetter(ctx, dl); err != nil {
+		wn.log.Printf("webhooks, dead letter of event %s to %s: %s\n", d.event, d.wh.URL, err.Error())
+	}
+}
@@ -0,0 +1,336 @@
+package app_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log"
+	"testing"
+	"time"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/domain"
+	"theskyinflames/car-sharing/internal/fixtures"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/theskyinflames/cqrs-eda/pkg/events"
+)
+
+func TestRegisterWebhook(t *testing.T) {
+	var (
+		randomErr = errors.New("")
+		validCmd  = app.RegisterWebhookCmd{
+			ID:     uuid.New(),
+			URL:    "https://example.com/events",
+			Events: []string{domain.GroupSetOnJourneyEventName, domain.GroupDroppedOffEventName},
+			Secret: "secret",
+		}
+	)
+	testCases := []struct {
+		name          string
+		cmd           app.RegisterWebhookCmd
+		wr            *WebhooksRepositoryMock
+		expectedCalls int
+		expectedErr   error
+	}{
+		{
+			name: `Given a relative URL, when the command is handled, then an error is returned`,
+			cmd: app.RegisterWebhookCmd{
+				URL:    "/events",
+				Events: validCmd.Events,
+				Secret: validCmd.Secret,
+			},
+			wr:          &WebhooksRepositoryMock{},
+			expectedErr: app.ErrWrongWebhookURL,
+		},
+		{
+			name: `Given a not http URL, when the command is handled, then an error is returned`,
+			cmd: app.RegisterWebhookCmd{
+				URL:    "ftp://example.com/events",
+				Events: validCmd.Events,
+				Secret: validCmd.Secret,
+			},
+			wr:          &WebhooksRepositoryMock{},
+			expectedErr: app.ErrWrongWebhookURL,
+		},
+		{
+			name: `Given no events, when the command is handled, then an error is returned`,
+			cmd: app.RegisterWebhookCmd{
+				URL:    validCmd.URL,
+				Secret: validCmd.Secret,
+			},
+			wr:          &WebhooksRepositoryMock{},
+			expectedErr: app.ErrWrongWebhookEvents,
+		},
+		{
+			name: `Given an event that can't be subscribed, when the command is handled, then an error is returned`,
+			cmd: app.RegisterWebhookCmd{
+				URL:    validCmd.URL,
+				Events: []string{domain.CarReservedEventName},
+				Secret: validCmd.Secret,
+			},
+			wr:          &WebhooksRepositoryMock{},
+			expectedErr: app.ErrWrongWebhookEvents,
+		},
+		{
+			name: `Given an empty secret, when the command is handled, then an error is returned`,
+			cmd: app.RegisterWebhookCmd{
+				URL:    validCmd.URL,
+				Events: validCmd.Events,
+			},
+			wr:          &WebhooksRepositoryMock{},
+			expectedErr: app.ErrWrongWebhookSecret,
+		},
+		{
+			name: `Given a repository that fails, when the command is handled, then an error is returned`,
+			cmd:  validCmd,
+			wr: &WebhooksRepositoryMock{
+				AddFunc: func(_ context.Context, _ app.Webhook) error {
+					return randomErr
+				},
+			},
+			expectedCalls: 1,
+			expectedErr:   randomErr,
+		},
+		{
+			name:          `Given a valid command, when it's handled, then the webhook is added`,
+			cmd:           validCmd,
+			wr:            &WebhooksRepositoryMock{},
+			expectedCalls: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			evs, err := app.NewRegisterWebhook(tc.wr).Handle(context.Background(), tc.cmd)
+			require.ErrorIs(t, err, tc.expectedErr)
+			require.Empty(t, evs)
+			require.Len(t, tc.wr.AddCalls(), tc.expectedCalls)
+			if tc.expectedCalls > 0 {
+				wh := tc.wr.AddCalls()[0].Wh
+				require.Equal(t, app.Webhook{ID: tc.cmd.ID, URL: tc.cmd.URL, Events: tc.cmd.Events, Secret: tc.cmd.Secret}, wh)
+			}
+		})
+	}
+}
+
+func TestErrWrongWebhookEvents(t *testing.T) {
+	for _, e := range app.WebhookEvents {
+		require.Contains(t, app.ErrWrongWebhookEvents.Error(), e)
+	}
+}
+
+func TestDeadLetters(t *testing.T) {
+	dls := []app.DeadLetter{{WebhookID: uuid.New(), Event: domain.GroupDroppedOffEventName, Attempts: 3}}
+	wr := &WebhooksRepositoryMock{
+		FindDeadLettersFunc: func(_ context.Context) ([]app.DeadLetter, error) {
+			return dls, nil
+		},
+	}
+
+	rs, err := app.NewDeadLetters(wr).Handle(context.Background(), app.DeadLettersQuery{})
+	require.NoError(t, err)
+	require.Equal(t, dls, rs)
+}
+
+func TestWebhooksNotifier(t *testing.T) {
+	var (
+		randomErr = errors.New("")
+		wh        = app.Webhook{ID: uuid.New(), URL: "https://example.com/events", Events: []string{domain.CarCreatedEventName}, Secret: "secret"}
+		car       = fixtures.Car{}.Build()
+	)
+	testCases := []struct {
+		name                string
+		webhooks            []app.Webhook
+		sendErrs            int
+		expectedSends       int
+		expectedDeadLetters int
+	}{
+		{
+			name:          `Given no webhooks subscribed to the event, when it's handled, then nothing is sent`,
+			expectedSends: 0,
+		},
+		{
+			name:          `Given two webhooks subscribed to the event, when it's handled, then it's sent to both`,
+			webhooks:      []app.Webhook{wh, wh},
+			expectedSends: 2,
+		},
+		{
+			name:          `Given a webhook that fails once, when the event is handled, then it's retried`,
+			webhooks:      []app.Webhook{wh},
+			sendErrs:      1,
+			expectedSends: 2,
+		},
+		{
+			name:                `Given a webhook that always fails, when the event is handled, then it's tried all the attempts and added to the dead letters`,
+			webhooks:            []app.Webhook{wh},
+			sendErrs:            3,
+			expectedSends:       3,
+			expectedDeadLetters: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				sent = make(chan struct{}, 10)
+				dead = make(chan app.DeadLetter, 10)
+				wr   = &WebhooksRepositoryMock{
+					FindByEventFunc: func(_ context.Context, _ string) ([]app.Webhook, error) {
+						return tc.webhooks, nil
+					},
+					AddDeadLetterFunc: func(_ context.Context, dl app.DeadLetter) error {
+						dead <- dl
+						return nil
+					},
+				}
+				sendErrs = tc.sendErrs
+				sender   = &WebhookSenderMock{
+					SendFunc: func(_ context.Context, _ app.Webhook, _ string, _ []byte) error {
+						defer func() { sent <- struct{}{} }()
+						if sendErrs > 0 {
+							sendErrs--
+							return randomErr
+						}
+						return nil
+					},
+				}
+				ctx, cancel = context.WithCancel(context.Background())
+			)
+			defer cancel()
+
+			wn := app.NewWebhooksNotifier(wr, sender, log.New(io.Discard, "", 0), 3, time.Millisecond)
+			go wn.Run(ctx)
+			wn.Handler()(domain.NewCarCreatedEvent(car))
+
+			for i := 0; i < tc.expectedSends; i++ {
+				select {
+				case <-sent:
+				case <-time.After(time.Second):
+					t.Fatalf("expected %d sends, got %d", tc.expectedSends, i)
+				}
+			}
+			for i := 0; i < tc.expectedDeadLetters; i++ {
+				select {
+				case dl := <-dead:
+					require.Equal(t, wh.ID, dl.WebhookID)
+					require.Equal(t, 3, dl.Attempts)
+				case <-time.After(time.Second):
+					t.Fatal("expected a dead letter")
+				}
+			}
+			// nothing else is sent
+			time.Sleep(10 * time.Millisecond)
+			require.Len(t, sent, 0)
+			require.Len(t, dead, 0)
+
+			if tc.expectedSends > 0 {
+				call := sender.SendCalls()[0]
+				require.Equal(t, domain.CarCreatedEventName, call.Event)
+				var payload app.WebhookPayload
+				require.NoError(t, json.Unmarshal(call.Payload, &payload))
+				require.Equal(t, domain.CarCreatedEventName, payload.Event)
+				require.Equal(t, car.ID(), payload.AggregateID)
+				require.NotEmpty(t, payload.Data)
+			}
+		})
+	}
+
+	t.Run(`Given an event delivered again by the outbox, when it's handled,
+		then its payload keeps the ID and the time it happened, and other events get another ID`, func(t *testing.T) {
+		var (
+			sent = make(chan []byte, 10)
+			wr   = &WebhooksRepositoryMock{
+				FindByEventFunc: func(_ context.Context, _ string) ([]app.Webhook, error) {
+					return []app.Webhook{wh}, nil
+				},
+			}
+			sender = &WebhookSenderMock{
+				SendFunc: func(_ context.Context, _ app.Webhook, _ string, payload []byte) error {
+					sent <- payload
+					return nil
+				},
+			}
+			ctx, cancel = context.WithCancel(context.Background())
+			ev          = domain.NewCarCreatedEvent(car)
+			other       = domain.NewCarCreatedEvent(car)
+		)
+		defer cancel()
+
+		body, err := domain.EventBody(ev)
+		require.NoError(t, err)
+		redelivered, err := domain.ParseEvent(ev.Name(), ev.AggregateID(), body)
+		require.NoError(t, err)
+
+		wn := app.NewWebhooksNotifier(wr, sender, log.New(io.Discard, "", 0), 3, time.Millisecond)
+		go wn.Run(ctx)
+		payloads := make([]app.WebhookPayload, 0, 3)
+		for _, e := range []events.Event{ev, redelivered, other} {
+			wn.Handler()(e)
+			select {
+			case b := <-sent:
+				var payload app.WebhookPayload
+				require.NoError(t, json.Unmarshal(b, &payload))
+				payloads = append(payloads, payload)
+			case <-time.After(time.Second):
+				t.Fatal("expected a send")
+			}
+		}
+		require.NotEqual(t, uuid.Nil, payloads[0].ID)
+		require.Equal(t, payloads[0].ID, payloads[1].ID)
+		require.NotEqual(t, payloads[0].ID, payloads[2].ID)
+		require.False(t, payloads[0].OccurredAt.IsZero())
+		require.True(t, payloads[0].OccurredAt.Equal(payloads[1].OccurredAt))
+	})
+
+	t.Run(`Given a webhook that is being retried, when an event is handled,
+		then it's sent to the other webhooks without waiting for it`, func(t *testing.T) {
+		var (
+			failing = app.Webhook{ID: uuid.New(), URL: "https://failing.example.com", Events: wh.Events, Secret: "secret"}
+			sent    = make(chan app.Webhook, 10)
+			wr      = &WebhooksRepositoryMock{
+				FindByEventFunc: func(_ context.Context, _ string) ([]app.Webhook, error) {
+					return []app.Webhook{failing, wh}, nil
+				},
+			}
+			sender = &WebhookSenderMock{
+				SendFunc: func(_ context.Context, w app.Webhook, _ string, _ []byte) error {
+					sent <- w
+					if w.ID == failing.ID {
+						return randomErr
+					}
+					return nil
+				},
+			}
+			ctx, cancel = context.WithCancel(context.Background())
+			done        = make(chan struct{})
+		)
+
+		// the failing webhook waits an hour before its next attempt
+		wn := app.NewWebhooksNotifier(wr, sender, log.New(io.Discard, "", 0), 3, time.Hour)
+		go func() {
+			wn.Run(ctx)
+			close(done)
+		}()
+		wn.Handler()(domain.NewCarCreatedEvent(car))
+
+		received := map[uuid.UUID]bool{}
+		for len(received) < 2 {
+			select {
+			case w := <-sent:
+				received[w.ID] = true
+			case <-time.After(time.Second):
+				t.Fatalf("expected a send to both webhooks, got %d", len(received))
+			}
+		}
+
+		cancel()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("expected Run to return once the context is cancelled")
+		}
+	})
+}
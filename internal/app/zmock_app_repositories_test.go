@@ -9,6 +9,7 @@ import (
 	"sync"
 	"theskyinflames/car-sharing/internal/app"
 	"theskyinflames/car-sharing/internal/domain"
+	"time"
 )
 
 // Ensure, that GroupsRepositoryMock does implement app.GroupsRepository.
@@ -363,6 +364,9 @@ var _ app.CarsRepository = &CarsRepositoryMock{}
 //			RemoveAllFunc: func(ctx context.Context) error {
 //				panic("mock out the RemoveAll method")
 //			},
+//			RemoveByIDFunc: func(ctx context.Context, ID uuid.UUID) error {
+//				panic("mock out the RemoveByID method")
+//			},
 //			UpdateFunc: func(ctx context.Context, car domain.Car) error {
 //				panic("mock out the Update method")
 //			},
@@ -385,6 +389,9 @@ type CarsRepositoryMock struct {
 	// RemoveAllFunc mocks the RemoveAll method.
 	RemoveAllFunc func(ctx context.Context) error
 
+	// RemoveByIDFunc mocks the RemoveByID method.
+	RemoveByIDFunc func(ctx context.Context, ID uuid.UUID) error
+
 	// UpdateFunc mocks the Update method.
 	UpdateFunc func(ctx context.Context, car domain.Car) error
 
@@ -414,6 +421,13 @@ type CarsRepositoryMock struct {
 			// Ctx is the ctx argument value.
 			Ctx context.Context
 		}
+		// RemoveByID holds details about calls to the RemoveByID method.
+		RemoveByID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the ID argument value.
+			ID uuid.UUID
+		}
 		// Update holds details about calls to the Update method.
 		Update []struct {
 			// Ctx is the ctx argument value.
@@ -422,11 +436,12 @@ type CarsRepositoryMock struct {
 			Car domain.Car
 		}
 	}
-	lockAddAll    sync.RWMutex
-	lockFindAll   sync.RWMutex
-	lockFindByID  sync.RWMutex
-	lockRemoveAll sync.RWMutex
-	lockUpdate    sync.RWMutex
+	lockAddAll     sync.RWMutex
+	lockFindAll    sync.RWMutex
+	lockFindByID   sync.RWMutex
+	lockRemoveAll  sync.RWMutex
+	lockRemoveByID sync.RWMutex
+	lockUpdate     sync.RWMutex
 }
 
 // AddAll calls AddAllFunc.
@@ -579,6 +594,45 @@ func (mock *CarsRepositoryMock) RemoveAllCalls() []struct {
 	return calls
 }
 
+// RemoveByID calls RemoveByIDFunc.
+func (mock *CarsRepositoryMock) RemoveByID(ctx context.Context, ID uuid.UUID) error {
+	callInfo := struct {
+		Ctx context.Context
+		ID  uuid.UUID
+	}{
+		Ctx: ctx,
+		ID:  ID,
+	}
+	mock.lockRemoveByID.Lock()
+	mock.calls.RemoveByID = append(mock.calls.RemoveByID, callInfo)
+	mock.lockRemoveByID.Unlock()
+	if mock.RemoveByIDFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.RemoveByIDFunc(ctx, ID)
+}
+
+// RemoveByIDCalls gets all the calls that were made to RemoveByID.
+// Check the length with:
+//
+//	len(mockedCarsRepository.RemoveByIDCalls())
+func (mock *CarsRepositoryMock) RemoveByIDCalls() []struct {
+	Ctx context.Context
+	ID  uuid.UUID
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  uuid.UUID
+	}
+	mock.lockRemoveByID.RLock()
+	calls = mock.calls.RemoveByID
+	mock.lockRemoveByID.RUnlock()
+	return calls
+}
+
 // Update calls UpdateFunc.
 func (mock *CarsRepositoryMock) Update(ctx context.Context, car domain.Car) error {
 	callInfo := struct {
@@ -617,3 +671,416 @@ func (mock *CarsRepositoryMock) UpdateCalls() []struct {
 	mock.lockUpdate.RUnlock()
 	return calls
 }
+
+// Ensure, that JourneysHistoryRepositoryMock does implement app.JourneysHistoryRepository.
+// If this is not the case, regenerate this file with moq.
+var _ app.JourneysHistoryRepository = &JourneysHistoryRepositoryMock{}
+
+// JourneysHistoryRepositoryMock is a mock implementation of app.JourneysHistoryRepository.
+//
+//	func TestSomethingThatUsesJourneysHistoryRepository(t *testing.T) {
+//
+//		// make and configure a mocked app.JourneysHistoryRepository
+//		mockedJourneysHistoryRepository := &JourneysHistoryRepositoryMock{
+//			FindByGroupIDFunc: func(ctx context.Context, groupID uuid.UUID) (app.JourneyRecord, error) {
+//				panic("mock out the FindByGroupID method")
+//			},
+//			FindWaitEndedSinceFunc: func(ctx context.Context, since time.Time) ([]app.JourneyRecord, error) {
+//				panic("mock out the FindWaitEndedSince method")
+//			},
+//			SaveFunc: func(ctx context.Context, r app.JourneyRecord) error {
+//				panic("mock out the Save method")
+//			},
+//		}
+//
+//		// use mockedJourneysHistoryRepository in code that requires app.JourneysHistoryRepository
+//		// and then make assertions.
+//
+//	}
+type JourneysHistoryRepositoryMock struct {
+	// FindByGroupIDFunc mocks the FindByGroupID method.
+	FindByGroupIDFunc func(ctx context.Context, groupID uuid.UUID) (app.JourneyRecord, error)
+
+	// FindWaitEndedSinceFunc mocks the FindWaitEndedSince method.
+	FindWaitEndedSinceFunc func(ctx context.Context, since time.Time) ([]app.JourneyRecord, error)
+
+	// SaveFunc mocks the Save method.
+	SaveFunc func(ctx context.Context, r app.JourneyRecord) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// FindByGroupID holds details about calls to the FindByGroupID method.
+		FindByGroupID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// GroupID is the groupID argument value.
+			GroupID uuid.UUID
+		}
+		// FindWaitEndedSince holds details about calls to the FindWaitEndedSince method.
+		FindWaitEndedSince []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Since is the since argument value.
+			Since time.Time
+		}
+		// Save holds details about calls to the Save method.
+		Save []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// R is the r argument value.
+			R app.JourneyRecord
+		}
+	}
+	lockFindByGroupID      sync.RWMutex
+	lockFindWaitEndedSince sync.RWMutex
+	lockSave               sync.RWMutex
+}
+
+// FindByGroupID calls FindByGroupIDFunc.
+func (mock *JourneysHistoryRepositoryMock) FindByGroupID(ctx context.Context, groupID uuid.UUID) (app.JourneyRecord, error) {
+	callInfo := struct {
+		Ctx     context.Context
+		GroupID uuid.UUID
+	}{
+		Ctx:     ctx,
+		GroupID: groupID,
+	}
+	mock.lockFindByGroupID.Lock()
+	mock.calls.FindByGroupID = append(mock.calls.FindByGroupID, callInfo)
+	mock.lockFindByGroupID.Unlock()
+	if mock.FindByGroupIDFunc == nil {
+		var (
+			journeyRecordOut app.JourneyRecord
+			errOut           error
+		)
+		return journeyRecordOut, errOut
+	}
+	return mock.FindByGroupIDFunc(ctx, groupID)
+}
+
+// FindByGroupIDCalls gets all the calls that were made to FindByGroupID.
+// Check the length with:
+//
+//	len(mockedJourneysHistoryRepository.FindByGroupIDCalls())
+func (mock *JourneysHistoryRepositoryMock) FindByGroupIDCalls() []struct {
+	Ctx     context.Context
+	GroupID uuid.UUID
+} {
+	var calls []struct {
+		Ctx     context.Context
+		GroupID uuid.UUID
+	}
+	mock.lockFindByGroupID.RLock()
+	calls = mock.calls.FindByGroupID
+	mock.lockFindByGroupID.RUnlock()
+	return calls
+}
+
+// FindWaitEndedSince calls FindWaitEndedSinceFunc.
+func (mock *JourneysHistoryRepositoryMock) FindWaitEndedSince(ctx context.Context, since time.Time) ([]app.JourneyRecord, error) {
+	callInfo := struct {
+		Ctx   context.Context
+		Since time.Time
+	}{
+		Ctx:   ctx,
+		Since: since,
+	}
+	mock.lockFindWaitEndedSince.Lock()
+	mock.calls.FindWaitEndedSince = append(mock.calls.FindWaitEndedSince, callInfo)
+	mock.lockFindWaitEndedSince.Unlock()
+	if mock.FindWaitEndedSinceFunc == nil {
+		var (
+			journeyRecordsOut []app.JourneyRecord
+			errOut            error
+		)
+		return journeyRecordsOut, errOut
+	}
+	return mock.FindWaitEndedSinceFunc(ctx, since)
+}
+
+// FindWaitEndedSinceCalls gets all the calls that were made to FindWaitEndedSince.
+// Check the length with:
+//
+//	len(mockedJourneysHistoryRepository.FindWaitEndedSinceCalls())
+func (mock *JourneysHistoryRepositoryMock) FindWaitEndedSinceCalls() []struct {
+	Ctx   context.Context
+	Since time.Time
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Since time.Time
+	}
+	mock.lockFindWaitEndedSince.RLock()
+	calls = mock.calls.FindWaitEndedSince
+	mock.lockFindWaitEndedSince.RUnlock()
+	return calls
+}
+
+// Save calls SaveFunc.
+func (mock *JourneysHistoryRepositoryMock) Save(ctx context.Context, r app.JourneyRecord) error {
+	callInfo := struct {
+		Ctx context.Context
+		R   app.JourneyRecord
+	}{
+		Ctx: ctx,
+		R:   r,
+	}
+	mock.lockSave.Lock()
+	mock.calls.Save = append(mock.calls.Save, callInfo)
+	mock.lockSave.Unlock()
+	if mock.SaveFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.SaveFunc(ctx, r)
+}
+
+// SaveCalls gets all the calls that were made to Save.
+// Check the length with:
+//
+//	len(mockedJourneysHistoryRepository.SaveCalls())
+func (mock *JourneysHistoryRepositoryMock) SaveCalls() []struct {
+	Ctx context.Context
+	R   app.JourneyRecord
+} {
+	var calls []struct {
+		Ctx context.Context
+		R   app.JourneyRecord
+	}
+	mock.lockSave.RLock()
+	calls = mock.calls.Save
+	mock.lockSave.RUnlock()
+	return calls
+}
+
+// Ensure, that ReservationsRepositoryMock does implement app.ReservationsRepository.
+// If this is not the case, regenerate this file with moq.
+var _ app.ReservationsRepository = &ReservationsRepositoryMock{}
+
+// ReservationsRepositoryMock is a mock implementation of app.ReservationsRepository.
+//
+//	func TestSomethingThatUsesReservationsRepository(t *testing.T) {
+//
+//		// make and configure a mocked app.ReservationsRepository
+//		mockedReservationsRepository := &ReservationsRepositoryMock{
+//			AddFunc: func(ctx context.Context, r domain.Reservation) error {
+//				panic("mock out the Add method")
+//			},
+//			FindAllFunc: func(ctx context.Context) ([]domain.Reservation, error) {
+//				panic("mock out the FindAll method")
+//			},
+//			FindByIDFunc: func(ctx context.Context, ID uuid.UUID) (domain.Reservation, error) {
+//				panic("mock out the FindByID method")
+//			},
+//			RemoveByIDFunc: func(ctx context.Context, ID uuid.UUID) error {
+//				panic("mock out the RemoveByID method")
+//			},
+//		}
+//
+//		// use mockedReservationsRepository in code that requires app.ReservationsRepository
+//		// and then make assertions.
+//
+//	}
+type ReservationsRepositoryMock struct {
+	// AddFunc mocks the Add method.
+	AddFunc func(ctx context.Context, r domain.Reservation) error
+
+	// FindAllFunc mocks the FindAll method.
+	FindAllFunc func(ctx context.Context) ([]domain.Reservation, error)
+
+	// FindByIDFunc mocks the FindByID method.
+	FindByIDFunc func(ctx context.Context, ID uuid.UUID) (domain.Reservation, error)
+
+	// RemoveByIDFunc mocks the RemoveByID method.
+	RemoveByIDFunc func(ctx context.Context, ID uuid.UUID) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Add holds details about calls to the Add method.
+		Add []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// R is the r argument value.
+			R domain.Reservation
+		}
+		// FindAll holds details about calls to the FindAll method.
+		FindAll []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+		// FindByID holds details about calls to the FindByID method.
+		FindByID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the ID argument value.
+			ID uuid.UUID
+		}
+		// RemoveByID holds details about calls to the RemoveByID method.
+		RemoveByID []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the ID argument value.
+			ID uuid.UUID
+		}
+	}
+	lockAdd        sync.RWMutex
+	lockFindAll    sync.RWMutex
+	lockFindByID   sync.RWMutex
+	lockRemoveByID sync.RWMutex
+}
+
+// Add calls AddFunc.
+func (mock *ReservationsRepositoryMock) Add(ctx context.Context, r domain.Reservation) error {
+	callInfo := struct {
+		Ctx context.Context
+		R   domain.Reservation
+	}{
+		Ctx: ctx,
+		R:   r,
+	}
+	mock.lockAdd.Lock()
+	mock.calls.Add = append(mock.calls.Add, callInfo)
+	mock.lockAdd.Unlock()
+	if mock.AddFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.AddFunc(ctx, r)
+}
+
+// AddCalls gets all the calls that were made to Add.
+// Check the length with:
+//
+//	len(mockedReservationsRepository.AddCalls())
+func (mock *ReservationsRepositoryMock) AddCalls() []struct {
+	Ctx context.Context
+	R   domain.Reservation
+} {
+	var calls []struct {
+		Ctx context.Context
+		R   domain.Reservation
+	}
+	mock.lockAdd.RLock()
+	calls = mock.calls.Add
+	mock.lockAdd.RUnlock()
+	return calls
+}
+
+// FindAll calls FindAllFunc.
+func (mock *ReservationsRepositoryMock) FindAll(ctx context.Context) ([]domain.Reservation, error) {
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockFindAll.Lock()
+	mock.calls.FindAll = append(mock.calls.FindAll, callInfo)
+	mock.lockFindAll.Unlock()
+	if mock.FindAllFunc == nil {
+		var (
+			reservationsOut []domain.Reservation
+			errOut          error
+		)
+		return reservationsOut, errOut
+	}
+	return mock.FindAllFunc(ctx)
+}
+
+// FindAllCalls gets all the calls that were made to FindAll.
+// Check the length with:
+//
+//	len(mockedReservationsRepository.FindAllCalls())
+func (mock *ReservationsRepositoryMock) FindAllCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockFindAll.RLock()
+	calls = mock.calls.FindAll
+	mock.lockFindAll.RUnlock()
+	return calls
+}
+
+// FindByID calls FindByIDFunc.
+func (mock *ReservationsRepositoryMock) FindByID(ctx context.Context, ID uuid.UUID) (domain.Reservation, error) {
+	callInfo := struct {
+		Ctx context.Context
+		ID  uuid.UUID
+	}{
+		Ctx: ctx,
+		ID:  ID,
+	}
+	mock.lockFindByID.Lock()
+	mock.calls.FindByID = append(mock.calls.FindByID, callInfo)
+	mock.lockFindByID.Unlock()
+	if mock.FindByIDFunc == nil {
+		var (
+			reservationOut domain.Reservation
+			errOut         error
+		)
+		return reservationOut, errOut
+	}
+	return mock.FindByIDFunc(ctx, ID)
+}
+
+// FindByIDCalls gets all the calls that were made to FindByID.
+// Check the length with:
+//
+//	len(mockedReservationsRepository.FindByIDCalls())
+func (mock *ReservationsRepositoryMock) FindByIDCalls() []struct {
+	Ctx context.Context
+	ID  uuid.UUID
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  uuid.UUID
+	}
+	mock.lockFindByID.RLock()
+	calls = mock.calls.FindByID
+	mock.lockFindByID.RUnlock()
+	return calls
+}
+
+// RemoveByID calls RemoveByIDFunc.
+func (mock *ReservationsRepositoryMock) RemoveByID(ctx context.Context, ID uuid.UUID) error {
+	callInfo := struct {
+		Ctx context.Context
+		ID  uuid.UUID
+	}{
+		Ctx: ctx,
+		ID:  ID,
+	}
+	mock.lockRemoveByID.Lock()
+	mock.calls.RemoveByID = append(mock.calls.RemoveByID, callInfo)
+	mock.lockRemoveByID.Unlock()
+	if mock.RemoveByIDFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.RemoveByIDFunc(ctx, ID)
+}
+
+// RemoveByIDCalls gets all the calls that were made to RemoveByID.
+// Check the length with:
+//
+//	len(mockedReservationsRepository.RemoveByIDCalls())
+func (mock *ReservationsRepositoryMock) RemoveByIDCalls() []struct {
+	Ctx context.Context
+	ID  uuid.UUID
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  uuid.UUID
+	}
+	mock.lockRemoveByID.RLock()
+	calls = mock.calls.RemoveByID
+	mock.lockRemoveByID.RUnlock()
+	return calls
+}
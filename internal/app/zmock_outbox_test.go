@@ -0,0 +1,194 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package app_test
+
+import (
+	"context"
+	"github.com/google/uuid"
+	"github.com/theskyinflames/cqrs-eda/pkg/events"
+	"sync"
+	"theskyinflames/car-sharing/internal/app"
+)
+
+// Ensure, that OutboxMock does implement app.Outbox.
+// If this is not the case, regenerate this file with moq.
+var _ app.Outbox = &OutboxMock{}
+
+// OutboxMock is a mock implementation of app.Outbox.
+//
+//	func TestSomethingThatUsesOutbox(t *testing.T) {
+//
+//		// make and configure a mocked app.Outbox
+//		mockedOutbox := &OutboxMock{
+//			AddFunc: func(ctx context.Context, evs []events.Event) error {
+//				panic("mock out the Add method")
+//			},
+//			MarkDeliveredFunc: func(ctx context.Context, id uuid.UUID) error {
+//				panic("mock out the MarkDelivered method")
+//			},
+//			PendingFunc: func(ctx context.Context, limit int) ([]app.OutboxMessage, error) {
+//				panic("mock out the Pending method")
+//			},
+//		}
+//
+//		// use mockedOutbox in code that requires app.Outbox
+//		// and then make assertions.
+//
+//	}
+type OutboxMock struct {
+	// AddFunc mocks the Add method.
+	AddFunc func(ctx context.Context, evs []events.Event) error
+
+	// MarkDeliveredFunc mocks the MarkDelivered method.
+	MarkDeliveredFunc func(ctx context.Context, id uuid.UUID) error
+
+	// PendingFunc mocks the Pending method.
+	PendingFunc func(ctx context.Context, limit int) ([]app.OutboxMessage, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Add holds details about calls to the Add method.
+		Add []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Evs is the evs argument value.
+			Evs []events.Event
+		}
+		// MarkDelivered holds details about calls to the MarkDelivered method.
+		MarkDelivered []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// ID is the id argument value.
+			ID uuid.UUID
+		}
+		// Pending holds details about calls to the Pending method.
+		Pending []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Limit is the limit argument value.
+			Limit int
+		}
+	}
+	lockAdd           sync.RWMutex
+	lockMarkDelivered sync.RWMutex
+	lockPending       sync.RWMutex
+}
+
+// Add calls AddFunc.
+func (mock *OutboxMock) Add(ctx context.Context, evs []events.Event) error {
+	callInfo := struct {
+		Ctx context.Context
+		Evs []events.Event
+	}{
+		Ctx: ctx,
+		Evs: evs,
+	}
+	mock.lockAdd.Lock()
+	mock.calls.Add = append(mock.calls.Add, callInfo)
+	mock.lockAdd.Unlock()
+	if mock.AddFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.AddFunc(ctx, evs)
+}
+
+// AddCalls gets all the calls that were made to Add.
+// Check the length with:
+//
+//	len(mockedOutbox.AddCalls())
+func (mock *OutboxMock) AddCalls() []struct {
+	Ctx context.Context
+	Evs []events.Event
+} {
+	var calls []struct {
+		Ctx context.Context
+		Evs []events.Event
+	}
+	mock.lockAdd.RLock()
+	calls = mock.calls.Add
+	mock.lockAdd.RUnlock()
+	return calls
+}
+
+// MarkDelivered calls MarkDeliveredFunc.
+func (mock *OutboxMock) MarkDelivered(ctx context.Context, id uuid.UUID) error {
+	callInfo := struct {
+		Ctx context.Context
+		ID  uuid.UUID
+	}{
+		Ctx: ctx,
+		ID:  id,
+	}
+	mock.lockMarkDelivered.Lock()
+	mock.calls.MarkDelivered = append(mock.calls.MarkDelivered, callInfo)
+	mock.lockMarkDelivered.Unlock()
+	if mock.MarkDeliveredFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.MarkDeliveredFunc(ctx, id)
+}
+
+// MarkDeliveredCalls gets all the calls that were made to MarkDelivered.
+// Check the length with:
+//
+//	len(mockedOutbox.MarkDeliveredCalls())
+func (mock *OutboxMock) MarkDeliveredCalls() []struct {
+	Ctx context.Context
+	ID  uuid.UUID
+} {
+	var calls []struct {
+		Ctx context.Context
+		ID  uuid.UUID
+	}
+	mock.lockMarkDelivered.RLock()
+	calls = mock.calls.MarkDelivered
+	mock.lockMarkDelivered.RUnlock()
+	return calls
+}
+
+// Pending calls PendingFunc.
+func (mock *OutboxMock) Pending(ctx context.Context, limit int) ([]app.OutboxMessage, error) {
+	callInfo := struct {
+		Ctx   context.Context
+		Limit int
+	}{
+		Ctx:   ctx,
+		Limit: limit,
+	}
+	mock.lockPending.Lock()
+	mock.calls.Pending = append(mock.calls.Pending, callInfo)
+	mock.lockPending.Unlock()
+	if mock.PendingFunc == nil {
+		var (
+			outboxMessagesOut []app.OutboxMessage
+			errOut            error
+		)
+		return outboxMessagesOut, errOut
+	}
+	return mock.PendingFunc(ctx, limit)
+}
+
+// PendingCalls gets all the calls that were made to Pending.
+// Check the length with:
+//
+//	len(mockedOutbox.PendingCalls())
+func (mock *OutboxMock) PendingCalls() []struct {
+	Ctx   context.Context
+	Limit int
+} {
+	var calls []struct {
+		Ctx   context.Context
+		Limit int
+	}
+	mock.lockPending.RLock()
+	calls = mock.calls.Pending
+	mock.lockPending.RUnlock()
+	return calls
+}
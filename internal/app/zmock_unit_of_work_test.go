@@ -0,0 +1,85 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package app_test
+
+import (
+	"context"
+	"sync"
+	"theskyinflames/car-sharing/internal/app"
+)
+
+// Ensure, that UnitOfWorkMock does implement app.UnitOfWork.
+// If this is not the case, regenerate this file with moq.
+var _ app.UnitOfWork = &UnitOfWorkMock{}
+
+// UnitOfWorkMock is a mock implementation of app.UnitOfWork.
+//
+//	func TestSomethingThatUsesUnitOfWork(t *testing.T) {
+//
+//		// make and configure a mocked app.UnitOfWork
+//		mockedUnitOfWork := &UnitOfWorkMock{
+//			DoFunc: func(ctx context.Context, fn func(ctx context.Context) error) error {
+//				panic("mock out the Do method")
+//			},
+//		}
+//
+//		// use mockedUnitOfWork in code that requires app.UnitOfWork
+//		// and then make assertions.
+//
+//	}
+type UnitOfWorkMock struct {
+	// DoFunc mocks the Do method.
+	DoFunc func(ctx context.Context, fn func(ctx context.Context) error) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Do holds details about calls to the Do method.
+		Do []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Fn is the fn argument value.
+			Fn func(ctx context.Context) error
+		}
+	}
+	lockDo sync.RWMutex
+}
+
+// Do calls DoFunc.
+func (mock *UnitOfWorkMock) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	callInfo := struct {
+		Ctx context.Context
+		Fn  func(ctx context.Context) error
+	}{
+		Ctx: ctx,
+		Fn:  fn,
+	}
+	mock.lockDo.Lock()
+	mock.calls.Do = append(mock.calls.Do, callInfo)
+	mock.lockDo.Unlock()
+	if mock.DoFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.DoFunc(ctx, fn)
+}
+
+// DoCalls gets all the calls that were made to Do.
+// Check the length with:
+//
+//	len(mockedUnitOfWork.DoCalls())
+func (mock *UnitOfWorkMock) DoCalls() []struct {
+	Ctx context.Context
+	Fn  func(ctx context.Context) error
+} {
+	var calls []struct {
+		Ctx context.Context
+		Fn  func(ctx context.Context) error
+	}
+	mock.lockDo.RLock()
+	calls = mock.calls.Do
+	mock.lockDo.RUnlock()
+	return calls
+}
@@ -0,0 +1,327 @@
+// Code generated by moq; DO NOT EDIT.
+// github.com/matryer/moq
+
+package app_test
+
+import (
+	"context"
+	"sync"
+	"theskyinflames/car-sharing/internal/app"
+)
+
+// Ensure, that WebhooksRepositoryMock does implement app.WebhooksRepository.
+// If this is not the case, regenerate this file with moq.
+var _ app.WebhooksRepository = &WebhooksRepositoryMock{}
+
+// WebhooksRepositoryMock is a mock implementation of app.WebhooksRepository.
+//
+//	func TestSomethingThatUsesWebhooksRepository(t *testing.T) {
+//
+//		// make and configure a mocked app.WebhooksRepository
+//		mockedWebhooksRepository := &WebhooksRepositoryMock{
+//			AddFunc: func(ctx context.Context, wh app.Webhook) error {
+//				panic("mock out the Add method")
+//			},
+//			AddDeadLetterFunc: func(ctx context.Context, dl app.DeadLetter) error {
+//				panic("mock out the AddDeadLetter method")
+//			},
+//			FindByEventFunc: func(ctx context.Context, name string) ([]app.Webhook, error) {
+//				panic("mock out the FindByEvent method")
+//			},
+//			FindDeadLettersFunc: func(ctx context.Context) ([]app.DeadLetter, error) {
+//				panic("mock out the FindDeadLetters method")
+//			},
+//		}
+//
+//		// use mockedWebhooksRepository in code that requires app.WebhooksRepository
+//		// and then make assertions.
+//
+//	}
+type WebhooksRepositoryMock struct {
+	// AddFunc mocks the Add method.
+	AddFunc func(ctx context.Context, wh app.Webhook) error
+
+	// AddDeadLetterFunc mocks the AddDeadLetter method.
+	AddDeadLetterFunc func(ctx context.Context, dl app.DeadLetter) error
+
+	// FindByEventFunc mocks the FindByEvent method.
+	FindByEventFunc func(ctx context.Context, name string) ([]app.Webhook, error)
+
+	// FindDeadLettersFunc mocks the FindDeadLetters method.
+	FindDeadLettersFunc func(ctx context.Context) ([]app.DeadLetter, error)
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Add holds details about calls to the Add method.
+		Add []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Wh is the wh argument value.
+			Wh app.Webhook
+		}
+		// AddDeadLetter holds details about calls to the AddDeadLetter method.
+		AddDeadLetter []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Dl is the dl argument value.
+			Dl app.DeadLetter
+		}
+		// FindByEvent holds details about calls to the FindByEvent method.
+		FindByEvent []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Name is the name argument value.
+			Name string
+		}
+		// FindDeadLetters holds details about calls to the FindDeadLetters method.
+		FindDeadLetters []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+		}
+	}
+	lockAdd             sync.RWMutex
+	lockAddDeadLetter   sync.RWMutex
+	lockFindByEvent     sync.RWMutex
+	lockFindDeadLetters sync.RWMutex
+}
+
+// Add calls AddFunc.
+func (mock *WebhooksRepositoryMock) Add(ctx context.Context, wh app.Webhook) error {
+	callInfo := struct {
+		Ctx context.Context
+		Wh  app.Webhook
+	}{
+		Ctx: ctx,
+		Wh:  wh,
+	}
+	mock.lockAdd.Lock()
+	mock.calls.Add = append(mock.calls.Add, callInfo)
+	mock.lockAdd.Unlock()
+	if mock.AddFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.AddFunc(ctx, wh)
+}
+
+// AddCalls gets all the calls that were made to Add.
+// Check the length with:
+//
+//	len(mockedWebhooksRepository.AddCalls())
+func (mock *WebhooksRepositoryMock) AddCalls() []struct {
+	Ctx context.Context
+	Wh  app.Webhook
+} {
+	var calls []struct {
+		Ctx context.Context
+		Wh  app.Webhook
+	}
+	mock.lockAdd.RLock()
+	calls = mock.calls.Add
+	mock.lockAdd.RUnlock()
+	return calls
+}
+
+// AddDeadLetter calls AddDeadLetterFunc.
+func (mock *WebhooksRepositoryMock) AddDeadLetter(ctx context.Context, dl app.DeadLetter) error {
+	callInfo := struct {
+		Ctx context.Context
+		Dl  app.DeadLetter
+	}{
+		Ctx: ctx,
+		Dl:  dl,
+	}
+	mock.lockAddDeadLetter.Lock()
+	mock.calls.AddDeadLetter = append(mock.calls.AddDeadLetter, callInfo)
+	mock.lockAddDeadLetter.Unlock()
+	if mock.AddDeadLetterFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.AddDeadLetterFunc(ctx, dl)
+}
+
+// AddDeadLetterCalls gets all the calls that were made to AddDeadLetter.
+// Check the length with:
+//
+//	len(mockedWebhooksRepository.AddDeadLetterCalls())
+func (mock *WebhooksRepositoryMock) AddDeadLetterCalls() []struct {
+	Ctx context.Context
+	Dl  app.DeadLetter
+} {
+	var calls []struct {
+		Ctx context.Context
+		Dl  app.DeadLetter
+	}
+	mock.lockAddDeadLetter.RLock()
+	calls = mock.calls.AddDeadLetter
+	mock.lockAddDeadLetter.RUnlock()
+	return calls
+}
+
+// FindByEvent calls FindByEventFunc.
+func (mock *WebhooksRepositoryMock) FindByEvent(ctx context.Context, name string) ([]app.Webhook, error) {
+	callInfo := struct {
+		Ctx  context.Context
+		Name string
+	}{
+		Ctx:  ctx,
+		Name: name,
+	}
+	mock.lockFindByEvent.Lock()
+	mock.calls.FindByEvent = append(mock.calls.FindByEvent, callInfo)
+	mock.lockFindByEvent.Unlock()
+	if mock.FindByEventFunc == nil {
+		var (
+			webhooksOut []app.Webhook
+			errOut      error
+		)
+		return webhooksOut, errOut
+	}
+	return mock.FindByEventFunc(ctx, name)
+}
+
+// FindByEventCalls gets all the calls that were made to FindByEvent.
+// Check the length with:
+//
+//	len(mockedWebhooksRepository.FindByEventCalls())
+func (mock *WebhooksRepositoryMock) FindByEventCalls() []struct {
+	Ctx  context.Context
+	Name string
+} {
+	var calls []struct {
+		Ctx  context.Context
+		Name string
+	}
+	mock.lockFindByEvent.RLock()
+	calls = mock.calls.FindByEvent
+	mock.lockFindByEvent.RUnlock()
+	return calls
+}
+
+// FindDeadLetters calls FindDeadLettersFunc.
+func (mock *WebhooksRepositoryMock) FindDeadLetters(ctx context.Context) ([]app.DeadLetter, error) {
+	callInfo := struct {
+		Ctx context.Context
+	}{
+		Ctx: ctx,
+	}
+	mock.lockFindDeadLetters.Lock()
+	mock.calls.FindDeadLetters = append(mock.calls.FindDeadLetters, callInfo)
+	mock.lockFindDeadLetters.Unlock()
+	if mock.FindDeadLettersFunc == nil {
+		var (
+			deadLettersOut []app.DeadLetter
+			errOut         error
+		)
+		return deadLettersOut, errOut
+	}
+	return mock.FindDeadLettersFunc(ctx)
+}
+
+// FindDeadLettersCalls gets all the calls that were made to FindDeadLetters.
+// Check the length with:
+//
+//	len(mockedWebhooksRepository.FindDeadLettersCalls())
+func (mock *WebhooksRepositoryMock) FindDeadLettersCalls() []struct {
+	Ctx context.Context
+} {
+	var calls []struct {
+		Ctx context.Context
+	}
+	mock.lockFindDeadLetters.RLock()
+	calls = mock.calls.FindDeadLetters
+	mock.lockFindDeadLetters.RUnlock()
+	return calls
+}
+
+// Ensure, that WebhookSenderMock does implement app.WebhookSender.
+// If this is not the case, regenerate this file with moq.
+var _ app.WebhookSender = &WebhookSenderMock{}
+
+// WebhookSenderMock is a mock implementation of app.WebhookSender.
+//
+//	func TestSomethingThatUsesWebhookSender(t *testing.T) {
+//
+//		// make and configure a mocked app.WebhookSender
+//		mockedWebhookSender := &WebhookSenderMock{
+//			SendFunc: func(ctx context.Context, wh app.Webhook, event string, payload []byte) error {
+//				panic("mock out the Send method")
+//			},
+//		}
+//
+//		// use mockedWebhookSender in code that requires app.WebhookSender
+//		// and then make assertions.
+//
+//	}
+type WebhookSenderMock struct {
+	// SendFunc mocks the Send method.
+	SendFunc func(ctx context.Context, wh app.Webhook, event string, payload []byte) error
+
+	// calls tracks calls to the methods.
+	calls struct {
+		// Send holds details about calls to the Send method.
+		Send []struct {
+			// Ctx is the ctx argument value.
+			Ctx context.Context
+			// Wh is the wh argument value.
+			Wh app.Webhook
+			// Event is the event argument value.
+			Event string
+			// Payload is the payload argument value.
+			Payload []byte
+		}
+	}
+	lockSend sync.RWMutex
+}
+
+// Send calls SendFunc.
+func (mock *WebhookSenderMock) Send(ctx context.Context, wh app.Webhook, event string, payload []byte) error {
+	callInfo := struct {
+		Ctx     context.Context
+		Wh      app.Webhook
+		Event   string
+		Payload []byte
+	}{
+		Ctx:     ctx,
+		Wh:      wh,
+		Event:   event,
+		Payload: payload,
+	}
+	mock.lockSend.Lock()
+	mock.calls.Send = append(mock.calls.Send, callInfo)
+	mock.lockSend.Unlock()
+	if mock.SendFunc == nil {
+		var (
+			errOut error
+		)
+		return errOut
+	}
+	return mock.SendFunc(ctx, wh, event, payload)
+}
+
+// SendCalls gets all the calls that were made to Send.
+// Check the length with:
+//
+//	len(mockedWebhookSender.SendCalls())
+func (mock *WebhookSenderMock) SendCalls() []struct {
+	Ctx     context.Context
+	Wh      app.Webhook
+	Event   string
+	Payload []byte
+} {
+	var calls []struct {
+		Ctx     context.Context
+		Wh      app.Webhook
+		Event   string
+		Payload []byte
+	}
+	mock.lockSend.RLock()
+	calls = mock.calls.Send
+	mock.lockSend.RUnlock()
+	return calls
+}
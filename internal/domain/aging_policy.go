@@ -0,0 +1,28 @@
+package domain
+
+import "time"
+
+// AgingPolicy prevents the big groups from being overtaken by the small ones indefinitely.
+// A group that has been waiting longer than MaxWait, or that has been overtaken MaxOvertakes times,
+// is starving. A starving group reserves the next car that frees seats and that is big enough for it.
+// A zero value disables the rule.
+type AgingPolicy struct {
+	MaxWait      time.Duration
+	MaxOvertakes int
+}
+
+// IsEnabled returns TRUE if any of the aging rules applies
+func (p AgingPolicy) IsEnabled() bool {
+	return p.MaxWait > 0 || p.MaxOvertakes > 0
+}
+
+// IsStarving returns TRUE if the group has to reserve a car
+func (p AgingPolicy) IsStarving(g Group, now time.Time) bool {
+	if g.IsOnJourney() {
+		return false
+	}
+	if p.MaxWait > 0 && now.Sub(g.RequestedAt()) >= p.MaxWait {
+		return true
+	}
+	return p.MaxOvertakes > 0 && g.Overtaken() >= p.MaxOvertakes
+}
@@ -0,0 +1,66 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"theskyinflames/car-sharing/internal/domain"
+	"theskyinflames/car-sharing/internal/fixtures"
+	"theskyinflames/car-sharing/internal/helpers"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestAgingPolicyIsStarving(t *testing.T) {
+	var (
+		now      = time.Now()
+		longAgo  = now.Add(-time.Hour)
+		recently = now.Add(-time.Second)
+	)
+	testCases := []struct {
+		name     string
+		policy   domain.AgingPolicy
+		g        domain.Group
+		expected bool
+	}{
+		{
+			name:     `Given a disabled aging policy, when it's called, then no group is starving`,
+			g:        fixtures.Group{RequestedAt: &longAgo, Overtaken: helpers.IntPtr(100)}.Build(),
+			expected: false,
+		},
+		{
+			name:     `Given a max waiting time, when a group has waited longer, then it's starving`,
+			policy:   domain.AgingPolicy{MaxWait: time.Minute},
+			g:        fixtures.Group{RequestedAt: &longAgo}.Build(),
+			expected: true,
+		},
+		{
+			name:     `Given a max waiting time, when a group has waited less, then it's not starving`,
+			policy:   domain.AgingPolicy{MaxWait: time.Minute},
+			g:        fixtures.Group{RequestedAt: &recently}.Build(),
+			expected: false,
+		},
+		{
+			name:     `Given a max number of overtakes, when a group has been overtaken so many times, then it's starving`,
+			policy:   domain.AgingPolicy{MaxOvertakes: 3},
+			g:        fixtures.Group{RequestedAt: &recently, Overtaken: helpers.IntPtr(3)}.Build(),
+			expected: true,
+		},
+		{
+			name:     `Given a max number of overtakes, when a group has been overtaken fewer times, then it's not starving`,
+			policy:   domain.AgingPolicy{MaxOvertakes: 3},
+			g:        fixtures.Group{RequestedAt: &recently, Overtaken: helpers.IntPtr(2)}.Build(),
+			expected: false,
+		},
+		{
+			name:     `Given an aging policy, when the group is on journey, then it's not starving`,
+			policy:   domain.AgingPolicy{MaxWait: time.Minute},
+			g:        fixtures.Group{RequestedAt: &longAgo, Car: helpers.EvPtr(fixtures.Car{}.Build())}.Build(),
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.expected, tc.policy.IsStarving(tc.g, now), tc.name)
+	}
+}
@@ -0,0 +1,110 @@
+package domain
+
+import "errors"
+
+// AssignmentStrategy chooses the car where a group gets on
+type AssignmentStrategy interface {
+	// Pick returns the index of the car where the group gets on, or -1 if no car can fit it
+	Pick(cars []Car, g Group) int
+}
+
+// Assignment strategies names
+const (
+	BestFitName  = "best-fit"
+	WorstFitName = "worst-fit"
+	FirstFitName = "first-fit"
+	NearestName  = "nearest"
+)
+
+// ErrUnknownAssignmentStrategy is self-described
+var ErrUnknownAssignmentStrategy = errors.New("unknown assignment strategy")
+
+// ParseAssignmentStrategy returns the assignment strategy for the given name.
+// An empty name returns the default one, worst-fit.
+func ParseAssignmentStrategy(name string) (AssignmentStrategy, error) {
+	switch name {
+	case "", WorstFitName:
+		return WorstFit{}, nil
+	case BestFitName:
+		return BestFit{}, nil
+	case FirstFitName:
+		return FirstFit{}, nil
+	case NearestName:
+		return Nearest{}, nil
+	default:
+		return nil, ErrUnknownAssignmentStrategy
+	}
+}
+
+// BestFit picks the car that leaves the fewest free seats after the group gets on
+type BestFit struct{}
+
+// Pick implements the AssignmentStrategy interface
+func (BestFit) Pick(cars []Car, g Group) int {
+	picked := -1
+	for i, car := range cars {
+		if car.Availability() < g.People() {
+			continue
+		}
+		if picked < 0 || car.Availability() < cars[picked].Availability() {
+			picked = i
+		}
+	}
+	return picked
+}
+
+// WorstFit picks the car with the most free seats
+type WorstFit struct{}
+
+// Pick implements the AssignmentStrategy interface
+func (WorstFit) Pick(cars []Car, g Group) int {
+	picked := -1
+	for i, car := range cars {
+		if car.Availability() < g.People() {
+			continue
+		}
+		if picked < 0 || car.Availability() > cars[picked].Availability() {
+			picked = i
+		}
+	}
+	return picked
+}
+
+// FirstFit picks the first car, in fleet order, that has enough free seats
+type FirstFit struct{}
+
+// Pick implements the AssignmentStrategy interface
+func (FirstFit) Pick(cars []Car, g Group) int {
+	for i, car := range cars {
+		if car.Availability() >= g.People() {
+			return i
+		}
+	}
+	return -1
+}
+
+// Nearest picks the car nearest to the pickup location of the group, by the haversine distance.
+// If the group has not given its pickup location, or no car that fits has reported its position,
+// it picks the first car that has enough free seats, as FirstFit
+type Nearest struct{}
+
+// Pick implements the AssignmentStrategy interface
+func (Nearest) Pick(cars []Car, g Group) int {
+	if g.Pickup() == nil {
+		return FirstFit{}.Pick(cars, g)
+	}
+	picked := -1
+	var pickedDistance float64
+	for i, car := range cars {
+		if car.Availability() < g.People() || car.Position() == nil {
+			continue
+		}
+		if d := car.Position().DistanceTo(*g.Pickup()); picked < 0 || d < pickedDistance {
+			picked, pickedDistance = i, d
+		}
+	}
+	if picked < 0 {
+		return FirstFit{}.Pick(cars, g)
+	}
+	return picked
+}
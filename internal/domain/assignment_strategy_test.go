@@ -0,0 +1,208 @@
+package domain_test
+
+import (
+	"testing"
+
+	"theskyinflames/car-sharing/internal/domain"
+	"theskyinflames/car-sharing/internal/fixtures"
+	"theskyinflames/car-sharing/internal/helpers"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAssignmentStrategy(t *testing.T) {
+	testCases := []struct {
+		name            string
+		strategy        string
+		expected        domain.AssignmentStrategy
+		expectedErrFunc func(*testing.T, error)
+	}{
+		{
+			name:     `Given an empty strategy name, when it's called, then the worst-fit strategy is returned`,
+			expected: domain.WorstFit{},
+		},
+		{
+			name:     `Given the best-fit strategy name, when it's called, then it's returned`,
+			strategy: domain.BestFitName,
+			expected: domain.BestFit{},
+		},
+		{
+			name:     `Given the worst-fit strategy name, when it's called, then it's returned`,
+			strategy: domain.WorstFitName,
+			expected: domain.WorstFit{},
+		},
+		{
+			name:     `Given the first-fit strategy name, when it's called, then it's returned`,
+			strategy: domain.FirstFitName,
+			expected: domain.FirstFit{},
+		},
+		{
+			name:     `Given the nearest strategy name, when it's called, then it's returned`,
+			strategy: domain.NearestName,
+			expected: domain.Nearest{},
+		},
+		{
+			name:     `Given an unknown strategy name, when it's called, then an error is returned`,
+			strategy: "random-fit",
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, domain.ErrUnknownAssignmentStrategy)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			s, err := domain.ParseAssignmentStrategy(tc.strategy)
+			require.Equal(t, tc.expectedErrFunc == nil, err == nil)
+			if err != nil {
+				tc.expectedErrFunc(t, err)
+				return
+			}
+			require.Equal(t, tc.expected, s)
+		})
+	}
+}
+
+func TestAssignmentStrategiesUtilisation(t *testing.T) {
+	testCases := []struct {
+		name                  string
+		strategy              domain.AssignmentStrategy
+		capacities            []domain.CarCapacity
+		groups                []int
+		expectedOccupiedSeats int
+		expectedWaitingGroups int
+	}{
+		{
+			name: `Given a worst-fit strategy and a small group followed by a big one,
+				when they request a journey, then the small group takes the biggest car and the big one waits`,
+			strategy:              domain.WorstFit{},
+			capacities:            []domain.CarCapacity{domain.CarCapacity4, domain.CarCapacity6},
+			groups:                []int{2, 6},
+			expectedOccupiedSeats: 2,
+			expectedWaitingGroups: 1,
+		},
+		{
+			name: `Given a best-fit strategy and a small group followed by a big one,
+				when they request a journey, then both groups get on a car`,
+			strategy:              domain.BestFit{},
+			capacities:            []domain.CarCapacity{domain.CarCapacity4, domain.CarCapacity6},
+			groups:                []int{2, 6},
+			expectedOccupiedSeats: 8,
+			expectedWaitingGroups: 0,
+		},
+		{
+			name: `Given a first-fit strategy and a small group followed by a big one,
+				when they request a journey, then the small group takes the first car and the big one gets on the second`,
+			strategy:              domain.FirstFit{},
+			capacities:            []domain.CarCapacity{domain.CarCapacity4, domain.CarCapacity6},
+			groups:                []int{2, 6},
+			expectedOccupiedSeats: 8,
+			expectedWaitingGroups: 0,
+		},
+		{
+			name: `Given a first-fit strategy with the biggest car first,
+				when a small group and a big one request a journey, then the big one waits`,
+			strategy:              domain.FirstFit{},
+			capacities:            []domain.CarCapacity{domain.CarCapacity6, domain.CarCapacity4},
+			groups:                []int{2, 6},
+			expectedOccupiedSeats: 2,
+			expectedWaitingGroups: 1,
+		},
+		{
+			name: `Given a worst-fit strategy and a mixed workload,
+				when the groups request a journey, then the capacity gets fragmented`,
+			strategy:              domain.WorstFit{},
+			capacities:            []domain.CarCapacity{domain.CarCapacity4, domain.CarCapacity5, domain.CarCapacity6},
+			groups:                []int{3, 3, 1, 2, 6, 4},
+			expectedOccupiedSeats: 9,
+			expectedWaitingGroups: 2,
+		},
+		{
+			name: `Given a best-fit strategy and a mixed workload,
+				when the groups request a journey, then the seats are used more efficiently`,
+			strategy:              domain.BestFit{},
+			capacities:            []domain.CarCapacity{domain.CarCapacity4, domain.CarCapacity5, domain.CarCapacity6},
+			groups:                []int{3, 3, 1, 2, 6, 4},
+			expectedOccupiedSeats: 15,
+			expectedWaitingGroups: 1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var cars []domain.Car
+			for _, c := range tc.capacities {
+				cars = append(cars, fixtures.Car{ID: helpers.UUIDPtr(uuid.New()), Capacity: helpers.CarCapacityPtr(c)}.Build())
+			}
+			fleet := domain.NewFleet(cars, nil, domain.WithAssignmentStrategy(tc.strategy))
+
+			var waiting int
+			for _, people := range tc.groups {
+				g, _ := fleet.Journey(fixtures.Group{ID: helpers.UUIDPtr(uuid.New()), People: helpers.IntPtr(people)}.Build())
+				if !g.IsOnJourney() {
+					waiting++
+				}
+			}
+
+			var occupied int
+			for _, car := range fleet.Cars() {
+				occupied += car.Capacity().Int() - car.Availability()
+			}
+			require.Equal(t, tc.expectedOccupiedSeats, occupied)
+			require.Equal(t, tc.expectedWaitingGroups, waiting)
+		})
+	}
+}
+
+func TestNearest(t *testing.T) {
+	var (
+		factory      = domain.Location{Lat: 41.3874, Lng: 2.1686}
+		headquarters = domain.Location{Lat: 41.4036, Lng: 2.1744}
+		warehouse    = domain.Location{Lat: 41.3275, Lng: 2.0954}
+
+		far     = fixtures.Car{Position: &warehouse}.Build()
+		near    = fixtures.Car{Position: &headquarters}.Build()
+		full    = fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity4), Position: &factory}.Build()
+		unknown = fixtures.Car{}.Build()
+	)
+	require.NoError(t, full.GetOn(fixtures.Group{People: helpers.IntPtr(4)}.Build()))
+
+	testCases := []struct {
+		name     string
+		cars     []domain.Car
+		g        domain.Group
+		expected int
+	}{
+		{
+			name:     `Given a group with its pickup location, when it's called, then the nearest car that fits is picked`,
+			cars:     []domain.Car{unknown, far, full, near},
+			g:        fixtures.Group{People: helpers.IntPtr(2), Pickup: &factory}.Build(),
+			expected: 3,
+		},
+		{
+			name:     `Given a group without pickup location, when it's called, then the first car that fits is picked`,
+			cars:     []domain.Car{full, far, near},
+			g:        fixtures.Group{People: helpers.IntPtr(2)}.Build(),
+			expected: 1,
+		},
+		{
+			name:     `Given cars that have not reported their position, when it's called, then the first car that fits is picked`,
+			cars:     []domain.Car{full, unknown},
+			g:        fixtures.Group{People: helpers.IntPtr(2), Pickup: &factory}.Build(),
+			expected: 1,
+		},
+		{
+			name:     `Given no car that fits, when it's called, then no car is picked`,
+			cars:     []domain.Car{full},
+			g:        fixtures.Group{People: helpers.IntPtr(2), Pickup: &factory}.Build(),
+			expected: -1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.expected, domain.Nearest{}.Pick(tc.cars, tc.g))
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package domain
+
+import "errors"
+
+// CapacityLimits are the seats that a car of the fleet can have. The largest group allowed is the one
+// that fills the largest car allowed. A zero value applies the default limits.
+type CapacityLimits struct {
+	MinSeats int
+	MaxSeats int
+}
+
+// DefaultCapacityLimits are the limits of a fleet of 4, 5 and 6 seats cars
+var DefaultCapacityLimits = CapacityLimits{MinSeats: CarCapacity4.Int(), MaxSeats: CarCapacity6.Int()}
+
+var (
+	// ErrWrongCapacityLimits is self-described
+	ErrWrongCapacityLimits = errors.New("wrong capacity limits, the min seats have to be at least 1 and not greater than the max ones")
+	// ErrGroupTooBig is self-described
+	ErrGroupTooBig = errors.New("the group is bigger than the largest car allowed")
+)
+
+// NewCapacityLimits is a constructor
+func NewCapacityLimits(minSeats, maxSeats int) (CapacityLimits, error) {
+	if minSeats < 1 || maxSeats < minSeats {
+		return CapacityLimits{}, ErrWrongCapacityLimits
+	}
+	return CapacityLimits{MinSeats: minSeats, MaxSeats: maxSeats}, nil
+}
+
+func (l CapacityLimits) orDefault() CapacityLimits {
+	if l == (CapacityLimits{}) {
+		return DefaultCapacityLimits
+	}
+	return l
+}
+
+// ParseCarCapacity returns the capacity of a car with the given seats, if they are within the limits
+func (l CapacityLimits) ParseCarCapacity(seats int) (CarCapacity, error) {
+	l = l.orDefault()
+	if seats < l.MinSeats || seats > l.MaxSeats {
+		return 0, ErrCapacityNotSupported
+	}
+	return CarCapacity(seats), nil
+}
+
+// MaxGroupSize returns the size of the largest group allowed
+func (l CapacityLimits) MaxGroupSize() int {
+	return l.orDefault().MaxSeats
+}
+
+// CheckGroupSize returns ErrGroupTooBig if a group with these people can't fit in any car allowed
+func (l CapacityLimits) CheckGroupSize(people int) error {
+	if people > l.MaxGroupSize() {
+		return ErrGroupTooBig
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package domain_test
+
+import (
+	"testing"
+
+	"theskyinflames/car-sharing/internal/domain"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewCapacityLimits(t *testing.T) {
+	testCases := []struct {
+		name        string
+		minSeats    int
+		maxSeats    int
+		expectedErr error
+	}{
+		{
+			name:        `Given no min seats, when it's called, then an error is returned`,
+			maxSeats:    6,
+			expectedErr: domain.ErrWrongCapacityLimits,
+		},
+		{
+			name:        `Given min seats greater than the max ones, when it's called, then an error is returned`,
+			minSeats:    9,
+			maxSeats:    6,
+			expectedErr: domain.ErrWrongCapacityLimits,
+		},
+		{
+			name:     `Given right limits, when it's called, then they are returned`,
+			minSeats: 4,
+			maxSeats: 15,
+		},
+	}
+
+	for _, tc := range testCases {
+		l, err := domain.NewCapacityLimits(tc.minSeats, tc.maxSeats)
+		if tc.expectedErr != nil {
+			require.ErrorIs(t, err, tc.expectedErr, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		require.Equal(t, domain.CapacityLimits{MinSeats: tc.minSeats, MaxSeats: tc.maxSeats}, l, tc.name)
+	}
+}
+
+func TestCapacityLimits(t *testing.T) {
+	minibuses := domain.CapacityLimits{MinSeats: 4, MaxSeats: 15}
+	testCases := []struct {
+		name             string
+		limits           domain.CapacityLimits
+		seats            int
+		people           int
+		expectedSeatsErr error
+		expectedSizeErr  error
+	}{
+		{
+			name: `Given the default limits,
+				when a 6 seats car and a group of 6 are checked,
+				then they are allowed`,
+			seats:  6,
+			people: 6,
+		},
+		{
+			name: `Given the default limits,
+				when a 9 seats car and a group of 7 are checked,
+				then they are rejected`,
+			seats:            9,
+			people:           7,
+			expectedSeatsErr: domain.ErrCapacityNotSupported,
+			expectedSizeErr:  domain.ErrGroupTooBig,
+		},
+		{
+			name: `Given limits up to 15 seats,
+				when a 15 seats car and a group of 15 are checked,
+				then they are allowed`,
+			limits: minibuses,
+			seats:  15,
+			people: 15,
+		},
+		{
+			name: `Given limits up to 15 seats,
+				when a 3 seats car and a group of 16 are checked,
+				then they are rejected`,
+			limits:           minibuses,
+			seats:            3,
+			people:           16,
+			expectedSeatsErr: domain.ErrCapacityNotSupported,
+			expectedSizeErr:  domain.ErrGroupTooBig,
+		},
+	}
+
+	for _, tc := range testCases {
+		capacity, err := tc.limits.ParseCarCapacity(tc.seats)
+		require.ErrorIs(t, err, tc.expectedSeatsErr, tc.name)
+		if tc.expectedSeatsErr == nil {
+			require.Equal(t, tc.seats, capacity.Int(), tc.name)
+		}
+		require.ErrorIs(t, tc.limits.CheckGroupSize(tc.people), tc.expectedSizeErr, tc.name)
+	}
+}
@@ -15,7 +15,7 @@ func (evc CarCapacity) Int() int {
 	return int(evc)
 }
 
-// Allowed EV capacities
+// Usual car capacities. The ones allowed are set by the CapacityLimits
 const (
 	CarCapacity4 CarCapacity = 4
 	CarCapacity5 CarCapacity = 5
@@ -25,17 +25,27 @@ const (
 // ErrCapacityNotSupported is self-described
 var ErrCapacityNotSupported = errors.New("capacity not supported")
 
-// ParseCarCapacityFromInt returns the int value for an EvCapacity
-func ParseCarCapacityFromInt(c int) (CarCapacity, error) {
-	switch c {
-	case 4:
-		return CarCapacity4, nil
-	case 5:
-		return CarCapacity5, nil
-	case 6:
-		return CarCapacity6, nil
+// CarStatus is the service status of a car
+type CarStatus string
+
+// Car statuses. Only the available cars take new groups. A draining car keeps its groups on journey,
+// and it's out of service once they are dropped off
+const (
+	CarAvailable    CarStatus = "available"
+	CarOutOfService CarStatus = "out_of_service"
+	CarDraining     CarStatus = "draining"
+)
+
+// ErrWrongCarStatus is self-described
+var ErrWrongCarStatus = errors.New("wrong car status")
+
+// ParseCarStatus is self-described
+func ParseCarStatus(s string) (CarStatus, error) {
+	switch st := CarStatus(s); st {
+	case CarAvailable, CarOutOfService, CarDraining:
+		return st, nil
 	default:
-		return 0, ErrCapacityNotSupported
+		return "", ErrWrongCarStatus
 	}
 }
 
@@ -48,14 +58,61 @@ type Car struct {
 
 	capacity CarCapacity
 	journeys Journeys
+
+	// reservedFor is the starving group that has reserved the car. uuid.Nil if there is not reservation
+	reservedFor uuid.UUID
+
+	// retiring is TRUE once the car has been asked to leave the fleet. No group can get on it,
+	// and it's removed as soon as its journeys finish
+	retiring bool
+
+	status CarStatus
+
+	// site is where the car is based. Only the groups from the same site get on it
+	site Site
+
+	// features is the equipment of the car. Only the groups whose requirements it meets get on it
+	features Features
+
+	// position is the last position reported by the car. nil if it has not reported any yet
+	position *Location
+
+	// heldBack are the seats held back for the upcoming reservations of the car. They are set by the Fleet,
+	// and they are not persisted
+	heldBack int
+
+	// version is the persisted version of the car. It's used to reject the updates done from a stale copy
+	version int
+}
+
+// CarOption sets the optional details of a car
+type CarOption func(*Car)
+
+// WithSite sets where the car is based. By default, the DefaultSite
+func WithSite(s Site) CarOption {
+	return func(e *Car) {
+		e.site = s
+	}
+}
+
+// WithFeatures sets the equipment of the car, i.e. the wheelchair access
+func WithFeatures(fs ...Feature) CarOption {
+	return func(e *Car) {
+		e.features = NewFeatures(fs...)
+	}
 }
 
 // NewCar is a constructor
-func NewCar(id uuid.UUID, capacity CarCapacity) Car {
+func NewCar(id uuid.UUID, capacity CarCapacity, opts ...CarOption) Car {
 	car := Car{
 		AggregateBasic: ddd.NewAggregateBasic(id),
 		capacity:       capacity,
 		journeys:       make(Journeys),
+		status:         CarAvailable,
+		site:           DefaultSite,
+	}
+	for _, opt := range opts {
+		opt(&car)
 	}
 
 	car.AggregateBasic.RecordEvent(NewCarCreatedEvent(car))
@@ -78,27 +135,155 @@ func (e Car) Journeys() Journeys {
 	return e.journeys
 }
 
-// Hydrate hydrates an EV
-func (e *Car) Hydrate(id uuid.UUID, capacity CarCapacity, journeys Journeys) {
-	e.AggregateBasic = ddd.NewAggregateBasic(id)
-	e.capacity = capacity
-	e.journeys = journeys
+// ReservedFor is a getter
+func (e Car) ReservedFor() uuid.UUID {
+	return e.reservedFor
+}
+
+// IsReserved returns TRUE if the car is reserved for a starving group
+func (e Car) IsReserved() bool {
+	return e.reservedFor != uuid.Nil
+}
+
+// IsRetiring is a getter
+func (e Car) IsRetiring() bool {
+	return e.retiring
+}
+
+// IsRetired returns TRUE if the car is retiring and it has no journeys left, so it can be removed from the fleet
+func (e Car) IsRetired() bool {
+	return e.retiring && len(e.journeys) == 0
+}
+
+// Status is a getter
+func (e Car) Status() CarStatus {
+	return e.status
+}
+
+// Site is a getter
+func (e Car) Site() Site {
+	return e.site.orDefault()
+}
+
+// Features is a getter
+func (e Car) Features() Features {
+	return e.features
+}
+
+// Meets returns TRUE if the car has all the features required by the group
+func (e Car) Meets(g Group) bool {
+	return e.features.HasAll(g.Requirements())
+}
+
+// Position is a getter. It returns nil if the car has not reported its position yet
+func (e Car) Position() *Location {
+	return e.position
 }
 
-// Availability returns the amount of available seats
+// poolsWith returns TRUE if the group can share the car with its groups on journey. An empty car pools with any group
+func (e Car) poolsWith(g Group) bool {
+	for _, j := range e.journeys {
+		if !j.SharesRouteWith(g) {
+			return false
+		}
+	}
+	return true
+}
+
+// acceptsGroups returns TRUE if new groups can get on the car
+func (e Car) acceptsGroups() bool {
+	return !e.retiring && e.status == CarAvailable
+}
+
+// Version is a getter
+func (e Car) Version() int {
+	return e.version
+}
+
+// CarSnapshot is the state of an EV, as it's stored
+type CarSnapshot struct {
+	ID          uuid.UUID
+	Capacity    CarCapacity
+	Journeys    Journeys
+	ReservedFor uuid.UUID
+	Retiring    bool
+	Status      CarStatus
+	Site        Site
+	Features    Features
+	Position    *Location
+	Version     int
+}
+
+// Hydrate hydrates an EV from its snapshot
+func (e *Car) Hydrate(s CarSnapshot) {
+	e.AggregateBasic = ddd.NewAggregateBasic(s.ID)
+	e.capacity = s.Capacity
+	e.journeys = s.Journeys
+	e.reservedFor = s.ReservedFor
+	e.retiring = s.Retiring
+	e.status = s.Status
+	e.site = s.Site
+	e.features = s.Features
+	e.position = s.Position
+	e.version = s.Version
+}
+
+// Snapshot returns the state of the EV. It shares its journeys
+func (e Car) Snapshot() CarSnapshot {
+	return CarSnapshot{
+		ID:          e.ID(),
+		Capacity:    e.capacity,
+		Journeys:    e.journeys,
+		ReservedFor: e.reservedFor,
+		Retiring:    e.retiring,
+		Status:      e.status,
+		Site:        e.site,
+		Features:    e.features,
+		Position:    e.position,
+		Version:     e.version,
+	}
+}
+
+// Availability returns the amount of available seats, without the ones held back for the upcoming reservations
 func (e Car) Availability() int {
+	return e.capacity.Int() - e.Occupancy() - e.heldBack
+}
+
+// Occupancy returns the amount of seats taken by the groups on journey
+func (e Car) Occupancy() int {
 	var currentLoad int
 	for _, g := range e.journeys {
 		currentLoad += g.people
 	}
-	return e.capacity.Int() - currentLoad
+	return currentLoad
 }
 
-// ErrNotFit is self-described
-var ErrNotFit = errors.New("not fit")
+// holdBack holds back the seats for the upcoming reservations, so no walk-in group can take them
+func (e *Car) holdBack(seats int) {
+	e.heldBack = seats
+}
+
+var (
+	// ErrNotFit is self-described
+	ErrNotFit = errors.New("not fit")
+	// ErrCarRetiring is self-described
+	ErrCarRetiring = errors.New("the car is retiring")
+	// ErrCapacityBelowOccupancy is self-described
+	ErrCapacityBelowOccupancy = errors.New("the capacity is lower than the seats taken by the groups on journey")
+	// ErrCarOutOfService is self-described
+	ErrCarOutOfService = errors.New("the car is out of service")
+	// ErrCarInService is self-described
+	ErrCarInService = errors.New("the car is in service")
+)
 
 // GetOn is self-described
 func (e *Car) GetOn(g Group) error {
+	if e.retiring {
+		return ErrCarRetiring
+	}
+	if e.status != CarAvailable {
+		return ErrCarOutOfService
+	}
 	if e.Availability() < g.people {
 		return ErrNotFit
 	}
@@ -117,5 +302,110 @@ func (e *Car) DropOff(id uuid.UUID) error {
 		return ErrNotFound
 	}
 	delete(e.journeys, id)
+
+	switch {
+	case e.IsRetired():
+		e.RecordEvent(NewCarRetiredEvent(*e))
+	case e.status == CarDraining && len(e.journeys) == 0:
+		e.status = CarOutOfService
+		e.RecordEvent(NewCarOutOfServiceEvent(*e))
+	}
 	return nil
 }
+
+// TakeOutOfService is self-described. If there are groups on journey, the car is drained: nobody is ejected,
+// but no other group can get on it, and it's out of service once they are dropped off.
+// Its reservation, if any, is released, so the starving group can reserve another car
+func (e *Car) TakeOutOfService() error {
+	if e.retiring {
+		return ErrCarRetiring
+	}
+	if e.status != CarAvailable {
+		return ErrCarOutOfService
+	}
+	e.reservedFor = uuid.Nil
+
+	if len(e.journeys) > 0 {
+		e.status = CarDraining
+		e.RecordEvent(NewCarDrainingEvent(*e))
+		return nil
+	}
+	e.status = CarOutOfService
+	e.RecordEvent(NewCarOutOfServiceEvent(*e))
+	return nil
+}
+
+// BackInService makes the car available again, whether it's out of service or being drained
+func (e *Car) BackInService() error {
+	if e.retiring {
+		return ErrCarRetiring
+	}
+	if e.status == CarAvailable {
+		return ErrCarInService
+	}
+	e.status = CarAvailable
+
+	e.RecordEvent(NewCarBackInServiceEvent(*e))
+	return nil
+}
+
+// Retire asks the car to leave the fleet. If there are groups on journey, it's kept until they are dropped off,
+// but no other group can get on it. Its reservation, if any, is released, so the starving group can reserve another car
+func (e *Car) Retire() error {
+	if e.retiring {
+		return ErrCarRetiring
+	}
+	e.retiring = true
+	e.reservedFor = uuid.Nil
+
+	if e.IsRetired() {
+		e.RecordEvent(NewCarRetiredEvent(*e))
+		return nil
+	}
+	e.RecordEvent(NewCarRetiringEvent(*e))
+	return nil
+}
+
+// ChangeCapacity is self-described. The capacity can't be lower than the seats already taken
+func (e *Car) ChangeCapacity(capacity CarCapacity) error {
+	if e.retiring {
+		return ErrCarRetiring
+	}
+	if capacity.Int() < e.Occupancy() {
+		return ErrCapacityBelowOccupancy
+	}
+	if capacity == e.capacity {
+		return nil
+	}
+	e.capacity = capacity
+
+	e.RecordEvent(NewCarUpdatedEvent(*e))
+	return nil
+}
+
+// ReportPosition sets the current position of the car
+func (e *Car) ReportPosition(l Location) {
+	e.position = &l
+
+	e.RecordEvent(NewCarPositionReportedEvent(*e))
+}
+
+// Reserve reserves the car for a starving group. No other group can get on the car until it gets on
+func (e *Car) Reserve(g Group) {
+	e.reservedFor = g.ID()
+
+	e.RecordEvent(NewCarReservedEvent(*e, g))
+}
+
+// ReserveForScheduledJourney reserves the booked car for the group of a reservation that has started, as Reserve does
+// for a starving group
+func (e *Car) ReserveForScheduledJourney(g Group) {
+	e.reservedFor = g.ID()
+
+	e.RecordEvent(NewCarReservedForScheduledJourneyEvent(*e, g))
+}
+
+// ReleaseReservation is self-described
+func (e *Car) ReleaseReservation() {
+	e.reservedFor = uuid.Nil
+}
@@ -24,6 +24,29 @@ func TestNewCar(t *testing.T) {
 	})
 }
 
+func TestCarSnapshot(t *testing.T) {
+	t.Run(`Given a car, when it's hydrated from its snapshot, then the same car is returned`, func(t *testing.T) {
+		var (
+			draining = domain.CarDraining
+			g        = fixtures.Group{People: helpers.IntPtr(2)}.Build()
+			car      = fixtures.Car{
+				Capacity:    helpers.CarCapacityPtr(domain.CarCapacity6),
+				Journeys:    domain.Journeys{g.ID(): g},
+				ReservedFor: helpers.UUIDPtr(uuid.New()),
+				Status:      &draining,
+				Site:        "north",
+				Features:    domain.NewFeatures(domain.FeatureWheelchair),
+				Position:    &domain.Location{Lat: 41.39, Lng: 2.17},
+				Version:     helpers.IntPtr(3),
+			}.Build()
+		)
+		var hydrated domain.Car
+		hydrated.Hydrate(car.Snapshot())
+		require.Equal(t, car.Snapshot(), hydrated.Snapshot())
+		require.Equal(t, car.ID(), hydrated.ID())
+	})
+}
+
 func TestCarAvailability(t *testing.T) {
 	var (
 		id1 = uuid.New()
@@ -158,3 +181,244 @@ func TestCarDropOff(t *testing.T) {
 		require.False(t, ok, tc.name)
 	}
 }
+
+func TestCarRetire(t *testing.T) {
+	gID := uuid.New()
+	testCases := []struct {
+		name              string
+		car               domain.Car
+		expectedRetired   bool
+		expectedEventName string
+		expectedErr       error
+	}{
+		{
+			name: `Given an empty Car,
+				when it's retired,
+				then it's retired straight away`,
+			car:               fixtures.Car{ReservedFor: helpers.UUIDPtr(uuid.New())}.Build(),
+			expectedRetired:   true,
+			expectedEventName: domain.CarRetiredEventName,
+		},
+		{
+			name: `Given a Car with groups on journey,
+				when it's retired,
+				then it's retiring until they are dropped off`,
+			car: fixtures.Car{
+				Journeys: domain.Journeys{gID: fixtures.Group{ID: helpers.UUIDPtr(gID), People: helpers.IntPtr(2)}.Build()},
+			}.Build(),
+			expectedEventName: domain.CarRetiringEventName,
+		},
+		{
+			name: `Given a retiring Car,
+				when it's retired again,
+				then an error is returned`,
+			car:         fixtures.Car{Retiring: true}.Build(),
+			expectedErr: domain.ErrCarRetiring,
+		},
+	}
+
+	for _, tc := range testCases {
+		err := tc.car.Retire()
+		if tc.expectedErr != nil {
+			require.ErrorIs(t, err, tc.expectedErr, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		require.True(t, tc.car.IsRetiring(), tc.name)
+		require.False(t, tc.car.IsReserved(), tc.name)
+		require.Equal(t, tc.expectedRetired, tc.car.IsRetired(), tc.name)
+		evs := tc.car.Events()
+		require.Len(t, evs, 1, tc.name)
+		require.Equal(t, tc.expectedEventName, evs[0].Name(), tc.name)
+	}
+
+	t.Run(`Given a retiring Car,
+		when a group tries to get on it, then an error is returned,
+		and when its last group is dropped off, then it's retired`, func(t *testing.T) {
+		car := fixtures.Car{
+			Capacity: helpers.CarCapacityPtr(domain.CarCapacity6),
+			Journeys: domain.Journeys{gID: fixtures.Group{ID: helpers.UUIDPtr(gID), People: helpers.IntPtr(2)}.Build()},
+			Retiring: true,
+		}.Build()
+		require.ErrorIs(t, car.GetOn(fixtures.Group{People: helpers.IntPtr(1)}.Build()), domain.ErrCarRetiring)
+
+		require.NoError(t, car.DropOff(gID))
+		require.True(t, car.IsRetired())
+		evs := car.Events()
+		require.Len(t, evs, 1)
+		require.Equal(t, domain.CarRetiredEventName, evs[0].Name())
+	})
+}
+
+func TestCarChangeCapacity(t *testing.T) {
+	gID := uuid.New()
+	onJourney := domain.Journeys{gID: fixtures.Group{ID: helpers.UUIDPtr(gID), People: helpers.IntPtr(5)}.Build()}
+	testCases := []struct {
+		name             string
+		car              domain.Car
+		capacity         domain.CarCapacity
+		expectedCapacity domain.CarCapacity
+		expectedEvents   int
+		expectedErr      error
+	}{
+		{
+			name: `Given a Car,
+				when its capacity is changed,
+				then it's updated`,
+			car:              fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity4)}.Build(),
+			capacity:         domain.CarCapacity6,
+			expectedCapacity: domain.CarCapacity6,
+			expectedEvents:   1,
+		},
+		{
+			name: `Given a Car,
+				when its capacity is changed to the same one,
+				then nothing is recorded`,
+			car:              fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity4)}.Build(),
+			capacity:         domain.CarCapacity4,
+			expectedCapacity: domain.CarCapacity4,
+		},
+		{
+			name: `Given a Car with 5 seats taken,
+				when its capacity is changed to 4,
+				then an error is returned`,
+			car:         fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity6), Journeys: onJourney}.Build(),
+			capacity:    domain.CarCapacity4,
+			expectedErr: domain.ErrCapacityBelowOccupancy,
+		},
+		{
+			name: `Given a retiring Car,
+				when its capacity is changed,
+				then an error is returned`,
+			car:         fixtures.Car{Retiring: true}.Build(),
+			capacity:    domain.CarCapacity6,
+			expectedErr: domain.ErrCarRetiring,
+		},
+	}
+
+	for _, tc := range testCases {
+		err := tc.car.ChangeCapacity(tc.capacity)
+		if tc.expectedErr != nil {
+			require.ErrorIs(t, err, tc.expectedErr, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.expectedCapacity, tc.car.Capacity(), tc.name)
+		require.Len(t, tc.car.Events(), tc.expectedEvents, tc.name)
+	}
+}
+
+func TestCarTakeOutOfService(t *testing.T) {
+	var (
+		gID      = uuid.New()
+		draining = domain.CarDraining
+	)
+	testCases := []struct {
+		name           string
+		car            domain.Car
+		expectedStatus domain.CarStatus
+		expectedEvent  string
+		expectedErr    error
+	}{
+		{
+			name: `Given an empty Car,
+				when it's taken out of service,
+				then it's out of service straight away`,
+			car:            fixtures.Car{ReservedFor: helpers.UUIDPtr(uuid.New())}.Build(),
+			expectedStatus: domain.CarOutOfService,
+			expectedEvent:  domain.CarOutOfServiceEventName,
+		},
+		{
+			name: `Given a Car with groups on journey,
+				when it's taken out of service,
+				then it's drained`,
+			car: fixtures.Car{
+				Journeys: domain.Journeys{gID: fixtures.Group{ID: helpers.UUIDPtr(gID), People: helpers.IntPtr(2)}.Build()},
+			}.Build(),
+			expectedStatus: domain.CarDraining,
+			expectedEvent:  domain.CarDrainingEventName,
+		},
+		{
+			name: `Given a draining Car,
+				when it's taken out of service again,
+				then an error is returned`,
+			car:         fixtures.Car{Status: &draining}.Build(),
+			expectedErr: domain.ErrCarOutOfService,
+		},
+		{
+			name: `Given a retiring Car,
+				when it's taken out of service,
+				then an error is returned`,
+			car:         fixtures.Car{Retiring: true}.Build(),
+			expectedErr: domain.ErrCarRetiring,
+		},
+	}
+
+	for _, tc := range testCases {
+		err := tc.car.TakeOutOfService()
+		if tc.expectedErr != nil {
+			require.ErrorIs(t, err, tc.expectedErr, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		require.Equal(t, tc.expectedStatus, tc.car.Status(), tc.name)
+		require.False(t, tc.car.IsReserved(), tc.name)
+		evs := tc.car.Events()
+		require.Len(t, evs, 1, tc.name)
+		require.Equal(t, tc.expectedEvent, evs[0].Name(), tc.name)
+	}
+
+	t.Run(`Given a draining Car,
+		when a group tries to get on it, then an error is returned,
+		and when its last group is dropped off, then it's out of service`, func(t *testing.T) {
+		car := fixtures.Car{
+			Capacity: helpers.CarCapacityPtr(domain.CarCapacity6),
+			Journeys: domain.Journeys{gID: fixtures.Group{ID: helpers.UUIDPtr(gID), People: helpers.IntPtr(2)}.Build()},
+			Status:   &draining,
+		}.Build()
+		require.ErrorIs(t, car.GetOn(fixtures.Group{People: helpers.IntPtr(1)}.Build()), domain.ErrCarOutOfService)
+		require.Len(t, car.Journeys(), 1, "nobody is ejected")
+
+		require.NoError(t, car.DropOff(gID))
+		require.Equal(t, domain.CarOutOfService, car.Status())
+		evs := car.Events()
+		require.Len(t, evs, 1)
+		require.Equal(t, domain.CarOutOfServiceEventName, evs[0].Name())
+	})
+}
+
+func TestCarBackInService(t *testing.T) {
+	outOfService := domain.CarOutOfService
+	testCases := []struct {
+		name        string
+		car         domain.Car
+		expectedErr error
+	}{
+		{
+			name: `Given an out of service Car,
+				when it's back in service,
+				then it's available`,
+			car: fixtures.Car{Status: &outOfService}.Build(),
+		},
+		{
+			name: `Given an available Car,
+				when it's back in service,
+				then an error is returned`,
+			car:         fixtures.Car{}.Build(),
+			expectedErr: domain.ErrCarInService,
+		},
+	}
+
+	for _, tc := range testCases {
+		err := tc.car.BackInService()
+		if tc.expectedErr != nil {
+			require.ErrorIs(t, err, tc.expectedErr, tc.name)
+			continue
+		}
+		require.NoError(t, err, tc.name)
+		require.Equal(t, domain.CarAvailable, tc.car.Status(), tc.name)
+		evs := tc.car.Events()
+		require.Len(t, evs, 1, tc.name)
+		require.Equal(t, domain.CarBackInServiceEventName, evs[0].Name(), tc.name)
+	}
+}
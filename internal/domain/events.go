@@ -2,6 +2,11 @@ package domain
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
 
 	"github.com/theskyinflames/cqrs-eda/pkg/events"
 )
@@ -9,7 +14,8 @@ import (
 // CarCreatedEventName is self-described
 const CarCreatedEventName = "car.added"
 
-// CarCreatedEvent is an event
+// CarCreatedEvent is an event. Its body has the time the car was added, so it tells apart the car added again
+// with the same ID once it has retired
 type CarCreatedEvent struct {
 	events.EventBasic
 }
@@ -17,42 +23,526 @@ type CarCreatedEvent struct {
 // NewCarCreatedEvent is a constructor
 func NewCarCreatedEvent(car Car) CarCreatedEvent {
 	b, _ := json.Marshal(map[string]interface{}{
-		"seats": car.Capacity().Int(),
+		"seats":    car.Capacity().Int(),
+		"site":     car.Site(),
+		"features": car.Features(),
+		"added_at": time.Now(),
 	})
 	return CarCreatedEvent{
 		EventBasic: events.NewEventBasic(car.ID(), CarCreatedEventName, b),
 	}
 }
 
+// CarUpdatedEventName is self-described
+const CarUpdatedEventName = "car.updated"
+
+// CarUpdatedEvent is an event. It's recorded when the capacity of a car is changed
+type CarUpdatedEvent struct {
+	events.EventBasic
+}
+
+// NewCarUpdatedEvent is a constructor
+func NewCarUpdatedEvent(car Car) CarUpdatedEvent {
+	b, _ := json.Marshal(map[string]interface{}{
+		"seats": car.Capacity().Int(),
+	})
+	return CarUpdatedEvent{
+		EventBasic: events.NewEventBasic(car.ID(), CarUpdatedEventName, b),
+	}
+}
+
+// CarRetiringEventName is self-described
+const CarRetiringEventName = "car.retiring"
+
+// CarRetiringEvent is an event. It's recorded when a car with groups on journey is asked to leave the fleet
+type CarRetiringEvent struct {
+	events.EventBasic
+}
+
+// NewCarRetiringEvent is a constructor
+func NewCarRetiringEvent(car Car) CarRetiringEvent {
+	b, _ := json.Marshal(map[string]interface{}{
+		"journeys": len(car.Journeys()),
+	})
+	return CarRetiringEvent{
+		EventBasic: events.NewEventBasic(car.ID(), CarRetiringEventName, b),
+	}
+}
+
+// CarRetiredEventName is self-described
+const CarRetiredEventName = "car.retired"
+
+// CarRetiredEvent is an event. It's recorded when a car leaves the fleet
+type CarRetiredEvent struct {
+	events.EventBasic
+}
+
+// NewCarRetiredEvent is a constructor
+func NewCarRetiredEvent(car Car) CarRetiredEvent {
+	b, _ := json.Marshal(map[string]interface{}{})
+	return CarRetiredEvent{
+		EventBasic: events.NewEventBasic(car.ID(), CarRetiredEventName, b),
+	}
+}
+
+// CarOutOfServiceEventName is self-described
+const CarOutOfServiceEventName = "car.out.of.service"
+
+// CarOutOfServiceEvent is an event. It's recorded when a car is out of service, straight away if it's empty,
+// or once the last group of a draining car is dropped off
+type CarOutOfServiceEvent struct {
+	events.EventBasic
+}
+
+// NewCarOutOfServiceEvent is a constructor
+func NewCarOutOfServiceEvent(car Car) CarOutOfServiceEvent {
+	b, _ := json.Marshal(map[string]interface{}{
+		"status":   string(car.Status()),
+		"journeys": len(car.Journeys()),
+	})
+	return CarOutOfServiceEvent{
+		EventBasic: events.NewEventBasic(car.ID(), CarOutOfServiceEventName, b),
+	}
+}
+
+// CarDrainingEventName is self-described
+const CarDrainingEventName = "car.draining"
+
+// CarDrainingEvent is an event. It's recorded when a car with groups on journey is taken out of service
+type CarDrainingEvent struct {
+	events.EventBasic
+}
+
+// NewCarDrainingEvent is a constructor
+func NewCarDrainingEvent(car Car) CarDrainingEvent {
+	b, _ := json.Marshal(map[string]interface{}{
+		"status":   string(car.Status()),
+		"journeys": len(car.Journeys()),
+	})
+	return CarDrainingEvent{
+		EventBasic: events.NewEventBasic(car.ID(), CarDrainingEventName, b),
+	}
+}
+
+// CarBackInServiceEventName is self-described
+const CarBackInServiceEventName = "car.back.in.service"
+
+// CarBackInServiceEvent is an event. It's recorded when a car is available again
+type CarBackInServiceEvent struct {
+	events.EventBasic
+}
+
+// NewCarBackInServiceEvent is a constructor
+func NewCarBackInServiceEvent(car Car) CarBackInServiceEvent {
+	b, _ := json.Marshal(map[string]interface{}{
+		"status": string(car.Status()),
+	})
+	return CarBackInServiceEvent{
+		EventBasic: events.NewEventBasic(car.ID(), CarBackInServiceEventName, b),
+	}
+}
+
+// CarReservedEventName is self-described
+const CarReservedEventName = "car.reserved"
+
+// CarReservedEvent is an event. It's recorded when a starving group reserves a car
+type CarReservedEvent struct {
+	events.EventBasic
+}
+
+// NewCarReservedEvent is a constructor
+func NewCarReservedEvent(car Car, g Group) CarReservedEvent {
+	b, _ := json.Marshal(map[string]interface{}{
+		"group":  g.ID().String(),
+		"people": g.People(),
+	})
+	return CarReservedEvent{
+		EventBasic: events.NewEventBasic(car.ID(), CarReservedEventName, b),
+	}
+}
+
+// CarReservedForScheduledJourneyEventName is self-described
+const CarReservedForScheduledJourneyEventName = "car.reserved.for.scheduled.journey"
+
+// CarReservedForScheduledJourneyEvent is an event. It's recorded when the group of a reservation that has started
+// reserves its booked car, because the groups on journey have not freed its seats yet
+type CarReservedForScheduledJourneyEvent struct {
+	events.EventBasic
+}
+
+// NewCarReservedForScheduledJourneyEvent is a constructor
+func NewCarReservedForScheduledJourneyEvent(car Car, g Group) CarReservedForScheduledJourneyEvent {
+	b, _ := json.Marshal(map[string]interface{}{
+		"group":  g.ID().String(),
+		"people": g.People(),
+	})
+	return CarReservedForScheduledJourneyEvent{
+		EventBasic: events.NewEventBasic(car.ID(), CarReservedForScheduledJourneyEventName, b),
+	}
+}
+
 // GroupSetOnJourneyEventName is self-described
 const GroupSetOnJourneyEventName = "group.is.on.journey"
 
 // GroupSetOnJourneyEvent is an event
 type GroupSetOnJourneyEvent struct {
 	events.EventBasic
+
+	carID       uuid.UUID
+	seats       CarCapacity
+	people      int
+	requestedAt time.Time
+	boardedAt   time.Time
 }
 
 // NewGroupSetOnJourneyEvent is a constructor
 func NewGroupSetOnJourneyEvent(g Group) GroupSetOnJourneyEvent {
+	var (
+		carID uuid.UUID
+		seats CarCapacity
+	)
+	if g.Car() != nil {
+		carID = g.Car().ID()
+		seats = g.Car().Capacity()
+	}
 	b, _ := json.Marshal(map[string]interface{}{
-		"people": g.People(),
+		"car":          carID.String(),
+		"seats":        seats.Int(),
+		"people":       g.People(),
+		"requested_at": g.RequestedAt(),
+		"boarded_at":   g.BoardedAt(),
 	})
 	return GroupSetOnJourneyEvent{
-		EventBasic: events.NewEventBasic(g.ID(), GroupSetOnJourneyEventName, b),
+		EventBasic:  events.NewEventBasic(g.ID(), GroupSetOnJourneyEventName, b),
+		carID:       carID,
+		seats:       seats,
+		people:      g.People(),
+		requestedAt: g.RequestedAt(),
+		boardedAt:   g.BoardedAt(),
 	}
 }
 
+// CarID is a getter
+func (e GroupSetOnJourneyEvent) CarID() uuid.UUID {
+	return e.carID
+}
+
+// Seats is a getter. It's the capacity of the car
+func (e GroupSetOnJourneyEvent) Seats() CarCapacity {
+	return e.seats
+}
+
+// People is a getter
+func (e GroupSetOnJourneyEvent) People() int {
+	return e.people
+}
+
+// RequestedAt is a getter
+func (e GroupSetOnJourneyEvent) RequestedAt() time.Time {
+	return e.requestedAt
+}
+
+// BoardedAt is a getter
+func (e GroupSetOnJourneyEvent) BoardedAt() time.Time {
+	return e.boardedAt
+}
+
 // GroupDroppedOffEventName is self-described
 const GroupDroppedOffEventName = "group.dropped.off"
 
 // GroupDroppedOffEvent is an event
 type GroupDroppedOffEvent struct {
 	events.EventBasic
+
+	boardedAt    time.Time
+	droppedOffAt time.Time
 }
 
 // NewGroupDroppedOff is a constructor
 func NewGroupDroppedOff(g Group) GroupDroppedOffEvent {
+	b, _ := json.Marshal(map[string]interface{}{
+		"boarded_at":     g.BoardedAt(),
+		"dropped_off_at": g.DroppedOffAt(),
+	})
 	return GroupDroppedOffEvent{
-		EventBasic: events.NewEventBasic(g.ID(), GroupDroppedOffEventName, nil),
+		EventBasic:   events.NewEventBasic(g.ID(), GroupDroppedOffEventName, b),
+		boardedAt:    g.BoardedAt(),
+		droppedOffAt: g.DroppedOffAt(),
+	}
+}
+
+// BoardedAt is a getter. It's zero if the group was dropped off before getting on a car
+func (e GroupDroppedOffEvent) BoardedAt() time.Time {
+	return e.boardedAt
+}
+
+// DroppedOffAt is a getter
+func (e GroupDroppedOffEvent) DroppedOffAt() time.Time {
+	return e.droppedOffAt
+}
+
+// GroupCancelledEventName is self-described
+const GroupCancelledEventName = "group.cancelled"
+
+// GroupCancelledEvent is an event. It's recorded when a waiting group gives up its journey
+type GroupCancelledEvent struct {
+	events.EventBasic
+
+	reason      CancelReason
+	people      int
+	requestedAt time.Time
+	cancelledAt time.Time
+}
+
+// NewGroupCancelledEvent is a constructor
+func NewGroupCancelledEvent(g Group, reason CancelReason, cancelledAt time.Time) GroupCancelledEvent {
+	b, _ := json.Marshal(map[string]interface{}{
+		"reason":       string(reason),
+		"people":       g.People(),
+		"requested_at": g.RequestedAt(),
+		"cancelled_at": cancelledAt,
+	})
+	return GroupCancelledEvent{
+		EventBasic:  events.NewEventBasic(g.ID(), GroupCancelledEventName, b),
+		reason:      reason,
+		people:      g.People(),
+		requestedAt: g.RequestedAt(),
+		cancelledAt: cancelledAt,
+	}
+}
+
+// Reason is a getter
+func (e GroupCancelledEvent) Reason() CancelReason {
+	return e.reason
+}
+
+// People is a getter
+func (e GroupCancelledEvent) People() int {
+	return e.people
+}
+
+// RequestedAt is a getter
+func (e GroupCancelledEvent) RequestedAt() time.Time {
+	return e.requestedAt
+}
+
+// CancelledAt is a getter
+func (e GroupCancelledEvent) CancelledAt() time.Time {
+	return e.cancelledAt
+}
+
+// GroupExpiredEventName is self-described
+const GroupExpiredEventName = "group.expired"
+
+// GroupExpiredEvent is an event. It's recorded when a group has been waiting too long, and it leaves the waiting list
+type GroupExpiredEvent struct {
+	events.EventBasic
+
+	people      int
+	requestedAt time.Time
+	expiredAt   time.Time
+}
+
+// NewGroupExpiredEvent is a constructor
+func NewGroupExpiredEvent(g Group, expiredAt time.Time) GroupExpiredEvent {
+	b, _ := json.Marshal(map[string]interface{}{
+		"people":       g.People(),
+		"requested_at": g.RequestedAt(),
+		"expired_at":   expiredAt,
+	})
+	return GroupExpiredEvent{
+		EventBasic:  events.NewEventBasic(g.ID(), GroupExpiredEventName, b),
+		people:      g.People(),
+		requestedAt: g.RequestedAt(),
+		expiredAt:   expiredAt,
+	}
+}
+
+// People is a getter
+func (e GroupExpiredEvent) People() int {
+	return e.people
+}
+
+// RequestedAt is a getter
+func (e GroupExpiredEvent) RequestedAt() time.Time {
+	return e.requestedAt
+}
+
+// ExpiredAt is a getter
+func (e GroupExpiredEvent) ExpiredAt() time.Time {
+	return e.expiredAt
+}
+
+// GroupUnservableEventName is self-described
+const GroupUnservableEventName = "group.unservable"
+
+// GroupUnservableEvent is an event. It's raised when a change of the fleet leaves a waiting group
+// without any car where it fits, so it would wait forever
+type GroupUnservableEvent struct {
+	events.EventBasic
+}
+
+// NewGroupUnservableEvent is a constructor. The seats are the capacity of the largest car of the site of the group
+func NewGroupUnservableEvent(g Group, seats CarCapacity) GroupUnservableEvent {
+	b, _ := json.Marshal(map[string]interface{}{
+		"people":        g.People(),
+		"site":          g.Site(),
+		"largest_seats": seats.Int(),
+	})
+	return GroupUnservableEvent{
+		EventBasic: events.NewEventBasic(g.ID(), GroupUnservableEventName, b),
+	}
+}
+
+// CarPositionReportedEventName is self-described
+const CarPositionReportedEventName = "car.position.reported"
+
+// CarPositionReportedEvent is an event
+type CarPositionReportedEvent struct {
+	events.EventBasic
+}
+
+// NewCarPositionReportedEvent is a constructor
+func NewCarPositionReportedEvent(car Car) CarPositionReportedEvent {
+	var b []byte
+	if p := car.Position(); p != nil {
+		b, _ = json.Marshal(map[string]float64{"lat": p.Lat, "lng": p.Lng})
+	}
+	return CarPositionReportedEvent{
+		EventBasic: events.NewEventBasic(car.ID(), CarPositionReportedEventName, b),
+	}
+}
+
+// ReservationScheduledEventName is self-described
+const ReservationScheduledEventName = "reservation.scheduled"
+
+// ReservationScheduledEvent is an event. It's recorded when the seats of a car are booked for a reservation
+type ReservationScheduledEvent struct {
+	events.EventBasic
+}
+
+// NewReservationScheduledEvent is a constructor
+func NewReservationScheduledEvent(r Reservation) ReservationScheduledEvent {
+	b, _ := json.Marshal(map[string]interface{}{
+		"car":          r.CarID().String(),
+		"people":       r.People(),
+		"requirements": r.Requirements().Strings(),
+		"start_at":     r.StartAt(),
+		"end_at":       r.EndAt(),
+	})
+	return ReservationScheduledEvent{
+		EventBasic: events.NewEventBasic(r.ID(), ReservationScheduledEventName, b),
+	}
+}
+
+// ErrUnknownEvent is self-described
+var ErrUnknownEvent = errors.New("unknown event")
+
+// EventBody returns the JSON body of a domain event
+func EventBody(e events.Event) ([]byte, error) {
+	eb, ok := e.(interface{ Body() interface{} })
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEvent, e.Name())
+	}
+	b, ok := eb.Body().([]byte)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEvent, e.Name())
+	}
+	return b, nil
+}
+
+// ParseEvent rebuilds a domain event from its name, the ID of its aggregate and its JSON body
+func ParseEvent(name string, aggregateID uuid.UUID, body []byte) (events.Event, error) {
+	switch name {
+	case CarCreatedEventName:
+		return CarCreatedEvent{EventBasic: events.NewEventBasic(aggregateID, name, body)}, nil
+	case CarUpdatedEventName:
+		return CarUpdatedEvent{EventBasic: events.NewEventBasic(aggregateID, name, body)}, nil
+	case CarRetiringEventName:
+		return CarRetiringEvent{EventBasic: events.NewEventBasic(aggregateID, name, body)}, nil
+	case CarRetiredEventName:
+		return CarRetiredEvent{EventBasic: events.NewEventBasic(aggregateID, name, body)}, nil
+	case CarOutOfServiceEventName:
+		return CarOutOfServiceEvent{EventBasic: events.NewEventBasic(aggregateID, name, body)}, nil
+	case CarDrainingEventName:
+		return CarDrainingEvent{EventBasic: events.NewEventBasic(aggregateID, name, body)}, nil
+	case CarBackInServiceEventName:
+		return CarBackInServiceEvent{EventBasic: events.NewEventBasic(aggregateID, name, body)}, nil
+	case CarReservedEventName:
+		return CarReservedEvent{EventBasic: events.NewEventBasic(aggregateID, name, body)}, nil
+	case CarReservedForScheduledJourneyEventName:
+		return CarReservedForScheduledJourneyEvent{EventBasic: events.NewEventBasic(aggregateID, name, body)}, nil
+	case GroupSetOnJourneyEventName:
+		var b struct {
+			Car         uuid.UUID `json:"car"`
+			Seats       int       `json:"seats"`
+			People      int       `json:"people"`
+			RequestedAt time.Time `json:"requested_at"`
+			BoardedAt   time.Time `json:"boarded_at"`
+		}
+		if err := json.Unmarshal(body, &b); err != nil {
+			return nil, err
+		}
+		return GroupSetOnJourneyEvent{
+			EventBasic:  events.NewEventBasic(aggregateID, name, body),
+			carID:       b.Car,
+			seats:       CarCapacity(b.Seats),
+			people:      b.People,
+			requestedAt: b.RequestedAt,
+			boardedAt:   b.BoardedAt,
+		}, nil
+	case GroupDroppedOffEventName:
+		var b struct {
+			BoardedAt    time.Time `json:"boarded_at"`
+			DroppedOffAt time.Time `json:"dropped_off_at"`
+		}
+		if err := json.Unmarshal(body, &b); err != nil {
+			return nil, err
+		}
+		return GroupDroppedOffEvent{
+			EventBasic:   events.NewEventBasic(aggregateID, name, body),
+			boardedAt:    b.BoardedAt,
+			droppedOffAt: b.DroppedOffAt,
+		}, nil
+	case GroupCancelledEventName:
+		var b struct {
+			Reason      string    `json:"reason"`
+			People      int       `json:"people"`
+			RequestedAt time.Time `json:"requested_at"`
+			CancelledAt time.Time `json:"cancelled_at"`
+		}
+		if err := json.Unmarshal(body, &b); err != nil {
+			return nil, err
+		}
+		return GroupCancelledEvent{
+			EventBasic:  events.NewEventBasic(aggregateID, name, body),
+			reason:      CancelReason(b.Reason),
+			people:      b.People,
+			requestedAt: b.RequestedAt,
+			cancelledAt: b.CancelledAt,
+		}, nil
+	case GroupExpiredEventName:
+		var b struct {
+			People      int       `json:"people"`
+			RequestedAt time.Time `json:"requested_at"`
+			ExpiredAt   time.Time `json:"expired_at"`
+		}
+		if err := json.Unmarshal(body, &b); err != nil {
+			return nil, err
+		}
+		return GroupExpiredEvent{
+			EventBasic:  events.NewEventBasic(aggregateID, name, body),
+			people:      b.People,
+			requestedAt: b.RequestedAt,
+			expiredAt:   b.ExpiredAt,
+		}, nil
+	case CarPositionReportedEventName:
+		return CarPositionReportedEvent{EventBasic: events.NewEventBasic(aggregateID, name, body)}, nil
+	case ReservationScheduledEventName:
+		return ReservationScheduledEvent{EventBasic: events.NewEventBasic(aggregateID, name, body)}, nil
+	case GroupUnservableEventName:
+		return GroupUnservableEvent{EventBasic: events.NewEventBasic(aggregateID, name, body)}, nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownEvent, name)
 	}
 }
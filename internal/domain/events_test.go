@@ -0,0 +1,181 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"theskyinflames/car-sharing/internal/domain"
+	"theskyinflames/car-sharing/internal/fixtures"
+	"theskyinflames/car-sharing/internal/helpers"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/theskyinflames/cqrs-eda/pkg/events"
+)
+
+func TestParseEvent(t *testing.T) {
+	var (
+		requestedAt = time.Now().Add(-time.Minute)
+		people      = 3
+		car         = fixtures.Car{}.Build()
+		onJourney   = fixtures.Group{People: &people, RequestedAt: &requestedAt}.Build()
+		droppedOff  = fixtures.Group{People: &people, RequestedAt: &requestedAt}.Build()
+	)
+	onJourney.GetOn(&car)
+	droppedOff.GetOn(&car)
+	droppedOff.DropOff()
+
+	testCases := []struct {
+		name      string
+		ev        events.Event
+		checkFunc func(*testing.T, events.Event)
+	}{
+		{
+			name: `Given a car created event, when it's parsed from its body, then the same event is returned`,
+			ev:   domain.NewCarCreatedEvent(car),
+			checkFunc: func(t *testing.T, ev events.Event) {
+				require.IsType(t, domain.CarCreatedEvent{}, ev)
+			},
+		},
+		{
+			name: `Given a car updated event, when it's parsed from its body, then the same event is returned`,
+			ev:   domain.NewCarUpdatedEvent(car),
+			checkFunc: func(t *testing.T, ev events.Event) {
+				require.IsType(t, domain.CarUpdatedEvent{}, ev)
+			},
+		},
+		{
+			name: `Given a car retiring event, when it's parsed from its body, then the same event is returned`,
+			ev:   domain.NewCarRetiringEvent(car),
+			checkFunc: func(t *testing.T, ev events.Event) {
+				require.IsType(t, domain.CarRetiringEvent{}, ev)
+			},
+		},
+		{
+			name: `Given a car retired event, when it's parsed from its body, then the same event is returned`,
+			ev:   domain.NewCarRetiredEvent(car),
+			checkFunc: func(t *testing.T, ev events.Event) {
+				require.IsType(t, domain.CarRetiredEvent{}, ev)
+			},
+		},
+		{
+			name: `Given a car out of service event, when it's parsed from its body, then the same event is returned`,
+			ev:   domain.NewCarOutOfServiceEvent(car),
+			checkFunc: func(t *testing.T, ev events.Event) {
+				require.IsType(t, domain.CarOutOfServiceEvent{}, ev)
+			},
+		},
+		{
+			name: `Given a car draining event, when it's parsed from its body, then the same event is returned`,
+			ev:   domain.NewCarDrainingEvent(car),
+			checkFunc: func(t *testing.T, ev events.Event) {
+				require.IsType(t, domain.CarDrainingEvent{}, ev)
+			},
+		},
+		{
+			name: `Given a car back in service event, when it's parsed from its body, then the same event is returned`,
+			ev:   domain.NewCarBackInServiceEvent(car),
+			checkFunc: func(t *testing.T, ev events.Event) {
+				require.IsType(t, domain.CarBackInServiceEvent{}, ev)
+			},
+		},
+		{
+			name: `Given a car reserved event, when it's parsed from its body, then the same event is returned`,
+			ev:   domain.NewCarReservedEvent(car, onJourney),
+			checkFunc: func(t *testing.T, ev events.Event) {
+				require.IsType(t, domain.CarReservedEvent{}, ev)
+			},
+		},
+		{
+			name: `Given a car reserved for scheduled journey event, when it's parsed from its body, then the same event is returned`,
+			ev:   domain.NewCarReservedForScheduledJourneyEvent(car, onJourney),
+			checkFunc: func(t *testing.T, ev events.Event) {
+				require.IsType(t, domain.CarReservedForScheduledJourneyEvent{}, ev)
+			},
+		},
+		{
+			name: `Given a group set on journey event, when it's parsed from its body, then the same event is returned`,
+			ev:   domain.NewGroupSetOnJourneyEvent(onJourney),
+			checkFunc: func(t *testing.T, ev events.Event) {
+				e, ok := ev.(domain.GroupSetOnJourneyEvent)
+				require.True(t, ok)
+				require.Equal(t, car.ID(), e.CarID())
+				require.Equal(t, car.Capacity(), e.Seats())
+				require.Equal(t, people, e.People())
+				require.True(t, requestedAt.Equal(e.RequestedAt()))
+				require.True(t, onJourney.BoardedAt().Equal(e.BoardedAt()))
+			},
+		},
+		{
+			name: `Given a group dropped off event, when it's parsed from its body, then the same event is returned`,
+			ev:   domain.NewGroupDroppedOff(droppedOff),
+			checkFunc: func(t *testing.T, ev events.Event) {
+				e, ok := ev.(domain.GroupDroppedOffEvent)
+				require.True(t, ok)
+				require.True(t, droppedOff.BoardedAt().Equal(e.BoardedAt()))
+				require.True(t, droppedOff.DroppedOffAt().Equal(e.DroppedOffAt()))
+			},
+		},
+		{
+			name: `Given a group cancelled event, when it's parsed from its body, then the same event is returned`,
+			ev:   domain.NewGroupCancelledEvent(onJourney, domain.CancelReasonPlansChanged, requestedAt),
+			checkFunc: func(t *testing.T, ev events.Event) {
+				e, ok := ev.(domain.GroupCancelledEvent)
+				require.True(t, ok)
+				require.Equal(t, domain.CancelReasonPlansChanged, e.Reason())
+				require.Equal(t, people, e.People())
+				require.True(t, requestedAt.Equal(e.RequestedAt()))
+				require.True(t, requestedAt.Equal(e.CancelledAt()))
+			},
+		},
+		{
+			name: `Given a group expired event, when it's parsed from its body, then the same event is returned`,
+			ev:   domain.NewGroupExpiredEvent(onJourney, requestedAt),
+			checkFunc: func(t *testing.T, ev events.Event) {
+				e, ok := ev.(domain.GroupExpiredEvent)
+				require.True(t, ok)
+				require.Equal(t, people, e.People())
+				require.True(t, requestedAt.Equal(e.RequestedAt()))
+				require.True(t, requestedAt.Equal(e.ExpiredAt()))
+			},
+		},
+		{
+			name: `Given a group unservable event, when it's parsed from its body, then the same event is returned`,
+			ev:   domain.NewGroupUnservableEvent(onJourney, domain.CarCapacity4),
+			checkFunc: func(t *testing.T, ev events.Event) {
+				require.IsType(t, domain.GroupUnservableEvent{}, ev)
+			},
+		},
+		{
+			name: `Given a car position reported event, when it's parsed from its body, then the same event is returned`,
+			ev:   domain.NewCarPositionReportedEvent(fixtures.Car{Position: &domain.Location{Lat: 41.3874, Lng: 2.1686}}.Build()),
+			checkFunc: func(t *testing.T, ev events.Event) {
+				require.IsType(t, domain.CarPositionReportedEvent{}, ev)
+			},
+		},
+		{
+			name: `Given a reservation scheduled event, when it's parsed from its body, then the same event is returned`,
+			ev:   domain.NewReservationScheduledEvent(fixtures.Reservation{CarID: helpers.UUIDPtr(car.ID())}.Build()),
+			checkFunc: func(t *testing.T, ev events.Event) {
+				require.IsType(t, domain.ReservationScheduledEvent{}, ev)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			body, err := domain.EventBody(tc.ev)
+			require.NoError(t, err)
+			ev, err := domain.ParseEvent(tc.ev.Name(), tc.ev.AggregateID(), body)
+			require.NoError(t, err)
+			require.Equal(t, tc.ev.Name(), ev.Name())
+			require.Equal(t, tc.ev.AggregateID(), ev.AggregateID())
+			tc.checkFunc(t, ev)
+		})
+	}
+
+	t.Run(`Given an unknown event, when it's parsed, then an error is returned`, func(t *testing.T) {
+		_, err := domain.ParseEvent("unknown", uuid.New(), nil)
+		require.ErrorIs(t, err, domain.ErrUnknownEvent)
+	})
+}
@@ -0,0 +1,92 @@
+package domain
+
+import (
+	"errors"
+	"sort"
+)
+
+// Feature is an equipment of a car that a group can require, i.e. the wheelchair access
+type Feature string
+
+// Car features
+const (
+	FeatureWheelchair Feature = "wheelchair"
+	FeatureChildSeat  Feature = "child_seat"
+	FeatureLuggage    Feature = "luggage"
+)
+
+// ErrWrongFeature is self-described
+var ErrWrongFeature = errors.New("wrong feature, it has to be wheelchair, child_seat or luggage")
+
+// ParseFeature is self-described
+func ParseFeature(s string) (Feature, error) {
+	switch f := Feature(s); f {
+	case FeatureWheelchair, FeatureChildSeat, FeatureLuggage:
+		return f, nil
+	default:
+		return "", ErrWrongFeature
+	}
+}
+
+// Features is a set of features. It's sorted and without duplicates, so two sets can be compared
+type Features []Feature
+
+// NewFeatures is a constructor
+func NewFeatures(fs ...Feature) Features {
+	if len(fs) == 0 {
+		return nil
+	}
+	set := make(map[Feature]struct{}, len(fs))
+	features := make(Features, 0, len(fs))
+	for _, f := range fs {
+		if _, ok := set[f]; ok {
+			continue
+		}
+		set[f] = struct{}{}
+		features = append(features, f)
+	}
+	sort.Slice(features, func(i, j int) bool { return features[i] < features[j] })
+	return features
+}
+
+// ParseFeatures is self-described
+func ParseFeatures(ss []string) (Features, error) {
+	fs := make([]Feature, 0, len(ss))
+	for _, s := range ss {
+		f, err := ParseFeature(s)
+		if err != nil {
+			return nil, err
+		}
+		fs = append(fs, f)
+	}
+	return NewFeatures(fs...), nil
+}
+
+// Has returns TRUE if the feature is in the set
+func (fs Features) Has(f Feature) bool {
+	for _, ff := range fs {
+		if ff == f {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAll returns TRUE if all the given features are in the set
+func (fs Features) HasAll(other Features) bool {
+	for _, f := range other {
+		if !fs.Has(f) {
+			return false
+		}
+	}
+	return true
+}
+
+// Strings is self-described
+func (fs Features) Strings() []string {
+	ss := make([]string, 0, len(fs))
+	for _, f := range fs {
+		ss = append(ss, string(f))
+	}
+	return ss
+}
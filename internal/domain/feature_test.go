@@ -0,0 +1,54 @@
+package domain_test
+
+import (
+	"testing"
+
+	"theskyinflames/car-sharing/internal/domain"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFeatures(t *testing.T) {
+	testCases := []struct {
+		name            string
+		ss              []string
+		expected        domain.Features
+		expectedErrFunc func(*testing.T, error)
+	}{
+		{
+			name: `Given no features, when it's called, then an empty set is returned`,
+		},
+		{
+			name:     `Given features unsorted and duplicated, when it's called, then they are returned sorted and without duplicates`,
+			ss:       []string{"wheelchair", "luggage", "wheelchair", "child_seat"},
+			expected: domain.Features{domain.FeatureChildSeat, domain.FeatureLuggage, domain.FeatureWheelchair},
+		},
+		{
+			name: `Given a wrong feature, when it's called, then an error is returned`,
+			ss:   []string{"luggage", "sunroof"},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, domain.ErrWrongFeature)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		fs, err := domain.ParseFeatures(tc.ss)
+		require.Equal(t, tc.expectedErrFunc == nil, err == nil, tc.name)
+		if err != nil {
+			tc.expectedErrFunc(t, err)
+			continue
+		}
+		require.Equal(t, tc.expected, fs, tc.name)
+	}
+}
+
+func TestFeaturesHasAll(t *testing.T) {
+	fs := domain.NewFeatures(domain.FeatureWheelchair, domain.FeatureLuggage)
+
+	require.True(t, fs.HasAll(nil))
+	require.True(t, fs.HasAll(domain.NewFeatures(domain.FeatureWheelchair)))
+	require.True(t, fs.HasAll(domain.NewFeatures(domain.FeatureLuggage, domain.FeatureWheelchair)))
+	require.False(t, fs.HasAll(domain.NewFeatures(domain.FeatureWheelchair, domain.FeatureChildSeat)))
+	require.False(t, domain.Features(nil).HasAll(domain.NewFeatures(domain.FeatureLuggage)))
+}
@@ -3,29 +3,114 @@ package domain
 import (
 	"errors"
 	"sort"
+	"time"
 
 	"github.com/google/uuid"
 )
 
+// ErrGroupNotServable is self-described
+var ErrGroupNotServable = errors.New("the group doesn't fit in any car of its site that meets its requirements")
+
 // Fleet is a domain service
 type Fleet struct {
-	cars          []Car
+	cars []Car
+
+	// waitingGroups are the groups of all the sites, sorted by arrival. Each site serves its own ones,
+	// so they make a queue by site
 	waitingGroups []Group
+
+	strategy AssignmentStrategy
+	aging    AgingPolicy
+	limits   CapacityLimits
+
+	// reservations are the upcoming reservations, and holdBack the time before their start from which
+	// the seats they booked are held back
+	reservations []Reservation
+	holdBack     time.Duration
+
+	// maxPickupRadius is the farthest, in kilometers, that a car goes to pick up a group. Zero if there is no limit
+	maxPickupRadius float64
+
+	// overtaken are the waiting groups whose overtaken counter has changed
+	overtaken map[uuid.UUID]struct{}
+
+	clock Clock
+}
+
+// Clock tells the current time
+type Clock interface {
+	Now() time.Time
+}
+
+// FleetOption customizes the Fleet business rules
+type FleetOption func(*Fleet)
+
+// WithAssignmentStrategy sets the strategy used to choose the car where a group gets on
+func WithAssignmentStrategy(s AssignmentStrategy) FleetOption {
+	return func(f *Fleet) {
+		f.strategy = s
+	}
+}
+
+// WithAgingPolicy sets the policy that prevents the starvation of the waiting groups
+func WithAgingPolicy(p AgingPolicy) FleetOption {
+	return func(f *Fleet) {
+		f.aging = p
+	}
+}
+
+// WithCapacityLimits sets the seats that a car of the fleet can have
+func WithCapacityLimits(l CapacityLimits) FleetOption {
+	return func(f *Fleet) {
+		f.limits = l
+	}
+}
+
+// WithReservations sets the upcoming reservations, whose seats are held back for them
+func WithReservations(rs []Reservation) FleetOption {
+	return func(f *Fleet) {
+		f.reservations = rs
+	}
+}
+
+// WithReservationHoldBack sets the time before the start of a reservation from which its seats are held back.
+// By default, DefaultReservationHoldBack
+func WithReservationHoldBack(d time.Duration) FleetOption {
+	return func(f *Fleet) {
+		f.holdBack = d
+	}
+}
+
+// WithMaxPickupRadius sets the farthest, in kilometers, that a car goes to pick up a group.
+// It only applies to the groups that give their pickup location and the cars that have reported their position
+func WithMaxPickupRadius(km float64) FleetOption {
+	return func(f *Fleet) {
+		f.maxPickupRadius = km
+	}
+}
+
+// WithClock sets the clock that tells when the reservations hold back their seats and the groups starve.
+// By default, the time of the system
+func WithClock(c Clock) FleetOption {
+	return func(f *Fleet) {
+		f.clock = c
+	}
 }
 
-// NewFleet is a constructor
-func NewFleet(evs []Car, waitingGroups []Group) Fleet {
-	fleet := Fleet{cars: evs, waitingGroups: waitingGroups}
+// NewFleet is a constructor. The cars are kept in the given order
+func NewFleet(evs []Car, waitingGroups []Group, opts ...FleetOption) Fleet {
+	fleet := Fleet{cars: evs, waitingGroups: waitingGroups, overtaken: make(map[uuid.UUID]struct{})}
+	for _, opt := range opts {
+		opt(&fleet)
+	}
 	fleet.sort()
+	fleet.holdBackSeats()
 	return fleet
 }
 
 func (f Fleet) sort() {
-	sort.SliceStable(f.cars, func(i, j int) bool { // order descending by ev availability
-		return f.cars[i].Availability() > f.cars[j].Availability()
-	})
-	sort.SliceStable(f.waitingGroups, func(i, j int) bool { // order ascending by group size
-		return f.waitingGroups[i].People() < f.waitingGroups[j].People()
+	sort.SliceStable(f.waitingGroups, func(i, j int) bool { // order ascending by arrival, first come, first serve
+		return f.waitingGroups[i].ArrivedBefore(f.waitingGroups[j])
 	})
 }
 
@@ -39,32 +124,145 @@ func (f Fleet) WaitingGroups() []Group {
 	return f.waitingGroups
 }
 
+// Now returns the current time, as told by the clock of the fleet
+func (f Fleet) Now() time.Time {
+	if f.clock == nil {
+		return time.Now()
+	}
+	return f.clock.Now()
+}
+
+// CapacityLimits returns the seats that a car of the fleet can have
+func (f Fleet) CapacityLimits() CapacityLimits {
+	return f.limits.orDefault()
+}
+
+// LargestCapacity returns the capacity of the largest car of the site. The retiring cars are skipped, because they
+// are leaving the fleet, but not the ones out of service, because they can come back
+func (f Fleet) LargestCapacity(site Site) CarCapacity {
+	return f.largestCapacity(site, nil)
+}
+
+// LargestCapacityFor returns the capacity of the largest car of the site of the group that meets its requirements
+func (f Fleet) LargestCapacityFor(g Group) CarCapacity {
+	return f.largestCapacity(g.Site(), g.Requirements())
+}
+
+func (f Fleet) largestCapacity(site Site, requirements Features) CarCapacity {
+	var largest CarCapacity
+	for _, car := range f.cars {
+		if car.Site() == site.orDefault() && !car.IsRetiring() && car.Features().HasAll(requirements) &&
+			car.Capacity() > largest {
+			largest = car.Capacity()
+		}
+	}
+	return largest
+}
+
+// CanServe returns TRUE if the group fits in some car of its site that meets its requirements, now or once it has room enough
+func (f Fleet) CanServe(g Group) bool {
+	return g.People() <= f.LargestCapacityFor(g).Int()
+}
+
+// UnservableGroups returns the waiting groups that don't fit in any car of their site, in order of arrival
+func (f Fleet) UnservableGroups() []Group {
+	var unservable []Group
+	for _, g := range f.waitingGroups {
+		if !f.CanServe(g) {
+			unservable = append(unservable, g)
+		}
+	}
+	return unservable
+}
+
+// OvertakenGroups returns the waiting groups whose overtaken counter has changed, so they have to be persisted
+func (f Fleet) OvertakenGroups() []Group {
+	var overtaken []Group
+	for _, g := range f.waitingGroups {
+		if _, ok := f.overtaken[g.ID()]; ok {
+			overtaken = append(overtaken, g)
+		}
+	}
+	return overtaken
+}
+
 // Hydrate hydrates a Fleet. Used for testing
-func (f *Fleet) Hydrate(cars []Car, waitingGroups []Group) {
+func (f *Fleet) Hydrate(cars []Car, waitingGroups []Group, opts ...FleetOption) {
 	f.cars = cars
 	f.waitingGroups = waitingGroups
+	f.overtaken = make(map[uuid.UUID]struct{})
+	for _, opt := range opts {
+		opt(f)
+	}
 	f.sort()
+	f.holdBackSeats()
 }
 
-// Journey adds a new group to the EV and out it on journey state
+func (f Fleet) assignmentStrategy() AssignmentStrategy {
+	if f.strategy == nil {
+		return WorstFit{}
+	}
+	return f.strategy
+}
+
+// Journey adds a new group to the car picked by the assignment strategy, and put it on journey state.
+// Only the cars of the origin site of the group that meet all its requirements are candidates. The cars reserved for a starving group,
+// and the ones that don't accept groups, retiring or out of service, are skipped.
+// A group that gives its destination is only pooled into the cars whose groups go to the same destination,
+// falling back to an empty car if none of them has room for it.
 func (f Fleet) Journey(g Group) (Group, Car) {
-	for _, car := range f.cars {
-		if err := car.GetOn(g); err == nil {
-			g.GetOn(&car)
-			return g, car
+	var (
+		pooled, empty               []Car
+		pooledIndexes, emptyIndexes []int
+	)
+	for i, car := range f.cars {
+		if car.Site() != g.Site() || !car.Meets(g) || f.isReservedForWaitingGroup(car) || !car.acceptsGroups() ||
+			!f.withinPickupRadius(car, g) || !car.poolsWith(g) {
+			continue
+		}
+		if g.Destination() != "" && len(car.Journeys()) == 0 {
+			empty = append(empty, car)
+			emptyIndexes = append(emptyIndexes, i)
+			continue
+		}
+		pooled = append(pooled, car)
+		pooledIndexes = append(pooledIndexes, i)
+	}
+
+	indexes := pooledIndexes
+	i := f.assignmentStrategy().Pick(pooled, g)
+	if i < 0 {
+		indexes = emptyIndexes
+		i = f.assignmentStrategy().Pick(empty, g)
+	}
+	if i < 0 {
+		return g, Car{}
+	}
+	car := f.cars[indexes[i]]
+	if err := car.GetOn(g); err != nil {
+		return g, Car{}
+	}
+	g.GetOn(&car)
+	f.cars[indexes[i]] = car
+
+	for i := range f.waitingGroups { // all the waiting groups of its site arrived before the new one
+		if f.waitingGroups[i].Site() == g.Site() {
+			f.overtake(&f.waitingGroups[i])
 		}
 	}
-	return g, Car{}
+	return g, car
 }
 
-// DropOff removes a group from the waiting list, or from its ev if it's on journey
+// DropOff removes a group from the waiting list, or from its ev if it's on journey. A waiting group leaves as
+// a cancelled one does, so the car reserved for it, if any, is released and returned
 func (f *Fleet) DropOff(g *Group, car *Car) (*Car, Journeys, error) {
 	if car == nil {
-		// look for the group in the waiting list
-		if removed := f.removeGroupsFromWaitingList(map[uuid.UUID]Group{g.ID(): *g}); removed == 0 {
-			return nil, nil, ErrNotFound
+		// a waiting group has not boarded, so nothing is recorded when it leaves
+		changed, newJourneys, err := f.leaveWaitingList(g, func() error { return nil })
+		if err != nil || len(changed) == 0 {
+			return nil, newJourneys, err
 		}
-		return nil, nil, nil
+		return &changed[0], newJourneys, nil
 	}
 
 	// At ch update on Journey Groups
@@ -80,24 +278,82 @@ func (f *Fleet) DropOff(g *Group, car *Car) (*Car, Journeys, error) {
 	return car, newJourneys, nil
 }
 
+// Cancel removes a waiting group from the waiting list. If a car was reserved for it, the reservation is released,
+// and the waiting groups that fit in the seats it held back get on the car. It returns that car, if it has changed,
+// and the groups that got on it
+func (f *Fleet) Cancel(g *Group, reason CancelReason) ([]Car, Journeys, error) {
+	return f.leaveWaitingList(g, func() error { return g.Cancel(reason) })
+}
+
+// Expire removes a group that has been waiting too long from the waiting list, as Cancel does
+func (f *Fleet) Expire(g *Group) ([]Car, Journeys, error) {
+	return f.leaveWaitingList(g, g.Expire)
+}
+
+// leaveWaitingList removes the group from the waiting list, and records why it leaves
+func (f *Fleet) leaveWaitingList(g *Group, leave func() error) ([]Car, Journeys, error) {
+	if g.IsOnJourney() {
+		return nil, nil, ErrGroupOnJourney
+	}
+	if removed := f.removeGroupsFromWaitingList(map[uuid.UUID]Group{g.ID(): *g}); removed == 0 {
+		return nil, nil, ErrNotFound
+	}
+	if err := leave(); err != nil {
+		return nil, nil, err
+	}
+
+	for i := range f.cars {
+		car := f.cars[i]
+		if car.ReservedFor() != g.ID() {
+			continue
+		}
+		car.ReleaseReservation()
+		newJourneys, err := f.RebuildWaitingGroupsList(&car)
+		if err != nil {
+			return nil, nil, err
+		}
+		f.cars[i] = car
+		return []Car{car}, newJourneys, nil
+	}
+	return nil, make(Journeys), nil
+}
+
 // RebuildWaitingGroupsList is self-described
 func (f *Fleet) RebuildWaitingGroupsList(car *Car) (newJourneys Journeys, err error) { // At ch update on journey groups
 	newJourneys = make(Journeys)
+	if !car.acceptsGroups() {
+		return newJourneys, nil
+	}
+	car.holdBack(f.heldBackFor(*car, f.Now()))
 
-	// Try to use the availability of the car to fit in it so many groups as it's possible
-	for _, wg := range f.waitingGroups {
-		if err := car.GetOn(wg); err != nil {
-			if errors.Is(err, ErrNotFit) {
-				continue
-			}
+	starving, ok := f.starvingGroupFor(car)
+	if ok {
+		if car.Availability() < starving.People() || !car.poolsWith(starving) {
+			return newJourneys, nil // the freed seats are kept for the starving group
+		}
+		car.ReleaseReservation()
+		if err := f.getOn(car, starving, newJourneys); err != nil {
 			return nil, err
 		}
-		wg.GetOn(car)
-		newJourneys[wg.ID()] = wg
+	}
 
+	// Try to use the availability of the car to fit in it so many groups as it's possible.
+	// Waiting groups are sorted by arrival, so the oldest group of its site that fits, and that goes
+	// to the same destination as the groups on the car, is served first
+	for _, wg := range f.waitingGroups {
 		if car.Availability() == 0 {
 			break
 		}
+		if _, ok := newJourneys[wg.ID()]; ok || wg.Site() != car.Site() || !car.Meets(wg) || !f.withinPickupRadius(*car, wg) ||
+			!car.poolsWith(wg) {
+			continue
+		}
+		if err := f.getOn(car, wg, newJourneys); err != nil {
+			if errors.Is(err, ErrNotFit) {
+				continue
+			}
+			return nil, err
+		}
 	}
 
 	if len(newJourneys) > 0 {
@@ -107,14 +363,360 @@ func (f *Fleet) RebuildWaitingGroupsList(car *Car) (newJourneys Journeys, err er
 	return newJourneys, nil
 }
 
-func (f *Fleet) removeGroupsFromWaitingList(toRemove map[uuid.UUID]Group) int {
-	var removed int
-	tmp := make(map[uuid.UUID]Group)
+// AddCars adds the cars to the fleet, and the waiting groups that fit in them get on them.
+// It returns the added cars, with their journeys, and the groups that got on them
+func (f *Fleet) AddCars(cars []Car) ([]Car, Journeys, error) {
+	first := len(f.cars)
+	f.cars = append(f.cars, cars...)
+
+	newJourneys := make(Journeys)
+	for i := first; i < len(f.cars); i++ {
+		car := f.cars[i]
+		nj, err := f.RebuildWaitingGroupsList(&car)
+		if err != nil {
+			return nil, nil, err
+		}
+		f.cars[i] = car
+		for id, g := range nj {
+			newJourneys[id] = g
+		}
+	}
+	return append([]Car{}, f.cars[first:]...), newJourneys, nil
+}
+
+// Retire asks the car to leave the fleet. If it was reserved for a starving group, the other cars are reassigned,
+// so the group can reserve another one. It returns the other cars that have changed, and the groups that got on them
+func (f *Fleet) Retire(car *Car) ([]Car, Journeys, error) {
+	wasReserved := f.isReservedForWaitingGroup(*car)
+	if err := car.Retire(); err != nil {
+		return nil, nil, err
+	}
+	f.replace(*car)
+
+	if !wasReserved {
+		return nil, make(Journeys), nil
+	}
+	return f.reassign(car.ID())
+}
+
+// TakeOutOfService takes the car out of service. If it was reserved for a starving group, the other cars are reassigned,
+// so the group can reserve another one. It returns the other cars that have changed, and the groups that got on them
+func (f *Fleet) TakeOutOfService(car *Car) ([]Car, Journeys, error) {
+	wasReserved := f.isReservedForWaitingGroup(*car)
+	if err := car.TakeOutOfService(); err != nil {
+		return nil, nil, err
+	}
+	f.replace(*car)
+
+	if !wasReserved {
+		return nil, make(Journeys), nil
+	}
+	return f.reassign(car.ID())
+}
+
+// BackInService makes the car available again, and the waiting groups that fit in it get on it
+func (f *Fleet) BackInService(car *Car) (Journeys, error) {
+	if err := car.BackInService(); err != nil {
+		return nil, err
+	}
+	newJourneys, err := f.RebuildWaitingGroupsList(car)
+	if err != nil {
+		return nil, err
+	}
+	f.replace(*car)
+	return newJourneys, nil
+}
+
+// ReportPosition sets the current position of the car, and the waiting groups that are within the pickup radius
+// now and fit in it get on it. It returns the groups that got on the car
+func (f *Fleet) ReportPosition(car *Car, l Location) (Journeys, error) {
+	car.ReportPosition(l)
+	newJourneys, err := f.RebuildWaitingGroupsList(car)
+	if err != nil {
+		return nil, err
+	}
+	f.replace(*car)
+	return newJourneys, nil
+}
+
+// ChangeCapacity changes the capacity of the car, and the waiting groups that fit in it get on it.
+// If the car can't hold its starving group anymore, its reservation is released and the other cars are reassigned.
+// It returns the other cars that have changed, and the groups that got on any car
+func (f *Fleet) ChangeCapacity(car *Car, capacity CarCapacity) ([]Car, Journeys, error) {
+	if err := car.ChangeCapacity(capacity); err != nil {
+		return nil, nil, err
+	}
+
+	var released bool
+	if starving, ok := f.waitingGroup(car.ReservedFor()); ok && starving.People() > capacity.Int() {
+		car.ReleaseReservation()
+		released = true
+	}
+
+	newJourneys, err := f.RebuildWaitingGroupsList(car)
+	if err != nil {
+		return nil, nil, err
+	}
+	f.replace(*car)
+	if !released {
+		return nil, newJourneys, nil
+	}
+
+	changed, nj, err := f.reassign(car.ID())
+	if err != nil {
+		return nil, nil, err
+	}
+	for id, g := range nj {
+		newJourneys[id] = g
+	}
+	return changed, newJourneys, nil
+}
+
+// Schedule books the seats of the first car of the site of the reservation that can hold it, besides the other
+// reservations that overlap its window. The retiring cars, the ones out of service and the ones that don't have
+// the features required by the reservation are skipped, as they are when a group asks for a car
+func (f *Fleet) Schedule(r *Reservation) error {
+	for _, car := range f.cars {
+		if car.Site() != r.Site() || !car.Features().HasAll(r.Requirements()) || !car.acceptsGroups() ||
+			car.Capacity().Int() < r.People() {
+			continue
+		}
+		booked := r.People()
+		for _, other := range f.reservations {
+			if other.CarID() == car.ID() && other.Overlaps(*r) {
+				booked += other.People()
+			}
+		}
+		if booked <= car.Capacity().Int() {
+			r.book(car)
+			f.reservations = append(f.reservations, *r)
+			return nil
+		}
+	}
+	return ErrReservationNotHonourable
+}
+
+// StartReservation turns the reservation into the journey of its group, which gets on the booked car.
+// If the groups on journey have not freed seats enough, the car is reserved for the group, so it gets on it
+// as soon as they do. If the car doesn't take groups anymore, the group asks for any car, as the walk-in ones.
+// It returns the group and the car that has changed, if any
+func (f *Fleet) StartReservation(r Reservation, g Group) (Group, *Car) {
+	f.removeReservation(r.ID())
+
+	for i := range f.cars {
+		car := f.cars[i]
+		if car.ID() != r.CarID() || !car.Meets(g) || !car.acceptsGroups() || f.isReservedForWaitingGroup(car) {
+			continue
+		}
+		car.holdBack(f.heldBackFor(car, f.Now()))
+		if err := car.GetOn(g); err != nil {
+			car.ReserveForScheduledJourney(g)
+			f.cars[i] = car
+			f.waitingGroups = append(f.waitingGroups, g)
+			return g, &car
+		}
+		g.GetOn(&car)
+		f.cars[i] = car
+		return g, &car
+	}
+
+	g, car := f.Journey(g)
+	if !g.IsOnJourney() {
+		f.waitingGroups = append(f.waitingGroups, g)
+		return g, nil
+	}
+	return g, &car
+}
+
+// withinPickupRadius returns TRUE if the car can pick up the group. If the group has not given its pickup location,
+// or the car has not reported its position, the distance is unknown and it's not limited
+func (f Fleet) withinPickupRadius(car Car, g Group) bool {
+	if f.maxPickupRadius == 0 || g.Pickup() == nil || car.Position() == nil {
+		return true
+	}
+	return car.Position().DistanceTo(*g.Pickup()) <= f.maxPickupRadius
+}
+
+// holdBackSeats holds back the seats of the cars booked by the upcoming reservations
+func (f Fleet) holdBackSeats() {
+	now := f.Now()
+	for i := range f.cars {
+		f.cars[i].holdBack(f.heldBackFor(f.cars[i], now))
+	}
+}
+
+// heldBackFor returns the seats of the car booked by the reservations that start within the hold back time.
+// A reservation whose window has ended doesn't hold back its seats anymore, even if it has not been started
+func (f Fleet) heldBackFor(car Car, now time.Time) int {
+	holdBack := f.holdBack
+	if holdBack == 0 {
+		holdBack = DefaultReservationHoldBack
+	}
+	var seats int
+	for _, r := range f.reservations {
+		if r.CarID() == car.ID() && r.HasStarted(now.Add(holdBack)) && !r.HasEnded(now) {
+			seats += r.People()
+		}
+	}
+	return seats
+}
+
+func (f *Fleet) removeReservation(id uuid.UUID) {
+	reservations := f.reservations
+	f.reservations = make([]Reservation, 0, len(reservations))
+	for _, r := range reservations {
+		if r.ID() != id {
+			f.reservations = append(f.reservations, r)
+		}
+	}
+}
+
+// reassign gives the waiting groups a chance to get on the cars, but the excluded one, in order.
+// It returns the cars that have changed, and the groups that got on them
+func (f *Fleet) reassign(exceptCarID uuid.UUID) ([]Car, Journeys, error) {
+	var (
+		changed     []Car
+		newJourneys = make(Journeys)
+	)
+	for i := range f.cars {
+		car := f.cars[i]
+		if car.ID() == exceptCarID || !car.acceptsGroups() {
+			continue
+		}
+		reservedFor := car.ReservedFor()
+		nj, err := f.RebuildWaitingGroupsList(&car)
+		if err != nil {
+			return nil, nil, err
+		}
+		f.cars[i] = car
+		if len(nj) > 0 || car.ReservedFor() != reservedFor {
+			changed = append(changed, car)
+		}
+		for id, g := range nj {
+			newJourneys[id] = g
+		}
+	}
+	return changed, newJourneys, nil
+}
+
+// replace replaces the fleet copy of the car
+func (f *Fleet) replace(car Car) {
+	for i := range f.cars {
+		if f.cars[i].ID() == car.ID() {
+			f.cars[i] = car
+			return
+		}
+	}
+}
+
+func (f *Fleet) getOn(car *Car, g Group, newJourneys Journeys) error {
+	if err := car.GetOn(g); err != nil {
+		return err
+	}
+	g.GetOn(car)
+	newJourneys[g.ID()] = g
+
+	for i := range f.waitingGroups { // the older groups of its site that are still waiting have been overtaken
+		wg := &f.waitingGroups[i]
+		if _, ok := newJourneys[wg.ID()]; ok || wg.Site() != g.Site() || !wg.ArrivedBefore(g) {
+			continue
+		}
+		f.overtake(wg)
+	}
+	return nil
+}
+
+// starvingGroupFor returns the starving group that the car is reserved for. If the car is not reserved yet,
+// it's reserved for the oldest starving group of its site that fits in it, whose requirements it meets,
+// and that has not already reserved another car.
+func (f *Fleet) starvingGroupFor(car *Car) (Group, bool) {
+	if car.IsReserved() {
+		if g, ok := f.waitingGroup(car.ReservedFor()); ok {
+			return g, true
+		}
+		car.ReleaseReservation() // the group is not waiting anymore
+	}
+
+	if !f.aging.IsEnabled() {
+		return Group{}, false
+	}
+
+	now := f.Now()
+	for _, wg := range f.waitingGroups {
+		if wg.Site() != car.Site() || !car.Meets(wg) || !f.aging.IsStarving(wg, now) || wg.People() > car.Capacity().Int() ||
+			f.hasReservedCar(wg, car.ID()) {
+			continue
+		}
+		if car.Availability() < wg.People() || !car.poolsWith(wg) {
+			car.Reserve(wg)
+		}
+		return wg, true
+	}
+	return Group{}, false
+}
+
+// HasReservation returns TRUE if there is a reservation with the ID, which is the ID of its group once it starts
+func (f Fleet) HasReservation(id uuid.UUID) bool {
+	for _, r := range f.reservations {
+		if r.ID() == id {
+			return true
+		}
+	}
+	return false
+}
+
+// HasGroup returns TRUE if the group is waiting for a car, or on journey in some car
+func (f Fleet) HasGroup(id uuid.UUID) bool {
+	if _, ok := f.waitingGroup(id); ok {
+		return true
+	}
+	for _, car := range f.cars {
+		if _, ok := car.Journeys()[id]; ok {
+			return true
+		}
+	}
+	return false
+}
+
+func (f Fleet) waitingGroup(id uuid.UUID) (Group, bool) {
 	for _, g := range f.waitingGroups {
-		tmp[g.ID()] = g
+		if g.ID() == id {
+			return g, true
+		}
 	}
+	return Group{}, false
+}
+
+func (f Fleet) hasReservedCar(g Group, exceptCarID uuid.UUID) bool {
+	for _, car := range f.cars {
+		if car.ID() != exceptCarID && car.ReservedFor() == g.ID() {
+			return true
+		}
+	}
+	return false
+}
+
+func (f Fleet) isReservedForWaitingGroup(car Car) bool {
+	if !car.IsReserved() {
+		return false
+	}
+	_, ok := f.waitingGroup(car.ReservedFor())
+	return ok
+}
+
+func (f Fleet) overtake(g *Group) {
+	if f.aging.MaxOvertakes == 0 {
+		return
+	}
+	g.Overtake()
+	f.overtaken[g.ID()] = struct{}{}
+}
+
+func (f *Fleet) removeGroupsFromWaitingList(toRemove map[uuid.UUID]Group) int {
+	var removed int
+	waitingGroups := f.waitingGroups
 	f.waitingGroups = make([]Group, 0)
-	for _, g := range tmp {
+	for _, g := range waitingGroups { // keep the arrival order
 		_, ok := toRemove[g.ID()]
 		if !ok {
 			f.waitingGroups = append(f.waitingGroups, g)
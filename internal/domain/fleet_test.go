@@ -2,6 +2,7 @@ package domain_test
 
 import (
 	"testing"
+	"time"
 
 	"theskyinflames/car-sharing/internal/domain"
 	"theskyinflames/car-sharing/internal/fixtures"
@@ -11,6 +12,14 @@ import (
 	"github.com/stretchr/testify/require"
 )
 
+type fixedClock struct {
+	now time.Time
+}
+
+func (c fixedClock) Now() time.Time {
+	return c.now
+}
+
 func TestNewFleet(t *testing.T) {
 	var (
 		carID1 = uuid.New()
@@ -18,28 +27,35 @@ func TestNewFleet(t *testing.T) {
 		carID3 = uuid.New()
 
 		gID1 = uuid.New()
+		gID2 = uuid.New()
+		gID3 = uuid.New()
+
+		now = time.Now()
+		t1  = now.Add(-3 * time.Minute)
+		t2  = now.Add(-2 * time.Minute)
+		t3  = now.Add(-1 * time.Minute)
 	)
-	t.Run(`Given an unordered array of evs, when it's called, then a fleet is returned`, func(t *testing.T) {
+	t.Run(`Given an unordered array of evs and waiting groups, when it's called, then a fleet is returned`, func(t *testing.T) {
 		cars := []domain.Car{
 			fixtures.Car{ID: helpers.UUIDPtr(carID1), Capacity: helpers.CarCapacityPtr(domain.CarCapacity5)}.Build(),
 			fixtures.Car{ID: helpers.UUIDPtr(carID2), Capacity: helpers.CarCapacityPtr(domain.CarCapacity4)}.Build(),
 			fixtures.Car{ID: helpers.UUIDPtr(carID3), Capacity: helpers.CarCapacityPtr(domain.CarCapacity6)}.Build(),
 		}
 		wg := []domain.Group{
-			fixtures.Group{ID: helpers.UUIDPtr(gID1), People: helpers.IntPtr(3)}.Build(),
-			fixtures.Group{ID: helpers.UUIDPtr(gID1), People: helpers.IntPtr(1)}.Build(),
-			fixtures.Group{ID: helpers.UUIDPtr(gID1), People: helpers.IntPtr(2)}.Build(),
+			fixtures.Group{ID: helpers.UUIDPtr(gID3), People: helpers.IntPtr(1), RequestedAt: &t3}.Build(),
+			fixtures.Group{ID: helpers.UUIDPtr(gID1), People: helpers.IntPtr(3), RequestedAt: &t1}.Build(),
+			fixtures.Group{ID: helpers.UUIDPtr(gID2), People: helpers.IntPtr(2), RequestedAt: &t2}.Build(),
 		}
 		fleet := domain.NewFleet(cars, wg)
 		require.Equal(t, []domain.Car{
-			fixtures.Car{ID: helpers.UUIDPtr(carID3), Capacity: helpers.CarCapacityPtr(domain.CarCapacity6)}.Build(),
 			fixtures.Car{ID: helpers.UUIDPtr(carID1), Capacity: helpers.CarCapacityPtr(domain.CarCapacity5)}.Build(),
 			fixtures.Car{ID: helpers.UUIDPtr(carID2), Capacity: helpers.CarCapacityPtr(domain.CarCapacity4)}.Build(),
+			fixtures.Car{ID: helpers.UUIDPtr(carID3), Capacity: helpers.CarCapacityPtr(domain.CarCapacity6)}.Build(),
 		}, fleet.Cars())
 		require.Equal(t, []domain.Group{
-			fixtures.Group{ID: helpers.UUIDPtr(gID1), People: helpers.IntPtr(1)}.Build(),
-			fixtures.Group{ID: helpers.UUIDPtr(gID1), People: helpers.IntPtr(2)}.Build(),
-			fixtures.Group{ID: helpers.UUIDPtr(gID1), People: helpers.IntPtr(3)}.Build(),
+			fixtures.Group{ID: helpers.UUIDPtr(gID1), People: helpers.IntPtr(3), RequestedAt: &t1}.Build(),
+			fixtures.Group{ID: helpers.UUIDPtr(gID2), People: helpers.IntPtr(2), RequestedAt: &t2}.Build(),
+			fixtures.Group{ID: helpers.UUIDPtr(gID3), People: helpers.IntPtr(1), RequestedAt: &t3}.Build(),
 		}, fleet.WaitingGroups())
 	})
 }
@@ -105,6 +121,12 @@ func TestFleetRebuildWaitingGroupsList(t *testing.T) {
 		gID2 = uuid.New()
 		gID3 = uuid.New()
 		gID4 = uuid.New()
+
+		now = time.Now()
+		t1  = now.Add(-4 * time.Minute)
+		t2  = now.Add(-3 * time.Minute)
+		t3  = now.Add(-2 * time.Minute)
+		t4  = now.Add(-1 * time.Minute)
 	)
 	testCases := []struct {
 		name                    string
@@ -148,6 +170,28 @@ func TestFleetRebuildWaitingGroupsList(t *testing.T) {
 			},
 			expectedCarAvailability: 0,
 		},
+		{
+			name: `Given a list of waiting groups where an older group and a newer one compete for the same seats,
+				when it's called, then the oldest group that fits is served first`,
+			fleet: fixtures.Fleet{
+				WaitingGroups: []domain.Group{
+					fixtures.Group{ID: helpers.UUIDPtr(gID1), People: helpers.IntPtr(1), RequestedAt: &t4}.Build(),
+					fixtures.Group{ID: helpers.UUIDPtr(gID2), People: helpers.IntPtr(6), RequestedAt: &t1}.Build(),
+					fixtures.Group{ID: helpers.UUIDPtr(gID3), People: helpers.IntPtr(4), RequestedAt: &t2}.Build(),
+					fixtures.Group{ID: helpers.UUIDPtr(gID4), People: helpers.IntPtr(2), RequestedAt: &t3}.Build(),
+				},
+			}.Build(),
+			car: fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity5)}.Build(),
+			expectedOnJourney: map[int]domain.Group{
+				1: fixtures.Group{ID: helpers.UUIDPtr(gID3), People: helpers.IntPtr(4), RequestedAt: &t2}.Build(),
+				2: fixtures.Group{ID: helpers.UUIDPtr(gID1), People: helpers.IntPtr(1), RequestedAt: &t4}.Build(),
+			},
+			expectedWaitingGroups: []domain.Group{
+				fixtures.Group{ID: helpers.UUIDPtr(gID2), People: helpers.IntPtr(6), RequestedAt: &t1}.Build(),
+				fixtures.Group{ID: helpers.UUIDPtr(gID4), People: helpers.IntPtr(2), RequestedAt: &t3}.Build(),
+			},
+			expectedCarAvailability: 0,
+		},
 	}
 
 	for _, tc := range testCases {
@@ -204,6 +248,32 @@ func TestDropOff(t *testing.T) {
 		require.Equal(t, expectedWaitingGroups, fleet.WaitingGroups())
 	})
 
+	t.Run(`Given a starving group with a reserved car,
+	when it's dropped off while waiting,
+	then the reservation is released, the car is returned and the waiting groups that fit get on it`, func(t *testing.T) {
+		var (
+			longAgo  = time.Now().Add(-time.Hour)
+			starving = fixtures.Group{ID: helpers.UUIDPtr(gID1), People: helpers.IntPtr(5), RequestedAt: &longAgo}.Build()
+			small    = fixtures.Group{ID: helpers.UUIDPtr(gID2), People: helpers.IntPtr(2)}.Build()
+			onBoard  = fixtures.Group{ID: helpers.UUIDPtr(gID3), People: helpers.IntPtr(2)}.Build()
+			reserved = fixtures.Car{
+				Capacity:    helpers.CarCapacityPtr(domain.CarCapacity6),
+				Journeys:    domain.Journeys{onBoard.ID(): onBoard},
+				ReservedFor: helpers.UUIDPtr(starving.ID()),
+			}.Build()
+			fleet = domain.NewFleet([]domain.Car{reserved}, []domain.Group{starving, small})
+		)
+
+		resultCar, onJourney, err := fleet.DropOff(&starving, nil)
+		require.NoError(t, err)
+		require.NotNil(t, resultCar)
+		require.Equal(t, reserved.ID(), resultCar.ID())
+		require.False(t, resultCar.IsReserved())
+		require.Contains(t, resultCar.Journeys(), small.ID())
+		require.Contains(t, onJourney, small.ID())
+		require.Empty(t, fleet.WaitingGroups())
+	})
+
 	t.Run(`Given a group that is on journey state and no other waiting group can fit its car when it's dropped off,
 	when it's called,
 	then it's removed from the car and no error is returned`, func(t *testing.T) {
@@ -300,3 +370,900 @@ func dropOffSetup() (domain.Group, domain.Group, domain.Car) {
 	g.GetOn(&car)
 	return g, g2, car
 }
+
+func TestFleetStarvationGuard(t *testing.T) {
+	var (
+		longAgo  = time.Now().Add(-time.Hour)
+		recently = time.Now().Add(-time.Second)
+
+		carID = uuid.New()
+	)
+
+	setup := func(aging domain.AgingPolicy, opts ...domain.FleetOption) (domain.Fleet, domain.Car, domain.Group, domain.Group, domain.Group, domain.Group) {
+		var (
+			onJourney1 = fixtures.Group{ID: helpers.UUIDPtr(uuid.New()), People: helpers.IntPtr(2), RequestedAt: &longAgo}.Build()
+			onJourney2 = fixtures.Group{ID: helpers.UUIDPtr(uuid.New()), People: helpers.IntPtr(4), RequestedAt: &longAgo}.Build()
+			big        = fixtures.Group{ID: helpers.UUIDPtr(uuid.New()), People: helpers.IntPtr(6), RequestedAt: &longAgo}.Build()
+			small      = fixtures.Group{ID: helpers.UUIDPtr(uuid.New()), People: helpers.IntPtr(2), RequestedAt: &recently}.Build()
+			car        = fixtures.Car{
+				ID:       helpers.UUIDPtr(carID),
+				Capacity: helpers.CarCapacityPtr(domain.CarCapacity6),
+				Journeys: domain.Journeys{
+					onJourney1.ID(): onJourney1,
+					onJourney2.ID(): onJourney2,
+				},
+			}.Build()
+		)
+		onJourney1.GetOn(&car)
+		onJourney2.GetOn(&car)
+		var fleet domain.Fleet
+		fleet.Hydrate([]domain.Car{car}, []domain.Group{big, small}, append(opts, domain.WithAgingPolicy(aging))...)
+		return fleet, car, onJourney1, onJourney2, big, small
+	}
+
+	t.Run(`Given a waiting group of 6 people and no aging policy,
+		when some seats are freed,
+		then a smaller group overtakes it`, func(t *testing.T) {
+		fleet, car, onJourney1, _, big, small := setup(domain.AgingPolicy{})
+
+		resultCar, onJourney, err := fleet.DropOff(&onJourney1, &car)
+		require.NoError(t, err)
+		require.Contains(t, onJourney, small.ID())
+		require.False(t, resultCar.IsReserved())
+		require.Equal(t, []domain.Group{big}, fleet.WaitingGroups())
+	})
+
+	t.Run(`Given a starving group of 6 people,
+		when some seats are freed but not enough for it,
+		then the car is reserved for it and the smaller groups keep waiting`, func(t *testing.T) {
+		aging := domain.AgingPolicy{MaxWait: time.Minute}
+		fleet, car, onJourney1, onJourney2, big, small := setup(aging)
+
+		resultCar, onJourney, err := fleet.DropOff(&onJourney1, &car)
+		require.NoError(t, err)
+		require.Empty(t, onJourney)
+		require.Equal(t, big.ID(), resultCar.ReservedFor())
+		require.Len(t, fleet.WaitingGroups(), 2)
+
+		evs := resultCar.Events()
+		require.Len(t, evs, 1)
+		require.Equal(t, domain.CarReservedEventName, evs[0].Name())
+
+		withReservedCar := domain.NewFleet([]domain.Car{*resultCar}, fleet.WaitingGroups(), domain.WithAgingPolicy(aging))
+		g, _ := withReservedCar.Journey(fixtures.Group{ID: helpers.UUIDPtr(uuid.New()), People: helpers.IntPtr(1)}.Build())
+		require.False(t, g.IsOnJourney(), "a new group can't get on a reserved car")
+
+		resultCar, onJourney, err = fleet.DropOff(&onJourney2, resultCar)
+		require.NoError(t, err)
+		require.Len(t, onJourney, 1)
+		require.Contains(t, onJourney, big.ID())
+		require.False(t, resultCar.IsReserved())
+		require.Equal(t, []domain.Group{small}, fleet.WaitingGroups())
+	})
+
+	t.Run(`Given a group of 6 people that is not starving yet,
+		when some seats are freed once the clock has passed its max wait,
+		then the car is reserved for it`, func(t *testing.T) {
+		aging := domain.AgingPolicy{MaxWait: 2 * time.Hour}
+		fleet, car, onJourney1, _, big, _ := setup(aging, domain.WithClock(fixedClock{now: time.Now().Add(2 * time.Hour)}))
+
+		resultCar, onJourney, err := fleet.DropOff(&onJourney1, &car)
+		require.NoError(t, err)
+		require.Empty(t, onJourney)
+		require.Equal(t, big.ID(), resultCar.ReservedFor())
+	})
+
+	t.Run(`Given a max number of overtakes,
+		when a newer group gets on a car before a waiting group,
+		then the waiting group is overtaken`, func(t *testing.T) {
+		var (
+			waiting = fixtures.Group{ID: helpers.UUIDPtr(uuid.New()), People: helpers.IntPtr(6), RequestedAt: &longAgo}.Build()
+			fleet   = domain.NewFleet(
+				[]domain.Car{fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity4)}.Build()},
+				[]domain.Group{waiting},
+				domain.WithAgingPolicy(domain.AgingPolicy{MaxOvertakes: 1}),
+			)
+		)
+
+		g, _ := fleet.Journey(fixtures.Group{ID: helpers.UUIDPtr(uuid.New()), People: helpers.IntPtr(2)}.Build())
+		require.True(t, g.IsOnJourney())
+
+		overtaken := fleet.OvertakenGroups()
+		require.Len(t, overtaken, 1)
+		require.Equal(t, waiting.ID(), overtaken[0].ID())
+		require.Equal(t, 1, overtaken[0].Overtaken())
+	})
+}
+
+func TestFleetAddCars(t *testing.T) {
+	t.Run(`Given a fleet with waiting groups,
+		when some cars are added,
+		then the waiting groups that fit in them get on them`, func(t *testing.T) {
+		var (
+			big   = fixtures.Group{ID: helpers.UUIDPtr(uuid.New()), People: helpers.IntPtr(6)}.Build()
+			small = fixtures.Group{ID: helpers.UUIDPtr(uuid.New()), People: helpers.IntPtr(3)}.Build()
+			full  = fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity4)}.Build()
+			newID = uuid.New()
+			fleet = domain.NewFleet([]domain.Car{full}, []domain.Group{big, small})
+		)
+
+		added, onJourney, err := fleet.AddCars([]domain.Car{domain.NewCar(newID, domain.CarCapacity5)})
+		require.NoError(t, err)
+		require.Len(t, added, 1)
+		require.Equal(t, newID, added[0].ID())
+		require.Equal(t, 2, added[0].Availability())
+		require.Len(t, onJourney, 1)
+		require.Contains(t, onJourney, small.ID())
+		require.Len(t, fleet.Cars(), 2)
+		require.Equal(t, []domain.Group{big}, fleet.WaitingGroups())
+	})
+}
+
+func TestFleetRetire(t *testing.T) {
+	longAgo := time.Now().Add(-time.Hour)
+
+	t.Run(`Given a car reserved for a starving group,
+		when it's retired,
+		then another car is reserved for the group`, func(t *testing.T) {
+		var (
+			starving = fixtures.Group{ID: helpers.UUIDPtr(uuid.New()), People: helpers.IntPtr(5), RequestedAt: &longAgo}.Build()
+			onBoard  = fixtures.Group{ID: helpers.UUIDPtr(uuid.New()), People: helpers.IntPtr(2)}.Build()
+			reserved = fixtures.Car{
+				Capacity:    helpers.CarCapacityPtr(domain.CarCapacity6),
+				Journeys:    domain.Journeys{onBoard.ID(): onBoard},
+				ReservedFor: helpers.UUIDPtr(starving.ID()),
+			}.Build()
+			other = fixtures.Car{
+				Capacity: helpers.CarCapacityPtr(domain.CarCapacity6),
+				Journeys: domain.Journeys{onBoard.ID(): onBoard},
+			}.Build()
+			fleet = domain.NewFleet(
+				[]domain.Car{reserved, other},
+				[]domain.Group{starving},
+				domain.WithAgingPolicy(domain.AgingPolicy{MaxWait: time.Minute}),
+			)
+		)
+
+		changed, onJourney, err := fleet.Retire(&reserved)
+		require.NoError(t, err)
+		require.True(t, reserved.IsRetiring())
+		require.False(t, reserved.IsReserved())
+		require.Empty(t, onJourney)
+		require.Len(t, changed, 1)
+		require.Equal(t, other.ID(), changed[0].ID())
+		require.Equal(t, starving.ID(), changed[0].ReservedFor())
+	})
+
+	t.Run(`Given a retiring car,
+		when a group asks for a journey,
+		then it's not assigned to it`, func(t *testing.T) {
+		car := fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity6)}.Build()
+		fleet := domain.NewFleet([]domain.Car{car}, nil)
+		_, _, err := fleet.Retire(&car)
+		require.NoError(t, err)
+
+		g, _ := fleet.Journey(fixtures.Group{People: helpers.IntPtr(2)}.Build())
+		require.False(t, g.IsOnJourney())
+	})
+}
+
+func TestFleetChangeCapacity(t *testing.T) {
+	longAgo := time.Now().Add(-time.Hour)
+
+	t.Run(`Given a waiting group,
+		when the capacity of a car is increased,
+		then the group gets on it`, func(t *testing.T) {
+		var (
+			waiting = fixtures.Group{ID: helpers.UUIDPtr(uuid.New()), People: helpers.IntPtr(6)}.Build()
+			car     = fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity4)}.Build()
+			fleet   = domain.NewFleet([]domain.Car{car}, []domain.Group{waiting})
+		)
+
+		changed, onJourney, err := fleet.ChangeCapacity(&car, domain.CarCapacity6)
+		require.NoError(t, err)
+		require.Empty(t, changed)
+		require.Contains(t, onJourney, waiting.ID())
+		require.Equal(t, 0, car.Availability())
+		require.Empty(t, fleet.WaitingGroups())
+	})
+
+	t.Run(`Given a car reserved for a starving group,
+		when its capacity is decreased below the group size,
+		then its reservation is released and another car is reserved`, func(t *testing.T) {
+		var (
+			starving = fixtures.Group{ID: helpers.UUIDPtr(uuid.New()), People: helpers.IntPtr(6), RequestedAt: &longAgo}.Build()
+			onBoard  = fixtures.Group{ID: helpers.UUIDPtr(uuid.New()), People: helpers.IntPtr(1)}.Build()
+			reserved = fixtures.Car{
+				Capacity:    helpers.CarCapacityPtr(domain.CarCapacity6),
+				Journeys:    domain.Journeys{onBoard.ID(): onBoard},
+				ReservedFor: helpers.UUIDPtr(starving.ID()),
+			}.Build()
+			other = fixtures.Car{
+				Capacity: helpers.CarCapacityPtr(domain.CarCapacity6),
+				Journeys: domain.Journeys{onBoard.ID(): onBoard},
+			}.Build()
+			fleet = domain.NewFleet(
+				[]domain.Car{reserved, other},
+				[]domain.Group{starving},
+				domain.WithAgingPolicy(domain.AgingPolicy{MaxWait: time.Minute}),
+			)
+		)
+
+		changed, onJourney, err := fleet.ChangeCapacity(&reserved, domain.CarCapacity5)
+		require.NoError(t, err)
+		require.False(t, reserved.IsReserved())
+		require.Empty(t, onJourney)
+		require.Len(t, changed, 1)
+		require.Equal(t, starving.ID(), changed[0].ReservedFor())
+	})
+}
+
+func TestFleetTakeOutOfService(t *testing.T) {
+	longAgo := time.Now().Add(-time.Hour)
+
+	t.Run(`Given an out of service car,
+		when a group asks for a journey,
+		then it's not assigned to it`, func(t *testing.T) {
+		var (
+			outOfService = fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity6)}.Build()
+			available    = fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity4)}.Build()
+			fleet        = domain.NewFleet([]domain.Car{outOfService, available}, nil)
+		)
+		_, _, err := fleet.TakeOutOfService(&outOfService)
+		require.NoError(t, err)
+
+		g, car := fleet.Journey(fixtures.Group{People: helpers.IntPtr(2)}.Build())
+		require.True(t, g.IsOnJourney())
+		require.Equal(t, available.ID(), car.ID())
+	})
+
+	t.Run(`Given a car reserved for a starving group,
+		when it's taken out of service,
+		then another car is reserved for the group`, func(t *testing.T) {
+		var (
+			starving = fixtures.Group{ID: helpers.UUIDPtr(uuid.New()), People: helpers.IntPtr(5), RequestedAt: &longAgo}.Build()
+			onBoard  = fixtures.Group{ID: helpers.UUIDPtr(uuid.New()), People: helpers.IntPtr(2)}.Build()
+			reserved = fixtures.Car{
+				Capacity:    helpers.CarCapacityPtr(domain.CarCapacity6),
+				Journeys:    domain.Journeys{onBoard.ID(): onBoard},
+				ReservedFor: helpers.UUIDPtr(starving.ID()),
+			}.Build()
+			other = fixtures.Car{
+				Capacity: helpers.CarCapacityPtr(domain.CarCapacity6),
+				Journeys: domain.Journeys{onBoard.ID(): onBoard},
+			}.Build()
+			fleet = domain.NewFleet(
+				[]domain.Car{reserved, other},
+				[]domain.Group{starving},
+				domain.WithAgingPolicy(domain.AgingPolicy{MaxWait: time.Minute}),
+			)
+		)
+
+		changed, onJourney, err := fleet.TakeOutOfService(&reserved)
+		require.NoError(t, err)
+		require.Equal(t, domain.CarDraining, reserved.Status())
+		require.Contains(t, reserved.Journeys(), onBoard.ID())
+		require.Empty(t, onJourney)
+		require.Len(t, changed, 1)
+		require.Equal(t, starving.ID(), changed[0].ReservedFor())
+	})
+}
+
+func TestFleetBackInService(t *testing.T) {
+	t.Run(`Given a waiting group,
+		when an out of service car is back in service,
+		then the group gets on it`, func(t *testing.T) {
+		var (
+			outOfService = domain.CarOutOfService
+			waiting      = fixtures.Group{ID: helpers.UUIDPtr(uuid.New()), People: helpers.IntPtr(4)}.Build()
+			car          = fixtures.Car{Status: &outOfService}.Build()
+			fleet        = domain.NewFleet([]domain.Car{car}, []domain.Group{waiting})
+		)
+
+		onJourney, err := fleet.BackInService(&car)
+		require.NoError(t, err)
+		require.Equal(t, domain.CarAvailable, car.Status())
+		require.Contains(t, onJourney, waiting.ID())
+		require.Empty(t, fleet.WaitingGroups())
+	})
+}
+
+func TestFleetUnservableGroups(t *testing.T) {
+	var (
+		outOfService = domain.CarOutOfService
+
+		five  = fixtures.Group{ID: helpers.UUIDPtr(uuid.New()), People: helpers.IntPtr(5)}.Build()
+		six   = fixtures.Group{ID: helpers.UUIDPtr(uuid.New()), People: helpers.IntPtr(6)}.Build()
+		small = fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity4)}.Build()
+	)
+
+	testCases := []struct {
+		name               string
+		cars               []domain.Car
+		expectedLargest    domain.CarCapacity
+		expectedUnservable []domain.Group
+	}{
+		{
+			name:               `Given an empty fleet, when it's asked, then no group can be served`,
+			expectedUnservable: []domain.Group{five, six},
+		},
+		{
+			name: `Given a fleet of four and five seats cars, when it's asked, then the group of six can't be served`,
+			cars: []domain.Car{
+				small,
+				fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity5)}.Build(),
+			},
+			expectedLargest:    domain.CarCapacity5,
+			expectedUnservable: []domain.Group{six},
+		},
+		{
+			name: `Given a six seats car that is retiring, when it's asked, then it's not taken into account`,
+			cars: []domain.Car{
+				small,
+				fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity6), Retiring: true}.Build(),
+			},
+			expectedLargest:    domain.CarCapacity4,
+			expectedUnservable: []domain.Group{five, six},
+		},
+		{
+			name: `Given a six seats car that is out of service, when it's asked, then all the groups can be served`,
+			cars: []domain.Car{
+				small,
+				fixtures.Car{
+					Capacity: helpers.CarCapacityPtr(domain.CarCapacity6),
+					Status:   &outOfService,
+				}.Build(),
+			},
+			expectedLargest: domain.CarCapacity6,
+		},
+		{
+			name: `Given a six seats car of another site, when it's asked, then it's not taken into account`,
+			cars: []domain.Car{
+				small,
+				fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity6), Site: "warehouse"}.Build(),
+			},
+			expectedLargest:    domain.CarCapacity4,
+			expectedUnservable: []domain.Group{five, six},
+		},
+	}
+
+	for _, tc := range testCases {
+		fleet := domain.NewFleet(tc.cars, []domain.Group{five, six})
+		require.Equal(t, tc.expectedLargest, fleet.LargestCapacity(domain.DefaultSite), tc.name)
+		require.Equal(t, tc.expectedUnservable, fleet.UnservableGroups(), tc.name)
+		require.Equal(t, len(tc.expectedUnservable) == 0, fleet.CanServe(six), tc.name)
+	}
+}
+
+func TestFleetCancel(t *testing.T) {
+	longAgo := time.Now().Add(-time.Hour)
+
+	t.Run(`Given a waiting group, when it's cancelled, then it leaves the waiting list`, func(t *testing.T) {
+		var (
+			waiting = fixtures.Group{ID: helpers.UUIDPtr(uuid.New()), People: helpers.IntPtr(6)}.Build()
+			other   = fixtures.Group{ID: helpers.UUIDPtr(uuid.New()), People: helpers.IntPtr(6)}.Build()
+			fleet   = domain.NewFleet(nil, []domain.Group{waiting, other})
+		)
+
+		changed, onJourney, err := fleet.Cancel(&waiting, domain.CancelReasonGaveUp)
+		require.NoError(t, err)
+		require.Empty(t, changed)
+		require.Empty(t, onJourney)
+		require.Equal(t, []domain.Group{other}, fleet.WaitingGroups())
+		require.Len(t, waiting.Events(), 1)
+	})
+
+	t.Run(`Given a starving group with a reserved car, when it's cancelled,
+		then the reservation is released and the waiting groups that fit get on the car`, func(t *testing.T) {
+		var (
+			starving = fixtures.Group{ID: helpers.UUIDPtr(uuid.New()), People: helpers.IntPtr(5), RequestedAt: &longAgo}.Build()
+			small    = fixtures.Group{ID: helpers.UUIDPtr(uuid.New()), People: helpers.IntPtr(2)}.Build()
+			onBoard  = fixtures.Group{ID: helpers.UUIDPtr(uuid.New()), People: helpers.IntPtr(2)}.Build()
+			reserved = fixtures.Car{
+				Capacity:    helpers.CarCapacityPtr(domain.CarCapacity6),
+				Journeys:    domain.Journeys{onBoard.ID(): onBoard},
+				ReservedFor: helpers.UUIDPtr(starving.ID()),
+			}.Build()
+			fleet = domain.NewFleet([]domain.Car{reserved}, []domain.Group{starving, small})
+		)
+
+		changed, onJourney, err := fleet.Cancel(&starving, domain.CancelReasonGaveUp)
+		require.NoError(t, err)
+		require.Len(t, changed, 1)
+		require.False(t, changed[0].IsReserved())
+		require.Contains(t, changed[0].Journeys(), small.ID())
+		require.Contains(t, onJourney, small.ID())
+		require.Empty(t, fleet.WaitingGroups())
+	})
+
+	t.Run(`Given a group on journey, when it's cancelled, then an error is returned`, func(t *testing.T) {
+		car := fixtures.Car{}.Build()
+		g := fixtures.Group{Car: &car}.Build()
+		fleet := domain.NewFleet([]domain.Car{car}, nil)
+
+		_, _, err := fleet.Cancel(&g, domain.CancelReasonGaveUp)
+		require.ErrorIs(t, err, domain.ErrGroupOnJourney)
+	})
+
+	t.Run(`Given a group that is not waiting, when it's cancelled, then a not found error is returned`, func(t *testing.T) {
+		g := fixtures.Group{}.Build()
+		fleet := domain.NewFleet(nil, nil)
+
+		_, _, err := fleet.Cancel(&g, domain.CancelReasonGaveUp)
+		require.ErrorIs(t, err, domain.ErrNotFound)
+	})
+}
+
+func TestFleetExpire(t *testing.T) {
+	var (
+		waiting = fixtures.Group{ID: helpers.UUIDPtr(uuid.New()), People: helpers.IntPtr(6)}.Build()
+		fleet   = domain.NewFleet(nil, []domain.Group{waiting})
+	)
+
+	_, _, err := fleet.Expire(&waiting)
+	require.NoError(t, err)
+	require.Empty(t, fleet.WaitingGroups())
+	evs := waiting.Events()
+	require.Len(t, evs, 1)
+	require.Equal(t, domain.GroupExpiredEventName, evs[0].Name())
+}
+
+func TestFleetSchedule(t *testing.T) {
+	start := time.Now().Add(2 * time.Hour)
+
+	t.Run(`Given a car with seats enough in the window, when a reservation is scheduled, then it books the car`, func(t *testing.T) {
+		var (
+			small  = fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity4)}.Build()
+			large  = fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity6)}.Build()
+			booked = fixtures.Reservation{People: helpers.IntPtr(2), CarID: helpers.UUIDPtr(small.ID()), StartAt: &start}.Build()
+			fleet  = domain.NewFleet([]domain.Car{small, large}, nil, domain.WithReservations([]domain.Reservation{booked}))
+			r      = fixtures.Reservation{People: helpers.IntPtr(3), StartAt: helpers.TimePtr(start.Add(30 * time.Minute))}.Build()
+		)
+
+		require.NoError(t, fleet.Schedule(&r))
+		require.Equal(t, large.ID(), r.CarID())
+		evs := r.Events()
+		require.Len(t, evs, 1)
+		require.Equal(t, domain.ReservationScheduledEventName, evs[0].Name())
+	})
+
+	t.Run(`Given cars fully booked in the window, when a reservation is scheduled, then it's not honourable`, func(t *testing.T) {
+		var (
+			car    = fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity4)}.Build()
+			booked = fixtures.Reservation{People: helpers.IntPtr(2), CarID: helpers.UUIDPtr(car.ID()), StartAt: &start}.Build()
+			fleet  = domain.NewFleet([]domain.Car{car}, nil, domain.WithReservations([]domain.Reservation{booked}))
+			r      = fixtures.Reservation{People: helpers.IntPtr(3), StartAt: &start}.Build()
+		)
+
+		require.ErrorIs(t, fleet.Schedule(&r), domain.ErrReservationNotHonourable)
+		require.Equal(t, uuid.Nil, r.CarID())
+	})
+
+	t.Run(`Given a car booked in another window, when a reservation is scheduled, then it books the car`, func(t *testing.T) {
+		var (
+			car    = fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity4)}.Build()
+			booked = fixtures.Reservation{People: helpers.IntPtr(4), CarID: helpers.UUIDPtr(car.ID()), StartAt: &start}.Build()
+			fleet  = domain.NewFleet([]domain.Car{car}, nil, domain.WithReservations([]domain.Reservation{booked}))
+			r      = fixtures.Reservation{People: helpers.IntPtr(4), StartAt: helpers.TimePtr(start.Add(time.Hour))}.Build()
+		)
+
+		require.NoError(t, fleet.Schedule(&r))
+		require.Equal(t, car.ID(), r.CarID())
+	})
+
+	t.Run(`Given a car out of service, when a reservation is scheduled, then it's not booked`, func(t *testing.T) {
+		var (
+			outOfService = domain.CarOutOfService
+			car          = fixtures.Car{Status: &outOfService}.Build()
+			fleet        = domain.NewFleet([]domain.Car{car}, nil)
+			r            = fixtures.Reservation{People: helpers.IntPtr(2), StartAt: &start}.Build()
+		)
+
+		require.ErrorIs(t, fleet.Schedule(&r), domain.ErrReservationNotHonourable)
+	})
+
+	t.Run(`Given a retiring car, when a reservation is scheduled, then it's not booked`, func(t *testing.T) {
+		var (
+			car   = fixtures.Car{Retiring: true}.Build()
+			fleet = domain.NewFleet([]domain.Car{car}, nil)
+			r     = fixtures.Reservation{People: helpers.IntPtr(2), StartAt: &start}.Build()
+		)
+
+		require.ErrorIs(t, fleet.Schedule(&r), domain.ErrReservationNotHonourable)
+	})
+
+	t.Run(`Given cars with and without the features required by the reservation, when it's scheduled,
+		then only the car that meets them is booked`, func(t *testing.T) {
+		var (
+			plain    = fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity6)}.Build()
+			equipped = fixtures.Car{
+				Capacity: helpers.CarCapacityPtr(domain.CarCapacity4),
+				Features: domain.NewFeatures(domain.FeatureWheelchair),
+			}.Build()
+			fleet = domain.NewFleet([]domain.Car{plain, equipped}, nil)
+			r     = fixtures.Reservation{
+				People:       helpers.IntPtr(2),
+				Requirements: domain.NewFeatures(domain.FeatureWheelchair),
+				StartAt:      &start,
+			}.Build()
+		)
+
+		require.NoError(t, fleet.Schedule(&r))
+		require.Equal(t, equipped.ID(), r.CarID())
+	})
+
+	t.Run(`Given no car with the features required by the reservation, when it's scheduled,
+		then it's not honourable`, func(t *testing.T) {
+		var (
+			car   = fixtures.Car{Features: domain.NewFeatures(domain.FeatureLuggage)}.Build()
+			fleet = domain.NewFleet([]domain.Car{car}, nil)
+			r     = fixtures.Reservation{
+				People:       helpers.IntPtr(2),
+				Requirements: domain.NewFeatures(domain.FeatureChildSeat),
+				StartAt:      &start,
+			}.Build()
+		)
+
+		require.ErrorIs(t, fleet.Schedule(&r), domain.ErrReservationNotHonourable)
+	})
+}
+
+func TestFleetHoldBack(t *testing.T) {
+	var (
+		car  = fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity4)}.Build()
+		soon = fixtures.Reservation{
+			People:  helpers.IntPtr(3),
+			CarID:   helpers.UUIDPtr(car.ID()),
+			StartAt: helpers.TimePtr(time.Now().Add(10 * time.Minute)),
+		}.Build()
+		g = fixtures.Group{People: helpers.IntPtr(2)}.Build()
+	)
+
+	t.Run(`Given a reservation that starts within the hold back time, when a group asks for a car,
+		then the seats booked are held back`, func(t *testing.T) {
+		fleet := domain.NewFleet([]domain.Car{car}, nil, domain.WithReservations([]domain.Reservation{soon}))
+		g, _ := fleet.Journey(g)
+		require.False(t, g.IsOnJourney())
+	})
+
+	t.Run(`Given a reservation that starts after the hold back time, when a group asks for a car,
+		then it gets on the car`, func(t *testing.T) {
+		fleet := domain.NewFleet([]domain.Car{car}, nil,
+			domain.WithReservations([]domain.Reservation{soon}),
+			domain.WithReservationHoldBack(5*time.Minute),
+		)
+		g, _ := fleet.Journey(g)
+		require.True(t, g.IsOnJourney())
+	})
+
+	t.Run(`Given a clock within the hold back time of a reservation, when a group asks for a car,
+		then the seats booked are held back`, func(t *testing.T) {
+		fleet := domain.NewFleet([]domain.Car{car}, nil,
+			domain.WithReservations([]domain.Reservation{soon}),
+			domain.WithReservationHoldBack(5*time.Minute),
+			domain.WithClock(fixedClock{now: time.Now().Add(6 * time.Minute)}),
+		)
+		g, _ := fleet.Journey(g)
+		require.False(t, g.IsOnJourney())
+	})
+
+	t.Run(`Given a reservation whose window has ended without being started, when a group asks for a car,
+		then the seats are not held back anymore`, func(t *testing.T) {
+		ended := fixtures.Reservation{
+			People:  helpers.IntPtr(3),
+			CarID:   helpers.UUIDPtr(car.ID()),
+			StartAt: helpers.TimePtr(time.Now().Add(-2 * time.Hour)),
+			EndAt:   helpers.TimePtr(time.Now().Add(-time.Hour)),
+		}.Build()
+		fleet := domain.NewFleet([]domain.Car{car}, nil, domain.WithReservations([]domain.Reservation{ended}))
+		g, _ := fleet.Journey(g)
+		require.True(t, g.IsOnJourney())
+	})
+}
+
+func TestFleetStartReservation(t *testing.T) {
+	t.Run(`Given a booked car with seats enough, when the reservation starts, then the group gets on it`, func(t *testing.T) {
+		var (
+			other  = fixtures.Car{}.Build()
+			car    = fixtures.Car{}.Build()
+			r      = fixtures.Reservation{People: helpers.IntPtr(4), CarID: helpers.UUIDPtr(car.ID()), StartAt: helpers.TimePtr(time.Now())}.Build()
+			g      = fixtures.Group{ID: helpers.UUIDPtr(r.ID()), People: helpers.IntPtr(4)}.Build()
+			walkIn = fixtures.Group{People: helpers.IntPtr(1)}.Build()
+			fleet  = domain.NewFleet([]domain.Car{other, car}, nil, domain.WithReservations([]domain.Reservation{r}))
+		)
+		// the seats are held back until the group gets on the car
+		walkIn, _ = fleet.Journey(walkIn)
+		require.Equal(t, other.ID(), walkIn.Car().ID())
+
+		g, changed := fleet.StartReservation(r, g)
+		require.True(t, g.IsOnJourney())
+		require.Equal(t, car.ID(), changed.ID())
+		require.Contains(t, changed.Journeys(), g.ID())
+	})
+
+	t.Run(`Given a booked car whose seats are still taken, when the reservation starts,
+		then the car is reserved for the group, which waits`, func(t *testing.T) {
+		var (
+			onBoard = fixtures.Group{People: helpers.IntPtr(2)}.Build()
+			car     = fixtures.Car{Journeys: domain.Journeys{onBoard.ID(): onBoard}}.Build()
+			r       = fixtures.Reservation{People: helpers.IntPtr(4), CarID: helpers.UUIDPtr(car.ID()), StartAt: helpers.TimePtr(time.Now())}.Build()
+			g       = fixtures.Group{ID: helpers.UUIDPtr(r.ID()), People: helpers.IntPtr(4)}.Build()
+			fleet   = domain.NewFleet([]domain.Car{car}, nil, domain.WithReservations([]domain.Reservation{r}))
+		)
+
+		g, changed := fleet.StartReservation(r, g)
+		require.False(t, g.IsOnJourney())
+		require.Equal(t, g.ID(), changed.ReservedFor())
+		require.Equal(t, []domain.Group{g}, fleet.WaitingGroups())
+		evs := changed.Events()
+		require.Len(t, evs, 1)
+		require.Equal(t, domain.CarReservedForScheduledJourneyEventName, evs[0].Name())
+	})
+
+	t.Run(`Given a booked car that has retired, when the reservation starts, then the group gets on any car`, func(t *testing.T) {
+		var (
+			car   = fixtures.Car{}.Build()
+			r     = fixtures.Reservation{People: helpers.IntPtr(4), CarID: helpers.UUIDPtr(uuid.New()), StartAt: helpers.TimePtr(time.Now())}.Build()
+			g     = fixtures.Group{ID: helpers.UUIDPtr(r.ID()), People: helpers.IntPtr(4)}.Build()
+			fleet = domain.NewFleet([]domain.Car{car}, nil, domain.WithReservations([]domain.Reservation{r}))
+		)
+
+		g, changed := fleet.StartReservation(r, g)
+		require.True(t, g.IsOnJourney())
+		require.Equal(t, car.ID(), changed.ID())
+	})
+}
+
+func TestFleetMaxPickupRadius(t *testing.T) {
+	var (
+		factory   = domain.Location{Lat: 41.3874, Lng: 2.1686}
+		warehouse = domain.Location{Lat: 41.3275, Lng: 2.0954} // ~9 km away from the factory
+		radius    = domain.WithMaxPickupRadius(5)
+	)
+
+	t.Run(`Given a car out of the pickup radius, when a group asks for a car, then it waits`, func(t *testing.T) {
+		car := fixtures.Car{Position: &warehouse}.Build()
+		fleet := domain.NewFleet([]domain.Car{car}, nil, radius)
+
+		g, _ := fleet.Journey(fixtures.Group{People: helpers.IntPtr(2), Pickup: &factory}.Build())
+		require.False(t, g.IsOnJourney())
+	})
+
+	t.Run(`Given a car that has not reported its position, when a group asks for a car, then it gets on it`, func(t *testing.T) {
+		car := fixtures.Car{}.Build()
+		fleet := domain.NewFleet([]domain.Car{car}, nil, radius)
+
+		g, _ := fleet.Journey(fixtures.Group{People: helpers.IntPtr(2), Pickup: &factory}.Build())
+		require.True(t, g.IsOnJourney())
+	})
+
+	t.Run(`Given a waiting group out of the pickup radius, when the car reports a position near it,
+		then the group gets on the car`, func(t *testing.T) {
+		var (
+			waiting = fixtures.Group{People: helpers.IntPtr(2), Pickup: &factory}.Build()
+			car     = fixtures.Car{Position: &warehouse}.Build()
+			fleet   = domain.NewFleet([]domain.Car{car}, []domain.Group{waiting}, radius)
+		)
+
+		onJourney, err := fleet.ReportPosition(&car, warehouse)
+		require.NoError(t, err)
+		require.Empty(t, onJourney)
+
+		onJourney, err = fleet.ReportPosition(&car, factory)
+		require.NoError(t, err)
+		require.Contains(t, onJourney, waiting.ID())
+		require.Equal(t, &factory, car.Position())
+		require.Empty(t, fleet.WaitingGroups())
+	})
+}
+
+func TestFleetSites(t *testing.T) {
+	const (
+		factory   domain.Site = "factory"
+		warehouse domain.Site = "warehouse"
+	)
+	longAgo := time.Now().Add(-time.Hour)
+
+	t.Run(`Given a car of another site, when a group asks for a car, then it waits`, func(t *testing.T) {
+		fleet := domain.NewFleet([]domain.Car{fixtures.Car{Site: warehouse}.Build()}, nil)
+
+		g, _ := fleet.Journey(fixtures.Group{People: helpers.IntPtr(2), Site: factory}.Build())
+		require.False(t, g.IsOnJourney())
+		require.False(t, fleet.CanServe(g))
+	})
+
+	t.Run(`Given cars of several sites, when a group asks for a car, then it gets on a car of its site`, func(t *testing.T) {
+		var (
+			inWarehouse = fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity6), Site: warehouse}.Build()
+			inFactory   = fixtures.Car{Site: factory}.Build()
+			fleet       = domain.NewFleet([]domain.Car{inWarehouse, inFactory}, nil)
+		)
+
+		g, car := fleet.Journey(fixtures.Group{People: helpers.IntPtr(2), Site: factory}.Build())
+		require.True(t, g.IsOnJourney())
+		require.Equal(t, inFactory.ID(), car.ID())
+	})
+
+	t.Run(`Given waiting groups of several sites, when a car frees seats, then only the groups of its site get on it`, func(t *testing.T) {
+		var (
+			onJourney   = fixtures.Group{People: helpers.IntPtr(4), Site: factory}.Build()
+			fromStore   = fixtures.Group{People: helpers.IntPtr(2), Site: warehouse, RequestedAt: &longAgo}.Build()
+			fromFactory = fixtures.Group{People: helpers.IntPtr(2), Site: factory}.Build()
+			car         = fixtures.Car{Journeys: domain.Journeys{onJourney.ID(): onJourney}, Site: factory}.Build()
+			fleet       = domain.NewFleet([]domain.Car{car}, []domain.Group{fromStore, fromFactory})
+		)
+		onJourney.GetOn(&car)
+
+		_, newJourneys, err := fleet.DropOff(&onJourney, &car)
+		require.NoError(t, err)
+		require.Len(t, newJourneys, 1)
+		require.Contains(t, newJourneys, fromFactory.ID())
+		require.Equal(t, []domain.Group{fromStore}, fleet.WaitingGroups())
+	})
+
+	t.Run(`Given a max number of overtakes and a waiting group of another site,
+		when a newer group gets on a car, then the waiting group is not overtaken`, func(t *testing.T) {
+		var (
+			waiting = fixtures.Group{People: helpers.IntPtr(6), Site: warehouse, RequestedAt: &longAgo}.Build()
+			fleet   = domain.NewFleet(
+				[]domain.Car{fixtures.Car{Site: factory}.Build()},
+				[]domain.Group{waiting},
+				domain.WithAgingPolicy(domain.AgingPolicy{MaxOvertakes: 1}),
+			)
+		)
+
+		g, _ := fleet.Journey(fixtures.Group{People: helpers.IntPtr(2), Site: factory}.Build())
+		require.True(t, g.IsOnJourney())
+		require.Empty(t, fleet.OvertakenGroups())
+	})
+
+	t.Run(`Given cars of several sites, when a reservation is scheduled, then a car of its site is booked`, func(t *testing.T) {
+		var (
+			inFactory = fixtures.Car{Site: factory}.Build()
+			fleet     = domain.NewFleet([]domain.Car{fixtures.Car{Site: warehouse}.Build(), inFactory}, nil)
+			r         = fixtures.Reservation{People: helpers.IntPtr(2), Site: factory}.Build()
+			other     = fixtures.Reservation{People: helpers.IntPtr(2), Site: "headquarters"}.Build()
+		)
+
+		require.NoError(t, fleet.Schedule(&r))
+		require.Equal(t, inFactory.ID(), r.CarID())
+		require.ErrorIs(t, fleet.Schedule(&other), domain.ErrReservationNotHonourable)
+	})
+}
+
+func TestFleetDestinations(t *testing.T) {
+	const (
+		airport  domain.Site = "airport"
+		downtown domain.Site = "downtown"
+	)
+
+	t.Run(`Given a car with a group going to the same destination and an empty car,
+		when a group asks for a car, then it's pooled into the car with the group`, func(t *testing.T) {
+		var (
+			onJourney = fixtures.Group{People: helpers.IntPtr(2), Destination: airport}.Build()
+			pooled    = fixtures.Car{Journeys: domain.Journeys{onJourney.ID(): onJourney}}.Build()
+			empty     = fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity6)}.Build()
+			fleet     = domain.NewFleet([]domain.Car{empty, pooled}, nil)
+		)
+
+		g, car := fleet.Journey(fixtures.Group{People: helpers.IntPtr(2), Destination: airport}.Build())
+		require.True(t, g.IsOnJourney())
+		require.Equal(t, pooled.ID(), car.ID())
+	})
+
+	t.Run(`Given a car with a group going to another destination and an empty car,
+		when a group asks for a car, then it gets on the empty car`, func(t *testing.T) {
+		var (
+			onJourney = fixtures.Group{People: helpers.IntPtr(2), Destination: downtown}.Build()
+			other     = fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity6), Journeys: domain.Journeys{onJourney.ID(): onJourney}}.Build()
+			empty     = fixtures.Car{}.Build()
+			fleet     = domain.NewFleet([]domain.Car{other, empty}, nil)
+		)
+
+		g, car := fleet.Journey(fixtures.Group{People: helpers.IntPtr(2), Destination: airport}.Build())
+		require.True(t, g.IsOnJourney())
+		require.Equal(t, empty.ID(), car.ID())
+	})
+
+	t.Run(`Given only a car with a group going to another destination, when a group asks for a car, then it waits`, func(t *testing.T) {
+		onJourney := fixtures.Group{People: helpers.IntPtr(2), Destination: downtown}.Build()
+		fleet := domain.NewFleet([]domain.Car{fixtures.Car{Journeys: domain.Journeys{onJourney.ID(): onJourney}}.Build()}, nil)
+
+		g, _ := fleet.Journey(fixtures.Group{People: helpers.IntPtr(2), Destination: airport}.Build())
+		require.False(t, g.IsOnJourney())
+	})
+
+	t.Run(`Given a car with a group going to a destination, when a group without destination asks for a car,
+		then it's pooled into the car`, func(t *testing.T) {
+		onJourney := fixtures.Group{People: helpers.IntPtr(2), Destination: downtown}.Build()
+		fleet := domain.NewFleet([]domain.Car{fixtures.Car{Journeys: domain.Journeys{onJourney.ID(): onJourney}}.Build()}, nil)
+
+		g, _ := fleet.Journey(fixtures.Group{People: helpers.IntPtr(2)}.Build())
+		require.True(t, g.IsOnJourney())
+	})
+
+	t.Run(`Given waiting groups going to several destinations, when a car frees seats,
+		then only the groups going to the destination of the car get on it`, func(t *testing.T) {
+		var (
+			longAgo    = time.Now().Add(-time.Hour)
+			staying    = fixtures.Group{People: helpers.IntPtr(2), Destination: airport}.Build()
+			leaving    = fixtures.Group{People: helpers.IntPtr(2), Destination: airport}.Build()
+			toDowntown = fixtures.Group{People: helpers.IntPtr(2), Destination: downtown, RequestedAt: &longAgo}.Build()
+			toAirport  = fixtures.Group{People: helpers.IntPtr(2), Destination: airport}.Build()
+			car        = fixtures.Car{Journeys: domain.Journeys{staying.ID(): staying, leaving.ID(): leaving}}.Build()
+			fleet      = domain.NewFleet([]domain.Car{car}, []domain.Group{toDowntown, toAirport})
+		)
+		leaving.GetOn(&car)
+
+		_, newJourneys, err := fleet.DropOff(&leaving, &car)
+		require.NoError(t, err)
+		require.Len(t, newJourneys, 1)
+		require.Contains(t, newJourneys, toAirport.ID())
+		require.Equal(t, []domain.Group{toDowntown}, fleet.WaitingGroups())
+	})
+}
+
+func TestFleetRequirements(t *testing.T) {
+	t.Run(`Given cars with and without the required features, when a group asks for a car,
+		then it gets on a car that meets all its requirements`, func(t *testing.T) {
+		var (
+			plain    = fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity6)}.Build()
+			partial  = fixtures.Car{Features: domain.NewFeatures(domain.FeatureWheelchair)}.Build()
+			equipped = fixtures.Car{Features: domain.NewFeatures(domain.FeatureWheelchair, domain.FeatureLuggage)}.Build()
+			fleet    = domain.NewFleet([]domain.Car{plain, partial, equipped}, nil)
+		)
+
+		g, car := fleet.Journey(fixtures.Group{
+			People:       helpers.IntPtr(2),
+			Requirements: domain.NewFeatures(domain.FeatureLuggage, domain.FeatureWheelchair),
+		}.Build())
+		require.True(t, g.IsOnJourney())
+		require.Equal(t, equipped.ID(), car.ID())
+	})
+
+	t.Run(`Given a car with features, when a group without requirements asks for a car, then it gets on it`, func(t *testing.T) {
+		fleet := domain.NewFleet([]domain.Car{fixtures.Car{Features: domain.NewFeatures(domain.FeatureChildSeat)}.Build()}, nil)
+
+		g, _ := fleet.Journey(fixtures.Group{People: helpers.IntPtr(2)}.Build())
+		require.True(t, g.IsOnJourney())
+	})
+
+	t.Run(`Given no car that meets the requirements, when a group asks for a car, then it waits, and it can't be served`,
+		func(t *testing.T) {
+			fleet := domain.NewFleet([]domain.Car{fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity6)}.Build()}, nil)
+
+			g, _ := fleet.Journey(fixtures.Group{
+				People:       helpers.IntPtr(2),
+				Requirements: domain.NewFeatures(domain.FeatureChildSeat),
+			}.Build())
+			require.False(t, g.IsOnJourney())
+			require.False(t, fleet.CanServe(g))
+			require.Equal(t, domain.CarCapacity(0), fleet.LargestCapacityFor(g))
+		})
+
+	t.Run(`Given waiting groups with several requirements, when a car frees seats,
+		then only the groups whose requirements it meets get on it`, func(t *testing.T) {
+		var (
+			longAgo    = time.Now().Add(-time.Hour)
+			onJourney  = fixtures.Group{People: helpers.IntPtr(4)}.Build()
+			wheelchair = fixtures.Group{
+				People:       helpers.IntPtr(2),
+				Requirements: domain.NewFeatures(domain.FeatureWheelchair),
+				RequestedAt:  &longAgo,
+			}.Build()
+			luggage = fixtures.Group{People: helpers.IntPtr(2), Requirements: domain.NewFeatures(domain.FeatureLuggage)}.Build()
+			car     = fixtures.Car{
+				Journeys: domain.Journeys{onJourney.ID(): onJourney},
+				Features: domain.NewFeatures(domain.FeatureLuggage),
+			}.Build()
+			fleet = domain.NewFleet([]domain.Car{car}, []domain.Group{wheelchair, luggage})
+		)
+		onJourney.GetOn(&car)
+
+		_, newJourneys, err := fleet.DropOff(&onJourney, &car)
+		require.NoError(t, err)
+		require.Len(t, newJourneys, 1)
+		require.Contains(t, newJourneys, luggage.ID())
+		require.Equal(t, []domain.Group{wheelchair}, fleet.WaitingGroups())
+	})
+}
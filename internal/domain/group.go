@@ -2,6 +2,8 @@ package domain
 
 import (
 	"errors"
+	"regexp"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/theskyinflames/cqrs-eda/pkg/ddd"
@@ -11,19 +13,137 @@ import (
 type Group struct {
 	ddd.AggregateBasic
 
-	people int
-	car    *Car
+	people      int
+	car         *Car
+	requestedAt time.Time
+
+	// site is the origin site of the group. It only gets on the cars based there
+	site Site
+
+	// destination is where the group is going, a named place as the sites are. Empty if it's not given
+	destination Site
+
+	// members are the employee IDs of the people of the group. The roster is optional, and it can be partial
+	members []string
+
+	// requirements are the features that a car must have for the group to get on it
+	requirements Features
+
+	// pickup is where the group waits to be picked up. nil if it's not given
+	pickup *Location
+
+	// boardedAt is when the group got on its car, and droppedOffAt when it was dropped off. Zero if it has not happened yet
+	boardedAt    time.Time
+	droppedOffAt time.Time
+
+	// overtaken is the number of times that a group that arrived later has got on a car before this one
+	overtaken int
+
+	// version is the persisted version of the group. It's used to reject the updates done from a stale copy
+	version int
+}
+
+var (
+	// ErrWrongSize is self-described
+	ErrWrongSize = errors.New("wrong size, it has to be at least 1")
+	// ErrWrongMembers is self-described
+	ErrWrongMembers = errors.New("wrong members, they have to be unique employee IDs, and no more than the people of the group")
+)
+
+var employeeID = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]{0,63}$`)
+
+// CancelReason is the reason why a waiting group gives up its journey
+type CancelReason string
+
+// Cancel reasons
+const (
+	CancelReasonGaveUp       CancelReason = "gave_up"
+	CancelReasonPlansChanged CancelReason = "plans_changed"
+	CancelReasonOther        CancelReason = "other"
+)
+
+var (
+	// ErrWrongCancelReason is self-described
+	ErrWrongCancelReason = errors.New("wrong cancel reason")
+	// ErrGroupOnJourney is self-described
+	ErrGroupOnJourney = errors.New("the group is on journey")
+)
+
+// ParseCancelReason is self-described
+func ParseCancelReason(s string) (CancelReason, error) {
+	switch r := CancelReason(s); r {
+	case CancelReasonGaveUp, CancelReasonPlansChanged, CancelReasonOther:
+		return r, nil
+	default:
+		return "", ErrWrongCancelReason
+	}
+}
+
+// GroupOption sets the optional details of a group
+type GroupOption func(*Group)
+
+// WithOrigin sets the site where the group starts its journey. By default, the DefaultSite
+func WithOrigin(s Site) GroupOption {
+	return func(g *Group) {
+		g.site = s
+	}
 }
 
-// ErrWrongSize is self-described
-var ErrWrongSize = errors.New("wrong size, it has to be from 1 to 6")
+// WithDestination sets where the group is going, so it's only pooled with the groups going to the same place
+func WithDestination(s Site) GroupOption {
+	return func(g *Group) {
+		g.destination = s
+	}
+}
 
-// NewGroup is a constructor
-func NewGroup(id uuid.UUID, people int) (Group, error) {
-	if people < 1 || people > 6 {
+// WithMembers sets the employee IDs of the people of the group
+func WithMembers(ids ...string) GroupOption {
+	return func(g *Group) {
+		g.members = ids
+	}
+}
+
+// WithRequirements sets the features that a car must have for the group to get on it
+func WithRequirements(fs ...Feature) GroupOption {
+	return func(g *Group) {
+		g.requirements = NewFeatures(fs...)
+	}
+}
+
+// WithPickup sets where the group waits to be picked up
+func WithPickup(l Location) GroupOption {
+	return func(g *Group) {
+		g.pickup = &l
+	}
+}
+
+// NewGroup is a constructor. The largest group allowed depends on the fleet, see CapacityLimits
+func NewGroup(id uuid.UUID, people int, opts ...GroupOption) (Group, error) {
+	if people < 1 {
 		return Group{}, ErrWrongSize
 	}
-	return Group{AggregateBasic: ddd.NewAggregateBasic(id), people: people}, nil
+	g := Group{AggregateBasic: ddd.NewAggregateBasic(id), people: people, requestedAt: time.Now(), site: DefaultSite}
+	for _, opt := range opts {
+		opt(&g)
+	}
+	if !g.validMembers() {
+		return Group{}, ErrWrongMembers
+	}
+	return g, nil
+}
+
+func (g Group) validMembers() bool {
+	if len(g.members) > g.people {
+		return false
+	}
+	seen := make(map[string]struct{}, len(g.members))
+	for _, id := range g.members {
+		if _, ok := seen[id]; ok || !employeeID.MatchString(id) {
+			return false
+		}
+		seen[id] = struct{}{}
+	}
+	return true
 }
 
 // ID is a getter
@@ -41,16 +161,118 @@ func (g Group) Car() *Car {
 	return g.car
 }
 
-// Hydrate hydrates a group
-func (g *Group) Hydrate(id uuid.UUID, people int, car *Car) {
-	g.AggregateBasic = ddd.NewAggregateBasic(id)
-	g.people = people
-	g.car = car
+// Site is a getter. It's the origin site of the group
+func (g Group) Site() Site {
+	return g.site.orDefault()
+}
+
+// Destination is a getter. It's empty if the destination is not given
+func (g Group) Destination() Site {
+	return g.destination
+}
+
+// Members is a getter. It's empty if the roster is not given
+func (g Group) Members() []string {
+	return g.members
+}
+
+// Requirements is a getter
+func (g Group) Requirements() Features {
+	return g.requirements
+}
+
+// Pickup is a getter. It returns nil if the pickup location is not given
+func (g Group) Pickup() *Location {
+	return g.pickup
+}
+
+// RequestedAt is a getter. It's the arrival time of the group, used to serve the groups in arrival order
+func (g Group) RequestedAt() time.Time {
+	return g.requestedAt
+}
+
+// BoardedAt is a getter
+func (g Group) BoardedAt() time.Time {
+	return g.boardedAt
+}
+
+// DroppedOffAt is a getter
+func (g Group) DroppedOffAt() time.Time {
+	return g.droppedOffAt
+}
+
+// WaitingTime returns the time that the group waited for a car. Zero if it has not got on a car yet
+func (g Group) WaitingTime() time.Duration {
+	if g.boardedAt.IsZero() {
+		return 0
+	}
+	return g.boardedAt.Sub(g.requestedAt)
+}
+
+// Overtaken is a getter
+func (g Group) Overtaken() int {
+	return g.overtaken
+}
+
+// Version is a getter
+func (g Group) Version() int {
+	return g.version
+}
+
+// GroupSnapshot is the state of a group, as it's stored
+type GroupSnapshot struct {
+	ID           uuid.UUID
+	People       int
+	Car          *Car
+	RequestedAt  time.Time
+	BoardedAt    time.Time
+	Overtaken    int
+	Site         Site
+	Destination  Site
+	Members      []string
+	Requirements Features
+	Pickup       *Location
+	Version      int
+}
+
+// Hydrate hydrates a group from its snapshot
+func (g *Group) Hydrate(s GroupSnapshot) {
+	g.AggregateBasic = ddd.NewAggregateBasic(s.ID)
+	g.people = s.People
+	g.car = s.Car
+	g.requestedAt = s.RequestedAt
+	g.site = s.Site
+	g.destination = s.Destination
+	g.members = s.Members
+	g.requirements = s.Requirements
+	g.pickup = s.Pickup
+	g.boardedAt = s.BoardedAt
+	g.overtaken = s.Overtaken
+	g.version = s.Version
+}
+
+// Snapshot returns the state of the group. It shares its car
+func (g Group) Snapshot() GroupSnapshot {
+	return GroupSnapshot{
+		ID:           g.ID(),
+		People:       g.people,
+		Car:          g.car,
+		RequestedAt:  g.requestedAt,
+		BoardedAt:    g.boardedAt,
+		Overtaken:    g.overtaken,
+		Site:         g.site,
+		Destination:  g.destination,
+		Members:      g.members,
+		Requirements: g.requirements,
+		Pickup:       g.pickup,
+		Version:      g.version,
+	}
 }
 
 // GetOn links a group to its EV
 func (g *Group) GetOn(car *Car) {
 	g.car = car
+	g.boardedAt = time.Now()
 
 	g.RecordEvent(NewGroupSetOnJourneyEvent(*g))
 }
@@ -58,11 +280,51 @@ func (g *Group) GetOn(car *Car) {
 // DropOff drops off the group from its car
 func (g *Group) DropOff() {
 	g.car = nil
+	g.droppedOffAt = time.Now()
 
 	g.RecordEvent(NewGroupDroppedOff(*g))
 }
 
+// Cancel cancels the journey of a waiting group. A group on journey has to be dropped off instead
+func (g *Group) Cancel(reason CancelReason) error {
+	if g.IsOnJourney() {
+		return ErrGroupOnJourney
+	}
+	g.RecordEvent(NewGroupCancelledEvent(*g, reason, time.Now()))
+	return nil
+}
+
+// Expire expires a group that has been waiting too long. A group on journey can't expire
+func (g *Group) Expire() error {
+	if g.IsOnJourney() {
+		return ErrGroupOnJourney
+	}
+	g.RecordEvent(NewGroupExpiredEvent(*g, time.Now()))
+	return nil
+}
+
+// HasWaitedLongerThan returns TRUE if, at the given time, the group has been waiting for a car longer than maxWait
+func (g Group) HasWaitedLongerThan(maxWait time.Duration, now time.Time) bool {
+	return !g.IsOnJourney() && now.Sub(g.requestedAt) > maxWait
+}
+
+// Overtake registers that a group that arrived later has got on a car before this one
+func (g *Group) Overtake() {
+	g.overtaken++
+}
+
 // IsOnJourney returns TRUE is the group is in a journey
 func (g Group) IsOnJourney() bool {
 	return g.car != nil
 }
+
+// SharesRouteWith returns TRUE if the group can share a car with the other one, because they go to the same destination.
+// A group without destination can share a car with any other
+func (g Group) SharesRouteWith(other Group) bool {
+	return g.destination == "" || other.destination == "" || g.destination == other.destination
+}
+
+// ArrivedBefore returns TRUE if the group arrived before the other one
+func (g Group) ArrivedBefore(other Group) bool {
+	return g.requestedAt.Before(other.requestedAt)
+}
@@ -2,6 +2,7 @@ package domain_test
 
 import (
 	"testing"
+	"time"
 
 	"theskyinflames/car-sharing/internal/domain"
 	"theskyinflames/car-sharing/internal/fixtures"
@@ -16,6 +17,7 @@ func TestNewGroup(t *testing.T) {
 		name            string
 		id              uuid.UUID
 		people          int
+		opts            []domain.GroupOption
 		expectedErrFunc func(*testing.T, error)
 	}{
 		{
@@ -26,22 +28,55 @@ func TestNewGroup(t *testing.T) {
 			},
 		},
 		{
-			name:   `Given an oversized group, when it's called then an error is returned`,
+			name:   `Given a group bigger than the default largest car, when it's called then no error is returned`,
 			id:     id,
-			people: 7,
+			people: 9,
+		},
+		{
+			name:   `Given a group, when it's called then no error is returned`,
+			id:     id,
+			people: 3,
+		},
+		{
+			name:   `Given a group with a partial roster and requirements, when it's called then no error is returned`,
+			id:     id,
+			people: 3,
+			opts: []domain.GroupOption{
+				domain.WithMembers("E-1024", "E-2048"),
+				domain.WithRequirements(domain.FeatureWheelchair),
+			},
+		},
+		{
+			name:   `Given a group with more members than people, when it's called then an error is returned`,
+			id:     id,
+			people: 1,
+			opts:   []domain.GroupOption{domain.WithMembers("E-1024", "E-2048")},
 			expectedErrFunc: func(t *testing.T, err error) {
-				require.ErrorIs(t, err, domain.ErrWrongSize)
+				require.ErrorIs(t, err, domain.ErrWrongMembers)
 			},
 		},
 		{
-			name:   `Given a group, when it's called then no error is returned`,
+			name:   `Given a group with a duplicated member, when it's called then an error is returned`,
 			id:     id,
 			people: 3,
+			opts:   []domain.GroupOption{domain.WithMembers("E-1024", "E-1024")},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, domain.ErrWrongMembers)
+			},
+		},
+		{
+			name:   `Given a group with a wrong employee ID, when it's called then an error is returned`,
+			id:     id,
+			people: 3,
+			opts:   []domain.GroupOption{domain.WithMembers("E 1024,")},
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, domain.ErrWrongMembers)
+			},
 		},
 	}
 
 	for _, tc := range testCases {
-		g, err := domain.NewGroup(tc.id, tc.people)
+		g, err := domain.NewGroup(tc.id, tc.people, tc.opts...)
 		require.Equal(t, tc.expectedErrFunc == nil, err == nil)
 		if err != nil {
 			tc.expectedErrFunc(t, err)
@@ -52,6 +87,29 @@ func TestNewGroup(t *testing.T) {
 	}
 }
 
+func TestGroupSnapshot(t *testing.T) {
+	t.Run(`Given a group, when it's hydrated from its snapshot, then the same group is returned`, func(t *testing.T) {
+		var (
+			now = time.Now()
+			car = fixtures.Car{}.Build()
+			g   = fixtures.Group{
+				Car:          &car,
+				RequestedAt:  &now,
+				BoardedAt:    &now,
+				Site:         "north",
+				Destination:  "south",
+				Members:      []string{"alice", "bob"},
+				Requirements: domain.NewFeatures(domain.FeatureWheelchair),
+				Pickup:       &domain.Location{Lat: 41.39, Lng: 2.17},
+			}.Build()
+		)
+		var hydrated domain.Group
+		hydrated.Hydrate(g.Snapshot())
+		require.Equal(t, g.Snapshot(), hydrated.Snapshot())
+		require.Equal(t, g.ID(), hydrated.ID())
+	})
+}
+
 func TestGroupIsOnJourney(t *testing.T) {
 	testCases := []struct {
 		name     string
@@ -74,3 +132,97 @@ func TestGroupIsOnJourney(t *testing.T) {
 		require.Equal(t, tc.expected, tc.g.IsOnJourney())
 	}
 }
+
+func TestParseCancelReason(t *testing.T) {
+	for _, s := range []string{"gave_up", "plans_changed", "other"} {
+		r, err := domain.ParseCancelReason(s)
+		require.NoError(t, err)
+		require.Equal(t, domain.CancelReason(s), r)
+	}
+
+	_, err := domain.ParseCancelReason("arrived")
+	require.ErrorIs(t, err, domain.ErrWrongCancelReason)
+}
+
+func TestGroupCancel(t *testing.T) {
+	t.Run(`Given a waiting group, when it's cancelled, then a group cancelled event with the reason is recorded`, func(t *testing.T) {
+		g := fixtures.Group{}.Build()
+		require.NoError(t, g.Cancel(domain.CancelReasonGaveUp))
+
+		evs := g.Events()
+		require.Len(t, evs, 1)
+		ev, ok := evs[0].(domain.GroupCancelledEvent)
+		require.True(t, ok)
+		require.Equal(t, domain.CancelReasonGaveUp, ev.Reason())
+		require.False(t, ev.CancelledAt().IsZero())
+	})
+
+	t.Run(`Given a group on journey, when it's cancelled, then an error is returned`, func(t *testing.T) {
+		car := fixtures.Car{}.Build()
+		g := fixtures.Group{Car: &car}.Build()
+		require.ErrorIs(t, g.Cancel(domain.CancelReasonGaveUp), domain.ErrGroupOnJourney)
+		require.Empty(t, g.Events())
+	})
+}
+
+func TestGroupExpire(t *testing.T) {
+	var (
+		maxWait = time.Minute
+		now     = time.Now()
+		longAgo = now.Add(-time.Hour)
+		car     = fixtures.Car{}.Build()
+	)
+
+	t.Run(`Given a group waiting longer than the max waiting time, when it expires, then a group expired event is recorded`, func(t *testing.T) {
+		g := fixtures.Group{RequestedAt: &longAgo}.Build()
+		require.True(t, g.HasWaitedLongerThan(maxWait, now))
+		require.NoError(t, g.Expire())
+
+		evs := g.Events()
+		require.Len(t, evs, 1)
+		require.IsType(t, domain.GroupExpiredEvent{}, evs[0])
+	})
+
+	t.Run(`Given a group that has just arrived, when it's checked, then it has not waited too long`, func(t *testing.T) {
+		g := fixtures.Group{RequestedAt: &now}.Build()
+		require.False(t, g.HasWaitedLongerThan(maxWait, now))
+	})
+
+	t.Run(`Given a group on journey, when it expires, then an error is returned`, func(t *testing.T) {
+		g := fixtures.Group{Car: &car, RequestedAt: &longAgo}.Build()
+		require.False(t, g.HasWaitedLongerThan(maxWait, now))
+		require.ErrorIs(t, g.Expire(), domain.ErrGroupOnJourney)
+	})
+}
+
+func TestGroupSharesRouteWith(t *testing.T) {
+	testCases := []struct {
+		name     string
+		g, other domain.Group
+		expected bool
+	}{
+		{
+			name:     `Given two groups going to the same destination, when it's called, then they share the route`,
+			g:        fixtures.Group{Destination: "airport"}.Build(),
+			other:    fixtures.Group{Destination: "airport"}.Build(),
+			expected: true,
+		},
+		{
+			name:     `Given two groups going to different destinations, when it's called, then they don't share the route`,
+			g:        fixtures.Group{Destination: "airport"}.Build(),
+			other:    fixtures.Group{Destination: "downtown"}.Build(),
+			expected: false,
+		},
+		{
+			name:     `Given a group without destination, when it's called, then it shares the route with any other`,
+			g:        fixtures.Group{}.Build(),
+			other:    fixtures.Group{Destination: "downtown"}.Build(),
+			expected: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.expected, tc.g.SharesRouteWith(tc.other), tc.name)
+		require.Equal(t, tc.expected, tc.other.SharesRouteWith(tc.g), tc.name)
+	}
+}
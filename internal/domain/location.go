@@ -0,0 +1,39 @@
+package domain
+
+import (
+	"errors"
+	"math"
+)
+
+// earthRadiusKm is the mean radius of the Earth, used to compute the distances
+const earthRadiusKm = 6371.0
+
+// ErrWrongLocation is self-described
+var ErrWrongLocation = errors.New("wrong location, the latitude has to be within [-90, 90] and the longitude within [-180, 180]")
+
+// Location is a value object. It's a point given by its coordinates, in degrees
+type Location struct {
+	Lat float64
+	Lng float64
+}
+
+// NewLocation is a constructor
+func NewLocation(lat, lng float64) (Location, error) {
+	if lat < -90 || lat > 90 || lng < -180 || lng > 180 {
+		return Location{}, ErrWrongLocation
+	}
+	return Location{Lat: lat, Lng: lng}, nil
+}
+
+// DistanceTo returns the great-circle distance to the other location, in kilometers, by the haversine formula
+func (l Location) DistanceTo(other Location) float64 {
+	lat1, lat2 := radians(l.Lat), radians(other.Lat)
+	dLat, dLng := lat2-lat1, radians(other.Lng-l.Lng)
+
+	h := math.Pow(math.Sin(dLat/2), 2) + math.Cos(lat1)*math.Cos(lat2)*math.Pow(math.Sin(dLng/2), 2)
+	return 2 * earthRadiusKm * math.Asin(math.Sqrt(h))
+}
+
+func radians(deg float64) float64 {
+	return deg * math.Pi / 180
+}
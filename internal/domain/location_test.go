@@ -0,0 +1,57 @@
+package domain_test
+
+import (
+	"testing"
+
+	"theskyinflames/car-sharing/internal/domain"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewLocation(t *testing.T) {
+	testCases := []struct {
+		name            string
+		lat, lng        float64
+		expectedErrFunc func(*testing.T, error)
+	}{
+		{
+			name: `Given a latitude out of range, when it's called, then an error is returned`,
+			lat:  90.1,
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, domain.ErrWrongLocation)
+			},
+		},
+		{
+			name: `Given a longitude out of range, when it's called, then an error is returned`,
+			lng:  -180.1,
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, domain.ErrWrongLocation)
+			},
+		},
+		{
+			name: `Given valid coordinates, when it's called, then no error is returned`,
+			lat:  41.3874,
+			lng:  2.1686,
+		},
+	}
+
+	for _, tc := range testCases {
+		l, err := domain.NewLocation(tc.lat, tc.lng)
+		require.Equal(t, tc.expectedErrFunc == nil, err == nil, tc.name)
+		if err != nil {
+			tc.expectedErrFunc(t, err)
+			continue
+		}
+		require.Equal(t, domain.Location{Lat: tc.lat, Lng: tc.lng}, l, tc.name)
+	}
+}
+
+func TestLocationDistanceTo(t *testing.T) {
+	var (
+		barcelona = domain.Location{Lat: 41.3874, Lng: 2.1686}
+		madrid    = domain.Location{Lat: 40.4168, Lng: -3.7038}
+	)
+	require.Zero(t, barcelona.DistanceTo(barcelona))
+	require.InDelta(t, 505, barcelona.DistanceTo(madrid), 5)
+	require.InDelta(t, barcelona.DistanceTo(madrid), madrid.DistanceTo(barcelona), 1e-9)
+}
@@ -0,0 +1,146 @@
+package domain
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/theskyinflames/cqrs-eda/pkg/ddd"
+)
+
+// DefaultReservationHoldBack is the time before the start of a reservation from which its seats are held back
+const DefaultReservationHoldBack = 30 * time.Minute
+
+var (
+	// ErrWrongWindow is self-described
+	ErrWrongWindow = errors.New("wrong window, it has to start in the future and end after it starts")
+	// ErrReservationNotHonourable is self-described
+	ErrReservationNotHonourable = errors.New("no car has seats enough for the reservation in its window")
+)
+
+// Reservation is an entity. It books the seats of a car for a group in a future time window.
+// Its ID is the ID of the group that gets on the car once it starts
+type Reservation struct {
+	ddd.AggregateBasic
+
+	people int
+	site   Site
+	// requirements are the features that the booked car must have, as the ones of the group
+	requirements Features
+	carID        uuid.UUID
+	startAt      time.Time
+	endAt        time.Time
+}
+
+// ReservationOption is a functional option for the Reservation constructor
+type ReservationOption func(*Reservation)
+
+// WithRequiredFeatures sets the features that the booked car must have
+func WithRequiredFeatures(fs ...Feature) ReservationOption {
+	return func(r *Reservation) {
+		r.requirements = NewFeatures(fs...)
+	}
+}
+
+// NewReservation is a constructor. The window has to start after now, and the car, which is based in the origin site
+// of the group, is set once it's scheduled by the Fleet
+func NewReservation(
+	id uuid.UUID,
+	people int,
+	site Site,
+	startAt, endAt, now time.Time,
+	opts ...ReservationOption,
+) (Reservation, error) {
+	if people < 1 {
+		return Reservation{}, ErrWrongSize
+	}
+	if !startAt.After(now) || !endAt.After(startAt) {
+		return Reservation{}, ErrWrongWindow
+	}
+	r := Reservation{
+		AggregateBasic: ddd.NewAggregateBasic(id),
+		people:         people,
+		site:           site,
+		startAt:        startAt,
+		endAt:          endAt,
+	}
+	for _, opt := range opts {
+		opt(&r)
+	}
+	return r, nil
+}
+
+// ID is a getter
+func (r Reservation) ID() uuid.UUID {
+	return r.AggregateBasic.ID()
+}
+
+// People is a getter
+func (r Reservation) People() int {
+	return r.people
+}
+
+// Site is a getter. It's the origin site of the group
+func (r Reservation) Site() Site {
+	return r.site.orDefault()
+}
+
+// Requirements is a getter
+func (r Reservation) Requirements() Features {
+	return r.requirements
+}
+
+// CarID is a getter
+func (r Reservation) CarID() uuid.UUID {
+	return r.carID
+}
+
+// StartAt is a getter
+func (r Reservation) StartAt() time.Time {
+	return r.startAt
+}
+
+// EndAt is a getter
+func (r Reservation) EndAt() time.Time {
+	return r.endAt
+}
+
+// Hydrate hydrates a reservation
+func (r *Reservation) Hydrate(
+	id uuid.UUID,
+	people int,
+	site Site,
+	requirements Features,
+	carID uuid.UUID,
+	startAt, endAt time.Time,
+) {
+	r.AggregateBasic = ddd.NewAggregateBasic(id)
+	r.people = people
+	r.site = site
+	r.requirements = requirements
+	r.carID = carID
+	r.startAt = startAt
+	r.endAt = endAt
+}
+
+// Overlaps returns TRUE if the window of the reservation overlaps the other one
+func (r Reservation) Overlaps(other Reservation) bool {
+	return r.startAt.Before(other.endAt) && other.startAt.Before(r.endAt)
+}
+
+// HasStarted returns TRUE if the window of the reservation has started at the given time
+func (r Reservation) HasStarted(now time.Time) bool {
+	return !now.Before(r.startAt)
+}
+
+// HasEnded returns TRUE if the window of the reservation has ended at the given time
+func (r Reservation) HasEnded(now time.Time) bool {
+	return !now.Before(r.endAt)
+}
+
+// book books the seats of the car
+func (r *Reservation) book(car Car) {
+	r.carID = car.ID()
+
+	r.RecordEvent(NewReservationScheduledEvent(*r))
+}
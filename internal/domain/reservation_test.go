@@ -0,0 +1,111 @@
+package domain_test
+
+import (
+	"testing"
+	"time"
+
+	"theskyinflames/car-sharing/internal/domain"
+	"theskyinflames/car-sharing/internal/fixtures"
+	"theskyinflames/car-sharing/internal/helpers"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewReservation(t *testing.T) {
+	now := time.Now()
+	testCases := []struct {
+		name            string
+		people          int
+		requirements    domain.Features
+		startAt         time.Time
+		endAt           time.Time
+		expectedErrFunc func(*testing.T, error)
+	}{
+		{
+			name:    `Given an empty group, when it's called then an error is returned`,
+			startAt: now.Add(time.Hour),
+			endAt:   now.Add(2 * time.Hour),
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, domain.ErrWrongSize)
+			},
+		},
+		{
+			name:    `Given a window that has already started, when it's called then an error is returned`,
+			people:  4,
+			startAt: now,
+			endAt:   now.Add(time.Hour),
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, domain.ErrWrongWindow)
+			},
+		},
+		{
+			name:    `Given a window that ends before it starts, when it's called then an error is returned`,
+			people:  4,
+			startAt: now.Add(2 * time.Hour),
+			endAt:   now.Add(time.Hour),
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, domain.ErrWrongWindow)
+			},
+		},
+		{
+			name:    `Given a future window, when it's called then no error is returned`,
+			people:  4,
+			startAt: now.Add(time.Hour),
+			endAt:   now.Add(2 * time.Hour),
+		},
+		{
+			name:         `Given a future window and required features, when it's called then no error is returned`,
+			people:       4,
+			requirements: domain.NewFeatures(domain.FeatureWheelchair, domain.FeatureLuggage),
+			startAt:      now.Add(time.Hour),
+			endAt:        now.Add(2 * time.Hour),
+		},
+	}
+
+	for _, tc := range testCases {
+		r, err := domain.NewReservation(uuid.New(), tc.people, domain.DefaultSite, tc.startAt, tc.endAt, now,
+			domain.WithRequiredFeatures(tc.requirements...))
+		require.Equal(t, tc.expectedErrFunc == nil, err == nil, tc.name)
+		if err != nil {
+			tc.expectedErrFunc(t, err)
+			continue
+		}
+		require.Equal(t, tc.people, r.People(), tc.name)
+		require.Equal(t, tc.requirements, r.Requirements(), tc.name)
+		require.Equal(t, uuid.Nil, r.CarID(), tc.name)
+	}
+}
+
+func TestReservationOverlaps(t *testing.T) {
+	var (
+		start = time.Now().Add(time.Hour)
+		r     = fixtures.Reservation{StartAt: &start, EndAt: helpers.TimePtr(start.Add(time.Hour))}.Build()
+	)
+	testCases := []struct {
+		name     string
+		other    domain.Reservation
+		expected bool
+	}{
+		{
+			name:     `Given a reservation that starts within the window, when it's called, then they overlap`,
+			other:    fixtures.Reservation{StartAt: helpers.TimePtr(start.Add(30 * time.Minute))}.Build(),
+			expected: true,
+		},
+		{
+			name:     `Given a reservation that ends when the window starts, when it's called, then they don't overlap`,
+			other:    fixtures.Reservation{StartAt: helpers.TimePtr(start.Add(-time.Hour)), EndAt: &start}.Build(),
+			expected: false,
+		},
+		{
+			name:     `Given a reservation that starts once the window ends, when it's called, then they don't overlap`,
+			other:    fixtures.Reservation{StartAt: helpers.TimePtr(start.Add(time.Hour))}.Build(),
+			expected: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		require.Equal(t, tc.expected, r.Overlaps(tc.other), tc.name)
+		require.Equal(t, tc.expected, tc.other.Overlaps(r), tc.name)
+	}
+}
@@ -0,0 +1,41 @@
+package domain
+
+import (
+	"errors"
+	"regexp"
+)
+
+// Site is a named place where the cars are based, i.e. the factory or the headquarters. A group only gets on
+// the cars of its origin site
+type Site string
+
+// DefaultSite is the site of the cars and groups that don't give any
+const DefaultSite Site = "default"
+
+// ErrWrongSite is self-described
+var ErrWrongSite = errors.New("wrong site, it has to be lowercase letters, digits, '-' or '_', up to 64 characters")
+
+var siteName = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]{0,63}$`)
+
+// ParseSite is self-described. An empty name is the DefaultSite
+func ParseSite(s string) (Site, error) {
+	if s == "" {
+		return DefaultSite, nil
+	}
+	if !siteName.MatchString(s) {
+		return "", ErrWrongSite
+	}
+	return Site(s), nil
+}
+
+// String implements the fmt.Stringer interface
+func (s Site) String() string {
+	return string(s.orDefault())
+}
+
+func (s Site) orDefault() Site {
+	if s == "" {
+		return DefaultSite
+	}
+	return s
+}
@@ -0,0 +1,60 @@
+package domain_test
+
+import (
+	"testing"
+
+	"theskyinflames/car-sharing/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSite(t *testing.T) {
+	testCases := []struct {
+		name            string
+		s               string
+		expected        domain.Site
+		expectedErrFunc func(*testing.T, error)
+	}{
+		{
+			name:     `Given an empty site name, when it's called, then the default site is returned`,
+			expected: domain.DefaultSite,
+		},
+		{
+			name:     `Given a right site name, when it's called, then the site is returned`,
+			s:        "head-quarters_2",
+			expected: domain.Site("head-quarters_2"),
+		},
+		{
+			name: `Given a site name with uppercase letters, when it's called, then an error is returned`,
+			s:    "Factory",
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, domain.ErrWrongSite)
+			},
+		},
+		{
+			name: `Given a site name with spaces, when it's called, then an error is returned`,
+			s:    "main factory",
+			expectedErrFunc: func(t *testing.T, err error) {
+				require.ErrorIs(t, err, domain.ErrWrongSite)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		s, err := domain.ParseSite(tc.s)
+		require.Equal(t, tc.expectedErrFunc == nil, err == nil, tc.name)
+		if err != nil {
+			tc.expectedErrFunc(t, err)
+			continue
+		}
+		require.Equal(t, tc.expected, s, tc.name)
+	}
+
+	t.Run(`Given a car and a group without site, when they are asked, then they are in the default site`, func(t *testing.T) {
+		require.Equal(t, domain.DefaultSite, domain.NewCar(uuid.New(), domain.CarCapacity4).Site())
+		g, err := domain.NewGroup(uuid.New(), 2)
+		require.NoError(t, err)
+		require.Equal(t, domain.DefaultSite, g.Site())
+	})
+}
@@ -8,9 +8,16 @@ import (
 
 // Car is fixture
 type Car struct {
-	ID       *uuid.UUID
-	Capacity *domain.CarCapacity
-	Journeys domain.Journeys
+	ID          *uuid.UUID
+	Capacity    *domain.CarCapacity
+	Journeys    domain.Journeys
+	ReservedFor *uuid.UUID
+	Retiring    bool
+	Status      *domain.CarStatus
+	Site        domain.Site
+	Features    domain.Features
+	Position    *domain.Location
+	Version     *int
 }
 
 // Build is self-described
@@ -27,7 +34,30 @@ func (e Car) Build() domain.Car {
 	if e.Journeys != nil {
 		journeys = e.Journeys
 	}
+	reservedFor := uuid.Nil
+	if e.ReservedFor != nil {
+		reservedFor = *e.ReservedFor
+	}
+	status := domain.CarAvailable
+	if e.Status != nil {
+		status = *e.Status
+	}
+	var version int
+	if e.Version != nil {
+		version = *e.Version
+	}
 	dev := domain.Car{}
-	dev.Hydrate(id, capacity, journeys)
+	dev.Hydrate(domain.CarSnapshot{
+		ID:          id,
+		Capacity:    capacity,
+		Journeys:    journeys,
+		ReservedFor: reservedFor,
+		Retiring:    e.Retiring,
+		Status:      status,
+		Site:        e.Site,
+		Features:    e.Features,
+		Position:    e.Position,
+		Version:     version,
+	})
 	return dev
 }
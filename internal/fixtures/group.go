@@ -1,6 +1,8 @@
 package fixtures
 
 import (
+	"time"
+
 	"theskyinflames/car-sharing/internal/domain"
 
 	"github.com/google/uuid"
@@ -8,9 +10,18 @@ import (
 
 // Group is a fixture
 type Group struct {
-	ID     *uuid.UUID
-	People *int
-	Car    *domain.Car
+	ID           *uuid.UUID
+	People       *int
+	Car          *domain.Car
+	RequestedAt  *time.Time
+	BoardedAt    *time.Time
+	Overtaken    *int
+	Site         domain.Site
+	Destination  domain.Site
+	Members      []string
+	Requirements domain.Features
+	Pickup       *domain.Location
+	Version      *int
 }
 
 // Build is self-described
@@ -27,7 +38,36 @@ func (g Group) Build() domain.Group {
 	if g.Car != nil {
 		car = g.Car
 	}
+	var requestedAt time.Time
+	if g.RequestedAt != nil {
+		requestedAt = *g.RequestedAt
+	}
+	var boardedAt time.Time
+	if g.BoardedAt != nil {
+		boardedAt = *g.BoardedAt
+	}
+	var overtaken int
+	if g.Overtaken != nil {
+		overtaken = *g.Overtaken
+	}
+	var version int
+	if g.Version != nil {
+		version = *g.Version
+	}
 	dg := domain.Group{}
-	dg.Hydrate(id, people, car)
+	dg.Hydrate(domain.GroupSnapshot{
+		ID:           id,
+		People:       people,
+		Car:          car,
+		RequestedAt:  requestedAt,
+		BoardedAt:    boardedAt,
+		Overtaken:    overtaken,
+		Site:         g.Site,
+		Destination:  g.Destination,
+		Members:      g.Members,
+		Requirements: g.Requirements,
+		Pickup:       g.Pickup,
+		Version:      version,
+	})
 	return dg
 }
@@ -0,0 +1,47 @@
+package fixtures
+
+import (
+	"time"
+
+	"theskyinflames/car-sharing/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// Reservation is a fixture
+type Reservation struct {
+	ID           *uuid.UUID
+	People       *int
+	Site         domain.Site
+	Requirements domain.Features
+	CarID        *uuid.UUID
+	StartAt      *time.Time
+	EndAt        *time.Time
+}
+
+// Build is self-described
+func (r Reservation) Build() domain.Reservation {
+	id := uuid.New()
+	if r.ID != nil {
+		id = *r.ID
+	}
+	people := 4
+	if r.People != nil {
+		people = *r.People
+	}
+	var carID uuid.UUID
+	if r.CarID != nil {
+		carID = *r.CarID
+	}
+	startAt := time.Now().Add(time.Hour)
+	if r.StartAt != nil {
+		startAt = *r.StartAt
+	}
+	endAt := startAt.Add(time.Hour)
+	if r.EndAt != nil {
+		endAt = *r.EndAt
+	}
+	dr := domain.Reservation{}
+	dr.Hydrate(id, people, r.Site, r.Requirements, carID, startAt, endAt)
+	return dr
+}
@@ -1,6 +1,8 @@
 package helpers
 
 import (
+	"time"
+
 	"theskyinflames/car-sharing/internal/domain"
 
 	"github.com/google/uuid"
@@ -30,3 +32,8 @@ func EvPtr(ev domain.Car) *domain.Car {
 func UUIDPtr(uuid uuid.UUID) *uuid.UUID {
 	return &uuid
 }
+
+// TimePtr is a helper
+func TimePtr(t time.Time) *time.Time {
+	return &t
+}
@@ -1,17 +1,22 @@
 package api
 
 import (
+	"crypto/rand"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"log"
 	"net/http"
+	"time"
 
 	"theskyinflames/car-sharing/internal/app"
 	"theskyinflames/car-sharing/internal/domain"
 	"theskyinflames/car-sharing/internal/infra/repository"
 
+	"github.com/go-chi/chi"
 	"github.com/google/uuid"
 	"github.com/theskyinflames/cqrs-eda/pkg/bus"
+	"github.com/theskyinflames/cqrs-eda/pkg/cqrs"
 )
 
 // InitializeFleet is the HTTP handler to initialize the fleet
@@ -29,30 +34,242 @@ func InitializeFleet(commandBus bus.Bus) func(w http.ResponseWriter, r *http.Req
 			return
 		}
 
-		var cars []app.Car
-		for _, car := range rq {
-			carID, err := uuid.Parse(car.Id)
-			if err != nil {
-				http.Error(w, "invalid car uuid", http.StatusBadRequest)
+		cars, err := carsFromRq(rq)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		cmd := app.InitializeFleetCmd{Cars: cars}
+		if _, err := commandBus.Dispatch(r.Context(), cmd); err != nil {
+			if errors.Is(err, domain.ErrCapacityNotSupported) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
 				return
 			}
-			seats, err := domain.ParseCarCapacityFromInt(int(car.Seats))
-			if err != nil {
-				w.WriteHeader(http.StatusBadRequest)
-				return
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// AddCars is the HTTP handler to add cars to the fleet, keeping the current ones and their journeys
+func AddCars(commandBus bus.Bus) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !checkHeader(r, "Content-Type", "application/json") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		var rq CarsRqJson
+		if err := json.NewDecoder(r.Body).Decode(&rq); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if len(rq) == 0 {
+			http.Error(w, "no cars", http.StatusBadRequest)
+			return
+		}
+
+		cars, err := carsFromRq(rq)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		if _, err := commandBus.Dispatch(r.Context(), app.AddCarsCmd{Cars: cars}); err != nil {
+			switch {
+			case errors.Is(err, domain.ErrCapacityNotSupported):
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			case errors.Is(err, repository.ErrPKConflict):
+				w.WriteHeader(http.StatusConflict)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
 			}
-			cars = append(cars, app.Car{ID: carID, Seats: seats})
+			return
 		}
 
-		cmd := app.InitializeFleetCmd{Cars: cars}
+		w.WriteHeader(http.StatusCreated)
+	}
+}
+
+// RetireCar is the HTTP handler to remove a car from the fleet. If it has groups on journey,
+// it's removed once they are dropped off, and meanwhile no other group gets on it
+func RetireCar(commandBus bus.Bus) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		carID, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if _, err := commandBus.Dispatch(r.Context(), app.RetireCarCmd{CarID: carID}); err != nil {
+			switch {
+			case errors.Is(err, domain.ErrNotFound) || errors.Is(err, repository.ErrNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, domain.ErrCarRetiring):
+				http.Error(w, err.Error(), http.StatusConflict)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			return
+		}
+
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+// UpdateCar is the HTTP handler to change the seats of a car
+func UpdateCar(commandBus bus.Bus) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !checkHeader(r, "Content-Type", "application/json") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		carID, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		var rq CarPatchRqJson
+		if err := json.NewDecoder(r.Body).Decode(&rq); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		cmd := app.UpdateCarCmd{CarID: carID, Seats: domain.CarCapacity(rq.Seats)}
 		if _, err := commandBus.Dispatch(r.Context(), cmd); err != nil {
-			w.WriteHeader(http.StatusInternalServerError)
+			switch {
+			case errors.Is(err, domain.ErrNotFound) || errors.Is(err, repository.ErrNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, domain.ErrCapacityNotSupported):
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			case errors.Is(err, domain.ErrCarRetiring), errors.Is(err, domain.ErrCapacityBelowOccupancy):
+				http.Error(w, err.Error(), http.StatusConflict)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}
+}
+
+// TakeCarOutOfService is the HTTP handler to take a car out of service. If it has groups on journey,
+// it's drained: they are not ejected, but no other group gets on it
+func TakeCarOutOfService(commandBus bus.Bus) func(w http.ResponseWriter, r *http.Request) {
+	return carServiceHandler(commandBus, func(carID uuid.UUID) cqrs.Command {
+		return app.TakeCarOutOfServiceCmd{CarID: carID}
+	})
+}
+
+// PutCarBackInService is the HTTP handler to make a car available again
+func PutCarBackInService(commandBus bus.Bus) func(w http.ResponseWriter, r *http.Request) {
+	return carServiceHandler(commandBus, func(carID uuid.UUID) cqrs.Command {
+		return app.PutCarBackInServiceCmd{CarID: carID}
+	})
+}
+
+// ReportCarPosition is the HTTP handler to report the current position of a car
+func ReportCarPosition(commandBus bus.Bus) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !checkHeader(r, "Content-Type", "application/json") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		carID, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		var rq CarPositionRqJson
+		if err := json.NewDecoder(r.Body).Decode(&rq); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		cmd := app.ReportCarPositionCmd{CarID: carID, Position: domain.Location{Lat: rq.Lat, Lng: rq.Lng}}
+		if _, err := commandBus.Dispatch(r.Context(), cmd); err != nil {
+			switch {
+			case errors.Is(err, domain.ErrNotFound) || errors.Is(err, repository.ErrNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, domain.ErrWrongLocation):
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func carServiceHandler(commandBus bus.Bus, cmd func(uuid.UUID) cqrs.Command) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		carID, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if _, err := commandBus.Dispatch(r.Context(), cmd(carID)); err != nil {
+			switch {
+			case errors.Is(err, domain.ErrNotFound) || errors.Is(err, repository.ErrNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, domain.ErrCarRetiring), errors.Is(err, domain.ErrCarOutOfService), errors.Is(err, domain.ErrCarInService):
+				http.Error(w, err.Error(), http.StatusConflict)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			return
 		}
 
 		w.WriteHeader(http.StatusOK)
 	}
 }
 
+func carsFromRq(rq CarsRqJson) ([]app.Car, error) {
+	var cars []app.Car
+	for _, car := range rq {
+		carID, err := uuid.Parse(car.Id)
+		if err != nil {
+			return nil, errors.New("invalid car uuid")
+		}
+		site, err := parseSite(car.Site)
+		if err != nil {
+			return nil, err
+		}
+		// the features are checked by the JSON decoder, as they are an enum of the schema
+		features := make([]domain.Feature, 0, len(car.Features))
+		for _, f := range car.Features {
+			features = append(features, domain.Feature(f))
+		}
+		// the seats are checked against the capacity limits of the fleet by the command handler
+		cars = append(cars, app.Car{
+			ID:       carID,
+			Seats:    domain.CarCapacity(car.Seats),
+			Site:     site,
+			Features: domain.NewFeatures(features...),
+		})
+	}
+	return cars, nil
+}
+
+// parseSite returns the domain.DefaultSite if the site is not given
+func parseSite(s *string) (domain.Site, error) {
+	if s == nil {
+		return domain.DefaultSite, nil
+	}
+	return domain.ParseSite(*s)
+}
+
 // Journey is the HTTP handler to add a new group
 func Journey(commandBus bus.Bus) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -71,19 +288,56 @@ func Journey(commandBus bus.Bus) func(w http.ResponseWriter, r *http.Request) {
 			http.Error(w, "minimum id value is 1", http.StatusBadRequest)
 			return
 		}
+		site, err := parseSite(rq.Site)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		requirements := make([]domain.Feature, 0, len(rq.Requirements))
+		for _, f := range rq.Requirements {
+			requirements = append(requirements, domain.Feature(f))
+		}
 
 		cmd := app.JourneyCmd{
-			ID:     gID,
-			People: int(rq.People),
+			ID:           gID,
+			People:       int(rq.People),
+			Site:         site,
+			Members:      rq.Members,
+			Requirements: domain.NewFeatures(requirements...),
+		}
+		if rq.Destination != nil && *rq.Destination != "" {
+			if cmd.Destination, err = domain.ParseSite(*rq.Destination); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+		}
+		if rq.Pickup != nil {
+			cmd.Pickup = &domain.Location{Lat: rq.Pickup.Lat, Lng: rq.Pickup.Lng}
 		}
 		if _, err := commandBus.Dispatch(r.Context(), cmd); err != nil {
 			switch {
 			case errors.Is(err, repository.ErrPKConflict):
 				w.WriteHeader(http.StatusBadRequest)
 				return
+			case errors.Is(err, app.ErrGroupIDTaken):
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			case errors.Is(err, domain.ErrWrongLocation):
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
 			case errors.Is(err, domain.ErrWrongSize):
 				w.WriteHeader(http.StatusBadRequest)
 				return
+			case errors.Is(err, domain.ErrWrongMembers):
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			case errors.Is(err, domain.ErrGroupTooBig):
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			case errors.Is(err, domain.ErrGroupNotServable):
+				http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+				return
 			default:
 				w.WriteHeader(http.StatusInternalServerError)
 				return
@@ -131,6 +385,116 @@ func DropOff(commandBus bus.Bus) func(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// CancelJourney is the HTTP handler to cancel the journey of a waiting group, with the reason why it gives up
+func CancelJourney(commandBus bus.Bus) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !checkHeader(r, "Content-Type", "application/x-www-form-urlencoded") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		gID, err := uuid.Parse(r.FormValue("ID"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		reason, err := domain.ParseCancelReason(r.FormValue("reason"))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		cmd := app.CancelJourneyCmd{
+			GroupID: gID,
+			Reason:  reason,
+		}
+
+		if _, err := commandBus.Dispatch(r.Context(), cmd); err != nil {
+			switch {
+			case errors.Is(err, domain.ErrNotFound) || errors.Is(err, repository.ErrNotFound):
+				w.WriteHeader(http.StatusNotFound)
+			case errors.Is(err, domain.ErrGroupOnJourney):
+				http.Error(w, err.Error(), http.StatusConflict)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// ScheduleJourney is the HTTP handler to book the seats of a car for a group in a future window.
+// Once it starts, the group gets on the booked car
+func ScheduleJourney(commandBus bus.Bus) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !checkHeader(r, "Content-Type", "application/json") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		var rq ReservationRqJson
+		if err := json.NewDecoder(r.Body).Decode(&rq); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		gID, err := uuid.Parse(rq.Id)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		site, err := parseSite(rq.Site)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		requirements := make([]domain.Feature, 0, len(rq.Requirements))
+		for _, f := range rq.Requirements {
+			requirements = append(requirements, domain.Feature(f))
+		}
+
+		cmd := app.ScheduleJourneyCmd{
+			ID:           gID,
+			People:       rq.People,
+			Site:         site,
+			Requirements: domain.NewFeatures(requirements...),
+			StartAt:      rq.StartAt,
+			EndAt:        rq.EndAt,
+		}
+		if _, err := commandBus.Dispatch(r.Context(), cmd); err != nil {
+			switch {
+			case errors.Is(err, domain.ErrWrongSize), errors.Is(err, domain.ErrWrongWindow), errors.Is(err, domain.ErrGroupTooBig):
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			case errors.Is(err, domain.ErrReservationNotHonourable), errors.Is(err, repository.ErrPKConflict),
+				errors.Is(err, app.ErrReservationIDTaken):
+				http.Error(w, err.Error(), http.StatusConflict)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			return
+		}
+
+		jsonRs := ReservationRsJson{
+			Id:      cmd.ID.String(),
+			People:  cmd.People,
+			Site:    cmd.Site.String(),
+			StartAt: cmd.StartAt,
+			EndAt:   cmd.EndAt,
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		b, _ := json.Marshal(jsonRs)
+		_, _ = w.Write(b)
+	}
+}
+
 // Locate is the HTTP handler to locate a group
 func Locate(queryBus bus.Bus) func(w http.ResponseWriter, r *http.Request) {
 	return func(w http.ResponseWriter, r *http.Request) {
@@ -174,8 +538,9 @@ func Locate(queryBus bus.Bus) func(w http.ResponseWriter, r *http.Request) {
 
 		w.Header().Set("Accept", "application/json")
 		jsonRs := LocateRsJson{
-			Id:    locateRs.Car.ID().String(),
-			Seats: LocateRsJsonSeats(locateRs.Car.Capacity()),
+			Id:     locateRs.Car.ID().String(),
+			Seats:  locateRs.Car.Capacity().Int(),
+			Status: LocateRsJsonStatus(locateRs.Car.Status()),
 		}
 		b, _ := json.Marshal(jsonRs)
 		if _, err := w.Write(b); err != nil {
@@ -184,6 +549,174 @@ func Locate(queryBus bus.Bus) func(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// WaitTimes is the HTTP handler to get the waiting times percentiles by group size.
+// The window can be set with the window query param (i.e. ?window=30m). Otherwise, the default one is used
+func WaitTimes(queryBus bus.Bus, defaultWindow time.Duration) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		window := defaultWindow
+		if v := r.URL.Query().Get("window"); v != "" {
+			d, err := time.ParseDuration(v)
+			if err != nil {
+				http.Error(w, "invalid window", http.StatusBadRequest)
+				return
+			}
+			window = d
+		}
+
+		queryRs, err := queryBus.Dispatch(r.Context(), app.WaitTimesQuery{Window: window})
+		if err != nil {
+			if errors.Is(err, app.ErrWrongWindow) {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		waitTimesRs := queryRs.(app.WaitTimesResponse)
+		jsonRs := WaitTimesRsJson{
+			WindowSeconds: waitTimesRs.Window.Seconds(),
+			Groups:        make([]WaitTimesRsJsonGroupsElem, 0, len(waitTimesRs.BySize)),
+		}
+		for _, s := range waitTimesRs.BySize {
+			jsonRs.Groups = append(jsonRs.Groups, WaitTimesRsJsonGroupsElem{
+				People:     s.People,
+				Journeys:   s.Journeys,
+				Abandoned:  s.Abandoned,
+				P50Seconds: s.P50.Seconds(),
+				P90Seconds: s.P90.Seconds(),
+				P99Seconds: s.P99.Seconds(),
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		b, _ := json.Marshal(jsonRs)
+		if _, err := w.Write(b); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
+
+// Sites is the HTTP handler to get the occupancy of the cars and the queue of waiting groups of each site
+func Sites(queryBus bus.Bus) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		queryRs, err := queryBus.Dispatch(r.Context(), app.SitesOccupancyQuery{})
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		sitesRs := queryRs.(app.SitesOccupancyResponse)
+		jsonRs := SitesRsJson{Sites: make([]SitesRsJsonSitesElem, 0, len(sitesRs.Sites))}
+		for _, s := range sitesRs.Sites {
+			jsonRs.Sites = append(jsonRs.Sites, SitesRsJsonSitesElem{
+				Site:          s.Site.String(),
+				Cars:          s.Cars,
+				Seats:         s.Seats,
+				OccupiedSeats: s.OccupiedSeats,
+				WaitingGroups: s.WaitingGroups,
+				WaitingPeople: s.WaitingPeople,
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		b, _ := json.Marshal(jsonRs)
+		if _, err := w.Write(b); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
+
+// RegisterWebhook is the HTTP handler to subscribe an URL to the journey lifecycle events.
+// If no secret is given, a random one is generated. It's returned in the response to verify the signature of the payloads
+func RegisterWebhook(commandBus bus.Bus) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !checkHeader(r, "Content-Type", "application/json") {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		var rq WebhookRqJson
+		if err := json.NewDecoder(r.Body).Decode(&rq); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		secret := randomSecret()
+		if rq.Secret != nil && *rq.Secret != "" {
+			secret = *rq.Secret
+		}
+		cmd := app.RegisterWebhookCmd{
+			ID:     uuid.New(),
+			URL:    rq.Url,
+			Events: make([]string, 0, len(rq.Events)),
+			Secret: secret,
+		}
+		for _, e := range rq.Events {
+			cmd.Events = append(cmd.Events, string(e))
+		}
+
+		if _, err := commandBus.Dispatch(r.Context(), cmd); err != nil {
+			switch {
+			case errors.Is(err, app.ErrWrongWebhookURL), errors.Is(err, app.ErrWrongWebhookEvents), errors.Is(err, app.ErrWrongWebhookSecret):
+				http.Error(w, err.Error(), http.StatusBadRequest)
+			default:
+				w.WriteHeader(http.StatusInternalServerError)
+			}
+			return
+		}
+
+		jsonRs := WebhookRsJson{
+			Id:     cmd.ID.String(),
+			Url:    cmd.URL,
+			Events: cmd.Events,
+			Secret: cmd.Secret,
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		b, _ := json.Marshal(jsonRs)
+		_, _ = w.Write(b)
+	}
+}
+
+// DeadLetters is the HTTP handler to get the webhook payloads that could not be delivered
+func DeadLetters(queryBus bus.Bus) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		queryRs, err := queryBus.Dispatch(r.Context(), app.DeadLettersQuery{})
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		dls := queryRs.([]app.DeadLetter)
+		jsonRs := DeadLettersRsJson{DeadLetters: make([]DeadLettersRsJsonDeadLettersElem, 0, len(dls))}
+		for _, dl := range dls {
+			jsonRs.DeadLetters = append(jsonRs.DeadLetters, DeadLettersRsJsonDeadLettersElem{
+				WebhookId: dl.WebhookID.String(),
+				Url:       dl.URL,
+				Event:     dl.Event,
+				Payload:   string(dl.Payload),
+				Attempts:  dl.Attempts,
+				Error:     dl.Err,
+				FailedAt:  dl.FailedAt,
+			})
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		b, _ := json.Marshal(jsonRs)
+		if _, err := w.Write(b); err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}
+}
+
+// randomSecret returns a random secret to sign the webhook payloads
+func randomSecret() string {
+	b := make([]byte, 32)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
 func checkHeader(r *http.Request, name string, expected string) bool {
 	v, ok := r.Header[name]
 	if !ok {
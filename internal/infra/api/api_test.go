@@ -5,17 +5,21 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"strings"
 	"testing"
+	"time"
 
 	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/domain"
 	"theskyinflames/car-sharing/internal/fixtures"
 	"theskyinflames/car-sharing/internal/infra/api"
 	"theskyinflames/car-sharing/internal/infra/repository"
 
+	"github.com/go-chi/chi"
 	"github.com/google/uuid"
 	"github.com/stretchr/testify/require"
 	"github.com/theskyinflames/cqrs-eda/pkg/bus"
@@ -62,13 +66,18 @@ func TestInitializeFleet(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 		},
 		{
-			name: `Given an initialize fleet endpoint,
-			when it's called with a wrong rq with a not allowed number of seats,
+			name: `Given an initialize fleet endpoint with a ch that returns a capacity not supported error,
+			when it's called with a not allowed number of seats,
 			then a 400 HTTP status is returned`,
 			rq: api.CarsRqJson{
 				{Id: uuid.New().String(), Seats: 3},
 			},
-			headers:        map[string]string{"Content-Type": "application/json"},
+			headers: map[string]string{"Content-Type": "application/json"},
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return nil, domain.ErrCapacityNotSupported
+				},
+			},
 			expectedStatus: http.StatusBadRequest,
 		},
 		{
@@ -86,6 +95,21 @@ func TestInitializeFleet(t *testing.T) {
 			},
 			expectedStatus: http.StatusInternalServerError,
 		},
+		{
+			name: `Given an initialize fleet endpoint with a ch that returns a version conflict error,
+			when it's called,
+			then only a 500 HTTP status is written`,
+			rq: api.CarsRqJson{
+				{Id: uuid.New().String(), Seats: 5},
+			},
+			headers: map[string]string{"Content-Type": "application/json"},
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return nil, fmt.Errorf("car %s: %w", uuid.New(), app.ErrVersionConflict)
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
 		{
 			name: `Given an initialize fleet endpoint,
 			when it's called with a right rq,
@@ -127,14 +151,31 @@ func TestInitializeFleet(t *testing.T) {
 		for h, v := range tc.headers {
 			r.Header.Add(h, v)
 		}
-		w := httptest.NewRecorder()
+		w := &headerRecorder{ResponseRecorder: httptest.NewRecorder()}
 		hnd(w, r)
 		require.Equal(t, tc.expectedStatus, w.Code, tc.name)
+		require.LessOrEqual(t, w.writes, 1, tc.name)
 	}
 }
 
+// headerRecorder counts the times the status is written, because the httptest.ResponseRecorder keeps the first one
+// and ignores the others
+type headerRecorder struct {
+	*httptest.ResponseRecorder
+	writes int
+}
+
+func (w *headerRecorder) WriteHeader(code int) {
+	w.writes++
+	w.ResponseRecorder.WriteHeader(code)
+}
+
 func TestJourney(t *testing.T) {
-	gID := uuid.New().String()
+	var (
+		gID         = uuid.New().String()
+		wrongSite   = "-factory"
+		destination = "airport"
+	)
 	testCases := []struct {
 		name           string
 		rq             api.JourneyRqJson
@@ -166,13 +207,44 @@ func TestJourney(t *testing.T) {
 			expectedStatus: http.StatusBadRequest,
 		},
 		{
-			name: `Given an journey endpoint,
-			when it's called with a wrong rq with a not allowed group size,
+			name: `Given an journey endpoint with a ch that returns a group too big error,
+			when it's called with a group bigger than the largest car allowed,
 			then a 400 HTTP status is returned`,
-			rq:             api.JourneyRqJson{Id: gID, People: 10},
-			headers:        map[string]string{"Content-Type": "application/json"},
+			rq:      api.JourneyRqJson{Id: gID, People: 10},
+			headers: map[string]string{"Content-Type": "application/json"},
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return nil, domain.ErrGroupTooBig
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: `Given an journey endpoint with a ch that returns a group id taken error,
+			when it's called with the id of a reservation,
+			then a 400 HTTP status is returned`,
+			rq:      api.JourneyRqJson{Id: gID, People: 4},
+			headers: map[string]string{"Content-Type": "application/json"},
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return nil, app.ErrGroupIDTaken
+				},
+			},
 			expectedStatus: http.StatusBadRequest,
 		},
+		{
+			name: `Given an journey endpoint with a ch that returns a group not servable error,
+			when it's called with a group bigger than the largest car of the fleet,
+			then a 422 HTTP status is returned`,
+			rq:      api.JourneyRqJson{Id: gID, People: 6},
+			headers: map[string]string{"Content-Type": "application/json"},
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return nil, domain.ErrGroupNotServable
+				},
+			},
+			expectedStatus: http.StatusUnprocessableEntity,
+		},
 		{
 			name: `Given an journey endpoint with a ch that returns an error, 
 			when it's called ,
@@ -199,6 +271,100 @@ func TestJourney(t *testing.T) {
 			},
 			expectedStatus: http.StatusBadRequest,
 		},
+		{
+			name: `Given an journey endpoint,
+			when it's called with a wrong site,
+			then a 400 HTTP status is returned`,
+			rq:             api.JourneyRqJson{Id: gID, People: 5, Site: &wrongSite},
+			headers:        map[string]string{"Content-Type": "application/json"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: `Given an journey endpoint,
+			when it's called with a wrong destination,
+			then a 400 HTTP status is returned`,
+			rq:             api.JourneyRqJson{Id: gID, People: 5, Destination: &wrongSite},
+			headers:        map[string]string{"Content-Type": "application/json"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: `Given an journey endpoint,
+			when it's called with a wrong requirement,
+			then a 400 HTTP status is returned`,
+			rq:             api.JourneyRqJson{Id: gID, People: 5, Requirements: []api.JourneyRqJsonRequirementsElem{"sunroof"}},
+			headers:        map[string]string{"Content-Type": "application/json"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: `Given an journey endpoint with a ch that returns a wrong members error,
+			when it's called with more members than people,
+			then a 400 HTTP status is returned`,
+			rq:      api.JourneyRqJson{Id: gID, People: 1, Members: []string{"E-1024", "E-2048"}},
+			headers: map[string]string{"Content-Type": "application/json"},
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return nil, domain.ErrWrongMembers
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: `Given an journey endpoint,
+			when it's called with a right rq with its members and requirements,
+			then a 200 HTTP status is returned`,
+			rq: api.JourneyRqJson{
+				Id:           gID,
+				People:       5,
+				Members:      []string{"E-1024", "E-2048"},
+				Requirements: []api.JourneyRqJsonRequirementsElem{api.JourneyRqJsonRequirementsElemWheelchair},
+			},
+			headers: map[string]string{"Content-Type": "application/json"},
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return nil, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: `Given an journey endpoint,
+			when it's called with a right rq with its destination,
+			then a 200 HTTP status is returned`,
+			rq:      api.JourneyRqJson{Id: gID, People: 5, Destination: &destination},
+			headers: map[string]string{"Content-Type": "application/json"},
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return nil, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: `Given an journey endpoint with a ch that returns a wrong location error,
+			when it's called with a pickup out of range,
+			then a 400 HTTP status is returned`,
+			rq:      api.JourneyRqJson{Id: gID, People: 5, Pickup: &api.JourneyRqJsonPickup{Lat: 91}},
+			headers: map[string]string{"Content-Type": "application/json"},
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return nil, domain.ErrWrongLocation
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: `Given an journey endpoint,
+			when it's called with a right rq with its pickup,
+			then a 200 HTTP status is returned`,
+			rq:      api.JourneyRqJson{Id: gID, People: 5, Pickup: &api.JourneyRqJsonPickup{Lat: 41.3874, Lng: 2.1686}},
+			headers: map[string]string{"Content-Type": "application/json"},
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return nil, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
 		{
 			name: `Given an journey endpoint,
 			when it's called with a right rq,
@@ -303,88 +469,223 @@ func TestDropOff(t *testing.T) {
 	}
 }
 
-func TestLocale(t *testing.T) {
+func TestCancelJourney(t *testing.T) {
 	gID := uuid.New().String()
-	car := fixtures.Car{}.Build()
 	testCases := []struct {
 		name           string
 		headers        map[string]string
-		expectedRs     *api.LocateRsJson
-		qh             *QueryHandlerMock
+		id             string
+		reason         string
+		ch             *CommandHandlerMock
 		expectedStatus int
 	}{
 		{
-			name: `Given a locale endpoint,
+			name: `Given a cancel journey endpoint,
 			when it's called without "Content-type: application/x-www-form-urlencoded" header,
 			then a 400 HTTP status is returned`,
+			id:             gID,
+			reason:         "gave_up",
 			expectedStatus: http.StatusBadRequest,
 		},
 		{
-			name: `Given a locale endpoint with a qh that returns an error other than not found,
-			when it's called ,
-			then a 500 HTTP status is returned`,
-			headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
-			qh: &QueryHandlerMock{
-				HandleFunc: func(ctx context.Context, query cqrs.Query) (cqrs.QueryResult, error) {
-					return nil, errors.New("")
-				},
-			},
-			expectedStatus: http.StatusInternalServerError,
+			name: `Given a cancel journey endpoint,
+			when it's called with a wrong id,
+			then a 400 HTTP status is returned`,
+			headers:        map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+			id:             "wrongID",
+			reason:         "gave_up",
+			expectedStatus: http.StatusBadRequest,
 		},
 		{
-			name: `Given locale endpoint with a ch that returns an not found error,
-			when it's called ,
+			name: `Given a cancel journey endpoint,
+			when it's called with an unknown reason,
+			then a 400 HTTP status is returned`,
+			headers:        map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+			id:             gID,
+			reason:         "arrived",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: `Given a cancel journey endpoint with a ch that returns a not found error,
+			when it's called,
 			then a 404 HTTP status is returned`,
 			headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
-			qh: &QueryHandlerMock{
-				HandleFunc: func(ctx context.Context, query cqrs.Query) (cqrs.QueryResult, error) {
+			id:      gID,
+			reason:  "gave_up",
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
 					return nil, repository.ErrNotFound
 				},
 			},
 			expectedStatus: http.StatusNotFound,
 		},
 		{
-			name: `Given locale endpoint and a waiting group,
-			when it's called with a right rq,
-			then a 200 HTTP status is returned`,
+			name: `Given a cancel journey endpoint with a ch that returns a group on journey error,
+			when it's called,
+			then a 409 HTTP status is returned`,
 			headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
-			qh: &QueryHandlerMock{
-				HandleFunc: func(ctx context.Context, query cqrs.Query) (cqrs.QueryResult, error) {
-					return app.LocateResponse{}, nil
+			id:      gID,
+			reason:  "gave_up",
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return nil, domain.ErrGroupOnJourney
 				},
 			},
-			expectedStatus: http.StatusNoContent,
+			expectedStatus: http.StatusConflict,
 		},
 		{
-			name: `Given locale endpoint and an journey group,
-			when it's called with a right rq,
-			then a 200 HTTP status is returned`,
+			name: `Given a cancel journey endpoint with a ch that returns an error,
+			when it's called,
+			then a 500 HTTP status is returned`,
 			headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
-			qh: &QueryHandlerMock{
-				HandleFunc: func(ctx context.Context, query cqrs.Query) (cqrs.QueryResult, error) {
-					return app.LocateResponse{
-						IsInJourney: true,
-						Car:         car,
-					}, nil
+			id:      gID,
+			reason:  "gave_up",
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return nil, errors.New("")
 				},
 			},
-			expectedRs: &api.LocateRsJson{
-				Id:    car.ID().String(),
-				Seats: api.LocateRsJsonSeats(car.Capacity()),
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name: `Given a cancel journey endpoint,
+			when it's called with a right rq,
+			then a 204 HTTP status is returned`,
+			headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+			id:      gID,
+			reason:  "plans_changed",
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, cmd cqrs.Command) ([]events.Event, error) {
+					require.Equal(t, domain.CancelReasonPlansChanged, cmd.(app.CancelJourneyCmd).Reason)
+					return nil, nil
+				},
 			},
-			expectedStatus: http.StatusOK,
+			expectedStatus: http.StatusNoContent,
 		},
 	}
 
 	for _, tc := range testCases {
 		params := url.Values{}
-		params.Set("ID", gID)
+		params.Set("ID", tc.id)
+		params.Set("reason", tc.reason)
 
 		bus := bus.New()
-		bus.Register(app.LocateName, helpers.BusQhHandler(tc.qh))
+		bus.Register(app.CancelJourneyName, helpers.BusChHandler(tc.ch))
 
-		hnd := api.Locate(bus)
-		r := httptest.NewRequest(http.MethodPost, "/locale", bytes.NewBufferString(params.Encode()))
+		hnd := api.CancelJourney(bus)
+		r := httptest.NewRequest(http.MethodPost, "/cancel", bytes.NewBufferString(params.Encode()))
+		for h, v := range tc.headers {
+			r.Header.Add(h, v)
+		}
+		w := httptest.NewRecorder()
+		hnd(w, r)
+		require.Equal(t, tc.expectedStatus, w.Code, tc.name)
+	}
+}
+
+func TestScheduleJourney(t *testing.T) {
+	rq := `{"id":"b7f1d3c2-5a4e-4f6b-9c8d-1e2f3a4b5c6d","people":4,"requirements":["wheelchair","luggage"],` +
+		`"start_at":"2030-01-01T09:00:00Z","end_at":"2030-01-01T10:00:00Z"}`
+	testCases := []struct {
+		name           string
+		rq             string
+		headers        map[string]string
+		ch             *CommandHandlerMock
+		expectedStatus int
+	}{
+		{
+			name: `Given a reservations endpoint,
+			when it's called without "Content-type: application/json" header,
+			then a 400 HTTP status is returned`,
+			rq:             rq,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: `Given a reservations endpoint,
+			when it's called without the window,
+			then a 400 HTTP status is returned`,
+			rq:             `{"id":"b7f1d3c2-5a4e-4f6b-9c8d-1e2f3a4b5c6d","people":4}`,
+			headers:        map[string]string{"Content-Type": "application/json"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: `Given a reservations endpoint,
+			when it's called with an unknown requirement,
+			then a 400 HTTP status is returned`,
+			rq: `{"id":"b7f1d3c2-5a4e-4f6b-9c8d-1e2f3a4b5c6d","people":4,"requirements":["sunroof"],` +
+				`"start_at":"2030-01-01T09:00:00Z","end_at":"2030-01-01T10:00:00Z"}`,
+			headers:        map[string]string{"Content-Type": "application/json"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: `Given a reservations endpoint with a ch that returns a wrong window error,
+			when it's called,
+			then a 400 HTTP status is returned`,
+			rq:      rq,
+			headers: map[string]string{"Content-Type": "application/json"},
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return nil, domain.ErrWrongWindow
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: `Given a reservations endpoint with a ch that returns a reservation not honourable error,
+			when it's called,
+			then a 409 HTTP status is returned`,
+			rq:      rq,
+			headers: map[string]string{"Content-Type": "application/json"},
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return nil, domain.ErrReservationNotHonourable
+				},
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: `Given a reservations endpoint with a ch that returns a reservation id taken error,
+			when it's called,
+			then a 409 HTTP status is returned`,
+			rq:      rq,
+			headers: map[string]string{"Content-Type": "application/json"},
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return nil, app.ErrReservationIDTaken
+				},
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: `Given a reservations endpoint with a ch that returns an error,
+			when it's called,
+			then a 500 HTTP status is returned`,
+			rq:      rq,
+			headers: map[string]string{"Content-Type": "application/json"},
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return nil, errors.New("")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name: `Given a reservations endpoint,
+			when it's called,
+			then a 201 HTTP status is returned with the reservation`,
+			rq:             rq,
+			headers:        map[string]string{"Content-Type": "application/json"},
+			ch:             &CommandHandlerMock{},
+			expectedStatus: http.StatusCreated,
+		},
+	}
+
+	for _, tc := range testCases {
+		bus := bus.New()
+		bus.Register(app.ScheduleJourneyName, helpers.BusChHandler(tc.ch))
+
+		hnd := api.ScheduleJourney(bus)
+		r := httptest.NewRequest(http.MethodPost, "/v1/reservations", strings.NewReader(tc.rq))
 		for h, v := range tc.headers {
 			r.Header.Add(h, v)
 		}
@@ -392,11 +693,116 @@ func TestLocale(t *testing.T) {
 		hnd(w, r)
 
 		require.Equal(t, tc.expectedStatus, w.Code, tc.name)
-		if tc.expectedRs == nil {
+		if tc.expectedStatus != http.StatusCreated {
 			continue
 		}
-		buff := &bytes.Buffer{}
-		_, err := buff.ReadFrom(w.Body)
+		cmd := tc.ch.HandleCalls()[0].Command.(app.ScheduleJourneyCmd)
+		require.Equal(t, "b7f1d3c2-5a4e-4f6b-9c8d-1e2f3a4b5c6d", cmd.ID.String(), tc.name)
+		require.Equal(t, 4, cmd.People, tc.name)
+		require.Equal(t, domain.NewFeatures(domain.FeatureLuggage, domain.FeatureWheelchair), cmd.Requirements, tc.name)
+		require.Equal(t, time.Hour, cmd.EndAt.Sub(cmd.StartAt), tc.name)
+		var rs api.ReservationRsJson
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&rs))
+		require.Equal(t, cmd.ID.String(), rs.Id, tc.name)
+		require.True(t, cmd.StartAt.Equal(rs.StartAt), tc.name)
+	}
+}
+
+func TestLocale(t *testing.T) {
+	gID := uuid.New().String()
+	car := fixtures.Car{}.Build()
+	testCases := []struct {
+		name           string
+		headers        map[string]string
+		expectedRs     *api.LocateRsJson
+		qh             *QueryHandlerMock
+		expectedStatus int
+	}{
+		{
+			name: `Given a locale endpoint,
+			when it's called without "Content-type: application/x-www-form-urlencoded" header,
+			then a 400 HTTP status is returned`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: `Given a locale endpoint with a qh that returns an error other than not found,
+			when it's called ,
+			then a 500 HTTP status is returned`,
+			headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+			qh: &QueryHandlerMock{
+				HandleFunc: func(ctx context.Context, query cqrs.Query) (cqrs.QueryResult, error) {
+					return nil, errors.New("")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name: `Given locale endpoint with a ch that returns an not found error,
+			when it's called ,
+			then a 404 HTTP status is returned`,
+			headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+			qh: &QueryHandlerMock{
+				HandleFunc: func(ctx context.Context, query cqrs.Query) (cqrs.QueryResult, error) {
+					return nil, repository.ErrNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: `Given locale endpoint and a waiting group,
+			when it's called with a right rq,
+			then a 200 HTTP status is returned`,
+			headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+			qh: &QueryHandlerMock{
+				HandleFunc: func(ctx context.Context, query cqrs.Query) (cqrs.QueryResult, error) {
+					return app.LocateResponse{}, nil
+				},
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+		{
+			name: `Given locale endpoint and an journey group,
+			when it's called with a right rq,
+			then a 200 HTTP status is returned`,
+			headers: map[string]string{"Content-Type": "application/x-www-form-urlencoded"},
+			qh: &QueryHandlerMock{
+				HandleFunc: func(ctx context.Context, query cqrs.Query) (cqrs.QueryResult, error) {
+					return app.LocateResponse{
+						IsInJourney: true,
+						Car:         car,
+					}, nil
+				},
+			},
+			expectedRs: &api.LocateRsJson{
+				Id:     car.ID().String(),
+				Seats:  car.Capacity().Int(),
+				Status: api.LocateRsJsonStatusAvailable,
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		params := url.Values{}
+		params.Set("ID", gID)
+
+		bus := bus.New()
+		bus.Register(app.LocateName, helpers.BusQhHandler(tc.qh))
+
+		hnd := api.Locate(bus)
+		r := httptest.NewRequest(http.MethodPost, "/locale", bytes.NewBufferString(params.Encode()))
+		for h, v := range tc.headers {
+			r.Header.Add(h, v)
+		}
+		w := httptest.NewRecorder()
+		hnd(w, r)
+
+		require.Equal(t, tc.expectedStatus, w.Code, tc.name)
+		if tc.expectedRs == nil {
+			continue
+		}
+		buff := &bytes.Buffer{}
+		_, err := buff.ReadFrom(w.Body)
 		require.NoError(t, err)
 
 		var rs api.LocateRsJson
@@ -406,3 +812,765 @@ func TestLocale(t *testing.T) {
 		require.Equal(t, "application/json", w.Header().Get("Accept"))
 	}
 }
+
+func TestWaitTimes(t *testing.T) {
+	testCases := []struct {
+		name           string
+		query          string
+		qh             *QueryHandlerMock
+		expectedWindow time.Duration
+		expectedRs     *api.WaitTimesRsJson
+		expectedStatus int
+	}{
+		{
+			name: `Given a wait times endpoint,
+			when it's called with a wrong window,
+			then a 400 HTTP status is returned`,
+			query:          "?window=yesterday",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: `Given a wait times endpoint with a qh that returns a wrong window error,
+			when it's called,
+			then a 400 HTTP status is returned`,
+			query: "?window=-1h",
+			qh: &QueryHandlerMock{
+				HandleFunc: func(ctx context.Context, query cqrs.Query) (cqrs.QueryResult, error) {
+					return nil, app.ErrWrongWindow
+				},
+			},
+			expectedWindow: -time.Hour,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: `Given a wait times endpoint with a qh that returns an error,
+			when it's called,
+			then a 500 HTTP status is returned`,
+			qh: &QueryHandlerMock{
+				HandleFunc: func(ctx context.Context, query cqrs.Query) (cqrs.QueryResult, error) {
+					return nil, errors.New("")
+				},
+			},
+			expectedWindow: time.Hour,
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name: `Given a wait times endpoint,
+			when it's called with a window,
+			then a 200 HTTP status is returned with the percentiles by group size`,
+			query: "?window=30m",
+			qh: &QueryHandlerMock{
+				HandleFunc: func(ctx context.Context, query cqrs.Query) (cqrs.QueryResult, error) {
+					return app.WaitTimesResponse{
+						Window: 30 * time.Minute,
+						BySize: []app.GroupSizeWaitTimes{
+							{People: 2, Journeys: 3, Abandoned: 1, P50: time.Second, P90: 2 * time.Second, P99: 3 * time.Second},
+						},
+					}, nil
+				},
+			},
+			expectedWindow: 30 * time.Minute,
+			expectedRs: &api.WaitTimesRsJson{
+				WindowSeconds: 1800,
+				Groups: []api.WaitTimesRsJsonGroupsElem{
+					{People: 2, Journeys: 3, Abandoned: 1, P50Seconds: 1, P90Seconds: 2, P99Seconds: 3},
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		bus := bus.New()
+		bus.Register(app.WaitTimesName, helpers.BusQhHandler(tc.qh))
+
+		hnd := api.WaitTimes(bus, time.Hour)
+		r := httptest.NewRequest(http.MethodGet, "/v1/stats/wait-times"+tc.query, nil)
+		w := httptest.NewRecorder()
+		hnd(w, r)
+
+		require.Equal(t, tc.expectedStatus, w.Code, tc.name)
+		if tc.qh != nil {
+			require.Len(t, tc.qh.HandleCalls(), 1, tc.name)
+			require.Equal(t, tc.expectedWindow, tc.qh.HandleCalls()[0].Query.(app.WaitTimesQuery).Window, tc.name)
+		}
+		if tc.expectedRs == nil {
+			continue
+		}
+		var rs api.WaitTimesRsJson
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&rs))
+		require.Equal(t, *tc.expectedRs, rs, tc.name)
+	}
+}
+
+func TestSites(t *testing.T) {
+	testCases := []struct {
+		name           string
+		qh             *QueryHandlerMock
+		expectedRs     *api.SitesRsJson
+		expectedStatus int
+	}{
+		{
+			name: `Given a sites endpoint with a qh that returns an error,
+			when it's called,
+			then a 500 HTTP status is returned`,
+			qh: &QueryHandlerMock{
+				HandleFunc: func(ctx context.Context, query cqrs.Query) (cqrs.QueryResult, error) {
+					return nil, errors.New("")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name: `Given a sites endpoint,
+			when it's called,
+			then a 200 HTTP status is returned with the occupancy of each site`,
+			qh: &QueryHandlerMock{
+				HandleFunc: func(ctx context.Context, query cqrs.Query) (cqrs.QueryResult, error) {
+					return app.SitesOccupancyResponse{Sites: []app.SiteOccupancy{
+						{Site: "factory", Cars: 2, Seats: 10, OccupiedSeats: 4, WaitingGroups: 1, WaitingPeople: 3},
+					}}, nil
+				},
+			},
+			expectedRs: &api.SitesRsJson{Sites: []api.SitesRsJsonSitesElem{
+				{Site: "factory", Cars: 2, Seats: 10, OccupiedSeats: 4, WaitingGroups: 1, WaitingPeople: 3},
+			}},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		bus := bus.New()
+		bus.Register(app.SitesOccupancyName, helpers.BusQhHandler(tc.qh))
+
+		hnd := api.Sites(bus)
+		r := httptest.NewRequest(http.MethodGet, "/v1/sites", nil)
+		w := httptest.NewRecorder()
+		hnd(w, r)
+
+		require.Equal(t, tc.expectedStatus, w.Code, tc.name)
+		if tc.expectedRs == nil {
+			continue
+		}
+		var rs api.SitesRsJson
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&rs))
+		require.Equal(t, *tc.expectedRs, rs, tc.name)
+	}
+}
+
+func TestRegisterWebhook(t *testing.T) {
+	secret := "secret"
+	testCases := []struct {
+		name           string
+		rq             string
+		headers        map[string]string
+		ch             *CommandHandlerMock
+		expectedSecret string
+		expectedStatus int
+	}{
+		{
+			name: `Given a webhooks endpoint,
+			when it's called without "Content-type: application/json" header,
+			then a 400 HTTP status is returned`,
+			rq:             `{"url":"https://example.com","events":["car.added"]}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: `Given a webhooks endpoint,
+			when it's called with an event that can't be subscribed,
+			then a 400 HTTP status is returned`,
+			rq:             `{"url":"https://example.com","events":["car.reserved"]}`,
+			headers:        map[string]string{"Content-Type": "application/json"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: `Given a webhooks endpoint with a ch that returns a wrong URL error,
+			when it's called,
+			then a 400 HTTP status is returned`,
+			rq:      `{"url":"example.com","events":["car.added"]}`,
+			headers: map[string]string{"Content-Type": "application/json"},
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return nil, app.ErrWrongWebhookURL
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: `Given a webhooks endpoint with a ch that returns an error,
+			when it's called,
+			then a 500 HTTP status is returned`,
+			rq:      `{"url":"https://example.com","events":["car.added"]}`,
+			headers: map[string]string{"Content-Type": "application/json"},
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return nil, errors.New("")
+				},
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+		{
+			name: `Given a webhooks endpoint,
+			when it's called with a secret,
+			then a 201 HTTP status is returned with the webhook`,
+			rq:             `{"url":"https://example.com","events":["car.added","group.dropped.off"],"secret":"secret"}`,
+			headers:        map[string]string{"Content-Type": "application/json"},
+			ch:             &CommandHandlerMock{},
+			expectedSecret: secret,
+			expectedStatus: http.StatusCreated,
+		},
+		{
+			name: `Given a webhooks endpoint,
+			when it's called without a secret,
+			then a 201 HTTP status is returned with a generated secret`,
+			rq:             `{"url":"https://example.com","events":["car.added","group.dropped.off"]}`,
+			headers:        map[string]string{"Content-Type": "application/json"},
+			ch:             &CommandHandlerMock{},
+			expectedStatus: http.StatusCreated,
+		},
+	}
+
+	for _, tc := range testCases {
+		bus := bus.New()
+		bus.Register(app.RegisterWebhookName, helpers.BusChHandler(tc.ch))
+
+		hnd := api.RegisterWebhook(bus)
+		r := httptest.NewRequest(http.MethodPost, "/v1/webhooks", strings.NewReader(tc.rq))
+		for h, v := range tc.headers {
+			r.Header.Add(h, v)
+		}
+		w := httptest.NewRecorder()
+		hnd(w, r)
+
+		require.Equal(t, tc.expectedStatus, w.Code, tc.name)
+		if tc.expectedStatus != http.StatusCreated {
+			continue
+		}
+		cmd := tc.ch.HandleCalls()[0].Command.(app.RegisterWebhookCmd)
+		var rs api.WebhookRsJson
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&rs))
+		require.Equal(t, api.WebhookRsJson{
+			Id:     cmd.ID.String(),
+			Url:    "https://example.com",
+			Events: []string{"car.added", "group.dropped.off"},
+			Secret: cmd.Secret,
+		}, rs, tc.name)
+		require.NotEmpty(t, cmd.Secret, tc.name)
+		if tc.expectedSecret != "" {
+			require.Equal(t, tc.expectedSecret, cmd.Secret, tc.name)
+		}
+	}
+}
+
+func TestDeadLetters(t *testing.T) {
+	dl := app.DeadLetter{
+		WebhookID: uuid.New(),
+		URL:       "https://example.com",
+		Event:     "group.dropped.off",
+		Payload:   []byte(`{}`),
+		Attempts:  5,
+		Err:       "unexpected status 503",
+		FailedAt:  time.Date(2022, 1, 1, 10, 0, 0, 0, time.UTC),
+	}
+	qh := &QueryHandlerMock{
+		HandleFunc: func(ctx context.Context, query cqrs.Query) (cqrs.QueryResult, error) {
+			return []app.DeadLetter{dl}, nil
+		},
+	}
+	bus := bus.New()
+	bus.Register(app.DeadLettersName, helpers.BusQhHandler(qh))
+
+	w := httptest.NewRecorder()
+	api.DeadLetters(bus)(w, httptest.NewRequest(http.MethodGet, "/v1/webhooks/dead-letters", nil))
+
+	require.Equal(t, http.StatusOK, w.Code)
+	var rs api.DeadLettersRsJson
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&rs))
+	require.Equal(t, api.DeadLettersRsJson{DeadLetters: []api.DeadLettersRsJsonDeadLettersElem{{
+		WebhookId: dl.WebhookID.String(),
+		Url:       dl.URL,
+		Event:     dl.Event,
+		Payload:   `{}`,
+		Attempts:  5,
+		Error:     dl.Err,
+		FailedAt:  dl.FailedAt,
+	}}}, rs)
+}
+
+func TestAddCars(t *testing.T) {
+	var (
+		site      = "factory"
+		wrongSite = "Main Factory"
+	)
+	testCases := []struct {
+		name           string
+		rq             api.CarsRqJson
+		headers        map[string]string
+		ch             *CommandHandlerMock
+		expectedStatus int
+	}{
+		{
+			name: `Given an add cars endpoint,
+			when it's called without "Content-type: application/json" header,
+			then a 400 HTTP status is returned`,
+			rq:             api.CarsRqJson{{Id: uuid.New().String(), Seats: 6}},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: `Given an add cars endpoint,
+			when it's called with an empty rq,
+			then a 400 HTTP status is returned`,
+			rq:             api.CarsRqJson{},
+			headers:        map[string]string{"Content-Type": "application/json"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: `Given an add cars endpoint,
+			when it's called with a wrong id,
+			then a 400 HTTP status is returned`,
+			rq:             api.CarsRqJson{{Id: "wrongID", Seats: 5}},
+			headers:        map[string]string{"Content-Type": "application/json"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: `Given an add cars endpoint,
+			when it's called with a wrong site,
+			then a 400 HTTP status is returned`,
+			rq:             api.CarsRqJson{{Id: uuid.New().String(), Seats: 5, Site: &wrongSite}},
+			headers:        map[string]string{"Content-Type": "application/json"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: `Given an add cars endpoint,
+			when it's called with a wrong feature,
+			then a 400 HTTP status is returned`,
+			rq:             api.CarsRqJson{{Id: uuid.New().String(), Seats: 5, Features: []api.CarsFeaturesElem{"sunroof"}}},
+			headers:        map[string]string{"Content-Type": "application/json"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: `Given an add cars endpoint with a ch that returns a pk conflict error,
+			when it's called,
+			then a 409 HTTP status is returned`,
+			rq:      api.CarsRqJson{{Id: uuid.New().String(), Seats: 5}},
+			headers: map[string]string{"Content-Type": "application/json"},
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return nil, repository.ErrPKConflict
+				},
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: `Given an add cars endpoint,
+			when it's called with a right rq,
+			then a 201 HTTP status is returned`,
+			rq: api.CarsRqJson{{
+				Id:       uuid.New().String(),
+				Seats:    5,
+				Site:     &site,
+				Features: []api.CarsFeaturesElem{api.CarsFeaturesElemWheelchair, api.CarsFeaturesElemLuggage},
+			}},
+			headers: map[string]string{"Content-Type": "application/json"},
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return nil, nil
+				},
+			},
+			expectedStatus: http.StatusCreated,
+		},
+	}
+
+	for _, tc := range testCases {
+		s, err := json.Marshal(tc.rq)
+		require.NoError(t, err)
+
+		bus := bus.New()
+		bus.Register(app.AddCarsName, helpers.BusChHandler(tc.ch))
+
+		hnd := api.AddCars(bus)
+		r := httptest.NewRequest(http.MethodPost, "/cars", bytes.NewReader(s))
+		for h, v := range tc.headers {
+			r.Header.Add(h, v)
+		}
+		w := httptest.NewRecorder()
+		hnd(w, r)
+		require.Equal(t, tc.expectedStatus, w.Code, tc.name)
+		if tc.expectedStatus == http.StatusCreated {
+			require.Len(t, tc.ch.HandleCalls(), 1)
+			require.Len(t, tc.ch.HandleCalls()[0].Command.(app.AddCarsCmd).Cars, 1)
+			require.Equal(t, domain.Site("factory"), tc.ch.HandleCalls()[0].Command.(app.AddCarsCmd).Cars[0].Site)
+			require.Equal(t,
+				domain.Features{domain.FeatureLuggage, domain.FeatureWheelchair},
+				tc.ch.HandleCalls()[0].Command.(app.AddCarsCmd).Cars[0].Features,
+			)
+		}
+	}
+}
+
+func TestRetireCar(t *testing.T) {
+	testCases := []struct {
+		name           string
+		id             string
+		ch             *CommandHandlerMock
+		expectedStatus int
+	}{
+		{
+			name: `Given a retire car endpoint,
+			when it's called with a wrong id,
+			then a 400 HTTP status is returned`,
+			id:             "wrongID",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: `Given a retire car endpoint with a ch that returns a not found error,
+			when it's called,
+			then a 404 HTTP status is returned`,
+			id: uuid.New().String(),
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return nil, repository.ErrNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: `Given a retire car endpoint with a ch that returns a car retiring error,
+			when it's called,
+			then a 409 HTTP status is returned`,
+			id: uuid.New().String(),
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return nil, domain.ErrCarRetiring
+				},
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: `Given a retire car endpoint,
+			when it's called with a right id,
+			then a 202 HTTP status is returned`,
+			id: uuid.New().String(),
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return nil, nil
+				},
+			},
+			expectedStatus: http.StatusAccepted,
+		},
+	}
+
+	for _, tc := range testCases {
+		bus := bus.New()
+		bus.Register(app.RetireCarName, helpers.BusChHandler(tc.ch))
+
+		router := chi.NewRouter()
+		router.Delete("/cars/{id}", api.RetireCar(bus))
+		r := httptest.NewRequest(http.MethodDelete, "/cars/"+tc.id, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		require.Equal(t, tc.expectedStatus, w.Code, tc.name)
+	}
+}
+
+func TestUpdateCar(t *testing.T) {
+	testCases := []struct {
+		name           string
+		id             string
+		rq             string
+		headers        map[string]string
+		ch             *CommandHandlerMock
+		expectedStatus int
+	}{
+		{
+			name: `Given an update car endpoint,
+			when it's called without "Content-type: application/json" header,
+			then a 400 HTTP status is returned`,
+			id:             uuid.New().String(),
+			rq:             `{"seats":6}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: `Given an update car endpoint,
+			when it's called with a wrong id,
+			then a 400 HTTP status is returned`,
+			id:             "wrongID",
+			rq:             `{"seats":6}`,
+			headers:        map[string]string{"Content-Type": "application/json"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: `Given an update car endpoint with a ch that returns a capacity not supported error,
+			when it's called with a not allowed number of seats,
+			then a 400 HTTP status is returned`,
+			id:      uuid.New().String(),
+			rq:      `{"seats":3}`,
+			headers: map[string]string{"Content-Type": "application/json"},
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return nil, domain.ErrCapacityNotSupported
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: `Given an update car endpoint with a ch that returns a not found error,
+			when it's called,
+			then a 404 HTTP status is returned`,
+			id:      uuid.New().String(),
+			rq:      `{"seats":6}`,
+			headers: map[string]string{"Content-Type": "application/json"},
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return nil, repository.ErrNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: `Given an update car endpoint with a ch that returns a capacity below occupancy error,
+			when it's called,
+			then a 409 HTTP status is returned`,
+			id:      uuid.New().String(),
+			rq:      `{"seats":4}`,
+			headers: map[string]string{"Content-Type": "application/json"},
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return nil, domain.ErrCapacityBelowOccupancy
+				},
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: `Given an update car endpoint,
+			when it's called with a right rq,
+			then a 200 HTTP status is returned`,
+			id:      uuid.New().String(),
+			rq:      `{"seats":6}`,
+			headers: map[string]string{"Content-Type": "application/json"},
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return nil, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		bus := bus.New()
+		bus.Register(app.UpdateCarName, helpers.BusChHandler(tc.ch))
+
+		router := chi.NewRouter()
+		router.Patch("/cars/{id}", api.UpdateCar(bus))
+		r := httptest.NewRequest(http.MethodPatch, "/cars/"+tc.id, strings.NewReader(tc.rq))
+		for h, v := range tc.headers {
+			r.Header.Add(h, v)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		require.Equal(t, tc.expectedStatus, w.Code, tc.name)
+		if tc.expectedStatus == http.StatusOK {
+			require.Equal(t, domain.CarCapacity6, tc.ch.HandleCalls()[0].Command.(app.UpdateCarCmd).Seats)
+		}
+	}
+}
+
+func TestReportCarPosition(t *testing.T) {
+	testCases := []struct {
+		name           string
+		id             string
+		rq             string
+		headers        map[string]string
+		ch             *CommandHandlerMock
+		expectedStatus int
+	}{
+		{
+			name: `Given a report car position endpoint,
+			when it's called without "Content-type: application/json" header,
+			then a 400 HTTP status is returned`,
+			id:             uuid.New().String(),
+			rq:             `{"lat":41.3874,"lng":2.1686}`,
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: `Given a report car position endpoint,
+			when it's called with a wrong id,
+			then a 400 HTTP status is returned`,
+			id:             "wrongID",
+			rq:             `{"lat":41.3874,"lng":2.1686}`,
+			headers:        map[string]string{"Content-Type": "application/json"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: `Given a report car position endpoint,
+			when it's called without the longitude,
+			then a 400 HTTP status is returned`,
+			id:             uuid.New().String(),
+			rq:             `{"lat":41.3874}`,
+			headers:        map[string]string{"Content-Type": "application/json"},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: `Given a report car position endpoint with a ch that returns a wrong location error,
+			when it's called with a position out of range,
+			then a 400 HTTP status is returned`,
+			id:      uuid.New().String(),
+			rq:      `{"lat":91,"lng":2.1686}`,
+			headers: map[string]string{"Content-Type": "application/json"},
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return nil, domain.ErrWrongLocation
+				},
+			},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: `Given a report car position endpoint with a ch that returns a not found error,
+			when it's called,
+			then a 404 HTTP status is returned`,
+			id:      uuid.New().String(),
+			rq:      `{"lat":41.3874,"lng":2.1686}`,
+			headers: map[string]string{"Content-Type": "application/json"},
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return nil, repository.ErrNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: `Given a report car position endpoint,
+			when it's called with a right rq,
+			then a 204 HTTP status is returned`,
+			id:      uuid.New().String(),
+			rq:      `{"lat":41.3874,"lng":2.1686}`,
+			headers: map[string]string{"Content-Type": "application/json"},
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return nil, nil
+				},
+			},
+			expectedStatus: http.StatusNoContent,
+		},
+	}
+
+	for _, tc := range testCases {
+		bus := bus.New()
+		bus.Register(app.ReportCarPositionName, helpers.BusChHandler(tc.ch))
+
+		router := chi.NewRouter()
+		router.Post("/cars/{id}/position", api.ReportCarPosition(bus))
+		r := httptest.NewRequest(http.MethodPost, "/cars/"+tc.id+"/position", strings.NewReader(tc.rq))
+		for h, v := range tc.headers {
+			r.Header.Add(h, v)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		require.Equal(t, tc.expectedStatus, w.Code, tc.name)
+		if tc.expectedStatus == http.StatusNoContent {
+			require.Equal(t, domain.Location{Lat: 41.3874, Lng: 2.1686}, tc.ch.HandleCalls()[0].Command.(app.ReportCarPositionCmd).Position)
+		}
+	}
+}
+
+func TestCarService(t *testing.T) {
+	testCases := []struct {
+		name           string
+		path           string
+		id             string
+		ch             *CommandHandlerMock
+		expectedStatus int
+	}{
+		{
+			name: `Given an out of service endpoint,
+			when it's called with a wrong id,
+			then a 400 HTTP status is returned`,
+			path:           "out-of-service",
+			id:             "wrongID",
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: `Given an out of service endpoint with a ch that returns a not found error,
+			when it's called,
+			then a 404 HTTP status is returned`,
+			path: "out-of-service",
+			id:   uuid.New().String(),
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return nil, repository.ErrNotFound
+				},
+			},
+			expectedStatus: http.StatusNotFound,
+		},
+		{
+			name: `Given an out of service endpoint with a ch that returns a car out of service error,
+			when it's called,
+			then a 409 HTTP status is returned`,
+			path: "out-of-service",
+			id:   uuid.New().String(),
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return nil, domain.ErrCarOutOfService
+				},
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: `Given an out of service endpoint,
+			when it's called with a right id,
+			then a 200 HTTP status is returned`,
+			path: "out-of-service",
+			id:   uuid.New().String(),
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return nil, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name: `Given a back in service endpoint with a ch that returns a car in service error,
+			when it's called,
+			then a 409 HTTP status is returned`,
+			path: "back-in-service",
+			id:   uuid.New().String(),
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return nil, domain.ErrCarInService
+				},
+			},
+			expectedStatus: http.StatusConflict,
+		},
+		{
+			name: `Given a back in service endpoint,
+			when it's called with a right id,
+			then a 200 HTTP status is returned`,
+			path: "back-in-service",
+			id:   uuid.New().String(),
+			ch: &CommandHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Command) ([]events.Event, error) {
+					return nil, nil
+				},
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		bus := bus.New()
+		bus.Register(app.TakeCarOutOfServiceName, helpers.BusChHandler(tc.ch))
+		bus.Register(app.PutCarBackInServiceName, helpers.BusChHandler(tc.ch))
+
+		router := chi.NewRouter()
+		router.Post("/cars/{id}/out-of-service", api.TakeCarOutOfService(bus))
+		router.Post("/cars/{id}/back-in-service", api.PutCarBackInService(bus))
+		r := httptest.NewRequest(http.MethodPost, "/cars/"+tc.id+"/"+tc.path, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+		require.Equal(t, tc.expectedStatus, w.Code, tc.name)
+		if tc.expectedStatus == http.StatusOK {
+			require.Len(t, tc.ch.HandleCalls(), 1, tc.name)
+		}
+	}
+}
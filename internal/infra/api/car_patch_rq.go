@@ -0,0 +1,30 @@
+// Code generated by github.com/atombender/go-jsonschema, DO NOT EDIT.
+
+package api
+
+import "encoding/json"
+import "fmt"
+
+// Schema definition to update a car of the fleet
+type CarPatchRqJson struct {
+	// car seats. The allowed ones are configured, by default from 4 to 6
+	Seats int `json:"seats"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *CarPatchRqJson) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["seats"]; raw != nil && !ok {
+		return fmt.Errorf("field seats in CarPatchRqJson: required")
+	}
+	type Plain CarPatchRqJson
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = CarPatchRqJson(plain)
+	return nil
+}
@@ -0,0 +1,36 @@
+// Code generated by github.com/atombender/go-jsonschema, DO NOT EDIT.
+
+package api
+
+import "encoding/json"
+import "fmt"
+
+// Schema definition to report the current position of a car
+type CarPositionRqJson struct {
+	// latitude, in degrees
+	Lat float64 `json:"lat"`
+
+	// longitude, in degrees
+	Lng float64 `json:"lng"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *CarPositionRqJson) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["lat"]; raw != nil && !ok {
+		return fmt.Errorf("field lat in CarPositionRqJson: required")
+	}
+	if _, ok := raw["lng"]; raw != nil && !ok {
+		return fmt.Errorf("field lng in CarPositionRqJson: required")
+	}
+	type Plain CarPositionRqJson
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = CarPositionRqJson(plain)
+	return nil
+}
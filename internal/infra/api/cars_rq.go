@@ -2,45 +2,58 @@
 
 package api
 
+import "encoding/json"
 import "fmt"
 import "reflect"
-import "encoding/json"
 
-type CarsSeats int
+type Cars struct {
+	// equipment of the car. Optional
+	Features []CarsFeaturesElem `json:"features,omitempty"`
+
+	// car UUID
+	Id string `json:"id"`
+
+	// car seats. The allowed ones are configured, by default from 4 to 6
+	Seats int `json:"seats"`
+
+	// site where the car is based. Optional, by default the default site
+	Site *string `json:"site,omitempty"`
+}
+
+type CarsFeaturesElem string
+
+const CarsFeaturesElemChildSeat CarsFeaturesElem = "child_seat"
+const CarsFeaturesElemLuggage CarsFeaturesElem = "luggage"
+const CarsFeaturesElemWheelchair CarsFeaturesElem = "wheelchair"
 
-var enumValues_CarsSeats = []interface{}{
-	4,
-	5,
-	6,
+var enumValues_CarsFeaturesElem = []interface{}{
+	"wheelchair",
+	"child_seat",
+	"luggage",
 }
 
 // UnmarshalJSON implements json.Unmarshaler.
-func (j *CarsSeats) UnmarshalJSON(b []byte) error {
-	var v int
+func (j *CarsFeaturesElem) UnmarshalJSON(b []byte) error {
+	var v string
 	if err := json.Unmarshal(b, &v); err != nil {
 		return err
 	}
 	var ok bool
-	for _, expected := range enumValues_CarsSeats {
+	for _, expected := range enumValues_CarsFeaturesElem {
 		if reflect.DeepEqual(v, expected) {
 			ok = true
 			break
 		}
 	}
 	if !ok {
-		return fmt.Errorf("invalid value (expected one of %#v): %#v", enumValues_CarsSeats, v)
+		return fmt.Errorf("invalid value (expected one of %#v): %#v", enumValues_CarsFeaturesElem, v)
 	}
-	*j = CarsSeats(v)
+	*j = CarsFeaturesElem(v)
 	return nil
 }
 
-type Cars struct {
-	// car UUID
-	Id string `json:"id"`
-
-	// car seats
-	Seats CarsSeats `json:"seats"`
-}
+// Schema definition to Initialize a fleet of cars
+type CarsRqJson []Cars
 
 // UnmarshalJSON implements json.Unmarshaler.
 func (j *Cars) UnmarshalJSON(b []byte) error {
@@ -48,11 +61,11 @@ func (j *Cars) UnmarshalJSON(b []byte) error {
 	if err := json.Unmarshal(b, &raw); err != nil {
 		return err
 	}
-	if v, ok := raw["id"]; !ok || v == nil {
-		return fmt.Errorf("field id: required")
+	if _, ok := raw["id"]; raw != nil && !ok {
+		return fmt.Errorf("field id in Cars: required")
 	}
-	if v, ok := raw["seats"]; !ok || v == nil {
-		return fmt.Errorf("field seats: required")
+	if _, ok := raw["seats"]; raw != nil && !ok {
+		return fmt.Errorf("field seats in Cars: required")
 	}
 	type Plain Cars
 	var plain Plain
@@ -62,6 +75,3 @@ func (j *Cars) UnmarshalJSON(b []byte) error {
 	*j = Cars(plain)
 	return nil
 }
-
-// Schema definition to Initialize a fleet of cars
-type CarsRqJson []Cars
@@ -0,0 +1,90 @@
+// Code generated by github.com/atombender/go-jsonschema, DO NOT EDIT.
+
+package api
+
+import "encoding/json"
+import "fmt"
+import "time"
+
+// Schema definition of the webhook payloads that could not be delivered
+type DeadLettersRsJson struct {
+	// payloads that could not be delivered, in the order they failed
+	DeadLetters []DeadLettersRsJsonDeadLettersElem `json:"dead_letters"`
+}
+
+type DeadLettersRsJsonDeadLettersElem struct {
+	// number of attempts made
+	Attempts int `json:"attempts"`
+
+	// error of the last attempt
+	Error string `json:"error"`
+
+	// event name
+	Event string `json:"event"`
+
+	// time of the last attempt
+	FailedAt time.Time `json:"failed_at"`
+
+	// payload that could not be delivered
+	Payload string `json:"payload"`
+
+	// URL of the webhook
+	Url string `json:"url"`
+
+	// webhook id
+	WebhookId string `json:"webhook_id"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *DeadLettersRsJsonDeadLettersElem) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["attempts"]; raw != nil && !ok {
+		return fmt.Errorf("field attempts in DeadLettersRsJsonDeadLettersElem: required")
+	}
+	if _, ok := raw["error"]; raw != nil && !ok {
+		return fmt.Errorf("field error in DeadLettersRsJsonDeadLettersElem: required")
+	}
+	if _, ok := raw["event"]; raw != nil && !ok {
+		return fmt.Errorf("field event in DeadLettersRsJsonDeadLettersElem: required")
+	}
+	if _, ok := raw["failed_at"]; raw != nil && !ok {
+		return fmt.Errorf("field failed_at in DeadLettersRsJsonDeadLettersElem: required")
+	}
+	if _, ok := raw["payload"]; raw != nil && !ok {
+		return fmt.Errorf("field payload in DeadLettersRsJsonDeadLettersElem: required")
+	}
+	if _, ok := raw["url"]; raw != nil && !ok {
+		return fmt.Errorf("field url in DeadLettersRsJsonDeadLettersElem: required")
+	}
+	if _, ok := raw["webhook_id"]; raw != nil && !ok {
+		return fmt.Errorf("field webhook_id in DeadLettersRsJsonDeadLettersElem: required")
+	}
+	type Plain DeadLettersRsJsonDeadLettersElem
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = DeadLettersRsJsonDeadLettersElem(plain)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *DeadLettersRsJson) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["dead_letters"]; raw != nil && !ok {
+		return fmt.Errorf("field dead_letters in DeadLettersRsJson: required")
+	}
+	type Plain DeadLettersRsJson
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = DeadLettersRsJson(plain)
+	return nil
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"net/http"
+	"time"
+
+	"theskyinflames/car-sharing/internal/app"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+)
+
+// WebSocket timings of the fleet board feed
+const (
+	fleetBoardWriteWait  = 10 * time.Second
+	fleetBoardPongWait   = 60 * time.Second
+	fleetBoardPingPeriod = fleetBoardPongWait * 9 / 10
+)
+
+var upgrader = websocket.Upgrader{
+	// the API is open to any origin, as in its CORS policy
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// FleetBoard is the WebSocket handler of the live fleet board. It sends a snapshot with all the cars and then
+// a diff with each change. If the client doesn't keep up with the diffs, the connection is closed with
+// the "try again later" status, and the client has to reconnect to get a new snapshot
+func FleetBoard(board app.FleetBoard) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil { // the upgrader has already replied
+			return
+		}
+		defer conn.Close()
+
+		cars, diffs, unsubscribe := board.Subscribe()
+		defer unsubscribe()
+
+		closed := readUntilClosed(conn)
+
+		if err := writeFleetBoard(conn, FleetBoardRsJsonTypeSnapshot, cars, nil); err != nil {
+			return
+		}
+
+		ticker := time.NewTicker(fleetBoardPingPeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-closed:
+				return
+			case <-ticker.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(fleetBoardWriteWait)); err != nil {
+					return
+				}
+			case diff, ok := <-diffs:
+				if !ok {
+					msg := websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "too slow")
+					_ = conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(fleetBoardWriteWait))
+					return
+				}
+				if err := writeFleetBoard(conn, FleetBoardRsJsonTypeDiff, diff.Updated, diff.Removed); err != nil {
+					return
+				}
+			}
+		}
+	}
+}
+
+// readUntilClosed reads the messages of the client, which are discarded, to handle the control ones.
+// The returned channel is closed once the connection is closed, or the client stops answering the pings
+func readUntilClosed(conn *websocket.Conn) <-chan struct{} {
+	closed := make(chan struct{})
+	conn.SetReadLimit(512)
+	_ = conn.SetReadDeadline(time.Now().Add(fleetBoardPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(fleetBoardPongWait))
+	})
+	go func() {
+		defer close(closed)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+	return closed
+}
+
+func writeFleetBoard(conn *websocket.Conn, typ FleetBoardRsJsonType, cars []app.BoardCar, removed []uuid.UUID) error {
+	jsonRs := FleetBoardRsJson{
+		Type:    typ,
+		Cars:    make([]FleetBoardRsJsonCarsElem, 0, len(cars)),
+		Removed: make([]string, 0, len(removed)),
+	}
+	for _, car := range cars {
+		c := FleetBoardRsJsonCarsElem{
+			Id:        car.ID.String(),
+			Site:      car.Site.String(),
+			Seats:     car.Capacity.Int(),
+			Available: car.Availability,
+			Status:    FleetBoardRsJsonCarsElemStatus(car.Status),
+			Groups:    make([]FleetBoardRsJsonCarsElemGroupsElem, 0, len(car.Groups)),
+		}
+		for _, g := range car.Groups {
+			c.Groups = append(c.Groups, FleetBoardRsJsonCarsElemGroupsElem{Id: g.ID.String(), People: g.People})
+		}
+		jsonRs.Cars = append(jsonRs.Cars, c)
+	}
+	for _, id := range removed {
+		jsonRs.Removed = append(jsonRs.Removed, id.String())
+	}
+
+	_ = conn.SetWriteDeadline(time.Now().Add(fleetBoardWriteWait))
+	return conn.WriteJSON(jsonRs)
+}
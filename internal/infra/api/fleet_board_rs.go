@@ -0,0 +1,189 @@
+// Code generated by github.com/atombender/go-jsonschema, DO NOT EDIT.
+
+package api
+
+import "encoding/json"
+import "fmt"
+import "reflect"
+
+// Schema definition of a message of the fleet board feed. The first one is a
+// snapshot with all the cars, and the next ones are the diffs with the cars added
+// or changed and the cars removed
+type FleetBoardRsJson struct {
+	// all the cars in a snapshot, or the cars added or changed in a diff
+	Cars []FleetBoardRsJsonCarsElem `json:"cars"`
+
+	// ids of the cars removed. Always empty in a snapshot
+	Removed []string `json:"removed"`
+
+	// message type
+	Type FleetBoardRsJsonType `json:"type"`
+}
+
+type FleetBoardRsJsonCarsElem struct {
+	// available seats
+	Available int `json:"available"`
+
+	// groups on journey
+	Groups []FleetBoardRsJsonCarsElemGroupsElem `json:"groups"`
+
+	// car id
+	Id string `json:"id"`
+
+	// car seats
+	Seats int `json:"seats"`
+
+	// site where the car is based
+	Site string `json:"site"`
+
+	// car service status
+	Status FleetBoardRsJsonCarsElemStatus `json:"status"`
+}
+
+type FleetBoardRsJsonCarsElemGroupsElem struct {
+	// group id
+	Id string `json:"id"`
+
+	// group size
+	People int `json:"people"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *FleetBoardRsJsonCarsElemGroupsElem) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["id"]; raw != nil && !ok {
+		return fmt.Errorf("field id in FleetBoardRsJsonCarsElemGroupsElem: required")
+	}
+	if _, ok := raw["people"]; raw != nil && !ok {
+		return fmt.Errorf("field people in FleetBoardRsJsonCarsElemGroupsElem: required")
+	}
+	type Plain FleetBoardRsJsonCarsElemGroupsElem
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = FleetBoardRsJsonCarsElemGroupsElem(plain)
+	return nil
+}
+
+type FleetBoardRsJsonCarsElemStatus string
+
+const FleetBoardRsJsonCarsElemStatusAvailable FleetBoardRsJsonCarsElemStatus = "available"
+const FleetBoardRsJsonCarsElemStatusDraining FleetBoardRsJsonCarsElemStatus = "draining"
+const FleetBoardRsJsonCarsElemStatusOutOfService FleetBoardRsJsonCarsElemStatus = "out_of_service"
+
+var enumValues_FleetBoardRsJsonCarsElemStatus = []interface{}{
+	"available",
+	"out_of_service",
+	"draining",
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *FleetBoardRsJsonCarsElemStatus) UnmarshalJSON(b []byte) error {
+	var v string
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	var ok bool
+	for _, expected := range enumValues_FleetBoardRsJsonCarsElemStatus {
+		if reflect.DeepEqual(v, expected) {
+			ok = true
+			break
+		}
+	}
+	if !ok {
+		return fmt.Errorf("invalid value (expected one of %#v): %#v", enumValues_FleetBoardRsJsonCarsElemStatus, v)
+	}
+	*j = FleetBoardRsJsonCarsElemStatus(v)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *FleetBoardRsJsonCarsElem) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["available"]; raw != nil && !ok {
+		return fmt.Errorf("field available in FleetBoardRsJsonCarsElem: required")
+	}
+	if _, ok := raw["groups"]; raw != nil && !ok {
+		return fmt.Errorf("field groups in FleetBoardRsJsonCarsElem: required")
+	}
+	if _, ok := raw["id"]; raw != nil && !ok {
+		return fmt.Errorf("field id in FleetBoardRsJsonCarsElem: required")
+	}
+	if _, ok := raw["seats"]; raw != nil && !ok {
+		return fmt.Errorf("field seats in FleetBoardRsJsonCarsElem: required")
+	}
+	if _, ok := raw["site"]; raw != nil && !ok {
+		return fmt.Errorf("field site in FleetBoardRsJsonCarsElem: required")
+	}
+	if _, ok := raw["status"]; raw != nil && !ok {
+		return fmt.Errorf("field status in FleetBoardRsJsonCarsElem: required")
+	}
+	type Plain FleetBoardRsJsonCarsElem
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = FleetBoardRsJsonCarsElem(plain)
+	return nil
+}
+
+type FleetBoardRsJsonType string
+
+const FleetBoardRsJsonTypeDiff FleetBoardRsJsonType = "diff"
+const FleetBoardRsJsonTypeSnapshot FleetBoardRsJsonType = "snapshot"
+
+var enumValues_FleetBoardRsJsonType = []interface{}{
+	"snapshot",
+	"diff",
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *FleetBoardRsJsonType) UnmarshalJSON(b []byte) error {
+	var v string
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	var ok bool
+	for _, expected := range enumValues_FleetBoardRsJsonType {
+		if reflect.DeepEqual(v, expected) {
+			ok = true
+			break
+		}
+	}
+	if !ok {
+		return fmt.Errorf("invalid value (expected one of %#v): %#v", enumValues_FleetBoardRsJsonType, v)
+	}
+	*j = FleetBoardRsJsonType(v)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *FleetBoardRsJson) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["cars"]; raw != nil && !ok {
+		return fmt.Errorf("field cars in FleetBoardRsJson: required")
+	}
+	if _, ok := raw["removed"]; raw != nil && !ok {
+		return fmt.Errorf("field removed in FleetBoardRsJson: required")
+	}
+	if _, ok := raw["type"]; raw != nil && !ok {
+		return fmt.Errorf("field type in FleetBoardRsJson: required")
+	}
+	type Plain FleetBoardRsJson
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = FleetBoardRsJson(plain)
+	return nil
+}
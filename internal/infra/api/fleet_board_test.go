@@ -0,0 +1,105 @@
+package api_test
+
+import (
+	"context"
+	"io"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/domain"
+	"theskyinflames/car-sharing/internal/fixtures"
+	"theskyinflames/car-sharing/internal/infra/api"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/require"
+	"github.com/theskyinflames/cqrs-eda/pkg/bus"
+	"github.com/theskyinflames/cqrs-eda/pkg/cqrs"
+	"github.com/theskyinflames/cqrs-eda/pkg/helpers"
+)
+
+func TestFleetBoard(t *testing.T) {
+	var (
+		capacity = domain.CarCapacity4
+		people   = 3
+		g        = fixtures.Group{People: &people}.Build()
+		car      = fixtures.Car{Capacity: &capacity}.Build()
+		carID    = car.ID()
+		carOn    = fixtures.Car{ID: &carID, Capacity: &capacity, Journeys: domain.Journeys{g.ID(): g}}.Build()
+		ev       = domain.NewCarCreatedEvent(car)
+
+		mux  sync.Mutex
+		cars = []domain.Car{car}
+		qh   = &QueryHandlerMock{
+			HandleFunc: func(_ context.Context, _ cqrs.Query) (cqrs.QueryResult, error) {
+				mux.Lock()
+				defer mux.Unlock()
+				return app.FleetStatusResponse{Cars: cars}, nil
+			},
+		}
+	)
+
+	queryBus := bus.New()
+	queryBus.Register(app.FleetStatusName, helpers.BusQhHandler(qh))
+	hub := app.NewEventsHub()
+	board := app.NewFleetBoard(queryBus, hub, log.New(io.Discard, "", 0), 1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go board.Run(ctx)
+	require.Eventually(t, func() bool {
+		snapshot, _, unsubscribe := board.Subscribe()
+		unsubscribe()
+		return len(snapshot) == 1
+	}, time.Second, time.Millisecond)
+
+	srv := httptest.NewServer(http.HandlerFunc(api.FleetBoard(board)))
+	defer srv.Close()
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	var rs api.FleetBoardRsJson
+	require.NoError(t, conn.ReadJSON(&rs))
+	require.Equal(t, api.FleetBoardRsJson{
+		Type:    api.FleetBoardRsJsonTypeSnapshot,
+		Cars:    []api.FleetBoardRsJsonCarsElem{{Id: car.ID().String(), Site: "default", Seats: 4, Available: 4, Status: api.FleetBoardRsJsonCarsElemStatusAvailable, Groups: []api.FleetBoardRsJsonCarsElemGroupsElem{}}},
+		Removed: []string{},
+	}, rs)
+
+	mux.Lock()
+	cars = []domain.Car{carOn}
+	mux.Unlock()
+	hub.Handler()(ev)
+
+	require.NoError(t, conn.ReadJSON(&rs))
+	require.Equal(t, api.FleetBoardRsJson{
+		Type: api.FleetBoardRsJsonTypeDiff,
+		Cars: []api.FleetBoardRsJsonCarsElem{{
+			Id:        car.ID().String(),
+			Site:      "default",
+			Seats:     4,
+			Available: 1,
+			Status:    api.FleetBoardRsJsonCarsElemStatusAvailable,
+			Groups:    []api.FleetBoardRsJsonCarsElemGroupsElem{{Id: g.ID().String(), People: 3}},
+		}},
+		Removed: []string{},
+	}, rs)
+
+	mux.Lock()
+	cars = nil
+	mux.Unlock()
+	hub.Handler()(ev)
+
+	require.NoError(t, conn.ReadJSON(&rs))
+	require.Equal(t, api.FleetBoardRsJson{
+		Type:    api.FleetBoardRsJsonTypeDiff,
+		Cars:    []api.FleetBoardRsJsonCarsElem{},
+		Removed: []string{car.ID().String()},
+	}, rs)
+}
@@ -0,0 +1,101 @@
+// Code generated by github.com/atombender/go-jsonschema, DO NOT EDIT.
+
+package api
+
+import "encoding/json"
+import "fmt"
+import "reflect"
+
+// Schema definition of a status change of a group, sent as the data of a
+// server-sent event
+type JourneyEventRsJson struct {
+	// car of the group. Only set while it's on journey
+	Car *JourneyEventRsJsonCar `json:"car,omitempty"`
+
+	// group status
+	Status JourneyEventRsJsonStatus `json:"status"`
+}
+
+// car of the group. Only set while it's on journey
+type JourneyEventRsJsonCar struct {
+	// car id
+	Id string `json:"id"`
+
+	// car seats
+	Seats int `json:"seats"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *JourneyEventRsJsonCar) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["id"]; raw != nil && !ok {
+		return fmt.Errorf("field id in JourneyEventRsJsonCar: required")
+	}
+	if _, ok := raw["seats"]; raw != nil && !ok {
+		return fmt.Errorf("field seats in JourneyEventRsJsonCar: required")
+	}
+	type Plain JourneyEventRsJsonCar
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = JourneyEventRsJsonCar(plain)
+	return nil
+}
+
+type JourneyEventRsJsonStatus string
+
+const JourneyEventRsJsonStatusCancelled JourneyEventRsJsonStatus = "cancelled"
+const JourneyEventRsJsonStatusDroppedOff JourneyEventRsJsonStatus = "dropped_off"
+const JourneyEventRsJsonStatusExpired JourneyEventRsJsonStatus = "expired"
+const JourneyEventRsJsonStatusOnJourney JourneyEventRsJsonStatus = "on_journey"
+const JourneyEventRsJsonStatusWaiting JourneyEventRsJsonStatus = "waiting"
+
+var enumValues_JourneyEventRsJsonStatus = []interface{}{
+	"waiting",
+	"on_journey",
+	"dropped_off",
+	"cancelled",
+	"expired",
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *JourneyEventRsJsonStatus) UnmarshalJSON(b []byte) error {
+	var v string
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	var ok bool
+	for _, expected := range enumValues_JourneyEventRsJsonStatus {
+		if reflect.DeepEqual(v, expected) {
+			ok = true
+			break
+		}
+	}
+	if !ok {
+		return fmt.Errorf("invalid value (expected one of %#v): %#v", enumValues_JourneyEventRsJsonStatus, v)
+	}
+	*j = JourneyEventRsJsonStatus(v)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *JourneyEventRsJson) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["status"]; raw != nil && !ok {
+		return fmt.Errorf("field status in JourneyEventRsJson: required")
+	}
+	type Plain JourneyEventRsJson
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = JourneyEventRsJson(plain)
+	return nil
+}
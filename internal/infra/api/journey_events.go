@@ -0,0 +1,104 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/domain"
+	"theskyinflames/car-sharing/internal/infra/repository"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/theskyinflames/cqrs-eda/pkg/bus"
+)
+
+// journeyEventsBufferSize is the number of events that a stream can have pending to be sent.
+// If it's exceeded, the stream is closed, and the client has to reconnect
+const journeyEventsBufferSize = 64
+
+// JourneyEvents is the HTTP handler to stream the status changes of a group as server-sent events.
+// The current status is sent first, and the stream is closed once the group is dropped off, cancelled or expired.
+// A comment is sent each keepAlive, so the idle connections are not closed by the proxies
+func JourneyEvents(queryBus bus.Bus, hub app.EventsHub, keepAlive time.Duration) func(w http.ResponseWriter, r *http.Request) {
+	return func(w http.ResponseWriter, r *http.Request) {
+		gID, err := uuid.Parse(chi.URLParam(r, "id"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		flusher, ok := w.(http.Flusher)
+		if !ok {
+			http.Error(w, "streaming not supported", http.StatusInternalServerError)
+			return
+		}
+
+		// it's subscribed before locating the group, so no status change is lost in between
+		evs, unsubscribe := hub.Subscribe(journeyEventsBufferSize)
+		defer unsubscribe()
+
+		queryRs, err := queryBus.Dispatch(r.Context(), app.LocateQuery{GroupID: gID})
+		if err != nil {
+			if errors.Is(err, domain.ErrNotFound) || errors.Is(err, repository.ErrNotFound) {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("Connection", "keep-alive")
+		w.WriteHeader(http.StatusOK)
+
+		last := app.NewGroupStatus(queryRs.(app.LocateResponse))
+		if err := writeGroupStatus(w, last); err != nil {
+			return
+		}
+		flusher.Flush()
+
+		ticker := time.NewTicker(keepAlive)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.Context().Done():
+				return
+			case <-ticker.C:
+				if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+					return
+				}
+				flusher.Flush()
+			case ev, ok := <-evs:
+				if !ok { // the stream didn't keep up with the events
+					return
+				}
+				status, ok := app.GroupStatusFromEvent(gID, ev)
+				if !ok || status == last {
+					continue
+				}
+				last = status
+				if err := writeGroupStatus(w, status); err != nil {
+					return
+				}
+				flusher.Flush()
+				if status.Status == app.GroupDroppedOff || status.Status == app.GroupCancelled || status.Status == app.GroupExpired {
+					return
+				}
+			}
+		}
+	}
+}
+
+func writeGroupStatus(w http.ResponseWriter, status app.GroupStatus) error {
+	jsonRs := JourneyEventRsJson{Status: JourneyEventRsJsonStatus(status.Status)}
+	if status.Status == app.GroupOnJourney {
+		jsonRs.Car = &JourneyEventRsJsonCar{Id: status.CarID.String(), Seats: status.Seats.Int()}
+	}
+	b, _ := json.Marshal(jsonRs)
+	_, err := fmt.Fprintf(w, "event: %s\ndata: %s\n\n", status.Status, b)
+	return err
+}
@@ -0,0 +1,142 @@
+package api_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/domain"
+	"theskyinflames/car-sharing/internal/fixtures"
+	"theskyinflames/car-sharing/internal/infra/api"
+
+	"github.com/go-chi/chi"
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/theskyinflames/cqrs-eda/pkg/bus"
+	"github.com/theskyinflames/cqrs-eda/pkg/cqrs"
+	"github.com/theskyinflames/cqrs-eda/pkg/helpers"
+)
+
+func TestJourneyEvents(t *testing.T) {
+	t.Run(`Given a journey events endpoint, when it's called with a wrong id, then a 400 HTTP status is returned`, func(t *testing.T) {
+		srv := journeyEventsServer(bus.New(), app.NewEventsHub())
+		defer srv.Close()
+
+		rs, err := http.Get(srv.URL + "/v1/journey/wrongID/events")
+		require.NoError(t, err)
+		defer rs.Body.Close()
+		require.Equal(t, http.StatusBadRequest, rs.StatusCode)
+	})
+
+	t.Run(`Given a journey events endpoint, when it's called for a group that doesn't exist, then a 404 HTTP status is returned`, func(t *testing.T) {
+		qh := &QueryHandlerMock{
+			HandleFunc: func(_ context.Context, _ cqrs.Query) (cqrs.QueryResult, error) {
+				return nil, domain.ErrNotFound
+			},
+		}
+		queryBus := bus.New()
+		queryBus.Register(app.LocateName, helpers.BusQhHandler(qh))
+		srv := journeyEventsServer(queryBus, app.NewEventsHub())
+		defer srv.Close()
+
+		rs, err := http.Get(srv.URL + "/v1/journey/" + uuid.NewString() + "/events")
+		require.NoError(t, err)
+		defer rs.Body.Close()
+		require.Equal(t, http.StatusNotFound, rs.StatusCode)
+	})
+
+	t.Run(`Given a journey events endpoint with a qh that returns an error, when it's called, then a 500 HTTP status is returned`, func(t *testing.T) {
+		qh := &QueryHandlerMock{
+			HandleFunc: func(_ context.Context, _ cqrs.Query) (cqrs.QueryResult, error) {
+				return nil, errors.New("")
+			},
+		}
+		queryBus := bus.New()
+		queryBus.Register(app.LocateName, helpers.BusQhHandler(qh))
+		srv := journeyEventsServer(queryBus, app.NewEventsHub())
+		defer srv.Close()
+
+		rs, err := http.Get(srv.URL + "/v1/journey/" + uuid.NewString() + "/events")
+		require.NoError(t, err)
+		defer rs.Body.Close()
+		require.Equal(t, http.StatusInternalServerError, rs.StatusCode)
+	})
+
+	t.Run(`Given a waiting group, when its events are streamed, then its status changes are sent until it's dropped off`, func(t *testing.T) {
+		capacity := domain.CarCapacity6
+		var (
+			car   = fixtures.Car{Capacity: &capacity}.Build()
+			g     = fixtures.Group{}.Build()
+			other = fixtures.Group{}.Build()
+			hub   = app.NewEventsHub()
+			qh    = &QueryHandlerMock{
+				HandleFunc: func(_ context.Context, _ cqrs.Query) (cqrs.QueryResult, error) {
+					return app.LocateResponse{}, nil
+				},
+			}
+		)
+		queryBus := bus.New()
+		queryBus.Register(app.LocateName, helpers.BusQhHandler(qh))
+		srv := journeyEventsServer(queryBus, hub)
+		defer srv.Close()
+
+		rs, err := http.Get(srv.URL + "/v1/journey/" + g.ID().String() + "/events")
+		require.NoError(t, err)
+		defer rs.Body.Close()
+		require.Equal(t, http.StatusOK, rs.StatusCode)
+		require.Equal(t, "text/event-stream", rs.Header.Get("Content-Type"))
+
+		r := bufio.NewReader(rs.Body)
+		require.Equal(t, api.JourneyEventRsJson{Status: api.JourneyEventRsJsonStatusWaiting}, readJourneyEvent(t, r, "waiting"))
+
+		other.GetOn(&car)
+		hub.Handler()(domain.NewGroupSetOnJourneyEvent(other))
+		g.GetOn(&car)
+		hub.Handler()(domain.NewGroupSetOnJourneyEvent(g))
+		require.Equal(t, api.JourneyEventRsJson{
+			Status: api.JourneyEventRsJsonStatusOnJourney,
+			Car:    &api.JourneyEventRsJsonCar{Id: car.ID().String(), Seats: 6},
+		}, readJourneyEvent(t, r, "on_journey"))
+
+		g.DropOff()
+		hub.Handler()(domain.NewGroupDroppedOff(g))
+		require.Equal(t, api.JourneyEventRsJson{Status: api.JourneyEventRsJsonStatusDroppedOff}, readJourneyEvent(t, r, "dropped_off"))
+
+		// the stream is closed
+		_, err = r.ReadString('\n')
+		require.Error(t, err)
+	})
+}
+
+func journeyEventsServer(queryBus bus.Bus, hub app.EventsHub) *httptest.Server {
+	r := chi.NewRouter()
+	r.Get("/v1/journey/{id}/events", api.JourneyEvents(queryBus, hub, time.Hour))
+	return httptest.NewServer(r)
+}
+
+// readJourneyEvent reads the next server-sent event, checking its name
+func readJourneyEvent(t *testing.T, r *bufio.Reader, name string) api.JourneyEventRsJson {
+	var lines []string
+	for {
+		line, err := r.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			break
+		}
+		lines = append(lines, line)
+	}
+	require.Len(t, lines, 2)
+	require.Equal(t, "event: "+name, lines[0])
+
+	var rs api.JourneyEventRsJson
+	require.NoError(t, json.Unmarshal([]byte(strings.TrimPrefix(lines[1], "data: ")), &rs))
+	return rs
+}
@@ -2,61 +2,111 @@
 
 package api
 
+import "encoding/json"
 import "fmt"
 import "reflect"
-import "encoding/json"
 
-type JourneyRqJsonPeople int
+// Schema definition to add a group for a journey
+type JourneyRqJson struct {
+	// where the group is going. It's only pooled with the groups going to the same
+	// destination. Optional
+	Destination *string `json:"destination,omitempty"`
+
+	// group id
+	Id string `json:"id"`
+
+	// employee IDs of the people of the group. Optional, and it can be partial, but
+	// no more than the people of the group
+	Members []string `json:"members,omitempty"`
+
+	// group size. The largest group allowed fills the largest car allowed, by default
+	// 6
+	People int `json:"people"`
+
+	// where the group waits to be picked up. Optional
+	Pickup *JourneyRqJsonPickup `json:"pickup,omitempty"`
 
-var enumValues_JourneyRqJsonPeople = []interface{}{
-	1,
-	2,
-	3,
-	4,
-	5,
-	6,
+	// features that the car must have for the group to get on it. Optional
+	Requirements []JourneyRqJsonRequirementsElem `json:"requirements,omitempty"`
+
+	// origin site of the group. It only gets on the cars based there. Optional, by
+	// default the default site
+	Site *string `json:"site,omitempty"`
+}
+
+// where the group waits to be picked up. Optional
+type JourneyRqJsonPickup struct {
+	// latitude, in degrees
+	Lat float64 `json:"lat"`
+
+	// longitude, in degrees
+	Lng float64 `json:"lng"`
 }
 
 // UnmarshalJSON implements json.Unmarshaler.
-func (j *JourneyRqJsonPeople) UnmarshalJSON(b []byte) error {
-	var v int
+func (j *JourneyRqJsonPickup) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["lat"]; raw != nil && !ok {
+		return fmt.Errorf("field lat in JourneyRqJsonPickup: required")
+	}
+	if _, ok := raw["lng"]; raw != nil && !ok {
+		return fmt.Errorf("field lng in JourneyRqJsonPickup: required")
+	}
+	type Plain JourneyRqJsonPickup
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = JourneyRqJsonPickup(plain)
+	return nil
+}
+
+type JourneyRqJsonRequirementsElem string
+
+const JourneyRqJsonRequirementsElemChildSeat JourneyRqJsonRequirementsElem = "child_seat"
+const JourneyRqJsonRequirementsElemLuggage JourneyRqJsonRequirementsElem = "luggage"
+const JourneyRqJsonRequirementsElemWheelchair JourneyRqJsonRequirementsElem = "wheelchair"
+
+var enumValues_JourneyRqJsonRequirementsElem = []interface{}{
+	"wheelchair",
+	"child_seat",
+	"luggage",
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *JourneyRqJsonRequirementsElem) UnmarshalJSON(b []byte) error {
+	var v string
 	if err := json.Unmarshal(b, &v); err != nil {
 		return err
 	}
 	var ok bool
-	for _, expected := range enumValues_JourneyRqJsonPeople {
+	for _, expected := range enumValues_JourneyRqJsonRequirementsElem {
 		if reflect.DeepEqual(v, expected) {
 			ok = true
 			break
 		}
 	}
 	if !ok {
-		return fmt.Errorf("invalid value (expected one of %#v): %#v", enumValues_JourneyRqJsonPeople, v)
+		return fmt.Errorf("invalid value (expected one of %#v): %#v", enumValues_JourneyRqJsonRequirementsElem, v)
 	}
-	*j = JourneyRqJsonPeople(v)
+	*j = JourneyRqJsonRequirementsElem(v)
 	return nil
 }
 
-// Schema definition to add a group for a journey
-type JourneyRqJson struct {
-	// group id
-	Id string `json:"id"`
-
-	// group size. Allowed from 1 to 6
-	People JourneyRqJsonPeople `json:"people"`
-}
-
 // UnmarshalJSON implements json.Unmarshaler.
 func (j *JourneyRqJson) UnmarshalJSON(b []byte) error {
 	var raw map[string]interface{}
 	if err := json.Unmarshal(b, &raw); err != nil {
 		return err
 	}
-	if v, ok := raw["id"]; !ok || v == nil {
-		return fmt.Errorf("field id: required")
+	if _, ok := raw["id"]; raw != nil && !ok {
+		return fmt.Errorf("field id in JourneyRqJson: required")
 	}
-	if v, ok := raw["people"]; !ok || v == nil {
-		return fmt.Errorf("field people: required")
+	if _, ok := raw["people"]; raw != nil && !ok {
+		return fmt.Errorf("field people in JourneyRqJson: required")
 	}
 	type Plain JourneyRqJson
 	var plain Plain
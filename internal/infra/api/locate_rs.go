@@ -2,58 +2,68 @@
 
 package api
 
+import "encoding/json"
 import "fmt"
 import "reflect"
-import "encoding/json"
 
-type LocateRsJsonSeats int
+// Schema definition to Initialize a fleet
+type LocateRsJson struct {
+	// car uuid
+	Id string `json:"id"`
+
+	// car seats
+	Seats int `json:"seats"`
 
-var enumValues_LocateRsJsonSeats = []interface{}{
-	4,
-	5,
-	6,
+	// car service status
+	Status LocateRsJsonStatus `json:"status"`
+}
+
+type LocateRsJsonStatus string
+
+const LocateRsJsonStatusAvailable LocateRsJsonStatus = "available"
+const LocateRsJsonStatusDraining LocateRsJsonStatus = "draining"
+const LocateRsJsonStatusOutOfService LocateRsJsonStatus = "out_of_service"
+
+var enumValues_LocateRsJsonStatus = []interface{}{
+	"available",
+	"out_of_service",
+	"draining",
 }
 
 // UnmarshalJSON implements json.Unmarshaler.
-func (j *LocateRsJsonSeats) UnmarshalJSON(b []byte) error {
-	var v int
+func (j *LocateRsJsonStatus) UnmarshalJSON(b []byte) error {
+	var v string
 	if err := json.Unmarshal(b, &v); err != nil {
 		return err
 	}
 	var ok bool
-	for _, expected := range enumValues_LocateRsJsonSeats {
+	for _, expected := range enumValues_LocateRsJsonStatus {
 		if reflect.DeepEqual(v, expected) {
 			ok = true
 			break
 		}
 	}
 	if !ok {
-		return fmt.Errorf("invalid value (expected one of %#v): %#v", enumValues_LocateRsJsonSeats, v)
+		return fmt.Errorf("invalid value (expected one of %#v): %#v", enumValues_LocateRsJsonStatus, v)
 	}
-	*j = LocateRsJsonSeats(v)
+	*j = LocateRsJsonStatus(v)
 	return nil
 }
 
-// Schema definition to Initialize a fleet
-type LocateRsJson struct {
-	// car uuid
-	Id string `json:"id"`
-
-	// ev seats
-	Seats LocateRsJsonSeats `json:"seats"`
-}
-
 // UnmarshalJSON implements json.Unmarshaler.
 func (j *LocateRsJson) UnmarshalJSON(b []byte) error {
 	var raw map[string]interface{}
 	if err := json.Unmarshal(b, &raw); err != nil {
 		return err
 	}
-	if v, ok := raw["id"]; !ok || v == nil {
-		return fmt.Errorf("field id: required")
+	if _, ok := raw["id"]; raw != nil && !ok {
+		return fmt.Errorf("field id in LocateRsJson: required")
+	}
+	if _, ok := raw["seats"]; raw != nil && !ok {
+		return fmt.Errorf("field seats in LocateRsJson: required")
 	}
-	if v, ok := raw["seats"]; !ok || v == nil {
-		return fmt.Errorf("field seats: required")
+	if _, ok := raw["status"]; raw != nil && !ok {
+		return fmt.Errorf("field status in LocateRsJson: required")
 	}
 	type Plain LocateRsJson
 	var plain Plain
@@ -0,0 +1,90 @@
+// Code generated by github.com/atombender/go-jsonschema, DO NOT EDIT.
+
+package api
+
+import "encoding/json"
+import "fmt"
+import "reflect"
+import "time"
+
+// Schema definition to book the seats of a car for a group in a future window
+type ReservationRqJson struct {
+	// end of the window, after its start
+	EndAt time.Time `json:"end_at"`
+
+	// group id. The group gets on the booked car with it once the window starts
+	Id string `json:"id"`
+
+	// group size. The largest group allowed fills the largest car allowed, by default
+	// 6
+	People int `json:"people"`
+
+	// features that the booked car must have. Optional
+	Requirements []ReservationRqJsonRequirementsElem `json:"requirements,omitempty"`
+
+	// origin site of the group. The booked car is based there. Optional, by default
+	// the default site
+	Site *string `json:"site,omitempty"`
+
+	// start of the window, in the future
+	StartAt time.Time `json:"start_at"`
+}
+
+type ReservationRqJsonRequirementsElem string
+
+const ReservationRqJsonRequirementsElemChildSeat ReservationRqJsonRequirementsElem = "child_seat"
+const ReservationRqJsonRequirementsElemLuggage ReservationRqJsonRequirementsElem = "luggage"
+const ReservationRqJsonRequirementsElemWheelchair ReservationRqJsonRequirementsElem = "wheelchair"
+
+var enumValues_ReservationRqJsonRequirementsElem = []interface{}{
+	"wheelchair",
+	"child_seat",
+	"luggage",
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *ReservationRqJsonRequirementsElem) UnmarshalJSON(b []byte) error {
+	var v string
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	var ok bool
+	for _, expected := range enumValues_ReservationRqJsonRequirementsElem {
+		if reflect.DeepEqual(v, expected) {
+			ok = true
+			break
+		}
+	}
+	if !ok {
+		return fmt.Errorf("invalid value (expected one of %#v): %#v", enumValues_ReservationRqJsonRequirementsElem, v)
+	}
+	*j = ReservationRqJsonRequirementsElem(v)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *ReservationRqJson) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["end_at"]; raw != nil && !ok {
+		return fmt.Errorf("field end_at in ReservationRqJson: required")
+	}
+	if _, ok := raw["id"]; raw != nil && !ok {
+		return fmt.Errorf("field id in ReservationRqJson: required")
+	}
+	if _, ok := raw["people"]; raw != nil && !ok {
+		return fmt.Errorf("field people in ReservationRqJson: required")
+	}
+	if _, ok := raw["start_at"]; raw != nil && !ok {
+		return fmt.Errorf("field start_at in ReservationRqJson: required")
+	}
+	type Plain ReservationRqJson
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = ReservationRqJson(plain)
+	return nil
+}
@@ -0,0 +1,55 @@
+// Code generated by github.com/atombender/go-jsonschema, DO NOT EDIT.
+
+package api
+
+import "encoding/json"
+import "fmt"
+import "time"
+
+// Schema definition of a reservation whose seats have been booked
+type ReservationRsJson struct {
+	// end of the window
+	EndAt time.Time `json:"end_at"`
+
+	// group id
+	Id string `json:"id"`
+
+	// group size
+	People int `json:"people"`
+
+	// origin site of the group
+	Site string `json:"site"`
+
+	// start of the window
+	StartAt time.Time `json:"start_at"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *ReservationRsJson) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["end_at"]; raw != nil && !ok {
+		return fmt.Errorf("field end_at in ReservationRsJson: required")
+	}
+	if _, ok := raw["id"]; raw != nil && !ok {
+		return fmt.Errorf("field id in ReservationRsJson: required")
+	}
+	if _, ok := raw["people"]; raw != nil && !ok {
+		return fmt.Errorf("field people in ReservationRsJson: required")
+	}
+	if _, ok := raw["site"]; raw != nil && !ok {
+		return fmt.Errorf("field site in ReservationRsJson: required")
+	}
+	if _, ok := raw["start_at"]; raw != nil && !ok {
+		return fmt.Errorf("field start_at in ReservationRsJson: required")
+	}
+	type Plain ReservationRsJson
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = ReservationRsJson(plain)
+	return nil
+}
@@ -0,0 +1,84 @@
+// Code generated by github.com/atombender/go-jsonschema, DO NOT EDIT.
+
+package api
+
+import "encoding/json"
+import "fmt"
+
+// Schema definition of the occupancy of the cars and the queue of waiting groups
+// of each site
+type SitesRsJson struct {
+	// the sites that have cars or waiting groups, sorted by name
+	Sites []SitesRsJsonSitesElem `json:"sites"`
+}
+
+type SitesRsJsonSitesElem struct {
+	// number of cars based in the site
+	Cars int `json:"cars"`
+
+	// number of seats occupied by groups on journey
+	OccupiedSeats int `json:"occupied_seats"`
+
+	// total number of seats of the cars of the site
+	Seats int `json:"seats"`
+
+	// site name
+	Site string `json:"site"`
+
+	// number of groups waiting for a car of the site
+	WaitingGroups int `json:"waiting_groups"`
+
+	// number of people waiting for a car of the site
+	WaitingPeople int `json:"waiting_people"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *SitesRsJsonSitesElem) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["cars"]; raw != nil && !ok {
+		return fmt.Errorf("field cars in SitesRsJsonSitesElem: required")
+	}
+	if _, ok := raw["occupied_seats"]; raw != nil && !ok {
+		return fmt.Errorf("field occupied_seats in SitesRsJsonSitesElem: required")
+	}
+	if _, ok := raw["seats"]; raw != nil && !ok {
+		return fmt.Errorf("field seats in SitesRsJsonSitesElem: required")
+	}
+	if _, ok := raw["site"]; raw != nil && !ok {
+		return fmt.Errorf("field site in SitesRsJsonSitesElem: required")
+	}
+	if _, ok := raw["waiting_groups"]; raw != nil && !ok {
+		return fmt.Errorf("field waiting_groups in SitesRsJsonSitesElem: required")
+	}
+	if _, ok := raw["waiting_people"]; raw != nil && !ok {
+		return fmt.Errorf("field waiting_people in SitesRsJsonSitesElem: required")
+	}
+	type Plain SitesRsJsonSitesElem
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = SitesRsJsonSitesElem(plain)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *SitesRsJson) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["sites"]; raw != nil && !ok {
+		return fmt.Errorf("field sites in SitesRsJson: required")
+	}
+	type Plain SitesRsJson
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = SitesRsJson(plain)
+	return nil
+}
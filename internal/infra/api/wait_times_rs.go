@@ -0,0 +1,91 @@
+// Code generated by github.com/atombender/go-jsonschema, DO NOT EDIT.
+
+package api
+
+import "encoding/json"
+import "fmt"
+
+// Schema definition of the waiting times of the groups that got on a car, or left
+// without it, during the window
+type WaitTimesRsJson struct {
+	// waiting times by group size
+	Groups []WaitTimesRsJsonGroupsElem `json:"groups"`
+
+	// window of the stats, in seconds
+	WindowSeconds float64 `json:"window_seconds"`
+}
+
+type WaitTimesRsJsonGroupsElem struct {
+	// number of groups of this size that were cancelled or expired during the window,
+	// without getting on a car. Their waits are in the percentiles
+	Abandoned int `json:"abandoned"`
+
+	// number of groups of this size that got on a car during the window
+	Journeys int `json:"journeys"`
+
+	// 50th percentile of the waiting time, in seconds
+	P50Seconds float64 `json:"p50_seconds"`
+
+	// 90th percentile of the waiting time, in seconds
+	P90Seconds float64 `json:"p90_seconds"`
+
+	// 99th percentile of the waiting time, in seconds
+	P99Seconds float64 `json:"p99_seconds"`
+
+	// group size
+	People int `json:"people"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *WaitTimesRsJsonGroupsElem) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["abandoned"]; raw != nil && !ok {
+		return fmt.Errorf("field abandoned in WaitTimesRsJsonGroupsElem: required")
+	}
+	if _, ok := raw["journeys"]; raw != nil && !ok {
+		return fmt.Errorf("field journeys in WaitTimesRsJsonGroupsElem: required")
+	}
+	if _, ok := raw["p50_seconds"]; raw != nil && !ok {
+		return fmt.Errorf("field p50_seconds in WaitTimesRsJsonGroupsElem: required")
+	}
+	if _, ok := raw["p90_seconds"]; raw != nil && !ok {
+		return fmt.Errorf("field p90_seconds in WaitTimesRsJsonGroupsElem: required")
+	}
+	if _, ok := raw["p99_seconds"]; raw != nil && !ok {
+		return fmt.Errorf("field p99_seconds in WaitTimesRsJsonGroupsElem: required")
+	}
+	if _, ok := raw["people"]; raw != nil && !ok {
+		return fmt.Errorf("field people in WaitTimesRsJsonGroupsElem: required")
+	}
+	type Plain WaitTimesRsJsonGroupsElem
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = WaitTimesRsJsonGroupsElem(plain)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *WaitTimesRsJson) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["groups"]; raw != nil && !ok {
+		return fmt.Errorf("field groups in WaitTimesRsJson: required")
+	}
+	if _, ok := raw["window_seconds"]; raw != nil && !ok {
+		return fmt.Errorf("field window_seconds in WaitTimesRsJson: required")
+	}
+	type Plain WaitTimesRsJson
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = WaitTimesRsJson(plain)
+	return nil
+}
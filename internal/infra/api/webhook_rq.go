@@ -0,0 +1,79 @@
+// Code generated by github.com/atombender/go-jsonschema, DO NOT EDIT.
+
+package api
+
+import "encoding/json"
+import "fmt"
+import "reflect"
+
+// Schema definition to subscribe an URL to the journey lifecycle events
+type WebhookRqJson struct {
+	// events the webhook is subscribed to
+	Events []WebhookRqJsonEventsElem `json:"events"`
+
+	// secret used to sign the payloads. If it's not given, a random one is generated
+	Secret *string `json:"secret,omitempty"`
+
+	// absolute http or https URL the events are POSTed to
+	Url string `json:"url"`
+}
+
+type WebhookRqJsonEventsElem string
+
+const WebhookRqJsonEventsElemCarAdded WebhookRqJsonEventsElem = "car.added"
+const WebhookRqJsonEventsElemGroupCancelled WebhookRqJsonEventsElem = "group.cancelled"
+const WebhookRqJsonEventsElemGroupDroppedOff WebhookRqJsonEventsElem = "group.dropped.off"
+const WebhookRqJsonEventsElemGroupExpired WebhookRqJsonEventsElem = "group.expired"
+const WebhookRqJsonEventsElemGroupIsOnJourney WebhookRqJsonEventsElem = "group.is.on.journey"
+
+var enumValues_WebhookRqJsonEventsElem = []interface{}{
+	"car.added",
+	"group.is.on.journey",
+	"group.dropped.off",
+	"group.cancelled",
+	"group.expired",
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *WebhookRqJsonEventsElem) UnmarshalJSON(b []byte) error {
+	var v string
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	var ok bool
+	for _, expected := range enumValues_WebhookRqJsonEventsElem {
+		if reflect.DeepEqual(v, expected) {
+			ok = true
+			break
+		}
+	}
+	if !ok {
+		return fmt.Errorf("invalid value (expected one of %#v): %#v", enumValues_WebhookRqJsonEventsElem, v)
+	}
+	*j = WebhookRqJsonEventsElem(v)
+	return nil
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *WebhookRqJson) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["events"]; raw != nil && !ok {
+		return fmt.Errorf("field events in WebhookRqJson: required")
+	}
+	if _, ok := raw["url"]; raw != nil && !ok {
+		return fmt.Errorf("field url in WebhookRqJson: required")
+	}
+	type Plain WebhookRqJson
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	if plain.Events != nil && len(plain.Events) < 1 {
+		return fmt.Errorf("field %s length: must be >= %d", "events", 1)
+	}
+	*j = WebhookRqJson(plain)
+	return nil
+}
@@ -0,0 +1,48 @@
+// Code generated by github.com/atombender/go-jsonschema, DO NOT EDIT.
+
+package api
+
+import "encoding/json"
+import "fmt"
+
+// Schema definition of a registered webhook
+type WebhookRsJson struct {
+	// events the webhook is subscribed to
+	Events []string `json:"events"`
+
+	// webhook id
+	Id string `json:"id"`
+
+	// secret used to sign the payloads
+	Secret string `json:"secret"`
+
+	// URL the events are POSTed to
+	Url string `json:"url"`
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+func (j *WebhookRsJson) UnmarshalJSON(b []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	if _, ok := raw["events"]; raw != nil && !ok {
+		return fmt.Errorf("field events in WebhookRsJson: required")
+	}
+	if _, ok := raw["id"]; raw != nil && !ok {
+		return fmt.Errorf("field id in WebhookRsJson: required")
+	}
+	if _, ok := raw["secret"]; raw != nil && !ok {
+		return fmt.Errorf("field secret in WebhookRsJson: required")
+	}
+	if _, ok := raw["url"]; raw != nil && !ok {
+		return fmt.Errorf("field url in WebhookRsJson: required")
+	}
+	type Plain WebhookRsJson
+	var plain Plain
+	if err := json.Unmarshal(b, &plain); err != nil {
+		return err
+	}
+	*j = WebhookRsJson(plain)
+	return nil
+}
@@ -0,0 +1,148 @@
+// Package metrics exposes the fleet and queue state, and the latency of the command/query bus, as Prometheus metrics
+package metrics
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"theskyinflames/car-sharing/internal/app"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/theskyinflames/cqrs-eda/pkg/bus"
+	"github.com/theskyinflames/cqrs-eda/pkg/cqrs"
+	"github.com/theskyinflames/cqrs-eda/pkg/events"
+)
+
+const namespace = "car_sharing"
+
+var (
+	seatsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "fleet", "seats"),
+		"Total number of seats in the fleet.",
+		nil, nil,
+	)
+	occupiedSeatsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "fleet", "occupied_seats"),
+		"Number of seats occupied by groups on journey.",
+		nil, nil,
+	)
+	waitingGroupsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "queue", "waiting_groups"),
+		"Number of groups waiting for a car, by number of people.",
+		[]string{"people"}, nil,
+	)
+	carsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(namespace, "fleet", "cars"),
+		"Number of cars, by number of available seats.",
+		[]string{"available_seats"}, nil,
+	)
+)
+
+// FleetCollector is a prometheus.Collector that reads the fleet state, through the fleet status query, on each scrape
+type FleetCollector struct {
+	queryBus bus.Bus
+	log      cqrs.Logger
+}
+
+// NewFleetCollector is a constructor
+func NewFleetCollector(queryBus bus.Bus, log cqrs.Logger) FleetCollector {
+	return FleetCollector{queryBus: queryBus, log: log}
+}
+
+// Describe implements the prometheus.Collector interface
+func (c FleetCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- seatsDesc
+	ch <- occupiedSeatsDesc
+	ch <- waitingGroupsDesc
+	ch <- carsDesc
+}
+
+// Collect implements the prometheus.Collector interface
+func (c FleetCollector) Collect(ch chan<- prometheus.Metric) {
+	rs, err := c.queryBus.Dispatch(context.Background(), app.FleetStatusQuery{})
+	if err != nil {
+		c.log.Printf("metrics, fleet status: %s\n", err.Error())
+		return
+	}
+	status, ok := rs.(app.FleetStatusResponse)
+	if !ok {
+		c.log.Printf("metrics, fleet status: unexpected response %T\n", rs)
+		return
+	}
+
+	var (
+		seats, occupied int
+		byAvailability  = make(map[int]int)
+		byPeople        = make(map[int]int)
+	)
+	for _, car := range status.Cars {
+		seats += car.Capacity().Int()
+		occupied += car.Capacity().Int() - car.Availability()
+		byAvailability[car.Availability()]++
+	}
+	for _, g := range status.WaitingGroups {
+		byPeople[g.People()]++
+	}
+
+	ch <- prometheus.MustNewConstMetric(seatsDesc, prometheus.GaugeValue, float64(seats))
+	ch <- prometheus.MustNewConstMetric(occupiedSeatsDesc, prometheus.GaugeValue, float64(occupied))
+	for available, n := range byAvailability {
+		ch <- prometheus.MustNewConstMetric(carsDesc, prometheus.GaugeValue, float64(n), strconv.Itoa(available))
+	}
+	for people, n := range byPeople {
+		ch <- prometheus.MustNewConstMetric(waitingGroupsDesc, prometheus.GaugeValue, float64(n), strconv.Itoa(people))
+	}
+}
+
+// BusLatency is the histogram of the time spent handling each command and query of the bus
+type BusLatency struct {
+	histogram *prometheus.HistogramVec
+}
+
+// NewBusLatency is a constructor
+func NewBusLatency() BusLatency {
+	return BusLatency{
+		histogram: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "bus",
+			Name:      "handler_duration_seconds",
+			Help:      "Time spent handling the commands and queries, by bus command name.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"command"}),
+	}
+}
+
+// Describe implements the prometheus.Collector interface
+func (bl BusLatency) Describe(ch chan<- *prometheus.Desc) {
+	bl.histogram.Describe(ch)
+}
+
+// Collect implements the prometheus.Collector interface
+func (bl BusLatency) Collect(ch chan<- prometheus.Metric) {
+	bl.histogram.Collect(ch)
+}
+
+// ChMw is a command handler middleware that observes the latency of each command
+func (bl BusLatency) ChMw() cqrs.CommandHandlerMiddleware {
+	return func(ch cqrs.CommandHandler) cqrs.CommandHandler {
+		return cqrs.CommandHandlerFunc(func(ctx context.Context, cmd cqrs.Command) ([]events.Event, error) {
+			defer bl.observe(cmd.Name(), time.Now())
+			return ch.Handle(ctx, cmd)
+		})
+	}
+}
+
+// QhMw is a query handler middleware that observes the latency of each query
+func (bl BusLatency) QhMw() cqrs.QueryHandlerMiddleware {
+	return func(qh cqrs.QueryHandler) cqrs.QueryHandler {
+		return cqrs.QueryHandlerFunc(func(ctx context.Context, q cqrs.Query) (cqrs.QueryResult, error) {
+			defer bl.observe(q.Name(), time.Now())
+			return qh.Handle(ctx, q)
+		})
+	}
+}
+
+func (bl BusLatency) observe(name string, start time.Time) {
+	bl.histogram.WithLabelValues(name).Observe(time.Since(start).Seconds())
+}
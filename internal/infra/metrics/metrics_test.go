@@ -0,0 +1,81 @@
+package metrics_test
+
+import (
+	"context"
+	"io"
+	"log"
+	"strings"
+	"testing"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/domain"
+	"theskyinflames/car-sharing/internal/infra/metrics"
+	"theskyinflames/car-sharing/internal/infra/repository"
+
+	"github.com/google/uuid"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"github.com/theskyinflames/cqrs-eda/pkg/bus"
+)
+
+func TestMetrics(t *testing.T) {
+	var (
+		ctx    = context.Background()
+		logger = log.New(io.Discard, "", 0)
+
+		gr  = repository.NewGroupsRepository()
+		evr = repository.NewCarRepository()
+
+		busLatency = metrics.NewBusLatency()
+		commandBus = app.BuildCommandQueryBus(logger, bus.New(), &gr, &evr, repository.NewUnitOfWork(&gr, &evr), repository.NewJourneysHistoryRepository(), repository.NewWebhooksRepository(), repository.NewReservationsRepository(),
+			app.WithCommandHandlerMiddleware(busLatency.ChMw()),
+			app.WithQueryHandlerMiddleware(busLatency.QhMw()),
+		)
+	)
+
+	_, err := commandBus.Dispatch(ctx, app.InitializeFleetCmd{Cars: []app.Car{
+		{ID: uuid.New(), Seats: domain.CarCapacity4},
+		{ID: uuid.New(), Seats: domain.CarCapacity6},
+	}})
+	require.NoError(t, err)
+	// the group of 3 gets on the 6 seats car, the group of 6 waits, and the group of 2 gets on the 4 seats car
+	for _, people := range []int{3, 6, 2} {
+		_, err := commandBus.Dispatch(ctx, app.JourneyCmd{ID: uuid.New(), People: people})
+		require.NoError(t, err)
+	}
+
+	t.Run(`Given a fleet with groups on journey and waiting groups, when it's collected, then its state is exported`, func(t *testing.T) {
+		expected := `
+# HELP car_sharing_fleet_cars Number of cars, by number of available seats.
+# TYPE car_sharing_fleet_cars gauge
+car_sharing_fleet_cars{available_seats="2"} 1
+car_sharing_fleet_cars{available_seats="3"} 1
+# HELP car_sharing_fleet_occupied_seats Number of seats occupied by groups on journey.
+# TYPE car_sharing_fleet_occupied_seats gauge
+car_sharing_fleet_occupied_seats 5
+# HELP car_sharing_fleet_seats Total number of seats in the fleet.
+# TYPE car_sharing_fleet_seats gauge
+car_sharing_fleet_seats 10
+# HELP car_sharing_queue_waiting_groups Number of groups waiting for a car, by number of people.
+# TYPE car_sharing_queue_waiting_groups gauge
+car_sharing_queue_waiting_groups{people="6"} 1
+`
+		require.NoError(t, testutil.CollectAndCompare(metrics.NewFleetCollector(commandBus, logger), strings.NewReader(expected)))
+	})
+
+	t.Run(`Given some handled commands, when the bus latency is collected, then there is an observation for each one`, func(t *testing.T) {
+		registry := prometheus.NewRegistry()
+		registry.MustRegister(busLatency)
+		families, err := registry.Gather()
+		require.NoError(t, err)
+		require.Len(t, families, 1)
+
+		counts := make(map[string]uint64)
+		for _, m := range families[0].GetMetric() {
+			counts[m.GetLabel()[0].GetValue()] = m.GetHistogram().GetSampleCount()
+		}
+		require.Equal(t, uint64(1), counts[app.InitializeFleetName])
+		require.Equal(t, uint64(3), counts[app.JourneyName])
+	})
+}
@@ -0,0 +1,280 @@
+package eventstore_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/domain"
+	"theskyinflames/car-sharing/internal/fixtures"
+	"theskyinflames/car-sharing/internal/helpers"
+	"theskyinflames/car-sharing/internal/infra/repository"
+	"theskyinflames/car-sharing/internal/infra/repository/eventstore"
+	"theskyinflames/car-sharing/internal/infra/repository/repositorytest"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventStoreRepositories(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) (app.GroupsRepository, app.CarsRepository, app.UnitOfWork) {
+		s, err := eventstore.Open(filepath.Join(t.TempDir(), "car-sharing.events"))
+		require.NoError(t, err)
+		t.Cleanup(func() { s.Close() })
+		return eventstore.NewGroupsRepository(s), eventstore.NewCarRepository(s), eventstore.NewUnitOfWork(s)
+	})
+}
+
+func TestReplay(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run(`Given a store with cars and groups, when it's opened again,
+		then their state is rebuilt by replaying the events`, func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "car-sharing.events")
+		s, err := eventstore.Open(path)
+		require.NoError(t, err)
+		var (
+			gr, cr, uow = eventstore.NewGroupsRepository(s), eventstore.NewCarRepository(s), eventstore.NewUnitOfWork(s)
+
+			car        = fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity4)}.Build()
+			onJourney  = fixtures.Group{People: helpers.IntPtr(3)}.Build()
+			waiting    = fixtures.Group{People: helpers.IntPtr(2)}.Build()
+			droppedOff = fixtures.Group{People: helpers.IntPtr(1)}.Build()
+		)
+		require.NoError(t, cr.AddAll(ctx, []domain.Car{car}))
+		for _, g := range []domain.Group{onJourney, waiting, droppedOff} {
+			require.NoError(t, gr.Add(ctx, g))
+		}
+		require.NoError(t, uow.Do(ctx, func(ctx context.Context) error {
+			if err := car.GetOn(onJourney); err != nil {
+				return err
+			}
+			onJourney.GetOn(&car)
+			waiting.Overtake()
+			car.Reserve(waiting)
+			if err := cr.Update(ctx, car); err != nil {
+				return err
+			}
+			if err := gr.Update(ctx, waiting); err != nil {
+				return err
+			}
+			if err := gr.Update(ctx, onJourney); err != nil {
+				return err
+			}
+			return gr.RemoveByID(ctx, droppedOff.ID())
+		}))
+		require.NoError(t, s.Close())
+
+		s, err = eventstore.Open(path)
+		require.NoError(t, err)
+		defer s.Close()
+		gr, cr = eventstore.NewGroupsRepository(s), eventstore.NewCarRepository(s)
+
+		found, err := cr.FindByID(ctx, car.ID())
+		require.NoError(t, err)
+		require.Equal(t, 1, found.Availability())
+		require.Contains(t, found.Journeys(), onJourney.ID())
+		require.Equal(t, waiting.ID(), found.ReservedFor())
+		require.Equal(t, car.Version()+1, found.Version())
+
+		foundGroup, err := gr.FindByID(ctx, onJourney.ID())
+		require.NoError(t, err)
+		require.Equal(t, car.ID(), foundGroup.Car().ID())
+		require.True(t, onJourney.BoardedAt().Equal(foundGroup.BoardedAt()))
+
+		wg, err := gr.FindGroupsWithoutCar(ctx)
+		require.NoError(t, err)
+		require.Len(t, wg, 1)
+		require.Equal(t, waiting.ID(), wg[0].ID())
+		require.Equal(t, 1, wg[0].Overtaken())
+
+		_, err = gr.FindByID(ctx, droppedOff.ID())
+		require.ErrorIs(t, err, repository.ErrNotFound)
+	})
+
+	t.Run(`Given a store whose last line was not completely written, when it's opened,
+		then the line is discarded`, func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "car-sharing.events")
+		s, err := eventstore.Open(path)
+		require.NoError(t, err)
+		g := fixtures.Group{}.Build()
+		require.NoError(t, eventstore.NewGroupsRepository(s).Add(ctx, g))
+		require.NoError(t, s.Close())
+
+		f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+		require.NoError(t, err)
+		_, err = f.WriteString(`[{"seq":2,"aggregate_id":`)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		s, err = eventstore.Open(path)
+		require.NoError(t, err)
+		defer s.Close()
+		gr := eventstore.NewGroupsRepository(s)
+		_, err = gr.FindByID(ctx, g.ID())
+		require.NoError(t, err)
+
+		other := fixtures.Group{}.Build()
+		require.NoError(t, gr.Add(ctx, other))
+		history, err := s.History(other.ID())
+		require.NoError(t, err)
+		require.Len(t, history, 1)
+		require.Equal(t, uint64(2), history[0].Seq)
+	})
+
+	t.Run(`Given a store with a corrupted line, when it's opened, then an error is returned`, func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "car-sharing.events")
+		require.NoError(t, os.WriteFile(path, []byte("not an event\n"), 0o644))
+
+		_, err := eventstore.Open(path)
+		require.ErrorIs(t, err, eventstore.ErrCorrupted)
+	})
+}
+
+func TestHistory(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run(`Given a group that gets on a car, when its history is asked for,
+		then all its events are returned in order`, func(t *testing.T) {
+		s, err := eventstore.Open(filepath.Join(t.TempDir(), "car-sharing.events"))
+		require.NoError(t, err)
+		defer s.Close()
+		var (
+			gr, cr = eventstore.NewGroupsRepository(s), eventstore.NewCarRepository(s)
+			car    = fixtures.Car{}.Build()
+			g      = fixtures.Group{}.Build()
+		)
+		require.NoError(t, cr.AddAll(ctx, []domain.Car{car}))
+		require.NoError(t, gr.Add(ctx, g))
+		g.GetOn(&car)
+		require.NoError(t, gr.Update(ctx, g))
+		require.NoError(t, gr.RemoveByID(ctx, g.ID()))
+
+		history, err := s.History(g.ID())
+		require.NoError(t, err)
+		names := make([]string, 0, len(history))
+		for _, e := range history {
+			names = append(names, e.Name)
+		}
+		require.Equal(t, []string{"group.added", domain.GroupSetOnJourneyEventName, "group.removed"}, names)
+		require.Equal(t, []int{0, 1, 2}, []int{history[0].Version, history[1].Version, history[2].Version})
+	})
+
+	t.Run(`Given a unit of work that fails, when the history is asked for, then its events are not there`, func(t *testing.T) {
+		s, err := eventstore.Open(filepath.Join(t.TempDir(), "car-sharing.events"))
+		require.NoError(t, err)
+		defer s.Close()
+		var (
+			gr, uow   = eventstore.NewGroupsRepository(s), eventstore.NewUnitOfWork(s)
+			g         = fixtures.Group{}.Build()
+			randomErr = errors.New("")
+		)
+		err = uow.Do(ctx, func(ctx context.Context) error {
+			if err := gr.Add(ctx, g); err != nil {
+				return err
+			}
+			return randomErr
+		})
+		require.ErrorIs(t, err, randomErr)
+
+		history, err := s.History(g.ID())
+		require.NoError(t, err)
+		require.Empty(t, history)
+	})
+}
+
+func TestEventStoreOutbox(t *testing.T) {
+	repositorytest.RunOutbox(t, func(t *testing.T) (app.Outbox, app.UnitOfWork) {
+		s, err := eventstore.Open(filepath.Join(t.TempDir(), "car-sharing.events"))
+		require.NoError(t, err)
+		t.Cleanup(func() { s.Close() })
+		return eventstore.NewOutbox(s), eventstore.NewUnitOfWork(s)
+	})
+}
+
+func TestEventStoreJourneysHistoryRepository(t *testing.T) {
+	repositorytest.RunJourneysHistory(t, func(t *testing.T) app.JourneysHistoryRepository {
+		s, err := eventstore.Open(filepath.Join(t.TempDir(), "car-sharing.events"))
+		require.NoError(t, err)
+		t.Cleanup(func() { s.Close() })
+		return eventstore.NewJourneysHistoryRepository(s)
+	})
+
+	t.Run(`Given a saved journey record, when the store is opened again, then it's rebuilt by replaying the events`, func(t *testing.T) {
+		var (
+			ctx  = context.Background()
+			path = filepath.Join(t.TempDir(), "car-sharing.events")
+			now  = time.Now()
+			r    = app.JourneyRecord{
+				GroupID:      uuid.New(),
+				People:       3,
+				RequestedAt:  now.Add(-time.Minute),
+				BoardedAt:    now,
+				DroppedOffAt: now.Add(time.Minute),
+			}
+		)
+		s, err := eventstore.Open(path)
+		require.NoError(t, err)
+		require.NoError(t, eventstore.NewJourneysHistoryRepository(s).Save(ctx, r))
+		require.NoError(t, s.Close())
+
+		s, err = eventstore.Open(path)
+		require.NoError(t, err)
+		t.Cleanup(func() { s.Close() })
+		found, err := eventstore.NewJourneysHistoryRepository(s).FindByGroupID(ctx, r.GroupID)
+		require.NoError(t, err)
+		require.Equal(t, r.People, found.People)
+		require.True(t, r.BoardedAt.Equal(found.BoardedAt))
+		require.True(t, r.DroppedOffAt.Equal(found.DroppedOffAt))
+	})
+}
+
+func TestEventStoreReservationsRepository(t *testing.T) {
+	repositorytest.RunReservations(t, func(t *testing.T) (app.ReservationsRepository, app.UnitOfWork) {
+		s, err := eventstore.Open(filepath.Join(t.TempDir(), "car-sharing.events"))
+		require.NoError(t, err)
+		t.Cleanup(func() { s.Close() })
+		return eventstore.NewReservationsRepository(s), eventstore.NewUnitOfWork(s)
+	})
+}
+
+func TestEventStoreWebhooksRepository(t *testing.T) {
+	repositorytest.RunWebhooks(t, func(t *testing.T) app.WebhooksRepository {
+		s, err := eventstore.Open(filepath.Join(t.TempDir(), "car-sharing.events"))
+		require.NoError(t, err)
+		t.Cleanup(func() { s.Close() })
+		return eventstore.NewWebhooksRepository(s)
+	})
+
+	t.Run(`Given a webhook and a dead letter, when the store is opened again, then they're rebuilt by replaying the events`, func(t *testing.T) {
+		var (
+			ctx  = context.Background()
+			path = filepath.Join(t.TempDir(), "car-sharing.events")
+			wh   = app.Webhook{ID: uuid.New(), URL: "http://localhost/hook", Events: []string{domain.GroupSetOnJourneyEventName}}
+			dl   = app.DeadLetter{WebhookID: wh.ID, URL: wh.URL, Event: domain.GroupSetOnJourneyEventName, Payload: []byte(`{}`), Attempts: 3, Err: "timeout", FailedAt: time.Now()}
+		)
+		s, err := eventstore.Open(path)
+		require.NoError(t, err)
+		wr := eventstore.NewWebhooksRepository(s)
+		require.NoError(t, wr.Add(ctx, wh))
+		require.NoError(t, wr.AddDeadLetter(ctx, dl))
+		require.NoError(t, s.Close())
+
+		s, err = eventstore.Open(path)
+		require.NoError(t, err)
+		t.Cleanup(func() { s.Close() })
+		wr = eventstore.NewWebhooksRepository(s)
+		whs, err := wr.FindByEvent(ctx, domain.GroupSetOnJourneyEventName)
+		require.NoError(t, err)
+		require.Equal(t, []app.Webhook{wh}, whs)
+		dls, err := wr.FindDeadLetters(ctx)
+		require.NoError(t, err)
+		require.Len(t, dls, 1)
+		require.Equal(t, dl.Err, dls[0].Err)
+		require.True(t, dl.FailedAt.Equal(dls[0].FailedAt))
+	})
+}
@@ -0,0 +1,71 @@
+package eventstore
+
+import (
+	"context"
+	"time"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/infra/repository"
+
+	"github.com/google/uuid"
+)
+
+// JourneysHistoryRepository is a repository. Each record saved is an event, so the history is rebuilt on replay
+type JourneysHistoryRepository struct {
+	s *Store
+}
+
+// NewJourneysHistoryRepository is a constructor
+func NewJourneysHistoryRepository(s *Store) JourneysHistoryRepository {
+	return JourneysHistoryRepository{s: s}
+}
+
+// Save adds the record, or replaces it if there is already one for the group
+func (hr JourneysHistoryRepository) Save(ctx context.Context, r app.JourneyRecord) error {
+	return hr.s.change(ctx, func(_ *state) ([]Event, error) {
+		return []Event{newEvent(r.GroupID, 0, journeyRecordedEvent, journeyRecordedBody{
+			People:       r.People,
+			RequestedAt:  r.RequestedAt,
+			BoardedAt:    r.BoardedAt,
+			DroppedOffAt: r.DroppedOffAt,
+			AbandonedAt:  r.AbandonedAt,
+		})}, nil
+	})
+}
+
+// FindByGroupID is a finder
+func (hr JourneysHistoryRepository) FindByGroupID(_ context.Context, groupID uuid.UUID) (app.JourneyRecord, error) {
+	hr.s.mux.RLock()
+	defer hr.s.mux.RUnlock()
+
+	b, ok := hr.s.state.journeys[groupID]
+	if !ok {
+		return app.JourneyRecord{}, repository.ErrNotFound
+	}
+	return b.record(groupID), nil
+}
+
+// FindWaitEndedSince returns the records of the groups that got on a car, or left without it, from the given time on
+func (hr JourneysHistoryRepository) FindWaitEndedSince(_ context.Context, since time.Time) ([]app.JourneyRecord, error) {
+	hr.s.mux.RLock()
+	defer hr.s.mux.RUnlock()
+
+	var records []app.JourneyRecord
+	for id, b := range hr.s.state.journeys {
+		if r := b.record(id); !r.WaitEndedAt().Before(since) {
+			records = append(records, r)
+		}
+	}
+	return records, nil
+}
+
+func (b journeyRecordedBody) record(groupID uuid.UUID) app.JourneyRecord {
+	return app.JourneyRecord{
+		GroupID:      groupID,
+		People:       b.People,
+		RequestedAt:  b.RequestedAt,
+		BoardedAt:    b.BoardedAt,
+		DroppedOffAt: b.DroppedOffAt,
+		AbandonedAt:  b.AbandonedAt,
+	}
+}
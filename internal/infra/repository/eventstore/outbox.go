@@ -0,0 +1,72 @@
+package eventstore
+
+import (
+	"context"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/theskyinflames/cqrs-eda/pkg/events"
+)
+
+// Outbox implements the app.Outbox interface. The messages are events of the store too,
+// so they are appended in the same line than the changes of their unit of work
+type Outbox struct {
+	s *Store
+}
+
+// NewOutbox is a constructor
+func NewOutbox(s *Store) Outbox {
+	return Outbox{s: s}
+}
+
+// Add is self-described
+func (ob Outbox) Add(ctx context.Context, evs []events.Event) error {
+	return ob.s.change(ctx, func(_ *state) ([]Event, error) {
+		added := make([]Event, 0, len(evs))
+		for _, e := range evs {
+			body, err := domain.EventBody(e)
+			if err != nil {
+				return nil, err
+			}
+			added = append(added, newEvent(uuid.New(), 0, outboxMessageAddedEvent, outboxMessageBody{
+				Name:        e.Name(),
+				AggregateID: e.AggregateID(),
+				Body:        body,
+			}))
+		}
+		return added, nil
+	})
+}
+
+// Pending is self-described
+func (ob Outbox) Pending(_ context.Context, limit int) ([]app.OutboxMessage, error) {
+	ob.s.mux.RLock()
+	defer ob.s.mux.RUnlock()
+
+	if limit > len(ob.s.state.outbox) {
+		limit = len(ob.s.state.outbox)
+	}
+	msgs := make([]app.OutboxMessage, 0, limit)
+	for _, m := range ob.s.state.outbox[:limit] {
+		e, err := domain.ParseEvent(m.Name, m.AggregateID, m.Body)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, app.OutboxMessage{ID: m.id, Event: e})
+	}
+	return msgs, nil
+}
+
+// MarkDelivered removes the message from the outbox. It does nothing if the message is not there
+func (ob Outbox) MarkDelivered(ctx context.Context, id uuid.UUID) error {
+	return ob.s.change(ctx, func(st *state) ([]Event, error) {
+		for _, m := range st.outbox {
+			if m.id == id {
+				return []Event{newEvent(id, 1, outboxMessageDeliveredEvent, nil)}, nil
+			}
+		}
+		return nil, nil
+	})
+}
@@ -0,0 +1,232 @@
+package eventstore
+
+import (
+	"context"
+	"sort"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/domain"
+	"theskyinflames/car-sharing/internal/infra/repository"
+
+	"github.com/google/uuid"
+)
+
+// CarRepository is a repository
+type CarRepository struct {
+	s *Store
+}
+
+// NewCarRepository is a constructor
+func NewCarRepository(s *Store) CarRepository {
+	return CarRepository{s: s}
+}
+
+// RemoveAll is self-described
+func (cr CarRepository) RemoveAll(ctx context.Context) error {
+	return cr.s.change(ctx, func(st *state) ([]Event, error) {
+		evs := make([]Event, 0, len(st.carIDs))
+		for _, id := range st.carIDs {
+			evs = append(evs, newEvent(id, st.cars[id].version+1, carRemovedEvent, nil))
+		}
+		return evs, nil
+	})
+}
+
+// AddAll is self-described
+func (cr CarRepository) AddAll(ctx context.Context, cars []domain.Car) error {
+	return cr.s.change(ctx, func(st *state) ([]Event, error) {
+		var (
+			evs   []Event
+			added = make(map[uuid.UUID]struct{}, len(cars))
+		)
+		for _, car := range cars {
+			if _, ok := st.cars[car.ID()]; ok {
+				return nil, repository.ErrPKConflict
+			}
+			if _, ok := added[car.ID()]; ok {
+				return nil, repository.ErrPKConflict
+			}
+			added[car.ID()] = struct{}{}
+
+			evs = append(evs, newEvent(car.ID(), car.Version(), carAddedEvent, carAddedBody{
+				Seats:    car.Capacity().Int(),
+				Site:     car.Site(),
+				Features: car.Features(),
+			}))
+			evs = append(evs, carChanges(carState{}, car, car.Version())...)
+		}
+		return evs, nil
+	})
+}
+
+// Update is self-described. It fails if the car has been updated since it was read
+func (cr CarRepository) Update(ctx context.Context, car domain.Car) error {
+	return cr.s.change(ctx, func(st *state) ([]Event, error) {
+		stored, ok := st.cars[car.ID()]
+		if !ok {
+			return nil, repository.ErrNotFound
+		}
+		if stored.version != car.Version() {
+			return nil, app.ErrVersionConflict
+		}
+		evs := carChanges(stored, car, car.Version()+1)
+		if len(evs) == 0 { // nothing is recorded, but the copies read before are not valid anymore
+			stored.version++
+			st.cars[car.ID()] = stored
+		}
+		return evs, nil
+	})
+}
+
+// FindAll returns the cars in the order they were added
+func (cr CarRepository) FindAll(_ context.Context) ([]domain.Car, error) {
+	cr.s.mux.RLock()
+	defer cr.s.mux.RUnlock()
+
+	cars := make([]domain.Car, 0, len(cr.s.state.carIDs))
+	for _, id := range cr.s.state.carIDs {
+		car, _ := cr.s.state.car(id)
+		cars = append(cars, car)
+	}
+	return cars, nil
+}
+
+// FindByID is a finder
+func (cr CarRepository) FindByID(_ context.Context, id uuid.UUID) (domain.Car, error) {
+	cr.s.mux.RLock()
+	defer cr.s.mux.RUnlock()
+
+	car, ok := cr.s.state.car(id)
+	if !ok {
+		return domain.Car{}, repository.ErrNotFound
+	}
+	return car, nil
+}
+
+// RemoveByID is self-described
+func (cr CarRepository) RemoveByID(ctx context.Context, id uuid.UUID) error {
+	return cr.s.change(ctx, func(st *state) ([]Event, error) {
+		stored, ok := st.cars[id]
+		if !ok {
+			return nil, repository.ErrNotFound
+		}
+		return []Event{newEvent(id, stored.version+1, carRemovedEvent, nil)}, nil
+	})
+}
+
+// GroupsRepository is a repository
+type GroupsRepository struct {
+	s *Store
+}
+
+// NewGroupsRepository is a constructor
+func NewGroupsRepository(s *Store) GroupsRepository {
+	return GroupsRepository{s: s}
+}
+
+// RemoveAll is self-described
+func (gr GroupsRepository) RemoveAll(ctx context.Context) error {
+	return gr.s.change(ctx, func(st *state) ([]Event, error) {
+		evs := make([]Event, 0, len(st.groups))
+		for _, id := range st.groupIDs() {
+			evs = append(evs, newEvent(id, st.groups[id].version+1, groupRemovedEvent, nil))
+		}
+		return evs, nil
+	})
+}
+
+// Add is self-described
+func (gr GroupsRepository) Add(ctx context.Context, g domain.Group) error {
+	return gr.s.change(ctx, func(st *state) ([]Event, error) {
+		if _, ok := st.groups[g.ID()]; ok {
+			return nil, repository.ErrPKConflict
+		}
+		evs := []Event{newEvent(g.ID(), g.Version(), groupAddedEvent, groupAddedBody{
+			People:       g.People(),
+			RequestedAt:  g.RequestedAt(),
+			Site:         g.Site(),
+			Destination:  g.Destination(),
+			Members:      g.Members(),
+			Requirements: g.Requirements(),
+			Pickup:       newLocationBody(g.Pickup()),
+		})}
+		return append(evs, groupChanges(groupState{}, g, g.Version())...), nil
+	})
+}
+
+// Update is self-described. It fails if the group has been updated since it was read
+func (gr GroupsRepository) Update(ctx context.Context, g domain.Group) error {
+	return gr.s.change(ctx, func(st *state) ([]Event, error) {
+		stored, ok := st.groups[g.ID()]
+		if !ok {
+			return nil, repository.ErrNotFound
+		}
+		if stored.version != g.Version() {
+			return nil, app.ErrVersionConflict
+		}
+		evs := groupChanges(stored, g, g.Version()+1)
+		if len(evs) == 0 { // nothing is recorded, but the copies read before are not valid anymore
+			stored.version++
+			st.groups[g.ID()] = stored
+		}
+		return evs, nil
+	})
+}
+
+// FindGroupsWithoutCar is a finder. The groups are returned in arrival order
+func (gr GroupsRepository) FindGroupsWithoutCar(_ context.Context) ([]domain.Group, error) {
+	gr.s.mux.RLock()
+	defer gr.s.mux.RUnlock()
+
+	var withoutCar []domain.Group
+	for _, id := range gr.s.state.groupIDs() {
+		gs := gr.s.state.groups[id]
+		if gs.carID == uuid.Nil {
+			withoutCar = append(withoutCar, gs.group(id, nil))
+		}
+	}
+	return withoutCar, nil
+}
+
+// FindByID is a finder
+func (gr GroupsRepository) FindByID(_ context.Context, id uuid.UUID) (domain.Group, error) {
+	gr.s.mux.RLock()
+	defer gr.s.mux.RUnlock()
+
+	gs, ok := gr.s.state.groups[id]
+	if !ok {
+		return domain.Group{}, repository.ErrNotFound
+	}
+	var car *domain.Car
+	if c, ok := gr.s.state.car(gs.carID); ok {
+		car = &c
+	}
+	return gs.group(id, car), nil
+}
+
+// RemoveByID is self-described
+func (gr GroupsRepository) RemoveByID(ctx context.Context, id uuid.UUID) error {
+	return gr.s.change(ctx, func(st *state) ([]Event, error) {
+		gs, ok := st.groups[id]
+		if !ok {
+			return nil, nil
+		}
+		return []Event{newEvent(id, gs.version+1, groupRemovedEvent, nil)}, nil
+	})
+}
+
+// groupIDs returns the IDs of the groups in arrival order
+func (st state) groupIDs() []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(st.groups))
+	for id := range st.groups {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool {
+		gi, gj := st.groups[ids[i]], st.groups[ids[j]]
+		if !gi.requestedAt.Equal(gj.requestedAt) {
+			return gi.requestedAt.Before(gj.requestedAt)
+		}
+		return gi.arrival < gj.arrival
+	})
+	return ids
+}
@@ -0,0 +1,81 @@
+package eventstore
+
+import (
+	"context"
+	"sort"
+
+	"theskyinflames/car-sharing/internal/domain"
+	"theskyinflames/car-sharing/internal/infra/repository"
+
+	"github.com/google/uuid"
+)
+
+// ReservationsRepository is a repository. A reservation is never updated, it's only added, and removed once it starts
+type ReservationsRepository struct {
+	s *Store
+}
+
+// NewReservationsRepository is a constructor
+func NewReservationsRepository(s *Store) ReservationsRepository {
+	return ReservationsRepository{s: s}
+}
+
+// Add is self-described
+func (rr ReservationsRepository) Add(ctx context.Context, r domain.Reservation) error {
+	return rr.s.change(ctx, func(st *state) ([]Event, error) {
+		if _, ok := st.reservations[r.ID()]; ok {
+			return nil, repository.ErrPKConflict
+		}
+		return []Event{newEvent(r.ID(), 1, reservationAddedEvent, reservationAddedBody{
+			People:       r.People(),
+			Site:         r.Site(),
+			Requirements: r.Requirements(),
+			Car:          r.CarID(),
+			StartAt:      r.StartAt(),
+			EndAt:        r.EndAt(),
+		})}, nil
+	})
+}
+
+// FindAll returns the reservations sorted by their start
+func (rr ReservationsRepository) FindAll(_ context.Context) ([]domain.Reservation, error) {
+	rr.s.mux.RLock()
+	defer rr.s.mux.RUnlock()
+
+	rs := make([]domain.Reservation, 0, len(rr.s.state.reservations))
+	for id, b := range rr.s.state.reservations {
+		rs = append(rs, b.reservation(id))
+	}
+	sort.Slice(rs, func(i, j int) bool {
+		return rs[i].StartAt().Before(rs[j].StartAt())
+	})
+	return rs, nil
+}
+
+// FindByID is a finder
+func (rr ReservationsRepository) FindByID(_ context.Context, id uuid.UUID) (domain.Reservation, error) {
+	rr.s.mux.RLock()
+	defer rr.s.mux.RUnlock()
+
+	b, ok := rr.s.state.reservations[id]
+	if !ok {
+		return domain.Reservation{}, repository.ErrNotFound
+	}
+	return b.reservation(id), nil
+}
+
+// RemoveByID is self-described
+func (rr ReservationsRepository) RemoveByID(ctx context.Context, id uuid.UUID) error {
+	return rr.s.change(ctx, func(st *state) ([]Event, error) {
+		if _, ok := st.reservations[id]; !ok {
+			return nil, repository.ErrNotFound
+		}
+		return []Event{newEvent(id, 2, reservationRemovedEvent, nil)}, nil
+	})
+}
+
+func (b reservationAddedBody) reservation(id uuid.UUID) domain.Reservation {
+	var r domain.Reservation
+	r.Hydrate(id, b.People, b.Site, b.Requirements, b.Car, b.StartAt, b.EndAt)
+	return r
+}
@@ -0,0 +1,560 @@
+package eventstore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"theskyinflames/car-sharing/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// Stored events. Those with the same meaning than a domain event share its name
+const (
+	carAddedEvent               = domain.CarCreatedEventName
+	carRemovedEvent             = "car.removed"
+	carGroupGotOnEvent          = "car.group.got.on"
+	carGroupGotOffEvent         = "car.group.got.off"
+	carReservedEvent            = domain.CarReservedEventName
+	carReservationReleasedEvent = "car.reservation.released"
+	carUpdatedEvent             = domain.CarUpdatedEventName
+	carRetiringEvent            = domain.CarRetiringEventName
+	carStatusChangedEvent       = "car.status.changed"
+	carPositionReportedEvent    = domain.CarPositionReportedEventName
+
+	groupAddedEvent      = "group.added"
+	groupOnJourneyEvent  = domain.GroupSetOnJourneyEventName
+	groupDroppedOffEvent = domain.GroupDroppedOffEventName
+	groupOvertakenEvent  = "group.overtaken"
+	groupRemovedEvent    = "group.removed"
+
+	reservationAddedEvent   = domain.ReservationScheduledEventName
+	reservationRemovedEvent = "reservation.removed"
+
+	journeyRecordedEvent = "journey.recorded"
+
+	webhookAddedEvent      = "webhook.added"
+	webhookDeadLetterEvent = "webhook.dead.lettered"
+
+	outboxMessageAddedEvent     = "outbox.message.added"
+	outboxMessageDeliveredEvent = "outbox.message.delivered"
+)
+
+type (
+	carAddedBody struct {
+		Seats    int             `json:"seats"`
+		Site     domain.Site     `json:"site,omitempty"`
+		Features domain.Features `json:"features,omitempty"`
+	}
+	carStatusBody struct {
+		Status domain.CarStatus `json:"status"`
+	}
+	locationBody struct {
+		Lat float64 `json:"lat"`
+		Lng float64 `json:"lng"`
+	}
+	carGroupBody struct {
+		Group  uuid.UUID `json:"group"`
+		People int       `json:"people,omitempty"`
+	}
+	groupAddedBody struct {
+		People       int             `json:"people"`
+		RequestedAt  time.Time       `json:"requested_at"`
+		Site         domain.Site     `json:"site,omitempty"`
+		Destination  domain.Site     `json:"destination,omitempty"`
+		Members      []string        `json:"members,omitempty"`
+		Requirements domain.Features `json:"requirements,omitempty"`
+		Pickup       *locationBody   `json:"pickup,omitempty"`
+	}
+	groupOnJourneyBody struct {
+		Car       uuid.UUID `json:"car"`
+		BoardedAt time.Time `json:"boarded_at"`
+	}
+	groupOvertakenBody struct {
+		Overtaken int `json:"overtaken"`
+	}
+	reservationAddedBody struct {
+		People       int             `json:"people"`
+		Site         domain.Site     `json:"site,omitempty"`
+		Requirements domain.Features `json:"requirements,omitempty"`
+		Car          uuid.UUID       `json:"car"`
+		StartAt      time.Time       `json:"start_at"`
+		EndAt        time.Time       `json:"end_at"`
+	}
+	journeyRecordedBody struct {
+		People       int       `json:"people"`
+		RequestedAt  time.Time `json:"requested_at"`
+		BoardedAt    time.Time `json:"boarded_at"`
+		DroppedOffAt time.Time `json:"dropped_off_at"`
+		AbandonedAt  time.Time `json:"abandoned_at"`
+	}
+	webhookAddedBody struct {
+		URL    string   `json:"url"`
+		Events []string `json:"events"`
+		Secret string   `json:"secret,omitempty"`
+	}
+	webhookDeadLetterBody struct {
+		Webhook  uuid.UUID `json:"webhook"`
+		URL      string    `json:"url"`
+		Event    string    `json:"event"`
+		Payload  []byte    `json:"payload"`
+		Attempts int       `json:"attempts"`
+		Err      string    `json:"error"`
+		FailedAt time.Time `json:"failed_at"`
+	}
+	outboxMessageBody struct {
+		Name        string          `json:"name"`
+		AggregateID uuid.UUID       `json:"aggregate_id"`
+		Body        json.RawMessage `json:"body"`
+	}
+)
+
+func newEvent(aggregateID uuid.UUID, version int, name string, body interface{}) Event {
+	e := Event{AggregateID: aggregateID, Version: version, Name: name, At: time.Now()}
+	if body != nil {
+		e.Body, _ = json.Marshal(body)
+	}
+	return e
+}
+
+type carState struct {
+	capacity domain.CarCapacity
+	// journeys has the people of each group on journey
+	journeys    map[uuid.UUID]int
+	reservedFor uuid.UUID
+	retiring    bool
+	status      domain.CarStatus
+	site        domain.Site
+	features    domain.Features
+	position    *domain.Location
+	version     int
+}
+
+type groupState struct {
+	people       int
+	carID        uuid.UUID
+	requestedAt  time.Time
+	boardedAt    time.Time
+	overtaken    int
+	site         domain.Site
+	destination  domain.Site
+	members      []string
+	requirements domain.Features
+	pickup       *domain.Location
+	version      int
+
+	// arrival is the insertion sequence of the group. It breaks ties between groups with the same request time
+	arrival uint64
+}
+
+// state is the current state of the aggregates, the result of applying all the events
+type state struct {
+	cars   map[uuid.UUID]carState
+	carIDs []uuid.UUID
+	groups map[uuid.UUID]groupState
+	seq    uint64
+
+	// reservations are the bodies of the reservations that have not started yet
+	reservations map[uuid.UUID]reservationAddedBody
+
+	// journeys is the journeys history, by group
+	journeys map[uuid.UUID]journeyRecordedBody
+
+	// webhooks and deadLetters are kept in the order they were added
+	webhooks    []webhookEntry
+	deadLetters []webhookDeadLetterBody
+
+	// outbox has the domain events not delivered yet, in order
+	outbox []outboxEntry
+}
+
+type webhookEntry struct {
+	id uuid.UUID
+	webhookAddedBody
+}
+
+type outboxEntry struct {
+	id uuid.UUID
+	outboxMessageBody
+}
+
+func newState() state {
+	return state{
+		cars:         make(map[uuid.UUID]carState),
+		groups:       make(map[uuid.UUID]groupState),
+		reservations: make(map[uuid.UUID]reservationAddedBody),
+		journeys:     make(map[uuid.UUID]journeyRecordedBody),
+	}
+}
+
+// clone returns a copy of the state that doesn't share the journeys of its cars
+func (st state) clone() state {
+	c := state{
+		cars:   make(map[uuid.UUID]carState, len(st.cars)),
+		carIDs: append([]uuid.UUID{}, st.carIDs...),
+		groups: make(map[uuid.UUID]groupState, len(st.groups)),
+		seq:    st.seq,
+		outbox: append([]outboxEntry{}, st.outbox...),
+
+		reservations: make(map[uuid.UUID]reservationAddedBody, len(st.reservations)),
+		journeys:     make(map[uuid.UUID]journeyRecordedBody, len(st.journeys)),
+		webhooks:     append([]webhookEntry{}, st.webhooks...),
+		deadLetters:  append([]webhookDeadLetterBody{}, st.deadLetters...),
+	}
+	for id, cs := range st.cars {
+		journeys := make(map[uuid.UUID]int, len(cs.journeys))
+		for gID, people := range cs.journeys {
+			journeys[gID] = people
+		}
+		cs.journeys = journeys
+		c.cars[id] = cs
+	}
+	for id, gs := range st.groups {
+		c.groups[id] = gs
+	}
+	for id, r := range st.reservations {
+		c.reservations[id] = r
+	}
+	for id, j := range st.journeys {
+		c.journeys[id] = j
+	}
+	return c
+}
+
+var (
+	errUnknownEvent     = errors.New("unknown event")
+	errUnknownAggregate = errors.New("unknown aggregate")
+)
+
+// apply changes the state with the event
+func (st *state) apply(e Event) error {
+	switch e.Name {
+	case carAddedEvent:
+		var b carAddedBody
+		if err := json.Unmarshal(e.Body, &b); err != nil {
+			return err
+		}
+		st.cars[e.AggregateID] = carState{
+			capacity: domain.CarCapacity(b.Seats),
+			journeys: make(map[uuid.UUID]int),
+			status:   domain.CarAvailable,
+			site:     b.Site,
+			features: b.Features,
+			version:  e.Version,
+		}
+		st.carIDs = append(st.carIDs, e.AggregateID)
+		return nil
+	case carRemovedEvent:
+		delete(st.cars, e.AggregateID)
+		for i, id := range st.carIDs {
+			if id == e.AggregateID {
+				st.carIDs = append(st.carIDs[:i:i], st.carIDs[i+1:]...)
+				break
+			}
+		}
+		return nil
+	case carGroupGotOnEvent, carGroupGotOffEvent, carReservedEvent, carReservationReleasedEvent, carUpdatedEvent, carRetiringEvent, carStatusChangedEvent,
+		carPositionReportedEvent:
+		return st.applyToCar(e)
+	case groupAddedEvent:
+		var b groupAddedBody
+		if err := json.Unmarshal(e.Body, &b); err != nil {
+			return err
+		}
+		st.seq++
+		st.groups[e.AggregateID] = groupState{
+			people:       b.People,
+			requestedAt:  b.RequestedAt,
+			site:         b.Site,
+			destination:  b.Destination,
+			members:      b.Members,
+			requirements: b.Requirements,
+			pickup:       b.Pickup.location(),
+			version:      e.Version,
+			arrival:      st.seq,
+		}
+		return nil
+	case groupRemovedEvent:
+		delete(st.groups, e.AggregateID)
+		return nil
+	case groupOnJourneyEvent, groupDroppedOffEvent, groupOvertakenEvent:
+		return st.applyToGroup(e)
+	case reservationAddedEvent:
+		var b reservationAddedBody
+		if err := json.Unmarshal(e.Body, &b); err != nil {
+			return err
+		}
+		st.reservations[e.AggregateID] = b
+		return nil
+	case reservationRemovedEvent:
+		delete(st.reservations, e.AggregateID)
+		return nil
+	case journeyRecordedEvent:
+		var b journeyRecordedBody
+		if err := json.Unmarshal(e.Body, &b); err != nil {
+			return err
+		}
+		st.journeys[e.AggregateID] = b
+		return nil
+	case webhookAddedEvent:
+		var b webhookAddedBody
+		if err := json.Unmarshal(e.Body, &b); err != nil {
+			return err
+		}
+		st.webhooks = append(st.webhooks, webhookEntry{id: e.AggregateID, webhookAddedBody: b})
+		return nil
+	case webhookDeadLetterEvent:
+		var b webhookDeadLetterBody
+		if err := json.Unmarshal(e.Body, &b); err != nil {
+			return err
+		}
+		st.deadLetters = append(st.deadLetters, b)
+		return nil
+	case outboxMessageAddedEvent:
+		var b outboxMessageBody
+		if err := json.Unmarshal(e.Body, &b); err != nil {
+			return err
+		}
+		st.outbox = append(st.outbox, outboxEntry{id: e.AggregateID, outboxMessageBody: b})
+		return nil
+	case outboxMessageDeliveredEvent:
+		for i, m := range st.outbox {
+			if m.id == e.AggregateID {
+				st.outbox = append(st.outbox[:i:i], st.outbox[i+1:]...)
+				break
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("%w: %s", errUnknownEvent, e.Name)
+	}
+}
+
+func (st *state) applyToCar(e Event) error {
+	cs, ok := st.cars[e.AggregateID]
+	if !ok {
+		return fmt.Errorf("%w: car %s", errUnknownAggregate, e.AggregateID)
+	}
+	switch e.Name {
+	case carUpdatedEvent:
+		var b carAddedBody
+		if err := json.Unmarshal(e.Body, &b); err != nil {
+			return err
+		}
+		cs.capacity = domain.CarCapacity(b.Seats)
+		cs.version = e.Version
+		st.cars[e.AggregateID] = cs
+		return nil
+	case carStatusChangedEvent:
+		var b carStatusBody
+		if err := json.Unmarshal(e.Body, &b); err != nil {
+			return err
+		}
+		cs.status = b.Status
+		cs.version = e.Version
+		st.cars[e.AggregateID] = cs
+		return nil
+	case carPositionReportedEvent:
+		var b locationBody
+		if err := json.Unmarshal(e.Body, &b); err != nil {
+			return err
+		}
+		cs.position = b.location()
+		cs.version = e.Version
+		st.cars[e.AggregateID] = cs
+		return nil
+	}
+
+	var b carGroupBody
+	if len(e.Body) > 0 {
+		if err := json.Unmarshal(e.Body, &b); err != nil {
+			return err
+		}
+	}
+	switch e.Name {
+	case carGroupGotOnEvent:
+		cs.journeys[b.Group] = b.People
+	case carGroupGotOffEvent:
+		delete(cs.journeys, b.Group)
+	case carReservedEvent:
+		cs.reservedFor = b.Group
+	case carReservationReleasedEvent:
+		cs.reservedFor = uuid.Nil
+	case carRetiringEvent:
+		cs.retiring = true
+	}
+	cs.version = e.Version
+	st.cars[e.AggregateID] = cs
+	return nil
+}
+
+func (st *state) applyToGroup(e Event) error {
+	gs, ok := st.groups[e.AggregateID]
+	if !ok {
+		return fmt.Errorf("%w: group %s", errUnknownAggregate, e.AggregateID)
+	}
+	switch e.Name {
+	case groupOnJourneyEvent:
+		var b groupOnJourneyBody
+		if err := json.Unmarshal(e.Body, &b); err != nil {
+			return err
+		}
+		gs.carID = b.Car
+		gs.boardedAt = b.BoardedAt
+	case groupDroppedOffEvent:
+		gs.carID = uuid.Nil
+	case groupOvertakenEvent:
+		var b groupOvertakenBody
+		if err := json.Unmarshal(e.Body, &b); err != nil {
+			return err
+		}
+		gs.overtaken = b.Overtaken
+	}
+	gs.version = e.Version
+	st.groups[e.AggregateID] = gs
+	return nil
+}
+
+// carChanges returns the events that change the stored car into the given one
+func carChanges(from carState, to domain.Car, version int) []Event {
+	var evs []Event
+	if from.capacity != 0 && from.capacity != to.Capacity() {
+		evs = append(evs, newEvent(to.ID(), version, carUpdatedEvent, carAddedBody{Seats: to.Capacity().Int()}))
+	}
+	if !from.retiring && to.IsRetiring() {
+		evs = append(evs, newEvent(to.ID(), version, carRetiringEvent, nil))
+	}
+	// a car that is being added has no status yet, and it's available
+	fromStatus := from.status
+	if fromStatus == "" {
+		fromStatus = domain.CarAvailable
+	}
+	if fromStatus != to.Status() {
+		evs = append(evs, newEvent(to.ID(), version, carStatusChangedEvent, carStatusBody{Status: to.Status()}))
+	}
+	if p := to.Position(); p != nil && (from.position == nil || *from.position != *p) {
+		evs = append(evs, newEvent(to.ID(), version, carPositionReportedEvent, newLocationBody(p)))
+	}
+	for _, gID := range sortedIDs(from.journeys) {
+		if _, ok := to.Journeys()[gID]; !ok {
+			evs = append(evs, newEvent(to.ID(), version, carGroupGotOffEvent, carGroupBody{Group: gID}))
+		}
+	}
+	people := make(map[uuid.UUID]int, len(to.Journeys()))
+	for gID, g := range to.Journeys() {
+		people[gID] = g.People()
+	}
+	for _, gID := range sortedIDs(people) {
+		if _, ok := from.journeys[gID]; !ok {
+			evs = append(evs, newEvent(to.ID(), version, carGroupGotOnEvent, carGroupBody{Group: gID, People: people[gID]}))
+		}
+	}
+	if from.reservedFor != to.ReservedFor() {
+		if to.IsReserved() {
+			evs = append(evs, newEvent(to.ID(), version, carReservedEvent, carGroupBody{Group: to.ReservedFor()}))
+		} else {
+			evs = append(evs, newEvent(to.ID(), version, carReservationReleasedEvent, nil))
+		}
+	}
+	return evs
+}
+
+// groupChanges returns the events that change the stored group into the given one.
+// The people, the request time and the site of a group don't change once it has been added
+func groupChanges(from groupState, to domain.Group, version int) []Event {
+	var (
+		evs   []Event
+		carID uuid.UUID
+	)
+	if to.Car() != nil {
+		carID = to.Car().ID()
+	}
+	if from.carID != carID {
+		if from.carID != uuid.Nil {
+			evs = append(evs, newEvent(to.ID(), version, groupDroppedOffEvent, nil))
+		}
+		if carID != uuid.Nil {
+			evs = append(evs, newEvent(to.ID(), version, groupOnJourneyEvent, groupOnJourneyBody{Car: carID, BoardedAt: to.BoardedAt()}))
+		}
+	}
+	if from.overtaken != to.Overtaken() {
+		evs = append(evs, newEvent(to.ID(), version, groupOvertakenEvent, groupOvertakenBody{Overtaken: to.Overtaken()}))
+	}
+	return evs
+}
+
+func sortedIDs(m map[uuid.UUID]int) []uuid.UUID {
+	ids := make([]uuid.UUID, 0, len(m))
+	for id := range m {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i].String() < ids[j].String() })
+	return ids
+}
+
+// car hydrates a car. As in the other repositories, the groups on journey are not linked back to the car
+func (st state) car(id uuid.UUID) (domain.Car, bool) {
+	cs, ok := st.cars[id]
+	if !ok {
+		return domain.Car{}, false
+	}
+	journeys := make(domain.Journeys, len(cs.journeys))
+	for gID, people := range cs.journeys {
+		gs, ok := st.groups[gID]
+		if !ok {
+			gs = groupState{people: people}
+		}
+		journeys[gID] = gs.group(gID, nil)
+	}
+	var car domain.Car
+	car.Hydrate(domain.CarSnapshot{
+		ID:          id,
+		Capacity:    cs.capacity,
+		Journeys:    journeys,
+		ReservedFor: cs.reservedFor,
+		Retiring:    cs.retiring,
+		Status:      cs.status,
+		Site:        cs.site,
+		Features:    cs.features,
+		Position:    cs.position,
+		Version:     cs.version,
+	})
+	return car, true
+}
+
+func (gs groupState) group(id uuid.UUID, car *domain.Car) domain.Group {
+	var g domain.Group
+	g.Hydrate(domain.GroupSnapshot{
+		ID:           id,
+		People:       gs.people,
+		Car:          car,
+		RequestedAt:  gs.requestedAt,
+		BoardedAt:    gs.boardedAt,
+		Overtaken:    gs.overtaken,
+		Site:         gs.site,
+		Destination:  gs.destination,
+		Members:      gs.members,
+		Requirements: gs.requirements,
+		Pickup:       gs.pickup,
+		Version:      gs.version,
+	})
+	return g
+}
+
+// newLocationBody returns nil if the location is not given
+func newLocationBody(l *domain.Location) *locationBody {
+	if l == nil {
+		return nil
+	}
+	return &locationBody{Lat: l.Lat, Lng: l.Lng}
+}
+
+func (b *locationBody) location() *domain.Location {
+	if b == nil {
+		return nil
+	}
+	return &domain.Location{Lat: b.Lat, Lng: b.Lng}
+}
@@ -0,0 +1,201 @@
+// Package eventstore implements the repositories with event sourcing. The changes of the cars and the groups
+// are appended as events to a local file, and the current state is rebuilt by replaying them when it's opened.
+package eventstore
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Event is a stored event. Its version is the version of the aggregate once the event has been applied
+type Event struct {
+	Seq         uint64          `json:"seq"`
+	AggregateID uuid.UUID       `json:"aggregate_id"`
+	Version     int             `json:"version"`
+	Name        string          `json:"name"`
+	At          time.Time       `json:"at"`
+	Body        json.RawMessage `json:"body,omitempty"`
+}
+
+// Store is the event store. Each line of its file has the events of a unit of work, so a unit of work
+// is stored as a whole or not at all. The current state of the aggregates is kept in memory.
+type Store struct {
+	path string
+	f    *os.File
+	size int64
+	seq  uint64
+
+	state state
+
+	// mux protects the state and the file, and uowMux serializes the units of work
+	mux    *sync.RWMutex
+	uowMux *sync.Mutex
+}
+
+// ErrCorrupted is returned when the file of the store can't be replayed
+var ErrCorrupted = errors.New("corrupted event store")
+
+// Open opens the event store, creating its file if it doesn't exist, and replays its events.
+// A last line that was not completely written, because the service crashed while writing it, is discarded
+func Open(path string) (*Store, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	s := &Store{path: path, f: f, state: newState(), mux: &sync.RWMutex{}, uowMux: &sync.Mutex{}}
+	if err := s.replay(); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Close closes the file of the store
+func (s *Store) Close() error {
+	return s.f.Close()
+}
+
+// History returns the events of an aggregate, in the order they happened
+func (s *Store) History(aggregateID uuid.UUID) ([]Event, error) {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	f, err := os.Open(s.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var history []Event
+	err = readLines(f, func(evs []Event) error {
+		for _, e := range evs {
+			if e.AggregateID == aggregateID {
+				history = append(history, e)
+			}
+		}
+		return nil
+	})
+	return history, err
+}
+
+func (s *Store) replay() error {
+	err := readLines(s.f, func(evs []Event) error {
+		for _, e := range evs {
+			if err := s.state.apply(e); err != nil {
+				return fmt.Errorf("%w: event %d: %s", ErrCorrupted, e.Seq, err.Error())
+			}
+			s.seq = e.Seq
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	size, err := completeLinesSize(s.f)
+	if err != nil {
+		return err
+	}
+	if err := s.f.Truncate(size); err != nil {
+		return err
+	}
+	s.size = size
+	_, err = s.f.Seek(size, io.SeekStart)
+	return err
+}
+
+// write appends the events as a new line of the file. If it fails, the file is left as it was.
+// It must be called with the lock held
+func (s *Store) write(evs []Event) error {
+	if len(evs) == 0 {
+		return nil
+	}
+
+	seq := s.seq
+	for i := range evs {
+		seq++
+		evs[i].Seq = seq
+	}
+	b, err := json.Marshal(evs)
+	if err != nil {
+		return err
+	}
+	b = append(b, '\n')
+
+	if _, err := s.f.Write(b); err != nil {
+		return s.rewind(err)
+	}
+	if err := s.f.Sync(); err != nil {
+		return s.rewind(err)
+	}
+	s.size += int64(len(b))
+	s.seq = seq
+	return nil
+}
+
+// rewind removes what has been written from a failed write
+func (s *Store) rewind(err error) error {
+	if tErr := s.f.Truncate(s.size); tErr != nil {
+		return fmt.Errorf("%w, and it could not be rewound: %s", err, tErr.Error())
+	}
+	if _, sErr := s.f.Seek(s.size, io.SeekStart); sErr != nil {
+		return fmt.Errorf("%w, and it could not be rewound: %s", err, sErr.Error())
+	}
+	return err
+}
+
+// readLines calls fn with the events of each complete line of the file
+func readLines(f *os.File, fn func([]Event) error) error {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if errors.Is(err, io.EOF) { // an incomplete last line is ignored
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		var evs []Event
+		if err := json.Unmarshal(line, &evs); err != nil {
+			return fmt.Errorf("%w: %s", ErrCorrupted, err.Error())
+		}
+		if err := fn(evs); err != nil {
+			return err
+		}
+	}
+}
+
+// completeLinesSize returns the size of the file without its incomplete last line, if any
+func completeLinesSize(f *os.File) (int64, error) {
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+	var size int64
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			return size, nil
+		}
+		if err != nil {
+			return 0, err
+		}
+		size += int64(len(line))
+	}
+}
@@ -0,0 +1,93 @@
+package eventstore
+
+import (
+	"context"
+)
+
+// tx keeps the events of the unit of work in progress, until it's committed
+type tx struct {
+	events []Event
+}
+
+type txKey struct{}
+
+// UnitOfWork implements the app.UnitOfWork interface for the event sourced repositories.
+// The events of a unit of work are applied to the state as soon as they happen, but they are
+// only appended to the store when it succeeds. If it fails, the state is restored.
+type UnitOfWork struct {
+	s *Store
+}
+
+// NewUnitOfWork is a constructor
+func NewUnitOfWork(s *Store) UnitOfWork {
+	return UnitOfWork{s: s}
+}
+
+// Do implements the app.UnitOfWork interface
+func (uow UnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*tx); ok { // already inside a unit of work, it joins it
+		return fn(ctx)
+	}
+
+	uow.s.uowMux.Lock()
+	defer uow.s.uowMux.Unlock()
+
+	snapshot := uow.s.snapshot()
+	t := &tx{}
+	if err := fn(context.WithValue(ctx, txKey{}, t)); err != nil {
+		uow.s.restore(snapshot)
+		return err
+	}
+	if err := uow.s.commit(t); err != nil {
+		uow.s.restore(snapshot)
+		return err
+	}
+	return nil
+}
+
+// change applies the events returned by fn to the state. Outside a unit of work, they are appended to the store
+// straight away. Otherwise, they are kept until the unit of work is committed. fn must not change the state
+// when it returns an error
+func (s *Store) change(ctx context.Context, fn func(st *state) ([]Event, error)) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	evs, err := fn(&s.state)
+	if err != nil || len(evs) == 0 {
+		return err
+	}
+
+	if t, ok := ctx.Value(txKey{}).(*tx); ok {
+		t.events = append(t.events, evs...)
+	} else if err := s.write(evs); err != nil {
+		return err
+	}
+
+	for _, e := range evs {
+		if err := s.state.apply(e); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *Store) commit(t *tx) error {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	return s.write(t.events)
+}
+
+func (s *Store) snapshot() state {
+	s.mux.RLock()
+	defer s.mux.RUnlock()
+
+	return s.state.clone()
+}
+
+func (s *Store) restore(st state) {
+	s.mux.Lock()
+	defer s.mux.Unlock()
+
+	s.state = st
+}
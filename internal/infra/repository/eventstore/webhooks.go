@@ -0,0 +1,91 @@
+package eventstore
+
+import (
+	"context"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/infra/repository"
+
+	"github.com/google/uuid"
+)
+
+// WebhooksRepository is a repository. Neither the webhooks nor the dead letters are ever updated, they're only added
+type WebhooksRepository struct {
+	s *Store
+}
+
+// NewWebhooksRepository is a constructor
+func NewWebhooksRepository(s *Store) WebhooksRepository {
+	return WebhooksRepository{s: s}
+}
+
+// Add is self-described
+func (wr WebhooksRepository) Add(ctx context.Context, wh app.Webhook) error {
+	return wr.s.change(ctx, func(st *state) ([]Event, error) {
+		for _, e := range st.webhooks {
+			if e.id == wh.ID {
+				return nil, repository.ErrPKConflict
+			}
+		}
+		return []Event{newEvent(wh.ID, 1, webhookAddedEvent, webhookAddedBody{
+			URL:    wh.URL,
+			Events: wh.Events,
+			Secret: wh.Secret,
+		})}, nil
+	})
+}
+
+// FindByEvent returns the webhooks subscribed to the event, in the order they were added
+func (wr WebhooksRepository) FindByEvent(_ context.Context, name string) ([]app.Webhook, error) {
+	wr.s.mux.RLock()
+	defer wr.s.mux.RUnlock()
+
+	var whs []app.Webhook
+	for _, e := range wr.s.state.webhooks {
+		wh := app.Webhook{
+			ID:     e.id,
+			URL:    e.URL,
+			Events: append([]string{}, e.Events...),
+			Secret: e.Secret,
+		}
+		if wh.Subscribes(name) {
+			whs = append(whs, wh)
+		}
+	}
+	return whs, nil
+}
+
+// AddDeadLetter is self-described. Each dead letter is an aggregate of its own
+func (wr WebhooksRepository) AddDeadLetter(ctx context.Context, dl app.DeadLetter) error {
+	return wr.s.change(ctx, func(_ *state) ([]Event, error) {
+		return []Event{newEvent(uuid.New(), 0, webhookDeadLetterEvent, webhookDeadLetterBody{
+			Webhook:  dl.WebhookID,
+			URL:      dl.URL,
+			Event:    dl.Event,
+			Payload:  dl.Payload,
+			Attempts: dl.Attempts,
+			Err:      dl.Err,
+			FailedAt: dl.FailedAt,
+		})}, nil
+	})
+}
+
+// FindDeadLetters returns the dead letters in the order they were added
+func (wr WebhooksRepository) FindDeadLetters(_ context.Context) ([]app.DeadLetter, error) {
+	wr.s.mux.RLock()
+	defer wr.s.mux.RUnlock()
+
+	dls := make([]app.DeadLetter, 0, len(wr.s.state.deadLetters))
+	for _, b := range wr.s.state.deadLetters {
+		dls = append(dls, app.DeadLetter{
+			WebhookID: b.Webhook,
+			URL:       b.URL,
+			Event:     b.Event,
+			Payload:   b.Payload,
+			Attempts:  b.Attempts,
+			Err:       b.Err,
+			FailedAt:  b.FailedAt,
+		})
+	}
+	return dls, nil
+}
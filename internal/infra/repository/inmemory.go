@@ -1,13 +1,18 @@
 package repository
 
-// By the moment, using an in-memory repository without transactions support.
-// If I have time enough, I'll add Sqlite3, with upper.io as ORM, with transactions support, as an CH middleware
+// In-memory repositories. They are used by default.
+// The persistent implementation, on top of SQLite, is in the sqlite package.
+// The aggregates are copied when they are stored and when they are returned, so the
+// callers never share the journeys of a car with the repository, nor with each other.
 
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
 	"sync"
 
+	"theskyinflames/car-sharing/internal/app"
 	"theskyinflames/car-sharing/internal/domain"
 
 	"github.com/google/uuid"
@@ -17,12 +22,15 @@ import (
 type CarRepository struct {
 	cars map[uuid.UUID]domain.Car
 
+	// ids keeps the cars in the order they were added
+	ids *[]uuid.UUID
+
 	mux *sync.RWMutex
 }
 
 // NewCarRepository is a constructor
 func NewCarRepository() CarRepository {
-	return CarRepository{cars: make(map[uuid.UUID]domain.Car), mux: &sync.RWMutex{}}
+	return CarRepository{cars: make(map[uuid.UUID]domain.Car), ids: &[]uuid.UUID{}, mux: &sync.RWMutex{}}
 }
 
 // RemoveAll is a constructor
@@ -31,6 +39,7 @@ func (cr *CarRepository) RemoveAll(_ context.Context) error {
 	defer cr.mux.Unlock()
 
 	cr.cars = make(map[uuid.UUID]domain.Car)
+	*cr.ids = []uuid.UUID{}
 	return nil
 }
 
@@ -47,34 +56,39 @@ func (cr CarRepository) AddAll(_ context.Context, evs []domain.Car) error {
 		if ok {
 			return ErrPKConflict
 		}
-		cr.cars[ev.ID()] = ev
+		cr.cars[ev.ID()] = copyCar(ev, ev.Version())
+		*cr.ids = append(*cr.ids, ev.ID())
 	}
 	return nil
 }
 
-// ErrNotFound is self-described
-var ErrNotFound = errors.New("not found")
+// ErrNotFound is self-described. It wraps the domain.ErrNotFound, so the app layer can tell it apart
+var ErrNotFound = fmt.Errorf("%w", domain.ErrNotFound)
 
-// Update is self-described
+// Update is self-described. It fails if the car has been updated since it was read
 func (cr CarRepository) Update(_ context.Context, ev domain.Car) error {
 	cr.mux.Lock()
 	defer cr.mux.Unlock()
 
-	if _, ok := cr.cars[ev.ID()]; !ok {
+	stored, ok := cr.cars[ev.ID()]
+	if !ok {
 		return ErrNotFound
 	}
-	cr.cars[ev.ID()] = ev
+	if stored.Version() != ev.Version() {
+		return app.ErrVersionConflict
+	}
+	cr.cars[ev.ID()] = copyCar(ev, ev.Version()+1)
 	return nil
 }
 
-// FindAll is self-described
+// FindAll returns the cars in the order they were added
 func (cr CarRepository) FindAll(_ context.Context) ([]domain.Car, error) {
 	cr.mux.RLock()
 	defer cr.mux.RUnlock()
 
 	evs := make([]domain.Car, 0)
-	for _, ev := range cr.cars {
-		evs = append(evs, ev)
+	for _, id := range *cr.ids {
+		evs = append(evs, copyCar(cr.cars[id], cr.cars[id].Version()))
 	}
 	return evs, nil
 }
@@ -86,22 +100,49 @@ func (cr CarRepository) FindByID(_ context.Context, id uuid.UUID) (domain.Car, e
 
 	for _, ev := range cr.cars {
 		if ev.ID() == id {
-			return ev, nil
+			return copyCar(ev, ev.Version()), nil
 		}
 	}
 	return domain.Car{}, ErrNotFound
 }
 
+// RemoveByID is self-described
+func (cr CarRepository) RemoveByID(_ context.Context, id uuid.UUID) error {
+	cr.mux.Lock()
+	defer cr.mux.Unlock()
+
+	if _, ok := cr.cars[id]; !ok {
+		return ErrNotFound
+	}
+	delete(cr.cars, id)
+	for i, stored := range *cr.ids {
+		if stored == id {
+			*cr.ids = append((*cr.ids)[:i:i], (*cr.ids)[i+1:]...)
+			break
+		}
+	}
+	return nil
+}
+
 // GroupsRepository is a repository
 type GroupsRepository struct {
 	groups map[uuid.UUID]domain.Group
 
+	// arrivals keeps the insertion sequence of each group. It breaks ties between groups with the same request time
+	arrivals map[uuid.UUID]uint64
+	seq      *uint64
+
 	mux *sync.RWMutex
 }
 
 // NewGroupsRepository is a constructor
 func NewGroupsRepository() GroupsRepository {
-	return GroupsRepository{groups: make(map[uuid.UUID]domain.Group), mux: &sync.RWMutex{}}
+	return GroupsRepository{
+		groups:   make(map[uuid.UUID]domain.Group),
+		arrivals: make(map[uuid.UUID]uint64),
+		seq:      new(uint64),
+		mux:      &sync.RWMutex{},
+	}
 }
 
 // RemoveAll is self-described
@@ -110,10 +151,11 @@ func (gr *GroupsRepository) RemoveAll(_ context.Context) error {
 	defer gr.mux.Unlock()
 
 	gr.groups = make(map[uuid.UUID]domain.Group)
+	gr.arrivals = make(map[uuid.UUID]uint64)
 	return nil
 }
 
-// FindGroupsWithoutCar is a finder
+// FindGroupsWithoutCar is a finder. The groups are returned in arrival order
 func (gr GroupsRepository) FindGroupsWithoutCar(_ context.Context) ([]domain.Group, error) {
 	gr.mux.RLock()
 	defer gr.mux.RUnlock()
@@ -121,21 +163,32 @@ func (gr GroupsRepository) FindGroupsWithoutCar(_ context.Context) ([]domain.Gro
 	var withoutEv []domain.Group
 	for _, g := range gr.groups {
 		if g.Car() == nil {
-			withoutEv = append(withoutEv, g)
+			withoutEv = append(withoutEv, copyGroup(g, g.Version()))
 		}
 	}
+	sort.Slice(withoutEv, func(i, j int) bool {
+		gi, gj := withoutEv[i], withoutEv[j]
+		if !gi.RequestedAt().Equal(gj.RequestedAt()) {
+			return gi.ArrivedBefore(gj)
+		}
+		return gr.arrivals[gi.ID()] < gr.arrivals[gj.ID()]
+	})
 	return withoutEv, nil
 }
 
-// Update is self-described
+// Update is self-described. It fails if the group has been updated since it was read
 func (gr GroupsRepository) Update(_ context.Context, g domain.Group) error {
 	gr.mux.Lock()
 	defer gr.mux.Unlock()
 
-	if _, ok := gr.groups[g.ID()]; !ok {
+	stored, ok := gr.groups[g.ID()]
+	if !ok {
 		return ErrNotFound
 	}
-	gr.groups[g.ID()] = g
+	if stored.Version() != g.Version() {
+		return app.ErrVersionConflict
+	}
+	gr.groups[g.ID()] = copyGroup(g, g.Version()+1)
 	return nil
 }
 
@@ -148,7 +201,9 @@ func (gr GroupsRepository) Add(_ context.Context, g domain.Group) error {
 	if ok {
 		return ErrPKConflict
 	}
-	gr.groups[g.ID()] = g
+	gr.groups[g.ID()] = copyGroup(g, g.Version())
+	*gr.seq++
+	gr.arrivals[g.ID()] = *gr.seq
 	return nil
 }
 
@@ -159,7 +214,7 @@ func (gr GroupsRepository) FindByID(_ context.Context, id uuid.UUID) (domain.Gro
 
 	for _, g := range gr.groups {
 		if g.ID() == id {
-			return g, nil
+			return copyGroup(g, g.Version()), nil
 		}
 	}
 	return domain.Group{}, ErrNotFound
@@ -171,5 +226,35 @@ func (gr GroupsRepository) RemoveByID(_ context.Context, id uuid.UUID) error {
 	defer gr.mux.Unlock()
 
 	delete(gr.groups, id)
+	delete(gr.arrivals, id)
 	return nil
 }
+
+// copyCar returns a copy of the car, with the given version, that doesn't share its journeys
+func copyCar(car domain.Car, version int) domain.Car {
+	journeys := make(domain.Journeys, len(car.Journeys()))
+	for gID, g := range car.Journeys() {
+		journeys[gID] = g
+	}
+	s := car.Snapshot()
+	s.Journeys = journeys
+	s.Version = version
+	var copied domain.Car
+	copied.Hydrate(s)
+	return copied
+}
+
+// copyGroup returns a copy of the group, with the given version, that doesn't share its car
+func copyGroup(g domain.Group, version int) domain.Group {
+	var car *domain.Car
+	if g.Car() != nil {
+		c := copyCar(*g.Car(), g.Car().Version())
+		car = &c
+	}
+	s := g.Snapshot()
+	s.Car = car
+	s.Version = version
+	var copied domain.Group
+	copied.Hydrate(s)
+	return copied
+}
@@ -0,0 +1,47 @@
+package repository_test
+
+import (
+	"testing"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/infra/repository"
+	"theskyinflames/car-sharing/internal/infra/repository/repositorytest"
+)
+
+func TestInMemoryRepositories(t *testing.T) {
+	repositorytest.Run(t, func(_ *testing.T) (app.GroupsRepository, app.CarsRepository, app.UnitOfWork) {
+		gr := repository.NewGroupsRepository()
+		cr := repository.NewCarRepository()
+		return &gr, &cr, repository.NewUnitOfWork(&gr, &cr)
+	})
+}
+
+func TestInMemoryJourneysHistoryRepository(t *testing.T) {
+	repositorytest.RunJourneysHistory(t, func(_ *testing.T) app.JourneysHistoryRepository {
+		return repository.NewJourneysHistoryRepository()
+	})
+}
+
+func TestInMemoryOutbox(t *testing.T) {
+	repositorytest.RunOutbox(t, func(_ *testing.T) (app.Outbox, app.UnitOfWork) {
+		gr := repository.NewGroupsRepository()
+		cr := repository.NewCarRepository()
+		ob := repository.NewOutbox()
+		return ob, repository.NewUnitOfWork(&gr, &cr).WithOutbox(&ob)
+	})
+}
+
+func TestInMemoryWebhooksRepository(t *testing.T) {
+	repositorytest.RunWebhooks(t, func(_ *testing.T) app.WebhooksRepository {
+		return repository.NewWebhooksRepository()
+	})
+}
+
+func TestInMemoryReservationsRepository(t *testing.T) {
+	repositorytest.RunReservations(t, func(_ *testing.T) (app.ReservationsRepository, app.UnitOfWork) {
+		gr := repository.NewGroupsRepository()
+		cr := repository.NewCarRepository()
+		rr := repository.NewReservationsRepository()
+		return rr, repository.NewUnitOfWork(&gr, &cr).WithReservations(&rr)
+	})
+}
@@ -0,0 +1,58 @@
+package repository
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"theskyinflames/car-sharing/internal/app"
+
+	"github.com/google/uuid"
+)
+
+// JourneysHistoryRepository is a repository
+type JourneysHistoryRepository struct {
+	records map[uuid.UUID]app.JourneyRecord
+
+	mux *sync.RWMutex
+}
+
+// NewJourneysHistoryRepository is a constructor
+func NewJourneysHistoryRepository() JourneysHistoryRepository {
+	return JourneysHistoryRepository{records: make(map[uuid.UUID]app.JourneyRecord), mux: &sync.RWMutex{}}
+}
+
+// Save adds the record, or replaces it if there is already one for the group
+func (hr JourneysHistoryRepository) Save(_ context.Context, r app.JourneyRecord) error {
+	hr.mux.Lock()
+	defer hr.mux.Unlock()
+
+	hr.records[r.GroupID] = r
+	return nil
+}
+
+// FindByGroupID is a finder
+func (hr JourneysHistoryRepository) FindByGroupID(_ context.Context, groupID uuid.UUID) (app.JourneyRecord, error) {
+	hr.mux.RLock()
+	defer hr.mux.RUnlock()
+
+	r, ok := hr.records[groupID]
+	if !ok {
+		return app.JourneyRecord{}, ErrNotFound
+	}
+	return r, nil
+}
+
+// FindWaitEndedSince returns the records of the groups that got on a car, or left without it, from the given time on
+func (hr JourneysHistoryRepository) FindWaitEndedSince(_ context.Context, since time.Time) ([]app.JourneyRecord, error) {
+	hr.mux.RLock()
+	defer hr.mux.RUnlock()
+
+	var records []app.JourneyRecord
+	for _, r := range hr.records {
+		if !r.WaitEndedAt().Before(since) {
+			records = append(records, r)
+		}
+	}
+	return records, nil
+}
@@ -0,0 +1,73 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"theskyinflames/car-sharing/internal/app"
+
+	"github.com/google/uuid"
+	"github.com/theskyinflames/cqrs-eda/pkg/events"
+)
+
+// Outbox implements the app.Outbox interface in memory
+type Outbox struct {
+	msgs *[]app.OutboxMessage
+
+	mux *sync.RWMutex
+}
+
+// NewOutbox is a constructor
+func NewOutbox() Outbox {
+	return Outbox{msgs: &[]app.OutboxMessage{}, mux: &sync.RWMutex{}}
+}
+
+// Add is self-described
+func (ob Outbox) Add(_ context.Context, evs []events.Event) error {
+	ob.mux.Lock()
+	defer ob.mux.Unlock()
+
+	for _, e := range evs {
+		*ob.msgs = append(*ob.msgs, app.OutboxMessage{ID: uuid.New(), Event: e})
+	}
+	return nil
+}
+
+// Pending is self-described
+func (ob Outbox) Pending(_ context.Context, limit int) ([]app.OutboxMessage, error) {
+	ob.mux.RLock()
+	defer ob.mux.RUnlock()
+
+	if limit > len(*ob.msgs) {
+		limit = len(*ob.msgs)
+	}
+	return append([]app.OutboxMessage{}, (*ob.msgs)[:limit]...), nil
+}
+
+// MarkDelivered removes the message from the outbox. It does nothing if the message is not there
+func (ob Outbox) MarkDelivered(_ context.Context, id uuid.UUID) error {
+	ob.mux.Lock()
+	defer ob.mux.Unlock()
+
+	for i, m := range *ob.msgs {
+		if m.ID == id {
+			*ob.msgs = append((*ob.msgs)[:i:i], (*ob.msgs)[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (ob Outbox) snapshot() []app.OutboxMessage {
+	ob.mux.RLock()
+	defer ob.mux.RUnlock()
+
+	return append([]app.OutboxMessage{}, *ob.msgs...)
+}
+
+func (ob Outbox) restore(msgs []app.OutboxMessage) {
+	ob.mux.Lock()
+	defer ob.mux.Unlock()
+
+	*ob.msgs = msgs
+}
@@ -0,0 +1,474 @@
+// Package repositorytest is the contract test suite that every implementation of the repositories has to pass
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/domain"
+	"theskyinflames/car-sharing/internal/fixtures"
+	"theskyinflames/car-sharing/internal/helpers"
+	"theskyinflames/car-sharing/internal/infra/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// Factory returns a new and empty pair of repositories sharing the same storage, and the unit of work over it
+type Factory func(t *testing.T) (app.GroupsRepository, app.CarsRepository, app.UnitOfWork)
+
+// Run runs the contract test suite against the repositories returned by the factory
+func Run(t *testing.T, factory Factory) {
+	t.Run("CarsRepository", func(t *testing.T) {
+		testCarsRepository(t, factory)
+		testCarsVersion(t, factory)
+	})
+	t.Run("GroupsRepository", func(t *testing.T) {
+		testGroupsRepository(t, factory)
+	})
+	t.Run("UnitOfWork", func(t *testing.T) {
+		testUnitOfWork(t, factory)
+	})
+}
+
+func testCarsRepository(t *testing.T, factory Factory) {
+	ctx := context.Background()
+
+	t.Run(`Given a list of cars, when they are added, then they are returned in the same order`, func(t *testing.T) {
+		_, cr, _ := factory(t)
+		cars := []domain.Car{
+			fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity6)}.Build(),
+			fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity4)}.Build(),
+			fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity5)}.Build(),
+		}
+		require.NoError(t, cr.AddAll(ctx, cars))
+
+		found, err := cr.FindAll(ctx)
+		require.NoError(t, err)
+		require.Len(t, found, len(cars))
+		for i := range cars {
+			require.Equal(t, cars[i].ID(), found[i].ID())
+			require.Equal(t, cars[i].Capacity(), found[i].Capacity())
+			require.Equal(t, cars[i].Availability(), found[i].Availability())
+		}
+	})
+
+	t.Run(`Given an already added car, when it's added again, then a pk conflict error is returned`, func(t *testing.T) {
+		_, cr, _ := factory(t)
+		car := fixtures.Car{}.Build()
+		require.NoError(t, cr.AddAll(ctx, []domain.Car{car}))
+		require.ErrorIs(t, cr.AddAll(ctx, []domain.Car{car}), repository.ErrPKConflict)
+	})
+
+	t.Run(`Given an unknown car, when it's looked for or updated, then a not found error is returned`, func(t *testing.T) {
+		_, cr, _ := factory(t)
+		_, err := cr.FindByID(ctx, uuid.New())
+		require.ErrorIs(t, err, repository.ErrNotFound)
+		require.ErrorIs(t, cr.Update(ctx, fixtures.Car{}.Build()), repository.ErrNotFound)
+	})
+
+	t.Run(`Given a car with groups on journey and a reservation, when it's updated, then the changes are persisted`, func(t *testing.T) {
+		gr, cr, _ := factory(t)
+		car := fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity6)}.Build()
+		require.NoError(t, cr.AddAll(ctx, []domain.Car{car}))
+
+		var (
+			g       = fixtures.Group{People: helpers.IntPtr(2)}.Build()
+			waiting = fixtures.Group{People: helpers.IntPtr(6)}.Build()
+		)
+		require.NoError(t, gr.Add(ctx, g))
+		require.NoError(t, gr.Add(ctx, waiting))
+		require.NoError(t, car.GetOn(g))
+		g.GetOn(&car)
+		car.Reserve(waiting)
+		require.NoError(t, gr.Update(ctx, g))
+		require.NoError(t, cr.Update(ctx, car))
+
+		found, err := cr.FindByID(ctx, car.ID())
+		require.NoError(t, err)
+		require.Equal(t, 4, found.Availability())
+		require.Contains(t, found.Journeys(), g.ID())
+		require.Equal(t, waiting.ID(), found.ReservedFor())
+
+		foundGroup, err := gr.FindByID(ctx, g.ID())
+		require.NoError(t, err)
+		require.True(t, foundGroup.IsOnJourney())
+		require.True(t, g.BoardedAt().Equal(foundGroup.BoardedAt()))
+
+		require.NoError(t, found.DropOff(g.ID()))
+		found.ReleaseReservation()
+		require.NoError(t, cr.Update(ctx, found))
+
+		found, err = cr.FindByID(ctx, car.ID())
+		require.NoError(t, err)
+		require.Equal(t, 6, found.Availability())
+		require.False(t, found.IsReserved())
+	})
+
+	t.Run(`Given a car, when its capacity is changed and it's retired, then the changes are persisted`, func(t *testing.T) {
+		gr, cr, _ := factory(t)
+		car := fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity4)}.Build()
+		require.NoError(t, cr.AddAll(ctx, []domain.Car{car}))
+
+		g := fixtures.Group{People: helpers.IntPtr(2)}.Build()
+		require.NoError(t, gr.Add(ctx, g))
+		require.NoError(t, car.ChangeCapacity(domain.CarCapacity6))
+		require.NoError(t, car.GetOn(g))
+		g.GetOn(&car)
+		require.NoError(t, car.Retire())
+		require.NoError(t, gr.Update(ctx, g))
+		require.NoError(t, cr.Update(ctx, car))
+
+		found, err := cr.FindByID(ctx, car.ID())
+		require.NoError(t, err)
+		require.Equal(t, domain.CarCapacity6, found.Capacity())
+		require.True(t, found.IsRetiring())
+		require.Contains(t, found.Journeys(), g.ID())
+	})
+
+	t.Run(`Given a car with groups on journey, when it's taken out of service and back, then its status is persisted`, func(t *testing.T) {
+		gr, cr, _ := factory(t)
+		car := fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity6)}.Build()
+		require.NoError(t, cr.AddAll(ctx, []domain.Car{car}))
+
+		g := fixtures.Group{People: helpers.IntPtr(2)}.Build()
+		require.NoError(t, gr.Add(ctx, g))
+		require.NoError(t, car.GetOn(g))
+		g.GetOn(&car)
+		require.NoError(t, car.TakeOutOfService())
+		require.NoError(t, gr.Update(ctx, g))
+		require.NoError(t, cr.Update(ctx, car))
+
+		found, err := cr.FindByID(ctx, car.ID())
+		require.NoError(t, err)
+		require.Equal(t, domain.CarDraining, found.Status())
+
+		require.NoError(t, found.BackInService())
+		require.NoError(t, cr.Update(ctx, found))
+
+		found, err = cr.FindByID(ctx, car.ID())
+		require.NoError(t, err)
+		require.Equal(t, domain.CarAvailable, found.Status())
+	})
+
+	t.Run(`Given cars of several sites, when they are added, then their sites and features are persisted`, func(t *testing.T) {
+		_, cr, _ := factory(t)
+		var (
+			inFactory = fixtures.Car{Site: "factory", Features: domain.NewFeatures(domain.FeatureWheelchair, domain.FeatureLuggage)}.Build()
+			inDefault = fixtures.Car{}.Build()
+		)
+		require.NoError(t, cr.AddAll(ctx, []domain.Car{inFactory, inDefault}))
+
+		found, err := cr.FindAll(ctx)
+		require.NoError(t, err)
+		require.Len(t, found, 2)
+		require.Equal(t, domain.Site("factory"), found[0].Site())
+		require.Equal(t, domain.DefaultSite, found[1].Site())
+		require.Equal(t, inFactory.Features(), found[0].Features())
+		require.Empty(t, found[1].Features())
+	})
+
+	t.Run(`Given a car, when it reports its position, then the position is persisted`, func(t *testing.T) {
+		_, cr, _ := factory(t)
+		car := fixtures.Car{}.Build()
+		require.NoError(t, cr.AddAll(ctx, []domain.Car{car}))
+
+		found, err := cr.FindByID(ctx, car.ID())
+		require.NoError(t, err)
+		require.Nil(t, found.Position())
+
+		found.ReportPosition(domain.Location{Lat: 41.3874, Lng: 2.1686})
+		require.NoError(t, cr.Update(ctx, found))
+
+		found, err = cr.FindByID(ctx, car.ID())
+		require.NoError(t, err)
+		require.Equal(t, &domain.Location{Lat: 41.3874, Lng: 2.1686}, found.Position())
+	})
+
+	t.Run(`Given some cars, when one of them is removed, then it's not found anymore`, func(t *testing.T) {
+		_, cr, _ := factory(t)
+		var (
+			removed = fixtures.Car{}.Build()
+			kept    = fixtures.Car{}.Build()
+		)
+		require.NoError(t, cr.AddAll(ctx, []domain.Car{removed, kept}))
+		require.NoError(t, cr.RemoveByID(ctx, removed.ID()))
+
+		_, err := cr.FindByID(ctx, removed.ID())
+		require.ErrorIs(t, err, repository.ErrNotFound)
+		found, err := cr.FindAll(ctx)
+		require.NoError(t, err)
+		require.Len(t, found, 1)
+		require.Equal(t, kept.ID(), found[0].ID())
+
+		require.ErrorIs(t, cr.RemoveByID(ctx, removed.ID()), repository.ErrNotFound)
+	})
+
+	t.Run(`Given some cars, when all of them are removed, then no car is found`, func(t *testing.T) {
+		_, cr, _ := factory(t)
+		require.NoError(t, cr.AddAll(ctx, []domain.Car{fixtures.Car{}.Build(), fixtures.Car{}.Build()}))
+		require.NoError(t, cr.RemoveAll(ctx))
+
+		found, err := cr.FindAll(ctx)
+		require.NoError(t, err)
+		require.Empty(t, found)
+	})
+}
+
+func testCarsVersion(t *testing.T, factory Factory) {
+	ctx := context.Background()
+
+	t.Run(`Given a car, when it's updated, then its version is increased`, func(t *testing.T) {
+		_, cr, _ := factory(t)
+		car := fixtures.Car{}.Build()
+		require.NoError(t, cr.AddAll(ctx, []domain.Car{car}))
+		require.NoError(t, cr.Update(ctx, car))
+
+		found, err := cr.FindByID(ctx, car.ID())
+		require.NoError(t, err)
+		require.Equal(t, car.Version()+1, found.Version())
+	})
+
+	t.Run(`Given a car that has been updated since it was read, when the stale copy is updated,
+		then a version conflict error is returned and the car is not changed`, func(t *testing.T) {
+		gr, cr, _ := factory(t)
+		car := fixtures.Car{}.Build()
+		require.NoError(t, cr.AddAll(ctx, []domain.Car{car}))
+		require.NoError(t, cr.Update(ctx, car))
+
+		g := fixtures.Group{}.Build()
+		require.NoError(t, gr.Add(ctx, g))
+		require.NoError(t, car.GetOn(g))
+		require.ErrorIs(t, cr.Update(ctx, car), app.ErrVersionConflict)
+
+		found, err := cr.FindByID(ctx, car.ID())
+		require.NoError(t, err)
+		require.Empty(t, found.Journeys())
+	})
+}
+
+func testGroupsRepository(t *testing.T, factory Factory) {
+	ctx := context.Background()
+
+	t.Run(`Given a group, when it's added, then it can be found by its ID`, func(t *testing.T) {
+		gr, _, _ := factory(t)
+		requestedAt := time.Now().Add(-time.Minute)
+		g := fixtures.Group{
+			People:       helpers.IntPtr(3),
+			RequestedAt:  &requestedAt,
+			Overtaken:    helpers.IntPtr(2),
+			Site:         "factory",
+			Destination:  "airport",
+			Members:      []string{"E-1024", "E-2048"},
+			Requirements: domain.NewFeatures(domain.FeatureChildSeat),
+			Pickup:       &domain.Location{Lat: 41.3874, Lng: 2.1686},
+		}.Build()
+		require.NoError(t, gr.Add(ctx, g))
+
+		found, err := gr.FindByID(ctx, g.ID())
+		require.NoError(t, err)
+		require.Equal(t, g.ID(), found.ID())
+		require.Equal(t, g.People(), found.People())
+		require.True(t, g.RequestedAt().Equal(found.RequestedAt()))
+		require.Equal(t, g.Overtaken(), found.Overtaken())
+		require.Equal(t, g.Site(), found.Site())
+		require.Equal(t, g.Destination(), found.Destination())
+		require.Equal(t, g.Members(), found.Members())
+		require.Equal(t, g.Requirements(), found.Requirements())
+		require.Equal(t, g.Pickup(), found.Pickup())
+		require.False(t, found.IsOnJourney())
+		require.True(t, found.BoardedAt().IsZero())
+	})
+
+	t.Run(`Given an already added group, when it's added again, then a pk conflict error is returned`, func(t *testing.T) {
+		gr, _, _ := factory(t)
+		g := fixtures.Group{}.Build()
+		require.NoError(t, gr.Add(ctx, g))
+		require.ErrorIs(t, gr.Add(ctx, g), repository.ErrPKConflict)
+	})
+
+	t.Run(`Given an unknown group, when it's looked for or updated, then a not found error is returned`, func(t *testing.T) {
+		gr, _, _ := factory(t)
+		_, err := gr.FindByID(ctx, uuid.New())
+		require.ErrorIs(t, err, repository.ErrNotFound)
+		require.ErrorIs(t, gr.Update(ctx, fixtures.Group{}.Build()), repository.ErrNotFound)
+	})
+
+	t.Run(`Given a group that gets on a car, when it's updated, then it's found on journey`, func(t *testing.T) {
+		gr, cr, _ := factory(t)
+		car := fixtures.Car{}.Build()
+		require.NoError(t, cr.AddAll(ctx, []domain.Car{car}))
+		g := fixtures.Group{}.Build()
+		require.NoError(t, gr.Add(ctx, g))
+
+		require.NoError(t, car.GetOn(g))
+		g.GetOn(&car)
+		require.NoError(t, gr.Update(ctx, g))
+		require.NoError(t, cr.Update(ctx, car))
+
+		found, err := gr.FindByID(ctx, g.ID())
+		require.NoError(t, err)
+		require.True(t, found.IsOnJourney())
+		require.Equal(t, car.ID(), found.Car().ID())
+
+		wg, err := gr.FindGroupsWithoutCar(ctx)
+		require.NoError(t, err)
+		require.Empty(t, wg)
+	})
+
+	t.Run(`Given some waiting groups, when they are looked for, then they are returned in arrival order`, func(t *testing.T) {
+		gr, _, _ := factory(t)
+		var (
+			now    = time.Now()
+			first  = now.Add(-3 * time.Minute)
+			second = now.Add(-2 * time.Minute)
+
+			g1 = fixtures.Group{RequestedAt: &second}.Build()
+			g2 = fixtures.Group{RequestedAt: &first}.Build()
+			g3 = fixtures.Group{RequestedAt: &now}.Build()
+			g4 = fixtures.Group{RequestedAt: &now}.Build() // same request time, but added later
+		)
+		for _, g := range []domain.Group{g1, g2, g3, g4} {
+			require.NoError(t, gr.Add(ctx, g))
+		}
+
+		wg, err := gr.FindGroupsWithoutCar(ctx)
+		require.NoError(t, err)
+		require.Len(t, wg, 4)
+		for i, expected := range []domain.Group{g2, g1, g3, g4} {
+			require.Equal(t, expected.ID(), wg[i].ID())
+		}
+	})
+
+	t.Run(`Given some groups, when one of them is removed, then it's not found anymore`, func(t *testing.T) {
+		gr, _, _ := factory(t)
+		var (
+			g1 = fixtures.Group{}.Build()
+			g2 = fixtures.Group{}.Build()
+		)
+		require.NoError(t, gr.Add(ctx, g1))
+		require.NoError(t, gr.Add(ctx, g2))
+		require.NoError(t, gr.RemoveByID(ctx, g1.ID()))
+
+		_, err := gr.FindByID(ctx, g1.ID())
+		require.ErrorIs(t, err, repository.ErrNotFound)
+		_, err = gr.FindByID(ctx, g2.ID())
+		require.NoError(t, err)
+	})
+
+	t.Run(`Given a group that has been updated since it was read, when the stale copy is updated,
+		then a version conflict error is returned and the group is not changed`, func(t *testing.T) {
+		gr, _, _ := factory(t)
+		g := fixtures.Group{}.Build()
+		require.NoError(t, gr.Add(ctx, g))
+		require.NoError(t, gr.Update(ctx, g))
+
+		found, err := gr.FindByID(ctx, g.ID())
+		require.NoError(t, err)
+		require.Equal(t, g.Version()+1, found.Version())
+
+		g.Overtake()
+		require.ErrorIs(t, gr.Update(ctx, g), app.ErrVersionConflict)
+
+		found, err = gr.FindByID(ctx, g.ID())
+		require.NoError(t, err)
+		require.Zero(t, found.Overtaken())
+	})
+
+	t.Run(`Given some groups, when all of them are removed, then no group is found`, func(t *testing.T) {
+		gr, _, _ := factory(t)
+		require.NoError(t, gr.Add(ctx, fixtures.Group{}.Build()))
+		require.NoError(t, gr.RemoveAll(ctx))
+
+		wg, err := gr.FindGroupsWithoutCar(ctx)
+		require.NoError(t, err)
+		require.Empty(t, wg)
+	})
+}
+
+func testUnitOfWork(t *testing.T, factory Factory) {
+	ctx := context.Background()
+
+	t.Run(`Given a unit of work, when its function succeeds, then the changes are committed`, func(t *testing.T) {
+		gr, cr, uow := factory(t)
+		var (
+			car = fixtures.Car{}.Build()
+			g   = fixtures.Group{}.Build()
+		)
+		err := uow.Do(ctx, func(ctx context.Context) error {
+			if err := cr.AddAll(ctx, []domain.Car{car}); err != nil {
+				return err
+			}
+			return gr.Add(ctx, g)
+		})
+		require.NoError(t, err)
+
+		_, err = cr.FindByID(ctx, car.ID())
+		require.NoError(t, err)
+		_, err = gr.FindByID(ctx, g.ID())
+		require.NoError(t, err)
+	})
+
+	t.Run(`Given a unit of work, when its function fails, then all the changes are rolled back`, func(t *testing.T) {
+		gr, cr, uow := factory(t)
+		car := fixtures.Car{Capacity: helpers.CarCapacityPtr(domain.CarCapacity4)}.Build()
+		require.NoError(t, cr.AddAll(ctx, []domain.Car{car}))
+		var (
+			g         = fixtures.Group{People: helpers.IntPtr(2)}.Build()
+			newGroup  = fixtures.Group{}.Build()
+			randomErr = errors.New("")
+		)
+		require.NoError(t, gr.Add(ctx, g))
+
+		err := uow.Do(ctx, func(ctx context.Context) error {
+			if err := car.GetOn(g); err != nil {
+				return err
+			}
+			g.GetOn(&car)
+			if err := cr.Update(ctx, car); err != nil {
+				return err
+			}
+			if err := gr.Update(ctx, g); err != nil {
+				return err
+			}
+			if err := gr.Add(ctx, newGroup); err != nil {
+				return err
+			}
+			return randomErr
+		})
+		require.ErrorIs(t, err, randomErr)
+
+		found, err := cr.FindByID(ctx, car.ID())
+		require.NoError(t, err)
+		require.Empty(t, found.Journeys())
+		require.Equal(t, int(domain.CarCapacity4), found.Availability())
+		foundGroup, err := gr.FindByID(ctx, g.ID())
+		require.NoError(t, err)
+		require.False(t, foundGroup.IsOnJourney())
+		_, err = gr.FindByID(ctx, newGroup.ID())
+		require.ErrorIs(t, err, repository.ErrNotFound)
+	})
+
+	t.Run(`Given a running unit of work, when another one is started with its context, then it joins the running one`, func(t *testing.T) {
+		gr, _, uow := factory(t)
+		var (
+			g         = fixtures.Group{}.Build()
+			randomErr = errors.New("")
+		)
+		err := uow.Do(ctx, func(ctx context.Context) error {
+			if err := uow.Do(ctx, func(ctx context.Context) error {
+				return gr.Add(ctx, g)
+			}); err != nil {
+				return err
+			}
+			return randomErr
+		})
+		require.ErrorIs(t, err, randomErr)
+
+		_, err = gr.FindByID(ctx, g.ID())
+		require.ErrorIs(t, err, repository.ErrNotFound)
+	})
+}
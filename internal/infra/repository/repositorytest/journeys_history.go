@@ -0,0 +1,92 @@
+package repositorytest
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/infra/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// JourneysHistoryFactory returns a new and empty journeys history repository
+type JourneysHistoryFactory func(t *testing.T) app.JourneysHistoryRepository
+
+// RunJourneysHistory runs the contract test suite against the journeys history repository returned by the factory
+func RunJourneysHistory(t *testing.T, factory JourneysHistoryFactory) {
+	ctx := context.Background()
+	now := time.Now()
+
+	t.Run(`Given a journey record, when it's saved, then it can be found by its group ID`, func(t *testing.T) {
+		hr := factory(t)
+		r := app.JourneyRecord{
+			GroupID:     uuid.New(),
+			People:      3,
+			RequestedAt: now.Add(-time.Minute),
+			BoardedAt:   now,
+		}
+		require.NoError(t, hr.Save(ctx, r))
+
+		found, err := hr.FindByGroupID(ctx, r.GroupID)
+		require.NoError(t, err)
+		requireEqualRecords(t, r, found)
+		require.True(t, found.DroppedOffAt.IsZero())
+	})
+
+	t.Run(`Given a saved journey record, when it's saved again, then it's replaced`, func(t *testing.T) {
+		hr := factory(t)
+		r := app.JourneyRecord{
+			GroupID:     uuid.New(),
+			People:      3,
+			RequestedAt: now.Add(-time.Minute),
+			BoardedAt:   now,
+		}
+		require.NoError(t, hr.Save(ctx, r))
+		r.DroppedOffAt = now.Add(time.Minute)
+		require.NoError(t, hr.Save(ctx, r))
+
+		found, err := hr.FindByGroupID(ctx, r.GroupID)
+		require.NoError(t, err)
+		requireEqualRecords(t, r, found)
+	})
+
+	t.Run(`Given an unknown group, when its journey record is looked for, then a not found error is returned`, func(t *testing.T) {
+		hr := factory(t)
+		_, err := hr.FindByGroupID(ctx, uuid.New())
+		require.ErrorIs(t, err, repository.ErrNotFound)
+	})
+
+	t.Run(`Given some journey records, when the ones whose wait ended since a time are looked for,
+		then the boarded and the abandoned ones are returned, but not the older ones`, func(t *testing.T) {
+		hr := factory(t)
+		var (
+			old          = app.JourneyRecord{GroupID: uuid.New(), People: 1, RequestedAt: now.Add(-3 * time.Hour), BoardedAt: now.Add(-2 * time.Hour)}
+			oldAbandoned = app.JourneyRecord{GroupID: uuid.New(), People: 1, RequestedAt: now.Add(-3 * time.Hour), AbandonedAt: now.Add(-2 * time.Hour)}
+			recent       = app.JourneyRecord{GroupID: uuid.New(), People: 2, RequestedAt: now.Add(-time.Hour), BoardedAt: now.Add(-time.Minute)}
+			abandoned    = app.JourneyRecord{GroupID: uuid.New(), People: 3, RequestedAt: now.Add(-time.Hour), AbandonedAt: now.Add(-time.Minute)}
+		)
+		for _, r := range []app.JourneyRecord{old, oldAbandoned, recent, abandoned} {
+			require.NoError(t, hr.Save(ctx, r))
+		}
+
+		found, err := hr.FindWaitEndedSince(ctx, now.Add(-time.Hour))
+		require.NoError(t, err)
+		require.Len(t, found, 2)
+		sort.Slice(found, func(i, j int) bool { return found[i].People < found[j].People })
+		requireEqualRecords(t, recent, found[0])
+		requireEqualRecords(t, abandoned, found[1])
+	})
+}
+
+func requireEqualRecords(t *testing.T, expected, got app.JourneyRecord) {
+	require.Equal(t, expected.GroupID, got.GroupID)
+	require.Equal(t, expected.People, got.People)
+	require.True(t, expected.RequestedAt.Equal(got.RequestedAt))
+	require.True(t, expected.BoardedAt.Equal(got.BoardedAt))
+	require.True(t, expected.DroppedOffAt.Equal(got.DroppedOffAt))
+	require.True(t, expected.AbandonedAt.Equal(got.AbandonedAt))
+}
@@ -0,0 +1,91 @@
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/domain"
+	"theskyinflames/car-sharing/internal/fixtures"
+	"theskyinflames/car-sharing/internal/helpers"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+	"github.com/theskyinflames/cqrs-eda/pkg/events"
+)
+
+// OutboxFactory returns a new and empty outbox, and the unit of work over its storage
+type OutboxFactory func(t *testing.T) (app.Outbox, app.UnitOfWork)
+
+// RunOutbox runs the contract test suite against the outbox returned by the factory
+func RunOutbox(t *testing.T, factory OutboxFactory) {
+	ctx := context.Background()
+	var (
+		car = fixtures.Car{}.Build()
+		g   = fixtures.Group{People: helpers.IntPtr(3)}.Build()
+	)
+	g.GetOn(&car)
+	evs := []events.Event{
+		domain.NewCarCreatedEvent(car),
+		domain.NewGroupSetOnJourneyEvent(g),
+		domain.NewCarReservedEvent(car, g),
+	}
+
+	t.Run(`Given some added events, when the pending ones are asked for, then they are returned in order`, func(t *testing.T) {
+		ob, _ := factory(t)
+		require.NoError(t, ob.Add(ctx, evs[:2]))
+		require.NoError(t, ob.Add(ctx, evs[2:]))
+
+		msgs, err := ob.Pending(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, msgs, len(evs))
+		for i, m := range msgs {
+			require.NotEqual(t, uuid.Nil, m.ID)
+			require.Equal(t, evs[i].Name(), m.Event.Name())
+			require.Equal(t, evs[i].AggregateID(), m.Event.AggregateID())
+		}
+
+		e, ok := msgs[1].Event.(domain.GroupSetOnJourneyEvent)
+		require.True(t, ok)
+		require.Equal(t, car.ID(), e.CarID())
+		require.Equal(t, g.People(), e.People())
+		require.True(t, g.BoardedAt().Equal(e.BoardedAt()))
+
+		msgs, err = ob.Pending(ctx, 2)
+		require.NoError(t, err)
+		require.Len(t, msgs, 2)
+	})
+
+	t.Run(`Given some pending events, when one is marked as delivered, then it's not pending anymore`, func(t *testing.T) {
+		ob, _ := factory(t)
+		require.NoError(t, ob.Add(ctx, evs))
+
+		msgs, err := ob.Pending(ctx, 10)
+		require.NoError(t, err)
+		require.NoError(t, ob.MarkDelivered(ctx, msgs[0].ID))
+		require.NoError(t, ob.MarkDelivered(ctx, msgs[0].ID))
+		require.NoError(t, ob.MarkDelivered(ctx, uuid.New()))
+
+		pending, err := ob.Pending(ctx, 10)
+		require.NoError(t, err)
+		require.Len(t, pending, len(evs)-1)
+		require.Equal(t, msgs[1].ID, pending[0].ID)
+	})
+
+	t.Run(`Given a unit of work that fails, when its events have been added, then they are rolled back`, func(t *testing.T) {
+		ob, uow := factory(t)
+		randomErr := errors.New("")
+		err := uow.Do(ctx, func(ctx context.Context) error {
+			if err := ob.Add(ctx, evs); err != nil {
+				return err
+			}
+			return randomErr
+		})
+		require.ErrorIs(t, err, randomErr)
+
+		msgs, err := ob.Pending(ctx, 10)
+		require.NoError(t, err)
+		require.Empty(t, msgs)
+	})
+}
@@ -0,0 +1,108 @@
+package repositorytest
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/domain"
+	"theskyinflames/car-sharing/internal/fixtures"
+	"theskyinflames/car-sharing/internal/helpers"
+	"theskyinflames/car-sharing/internal/infra/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// ReservationsFactory returns a new and empty reservations repository, and the unit of work over its storage
+type ReservationsFactory func(t *testing.T) (app.ReservationsRepository, app.UnitOfWork)
+
+// RunReservations runs the contract test suite against the reservations repository returned by the factory
+func RunReservations(t *testing.T, factory ReservationsFactory) {
+	ctx := context.Background()
+	now := time.Now()
+
+	t.Run(`Given some reservations, when they are added, then they are returned sorted by their start`, func(t *testing.T) {
+		rr, _ := factory(t)
+		var (
+			later    = fixtures.Reservation{StartAt: helpers.TimePtr(now.Add(2 * time.Hour))}.Build()
+			earliest = fixtures.Reservation{
+				People:       helpers.IntPtr(3),
+				Site:         "factory",
+				Requirements: domain.NewFeatures(domain.FeatureWheelchair, domain.FeatureLuggage),
+				CarID:        helpers.UUIDPtr(uuid.New()),
+				StartAt:      helpers.TimePtr(now.Add(time.Hour)),
+			}.Build()
+		)
+		require.NoError(t, rr.Add(ctx, later))
+		require.NoError(t, rr.Add(ctx, earliest))
+
+		rs, err := rr.FindAll(ctx)
+		require.NoError(t, err)
+		require.Len(t, rs, 2)
+		require.Equal(t, earliest.ID(), rs[0].ID())
+		require.Equal(t, later.ID(), rs[1].ID())
+
+		found, err := rr.FindByID(ctx, earliest.ID())
+		require.NoError(t, err)
+		requireSameReservation(t, earliest, found)
+	})
+
+	t.Run(`Given an already added reservation, when it's added again, then a pk conflict error is returned`, func(t *testing.T) {
+		rr, _ := factory(t)
+		r := fixtures.Reservation{}.Build()
+		require.NoError(t, rr.Add(ctx, r))
+		require.ErrorIs(t, rr.Add(ctx, r), repository.ErrPKConflict)
+	})
+
+	t.Run(`Given a reservation, when it's removed, then it can't be found anymore`, func(t *testing.T) {
+		rr, _ := factory(t)
+		r := fixtures.Reservation{}.Build()
+		require.NoError(t, rr.Add(ctx, r))
+
+		require.NoError(t, rr.RemoveByID(ctx, r.ID()))
+		require.ErrorIs(t, rr.RemoveByID(ctx, r.ID()), repository.ErrNotFound)
+		_, err := rr.FindByID(ctx, r.ID())
+		require.ErrorIs(t, err, repository.ErrNotFound)
+	})
+
+	t.Run(`Given a unit of work that fails, when a reservation has been removed in it, then it's rolled back`, func(t *testing.T) {
+		rr, uow := factory(t)
+		var (
+			r         = fixtures.Reservation{}.Build()
+			added     = fixtures.Reservation{}.Build()
+			randomErr = errors.New("")
+		)
+		require.NoError(t, rr.Add(ctx, r))
+
+		err := uow.Do(ctx, func(ctx context.Context) error {
+			if err := rr.RemoveByID(ctx, r.ID()); err != nil {
+				return err
+			}
+			if err := rr.Add(ctx, added); err != nil {
+				return err
+			}
+			return randomErr
+		})
+		require.ErrorIs(t, err, randomErr)
+
+		found, err := rr.FindByID(ctx, r.ID())
+		require.NoError(t, err)
+		requireSameReservation(t, r, found)
+		_, err = rr.FindByID(ctx, added.ID())
+		require.ErrorIs(t, err, repository.ErrNotFound)
+	})
+}
+
+func requireSameReservation(t *testing.T, expected, found domain.Reservation) {
+	t.Helper()
+	require.Equal(t, expected.ID(), found.ID())
+	require.Equal(t, expected.People(), found.People())
+	require.Equal(t, expected.Site(), found.Site())
+	require.Equal(t, expected.Requirements(), found.Requirements())
+	require.Equal(t, expected.CarID(), found.CarID())
+	require.True(t, expected.StartAt().Equal(found.StartAt()))
+	require.True(t, expected.EndAt().Equal(found.EndAt()))
+}
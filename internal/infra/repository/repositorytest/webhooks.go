@@ -0,0 +1,105 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/domain"
+	"theskyinflames/car-sharing/internal/infra/repository"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/require"
+)
+
+// WebhooksFactory returns a new and empty webhooks repository
+type WebhooksFactory func(t *testing.T) app.WebhooksRepository
+
+// RunWebhooks runs the contract test suite against the webhooks repository returned by the factory
+func RunWebhooks(t *testing.T, factory WebhooksFactory) {
+	ctx := context.Background()
+
+	t.Run(`Given some webhooks, when they are looked for by event, then the subscribed ones are returned in order`, func(t *testing.T) {
+		wr := factory(t)
+		var (
+			onJourney = app.Webhook{
+				ID:     uuid.New(),
+				URL:    "https://example.com/a",
+				Events: []string{domain.GroupSetOnJourneyEventName},
+				Secret: "a",
+			}
+			both = app.Webhook{
+				ID:     uuid.New(),
+				URL:    "https://example.com/b",
+				Events: []string{domain.GroupSetOnJourneyEventName, domain.GroupDroppedOffEventName},
+				Secret: "b",
+			}
+		)
+		require.NoError(t, wr.Add(ctx, onJourney))
+		require.NoError(t, wr.Add(ctx, both))
+
+		whs, err := wr.FindByEvent(ctx, domain.GroupSetOnJourneyEventName)
+		require.NoError(t, err)
+		require.Equal(t, []app.Webhook{onJourney, both}, whs)
+
+		whs, err = wr.FindByEvent(ctx, domain.GroupDroppedOffEventName)
+		require.NoError(t, err)
+		require.Equal(t, []app.Webhook{both}, whs)
+
+		whs, err = wr.FindByEvent(ctx, domain.CarCreatedEventName)
+		require.NoError(t, err)
+		require.Empty(t, whs)
+	})
+
+	t.Run(`Given an already added webhook, when it's added again, then a pk conflict error is returned`, func(t *testing.T) {
+		wr := factory(t)
+		wh := app.Webhook{ID: uuid.New(), URL: "https://example.com", Events: []string{domain.CarCreatedEventName}, Secret: "s"}
+		require.NoError(t, wr.Add(ctx, wh))
+		require.ErrorIs(t, wr.Add(ctx, wh), repository.ErrPKConflict)
+	})
+
+	t.Run(`Given some dead letters, when they are looked for, then they are returned in order`, func(t *testing.T) {
+		wr := factory(t)
+		dls := []app.DeadLetter{
+			{
+				WebhookID: uuid.New(),
+				URL:       "https://example.com/a",
+				Event:     domain.GroupDroppedOffEventName,
+				Payload:   []byte(`{"event":"group.dropped.off"}`),
+				Attempts:  5,
+				Err:       "unexpected status 503",
+				FailedAt:  time.Now().Add(-time.Minute),
+			},
+			{
+				WebhookID: uuid.New(),
+				URL:       "https://example.com/b",
+				Event:     domain.CarCreatedEventName,
+				Payload:   []byte(`{"event":"car.added"}`),
+				Err:       "too many pending deliveries",
+				FailedAt:  time.Now(),
+			},
+		}
+		for _, dl := range dls {
+			require.NoError(t, wr.AddDeadLetter(ctx, dl))
+		}
+
+		found, err := wr.FindDeadLetters(ctx)
+		require.NoError(t, err)
+		require.Len(t, found, len(dls))
+		for i := range dls {
+			requireSameDeadLetter(t, dls[i], found[i])
+		}
+	})
+}
+
+func requireSameDeadLetter(t *testing.T, expected, found app.DeadLetter) {
+	t.Helper()
+	require.Equal(t, expected.WebhookID, found.WebhookID)
+	require.Equal(t, expected.URL, found.URL)
+	require.Equal(t, expected.Event, found.Event)
+	require.Equal(t, expected.Payload, found.Payload)
+	require.Equal(t, expected.Attempts, found.Attempts)
+	require.Equal(t, expected.Err, found.Err)
+	require.True(t, expected.FailedAt.Equal(found.FailedAt))
+}
@@ -0,0 +1,97 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"theskyinflames/car-sharing/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+// ReservationsRepository is a repository in memory
+type ReservationsRepository struct {
+	reservations map[uuid.UUID]domain.Reservation
+
+	mux *sync.RWMutex
+}
+
+// NewReservationsRepository is a constructor
+func NewReservationsRepository() ReservationsRepository {
+	return ReservationsRepository{reservations: make(map[uuid.UUID]domain.Reservation), mux: &sync.RWMutex{}}
+}
+
+// Add is self-described
+func (rr ReservationsRepository) Add(_ context.Context, r domain.Reservation) error {
+	rr.mux.Lock()
+	defer rr.mux.Unlock()
+
+	if _, ok := rr.reservations[r.ID()]; ok {
+		return ErrPKConflict
+	}
+	rr.reservations[r.ID()] = r
+	return nil
+}
+
+// FindAll returns the reservations sorted by their start
+func (rr ReservationsRepository) FindAll(_ context.Context) ([]domain.Reservation, error) {
+	rr.mux.RLock()
+	defer rr.mux.RUnlock()
+
+	rs := make([]domain.Reservation, 0, len(rr.reservations))
+	for _, r := range rr.reservations {
+		rs = append(rs, r)
+	}
+	sort.Slice(rs, func(i, j int) bool {
+		return rs[i].StartAt().Before(rs[j].StartAt())
+	})
+	return rs, nil
+}
+
+// FindByID is self-described
+func (rr ReservationsRepository) FindByID(_ context.Context, id uuid.UUID) (domain.Reservation, error) {
+	rr.mux.RLock()
+	defer rr.mux.RUnlock()
+
+	r, ok := rr.reservations[id]
+	if !ok {
+		return domain.Reservation{}, ErrNotFound
+	}
+	return r, nil
+}
+
+// RemoveByID is self-described
+func (rr ReservationsRepository) RemoveByID(_ context.Context, id uuid.UUID) error {
+	rr.mux.Lock()
+	defer rr.mux.Unlock()
+
+	if _, ok := rr.reservations[id]; !ok {
+		return ErrNotFound
+	}
+	delete(rr.reservations, id)
+	return nil
+}
+
+func (rr ReservationsRepository) snapshot() map[uuid.UUID]domain.Reservation {
+	rr.mux.RLock()
+	defer rr.mux.RUnlock()
+
+	s := make(map[uuid.UUID]domain.Reservation, len(rr.reservations))
+	for id, r := range rr.reservations {
+		s[id] = r
+	}
+	return s
+}
+
+func (rr ReservationsRepository) restore(s map[uuid.UUID]domain.Reservation) {
+	rr.mux.Lock()
+	defer rr.mux.Unlock()
+
+	for id := range rr.reservations {
+		delete(rr.reservations, id)
+	}
+	for id, r := range s {
+		rr.reservations[id] = r
+	}
+}
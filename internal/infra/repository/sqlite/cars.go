@@ -0,0 +1,264 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/domain"
+	"theskyinflames/car-sharing/internal/infra/repository"
+
+	"github.com/google/uuid"
+)
+
+// CarRepository is a repository
+type CarRepository struct {
+	db *sql.DB
+}
+
+// NewCarRepository is a constructor
+func NewCarRepository(db *sql.DB) CarRepository {
+	return CarRepository{db: db}
+}
+
+// RemoveAll is self-described
+func (cr CarRepository) RemoveAll(ctx context.Context) error {
+	if _, err := conn(ctx, cr.db).ExecContext(ctx, `DELETE FROM journeys`); err != nil {
+		return err
+	}
+	_, err := conn(ctx, cr.db).ExecContext(ctx, `DELETE FROM cars`)
+	return err
+}
+
+// AddAll is self-described
+func (cr CarRepository) AddAll(ctx context.Context, cars []domain.Car) error {
+	for _, car := range cars {
+		var exists bool
+		if err := conn(ctx, cr.db).QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM cars WHERE id = ?)`, car.ID().String()).Scan(&exists); err != nil {
+			return err
+		}
+		if exists {
+			return repository.ErrPKConflict
+		}
+		lat, lng := latLng(car.Position())
+		if _, err := conn(ctx, cr.db).ExecContext(ctx,
+			`INSERT INTO cars (id, capacity, reserved_for, retiring, status, site, features, position_lat, position_lng, version)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			car.ID().String(), car.Capacity().Int(), uuidOrEmpty(car.ReservedFor()), car.IsRetiring(), string(car.Status()), string(car.Site()),
+			joinList(car.Features().Strings()), lat, lng, car.Version(),
+		); err != nil {
+			return err
+		}
+		if err := cr.saveJourneys(ctx, car); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Update is self-described. It fails if the car has been updated since it was read
+func (cr CarRepository) Update(ctx context.Context, car domain.Car) error {
+	lat, lng := latLng(car.Position())
+	rs, err := conn(ctx, cr.db).ExecContext(ctx,
+		`UPDATE cars SET capacity = ?, reserved_for = ?, retiring = ?, status = ?, position_lat = ?, position_lng = ?, version = version + 1
+		WHERE id = ? AND version = ?`,
+		car.Capacity().Int(), uuidOrEmpty(car.ReservedFor()), car.IsRetiring(), string(car.Status()), lat, lng, car.ID().String(), car.Version(),
+	)
+	if err != nil {
+		return err
+	}
+	if err := checkUpdated(ctx, cr.db, rs, `SELECT EXISTS (SELECT 1 FROM cars WHERE id = ?)`, car.ID()); err != nil {
+		return err
+	}
+	return cr.saveJourneys(ctx, car)
+}
+
+func (cr CarRepository) saveJourneys(ctx context.Context, car domain.Car) error {
+	if _, err := conn(ctx, cr.db).ExecContext(ctx, `DELETE FROM journeys WHERE car_id = ?`, car.ID().String()); err != nil {
+		return err
+	}
+	for gID := range car.Journeys() {
+		if _, err := conn(ctx, cr.db).ExecContext(ctx,
+			`INSERT OR REPLACE INTO journeys (group_id, car_id) VALUES (?, ?)`,
+			gID.String(), car.ID().String(),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FindAll returns the cars in the order they were added
+func (cr CarRepository) FindAll(ctx context.Context) ([]domain.Car, error) {
+	rows, err := conn(ctx, cr.db).QueryContext(ctx, `SELECT id, capacity, reserved_for, retiring, status, site, features, position_lat, position_lng, version
+		FROM cars ORDER BY seq`)
+	if err != nil {
+		return nil, err
+	}
+
+	var rcs []carRow
+	for rows.Next() {
+		var rc carRow
+		if err := rows.Scan(&rc.id, &rc.capacity, &rc.reservedFor, &rc.retiring, &rc.status, &rc.site, &rc.features, &rc.lat, &rc.lng,
+			&rc.version); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		rcs = append(rcs, rc)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	cars := make([]domain.Car, 0, len(rcs))
+	for _, rc := range rcs {
+		car, err := cr.hydrate(ctx, rc)
+		if err != nil {
+			return nil, err
+		}
+		cars = append(cars, car)
+	}
+	return cars, nil
+}
+
+// FindByID is a finder
+func (cr CarRepository) FindByID(ctx context.Context, id uuid.UUID) (domain.Car, error) {
+	var rc carRow
+	err := conn(ctx, cr.db).QueryRowContext(ctx,
+		`SELECT id, capacity, reserved_for, retiring, status, site, features, position_lat, position_lng, version FROM cars WHERE id = ?`,
+		id.String(),
+	).Scan(&rc.id, &rc.capacity, &rc.reservedFor, &rc.retiring, &rc.status, &rc.site, &rc.features, &rc.lat, &rc.lng, &rc.version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Car{}, repository.ErrNotFound
+	}
+	if err != nil {
+		return domain.Car{}, err
+	}
+	return cr.hydrate(ctx, rc)
+}
+
+// RemoveByID is self-described
+func (cr CarRepository) RemoveByID(ctx context.Context, id uuid.UUID) error {
+	rs, err := conn(ctx, cr.db).ExecContext(ctx, `DELETE FROM cars WHERE id = ?`, id.String())
+	if err != nil {
+		return err
+	}
+	n, err := rs.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+	_, err = conn(ctx, cr.db).ExecContext(ctx, `DELETE FROM journeys WHERE car_id = ?`, id.String())
+	return err
+}
+
+type carRow struct {
+	id          string
+	capacity    int
+	reservedFor string
+	retiring    bool
+	status      string
+	site        string
+	features    string
+	lat         sql.NullFloat64
+	lng         sql.NullFloat64
+	version     int
+}
+
+func (cr CarRepository) hydrate(ctx context.Context, rc carRow) (domain.Car, error) {
+	id, err := uuid.Parse(rc.id)
+	if err != nil {
+		return domain.Car{}, err
+	}
+	reservedFor, err := parseUUIDOrNil(rc.reservedFor)
+	if err != nil {
+		return domain.Car{}, err
+	}
+	status, err := domain.ParseCarStatus(rc.status)
+	if err != nil {
+		return domain.Car{}, err
+	}
+
+	// as in the in-memory repository, the groups on journey are not linked back to the car
+	rows, err := conn(ctx, cr.db).QueryContext(ctx,
+		`SELECT g.id, g.people, g.requested_at, g.boarded_at, g.overtaken, g.site, g.destination, g.members, g.requirements, g.pickup_lat, g.pickup_lng, g.version
+		FROM journeys j JOIN passenger_groups g ON g.id = j.group_id
+		WHERE j.car_id = ?`, rc.id,
+	)
+	if err != nil {
+		return domain.Car{}, err
+	}
+	defer rows.Close()
+
+	journeys := make(domain.Journeys)
+	for rows.Next() {
+		var gr groupRow
+		if err := rows.Scan(&gr.id, &gr.people, &gr.requestedAt, &gr.boardedAt, &gr.overtaken, &gr.site, &gr.destination,
+			&gr.members, &gr.requirements, &gr.lat, &gr.lng, &gr.version); err != nil {
+			return domain.Car{}, err
+		}
+		g, err := gr.group(nil)
+		if err != nil {
+			return domain.Car{}, err
+		}
+		journeys[g.ID()] = g
+	}
+	if err := rows.Err(); err != nil {
+		return domain.Car{}, err
+	}
+
+	var car domain.Car
+	car.Hydrate(domain.CarSnapshot{
+		ID:          id,
+		Capacity:    domain.CarCapacity(rc.capacity),
+		Journeys:    journeys,
+		ReservedFor: reservedFor,
+		Retiring:    rc.retiring,
+		Status:      status,
+		Site:        domain.Site(rc.site),
+		Features:    features(rc.features),
+		Position:    location(rc.lat, rc.lng),
+		Version:     rc.version,
+	})
+	return car, nil
+}
+
+// checkUpdated checks that an optimistic update has changed a row. Otherwise, the row doesn't exist,
+// or its version has changed since it was read
+func checkUpdated(ctx context.Context, db *sql.DB, rs sql.Result, existsQuery string, id uuid.UUID) error {
+	n, err := rs.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		return nil
+	}
+	var exists bool
+	if err := conn(ctx, db).QueryRowContext(ctx, existsQuery, id.String()).Scan(&exists); err != nil {
+		return err
+	}
+	if !exists {
+		return repository.ErrNotFound
+	}
+	return app.ErrVersionConflict
+}
+
+func uuidOrEmpty(id uuid.UUID) string {
+	if id == uuid.Nil {
+		return ""
+	}
+	return id.String()
+}
+
+func parseUUIDOrNil(s string) (uuid.UUID, error) {
+	if s == "" {
+		return uuid.Nil, nil
+	}
+	return uuid.Parse(s)
+}
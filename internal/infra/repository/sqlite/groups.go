@@ -0,0 +1,229 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"time"
+
+	"theskyinflames/car-sharing/internal/domain"
+	"theskyinflames/car-sharing/internal/infra/repository"
+
+	"github.com/google/uuid"
+)
+
+// GroupsRepository is a repository
+type GroupsRepository struct {
+	db   *sql.DB
+	cars CarRepository
+}
+
+// NewGroupsRepository is a constructor
+func NewGroupsRepository(db *sql.DB) GroupsRepository {
+	return GroupsRepository{db: db, cars: NewCarRepository(db)}
+}
+
+// RemoveAll is self-described
+func (gr GroupsRepository) RemoveAll(ctx context.Context) error {
+	_, err := conn(ctx, gr.db).ExecContext(ctx, `DELETE FROM passenger_groups`)
+	return err
+}
+
+// Add is self-described
+func (gr GroupsRepository) Add(ctx context.Context, g domain.Group) error {
+	var exists bool
+	if err := conn(ctx, gr.db).QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM passenger_groups WHERE id = ?)`, g.ID().String()).Scan(&exists); err != nil {
+		return err
+	}
+	if exists {
+		return repository.ErrPKConflict
+	}
+	lat, lng := latLng(g.Pickup())
+	_, err := conn(ctx, gr.db).ExecContext(ctx,
+		`INSERT INTO passenger_groups (id, people, car_id, requested_at, boarded_at, overtaken, site, destination, members, requirements,
+		pickup_lat, pickup_lng, version)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		g.ID().String(), g.People(), carID(g), g.RequestedAt().UnixNano(), unixNano(g.BoardedAt()), g.Overtaken(), string(g.Site()),
+		string(g.Destination()), joinList(g.Members()), joinList(g.Requirements().Strings()), lat, lng, g.Version(),
+	)
+	return err
+}
+
+// Update is self-described. It fails if the group has been updated since it was read
+func (gr GroupsRepository) Update(ctx context.Context, g domain.Group) error {
+	lat, lng := latLng(g.Pickup())
+	rs, err := conn(ctx, gr.db).ExecContext(ctx,
+		`UPDATE passenger_groups SET people = ?, car_id = ?, requested_at = ?, boarded_at = ?, overtaken = ?, pickup_lat = ?, pickup_lng = ?,
+		version = version + 1
+		WHERE id = ? AND version = ?`,
+		g.People(), carID(g), g.RequestedAt().UnixNano(), unixNano(g.BoardedAt()), g.Overtaken(), lat, lng, g.ID().String(), g.Version(),
+	)
+	if err != nil {
+		return err
+	}
+	return checkUpdated(ctx, gr.db, rs, `SELECT EXISTS (SELECT 1 FROM passenger_groups WHERE id = ?)`, g.ID())
+}
+
+// FindGroupsWithoutCar is a finder. The groups are returned in arrival order
+func (gr GroupsRepository) FindGroupsWithoutCar(ctx context.Context) ([]domain.Group, error) {
+	rows, err := conn(ctx, gr.db).QueryContext(ctx,
+		`SELECT id, people, car_id, requested_at, boarded_at, overtaken, site, destination, members, requirements, pickup_lat, pickup_lng,
+		version
+		FROM passenger_groups
+		WHERE car_id = '' ORDER BY requested_at, seq`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var withoutCar []domain.Group
+	for rows.Next() {
+		var r groupRow
+		if err := rows.Scan(&r.id, &r.people, &r.carID, &r.requestedAt, &r.boardedAt, &r.overtaken, &r.site, &r.destination,
+			&r.members, &r.requirements, &r.lat, &r.lng, &r.version); err != nil {
+			return nil, err
+		}
+		g, err := r.group(nil)
+		if err != nil {
+			return nil, err
+		}
+		withoutCar = append(withoutCar, g)
+	}
+	return withoutCar, rows.Err()
+}
+
+// FindByID is a finder
+func (gr GroupsRepository) FindByID(ctx context.Context, id uuid.UUID) (domain.Group, error) {
+	var r groupRow
+	err := conn(ctx, gr.db).QueryRowContext(ctx,
+		`SELECT id, people, car_id, requested_at, boarded_at, overtaken, site, destination, members, requirements, pickup_lat, pickup_lng,
+		version
+		FROM passenger_groups WHERE id = ?`,
+		id.String(),
+	).Scan(&r.id, &r.people, &r.carID, &r.requestedAt, &r.boardedAt, &r.overtaken, &r.site, &r.destination,
+		&r.members, &r.requirements, &r.lat, &r.lng, &r.version)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Group{}, repository.ErrNotFound
+	}
+	if err != nil {
+		return domain.Group{}, err
+	}
+
+	var car *domain.Car
+	if r.carID != "" {
+		cID, err := uuid.Parse(r.carID)
+		if err != nil {
+			return domain.Group{}, err
+		}
+		c, err := gr.cars.FindByID(ctx, cID)
+		if err != nil {
+			return domain.Group{}, err
+		}
+		car = &c
+	}
+	return r.group(car)
+}
+
+// RemoveByID is self-described
+func (gr GroupsRepository) RemoveByID(ctx context.Context, id uuid.UUID) error {
+	_, err := conn(ctx, gr.db).ExecContext(ctx, `DELETE FROM passenger_groups WHERE id = ?`, id.String())
+	return err
+}
+
+type groupRow struct {
+	id           string
+	people       int
+	carID        string
+	requestedAt  int64
+	boardedAt    int64
+	overtaken    int
+	site         string
+	destination  string
+	members      string
+	requirements string
+	lat          sql.NullFloat64
+	lng          sql.NullFloat64
+	version      int
+}
+
+func (r groupRow) group(car *domain.Car) (domain.Group, error) {
+	id, err := uuid.Parse(r.id)
+	if err != nil {
+		return domain.Group{}, err
+	}
+	var g domain.Group
+	g.Hydrate(domain.GroupSnapshot{
+		ID:           id,
+		People:       r.people,
+		Car:          car,
+		RequestedAt:  time.Unix(0, r.requestedAt),
+		BoardedAt:    timeFromUnixNano(r.boardedAt),
+		Overtaken:    r.overtaken,
+		Site:         domain.Site(r.site),
+		Destination:  domain.Site(r.destination),
+		Members:      splitList(r.members),
+		Requirements: features(r.requirements),
+		Pickup:       location(r.lat, r.lng),
+		Version:      r.version,
+	})
+	return g, nil
+}
+
+func carID(g domain.Group) string {
+	if g.Car() == nil {
+		return ""
+	}
+	return g.Car().ID().String()
+}
+
+// unixNano returns 0 for the zero time, which means that the event has not happened yet
+func unixNano(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.UnixNano()
+}
+
+func timeFromUnixNano(n int64) time.Time {
+	if n == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, n)
+}
+
+// latLng returns the coordinates of the location, or NULL if it's not given
+func latLng(l *domain.Location) (sql.NullFloat64, sql.NullFloat64) {
+	if l == nil {
+		return sql.NullFloat64{}, sql.NullFloat64{}
+	}
+	return sql.NullFloat64{Float64: l.Lat, Valid: true}, sql.NullFloat64{Float64: l.Lng, Valid: true}
+}
+
+func location(lat, lng sql.NullFloat64) *domain.Location {
+	if !lat.Valid || !lng.Valid {
+		return nil
+	}
+	return &domain.Location{Lat: lat.Float64, Lng: lng.Float64}
+}
+
+// joinList stores a list of names in a single column. The names are validated by the domain, so they have no commas
+func joinList(ss []string) string {
+	return strings.Join(ss, ",")
+}
+
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+func features(s string) domain.Features {
+	var fs domain.Features
+	for _, f := range splitList(s) {
+		fs = append(fs, domain.Feature(f))
+	}
+	return fs
+}
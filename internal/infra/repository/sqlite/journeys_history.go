@@ -0,0 +1,101 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/infra/repository"
+
+	"github.com/google/uuid"
+)
+
+// JourneysHistoryRepository is a repository
+type JourneysHistoryRepository struct {
+	db *sql.DB
+}
+
+// NewJourneysHistoryRepository is a constructor
+func NewJourneysHistoryRepository(db *sql.DB) JourneysHistoryRepository {
+	return JourneysHistoryRepository{db: db}
+}
+
+// Save adds the record, or replaces it if there is already one for the group
+func (hr JourneysHistoryRepository) Save(ctx context.Context, r app.JourneyRecord) error {
+	_, err := conn(ctx, hr.db).ExecContext(ctx,
+		`INSERT OR REPLACE INTO journeys_history (group_id, people, requested_at, boarded_at, dropped_off_at, abandoned_at)
+		VALUES (?, ?, ?, ?, ?, ?)`,
+		r.GroupID.String(), r.People, unixNano(r.RequestedAt), unixNano(r.BoardedAt), unixNano(r.DroppedOffAt),
+		unixNano(r.AbandonedAt),
+	)
+	return err
+}
+
+// FindByGroupID is a finder
+func (hr JourneysHistoryRepository) FindByGroupID(ctx context.Context, groupID uuid.UUID) (app.JourneyRecord, error) {
+	var r journeyRecordRow
+	err := conn(ctx, hr.db).QueryRowContext(ctx,
+		`SELECT group_id, people, requested_at, boarded_at, dropped_off_at, abandoned_at FROM journeys_history WHERE group_id = ?`,
+		groupID.String(),
+	).Scan(&r.groupID, &r.people, &r.requestedAt, &r.boardedAt, &r.droppedOffAt, &r.abandonedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return app.JourneyRecord{}, repository.ErrNotFound
+	}
+	if err != nil {
+		return app.JourneyRecord{}, err
+	}
+	return r.record()
+}
+
+// FindWaitEndedSince returns the records of the groups that got on a car, or left without it, from the given time on
+func (hr JourneysHistoryRepository) FindWaitEndedSince(ctx context.Context, since time.Time) ([]app.JourneyRecord, error) {
+	rows, err := conn(ctx, hr.db).QueryContext(ctx,
+		`SELECT group_id, people, requested_at, boarded_at, dropped_off_at, abandoned_at FROM journeys_history
+		WHERE boarded_at >= ? OR abandoned_at >= ?`,
+		since.UnixNano(), since.UnixNano(),
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var records []app.JourneyRecord
+	for rows.Next() {
+		var r journeyRecordRow
+		if err := rows.Scan(&r.groupID, &r.people, &r.requestedAt, &r.boardedAt, &r.droppedOffAt, &r.abandonedAt); err != nil {
+			return nil, err
+		}
+		record, err := r.record()
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, record)
+	}
+	return records, rows.Err()
+}
+
+type journeyRecordRow struct {
+	groupID      string
+	people       int
+	requestedAt  int64
+	boardedAt    int64
+	droppedOffAt int64
+	abandonedAt  int64
+}
+
+func (r journeyRecordRow) record() (app.JourneyRecord, error) {
+	id, err := uuid.Parse(r.groupID)
+	if err != nil {
+		return app.JourneyRecord{}, err
+	}
+	return app.JourneyRecord{
+		GroupID:      id,
+		People:       r.people,
+		RequestedAt:  timeFromUnixNano(r.requestedAt),
+		BoardedAt:    timeFromUnixNano(r.boardedAt),
+		DroppedOffAt: timeFromUnixNano(r.droppedOffAt),
+		AbandonedAt:  timeFromUnixNano(r.abandonedAt),
+	}, nil
+}
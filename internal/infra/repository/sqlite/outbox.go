@@ -0,0 +1,90 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/domain"
+
+	"github.com/google/uuid"
+	"github.com/theskyinflames/cqrs-eda/pkg/events"
+)
+
+// Outbox implements the app.Outbox interface on top of the outbox table
+type Outbox struct {
+	db *sql.DB
+}
+
+// NewOutbox is a constructor
+func NewOutbox(db *sql.DB) Outbox {
+	return Outbox{db: db}
+}
+
+// Add is self-described
+func (ob Outbox) Add(ctx context.Context, evs []events.Event) error {
+	for _, e := range evs {
+		body, err := domain.EventBody(e)
+		if err != nil {
+			return err
+		}
+		if _, err := conn(ctx, ob.db).ExecContext(ctx,
+			`INSERT INTO outbox (id, name, aggregate_id, body, created_at) VALUES (?, ?, ?, ?, ?)`,
+			uuid.New().String(), e.Name(), e.AggregateID().String(), body, time.Now().UnixNano(),
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Pending is self-described
+func (ob Outbox) Pending(ctx context.Context, limit int) ([]app.OutboxMessage, error) {
+	rows, err := conn(ctx, ob.db).QueryContext(ctx,
+		`SELECT id, name, aggregate_id, body FROM outbox ORDER BY seq LIMIT ?`, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var msgs []app.OutboxMessage
+	for rows.Next() {
+		var (
+			id, name, aggregateID string
+			body                  []byte
+		)
+		if err := rows.Scan(&id, &name, &aggregateID, &body); err != nil {
+			return nil, err
+		}
+		m, err := outboxMessage(id, name, aggregateID, body)
+		if err != nil {
+			return nil, err
+		}
+		msgs = append(msgs, m)
+	}
+	return msgs, rows.Err()
+}
+
+// MarkDelivered removes the message from the outbox. It does nothing if the message is not there
+func (ob Outbox) MarkDelivered(ctx context.Context, id uuid.UUID) error {
+	_, err := conn(ctx, ob.db).ExecContext(ctx, `DELETE FROM outbox WHERE id = ?`, id.String())
+	return err
+}
+
+func outboxMessage(id, name, aggregateID string, body []byte) (app.OutboxMessage, error) {
+	mID, err := uuid.Parse(id)
+	if err != nil {
+		return app.OutboxMessage{}, err
+	}
+	aID, err := uuid.Parse(aggregateID)
+	if err != nil {
+		return app.OutboxMessage{}, err
+	}
+	e, err := domain.ParseEvent(name, aID, body)
+	if err != nil {
+		return app.OutboxMessage{}, err
+	}
+	return app.OutboxMessage{ID: mID, Event: e}, nil
+}
@@ -0,0 +1,121 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	"theskyinflames/car-sharing/internal/domain"
+	"theskyinflames/car-sharing/internal/infra/repository"
+
+	"github.com/google/uuid"
+)
+
+// ReservationsRepository is a repository
+type ReservationsRepository struct {
+	db *sql.DB
+}
+
+// NewReservationsRepository is a constructor
+func NewReservationsRepository(db *sql.DB) ReservationsRepository {
+	return ReservationsRepository{db: db}
+}
+
+// Add is self-described
+func (rr ReservationsRepository) Add(ctx context.Context, r domain.Reservation) error {
+	var exists bool
+	if err := conn(ctx, rr.db).QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM reservations WHERE id = ?)`, r.ID().String()).Scan(&exists); err != nil {
+		return err
+	}
+	if exists {
+		return repository.ErrPKConflict
+	}
+	_, err := conn(ctx, rr.db).ExecContext(ctx,
+		`INSERT INTO reservations (id, people, site, requirements, car_id, start_at, end_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		r.ID().String(), r.People(), string(r.Site()), joinList(r.Requirements().Strings()), uuidOrEmpty(r.CarID()),
+		r.StartAt().UnixNano(), r.EndAt().UnixNano(),
+	)
+	return err
+}
+
+// FindAll returns the reservations sorted by their start
+func (rr ReservationsRepository) FindAll(ctx context.Context) ([]domain.Reservation, error) {
+	rows, err := conn(ctx, rr.db).QueryContext(ctx,
+		`SELECT id, people, site, requirements, car_id, start_at, end_at FROM reservations ORDER BY start_at, seq`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rs []domain.Reservation
+	for rows.Next() {
+		var row reservationRow
+		if err := rows.Scan(&row.id, &row.people, &row.site, &row.requirements, &row.carID, &row.startAt, &row.endAt); err != nil {
+			return nil, err
+		}
+		r, err := row.reservation()
+		if err != nil {
+			return nil, err
+		}
+		rs = append(rs, r)
+	}
+	return rs, rows.Err()
+}
+
+// FindByID is a finder
+func (rr ReservationsRepository) FindByID(ctx context.Context, id uuid.UUID) (domain.Reservation, error) {
+	var row reservationRow
+	err := conn(ctx, rr.db).QueryRowContext(ctx,
+		`SELECT id, people, site, requirements, car_id, start_at, end_at FROM reservations WHERE id = ?`, id.String(),
+	).Scan(&row.id, &row.people, &row.site, &row.requirements, &row.carID, &row.startAt, &row.endAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return domain.Reservation{}, repository.ErrNotFound
+	}
+	if err != nil {
+		return domain.Reservation{}, err
+	}
+	return row.reservation()
+}
+
+// RemoveByID is self-described
+func (rr ReservationsRepository) RemoveByID(ctx context.Context, id uuid.UUID) error {
+	rs, err := conn(ctx, rr.db).ExecContext(ctx, `DELETE FROM reservations WHERE id = ?`, id.String())
+	if err != nil {
+		return err
+	}
+	n, err := rs.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return repository.ErrNotFound
+	}
+	return nil
+}
+
+type reservationRow struct {
+	id           string
+	people       int
+	site         string
+	requirements string
+	carID        string
+	startAt      int64
+	endAt        int64
+}
+
+func (row reservationRow) reservation() (domain.Reservation, error) {
+	id, err := uuid.Parse(row.id)
+	if err != nil {
+		return domain.Reservation{}, err
+	}
+	carID, err := parseUUIDOrNil(row.carID)
+	if err != nil {
+		return domain.Reservation{}, err
+	}
+	var r domain.Reservation
+	r.Hydrate(id, row.people, domain.Site(row.site), features(row.requirements), carID,
+		time.Unix(0, row.startAt), time.Unix(0, row.endAt))
+	return r, nil
+}
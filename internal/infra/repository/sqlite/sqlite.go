@@ -0,0 +1,141 @@
+// Package sqlite implements the repositories on top of a SQLite database
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	// SQLite driver. It's a pure Go implementation, so no CGO is needed
+	_ "modernc.org/sqlite"
+)
+
+// migrations are applied in order. Once released, a migration can't be changed, only new ones can be appended
+var migrations = []string{
+	`CREATE TABLE cars (
+		seq          INTEGER PRIMARY KEY AUTOINCREMENT,
+		id           TEXT    NOT NULL UNIQUE,
+		capacity     INTEGER NOT NULL,
+		reserved_for TEXT    NOT NULL DEFAULT ''
+	)`,
+	`CREATE TABLE passenger_groups (
+		seq          INTEGER PRIMARY KEY AUTOINCREMENT,
+		id           TEXT    NOT NULL UNIQUE,
+		people       INTEGER NOT NULL,
+		car_id       TEXT    NOT NULL DEFAULT '',
+		requested_at INTEGER NOT NULL,
+		overtaken    INTEGER NOT NULL DEFAULT 0
+	)`,
+	`CREATE TABLE journeys (
+		group_id TEXT NOT NULL PRIMARY KEY,
+		car_id   TEXT NOT NULL
+	)`,
+	`CREATE INDEX journeys_car_id ON journeys (car_id)`,
+	`ALTER TABLE cars ADD COLUMN version INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE passenger_groups ADD COLUMN version INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE passenger_groups ADD COLUMN boarded_at INTEGER NOT NULL DEFAULT 0`,
+	`CREATE TABLE journeys_history (
+		group_id       TEXT    NOT NULL PRIMARY KEY,
+		people         INTEGER NOT NULL,
+		requested_at   INTEGER NOT NULL,
+		boarded_at     INTEGER NOT NULL,
+		dropped_off_at INTEGER NOT NULL DEFAULT 0
+	)`,
+	`CREATE INDEX journeys_history_boarded_at ON journeys_history (boarded_at)`,
+	`CREATE TABLE outbox (
+		seq          INTEGER PRIMARY KEY AUTOINCREMENT,
+		id           TEXT    NOT NULL UNIQUE,
+		name         TEXT    NOT NULL,
+		aggregate_id TEXT    NOT NULL,
+		body         BLOB    NOT NULL,
+		created_at   INTEGER NOT NULL
+	)`,
+	`ALTER TABLE cars ADD COLUMN retiring INTEGER NOT NULL DEFAULT 0`,
+	`ALTER TABLE cars ADD COLUMN status TEXT NOT NULL DEFAULT 'available'`,
+	`ALTER TABLE cars ADD COLUMN position_lat REAL`,
+	`ALTER TABLE cars ADD COLUMN position_lng REAL`,
+	`ALTER TABLE passenger_groups ADD COLUMN pickup_lat REAL`,
+	`ALTER TABLE passenger_groups ADD COLUMN pickup_lng REAL`,
+	`ALTER TABLE cars ADD COLUMN site TEXT NOT NULL DEFAULT 'default'`,
+	`ALTER TABLE passenger_groups ADD COLUMN site TEXT NOT NULL DEFAULT 'default'`,
+	`ALTER TABLE passenger_groups ADD COLUMN destination TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE cars ADD COLUMN features TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE passenger_groups ADD COLUMN members TEXT NOT NULL DEFAULT ''`,
+	`ALTER TABLE passenger_groups ADD COLUMN requirements TEXT NOT NULL DEFAULT ''`,
+	`CREATE TABLE reservations (
+		seq      INTEGER PRIMARY KEY AUTOINCREMENT,
+		id       TEXT    NOT NULL UNIQUE,
+		people   INTEGER NOT NULL,
+		site     TEXT    NOT NULL,
+		car_id   TEXT    NOT NULL,
+		start_at INTEGER NOT NULL,
+		end_at   INTEGER NOT NULL
+	)`,
+	`ALTER TABLE reservations ADD COLUMN requirements TEXT NOT NULL DEFAULT ''`,
+	`CREATE TABLE webhooks (
+		seq    INTEGER PRIMARY KEY AUTOINCREMENT,
+		id     TEXT    NOT NULL UNIQUE,
+		url    TEXT    NOT NULL,
+		events TEXT    NOT NULL,
+		secret TEXT    NOT NULL
+	)`,
+	`CREATE TABLE dead_letters (
+		seq        INTEGER PRIMARY KEY AUTOINCREMENT,
+		webhook_id TEXT    NOT NULL,
+		url        TEXT    NOT NULL,
+		event      TEXT    NOT NULL,
+		payload    BLOB    NOT NULL,
+		attempts   INTEGER NOT NULL,
+		error      TEXT    NOT NULL,
+		failed_at  INTEGER NOT NULL
+	)`,
+	`ALTER TABLE journeys_history ADD COLUMN abandoned_at INTEGER NOT NULL DEFAULT 0`,
+	`CREATE INDEX journeys_history_abandoned_at ON journeys_history (abandoned_at)`,
+}
+
+// Open opens the SQLite database and applies the pending migrations
+func Open(ctx context.Context, dsn string) (*sql.DB, error) {
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	// SQLite serializes the writes, so a single connection avoids "database is locked" errors
+	db.SetMaxOpenConns(1)
+
+	if err := migrate(ctx, db); err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return db, nil
+}
+
+func migrate(ctx context.Context, db *sql.DB) error {
+	if _, err := db.ExecContext(ctx, `CREATE TABLE IF NOT EXISTS schema_migrations (version INTEGER NOT NULL)`); err != nil {
+		return err
+	}
+
+	var version int
+	if err := db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM schema_migrations`).Scan(&version); err != nil {
+		return err
+	}
+
+	for i := version; i < len(migrations); i++ {
+		tx, err := db.BeginTx(ctx, nil)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, migrations[i]); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO schema_migrations (version) VALUES (?)`, i+1); err != nil {
+			_ = tx.Rollback()
+			return fmt.Errorf("migration %d: %w", i+1, err)
+		}
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
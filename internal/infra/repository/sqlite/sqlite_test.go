@@ -0,0 +1,71 @@
+package sqlite_test
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/infra/repository/repositorytest"
+	"theskyinflames/car-sharing/internal/infra/repository/sqlite"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSQLiteRepositories(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) (app.GroupsRepository, app.CarsRepository, app.UnitOfWork) {
+		db, err := sqlite.Open(context.Background(), filepath.Join(t.TempDir(), "car-sharing.db"))
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		return sqlite.NewGroupsRepository(db), sqlite.NewCarRepository(db), sqlite.NewUnitOfWork(db)
+	})
+}
+
+func TestSQLiteJourneysHistoryRepository(t *testing.T) {
+	repositorytest.RunJourneysHistory(t, func(t *testing.T) app.JourneysHistoryRepository {
+		db, err := sqlite.Open(context.Background(), filepath.Join(t.TempDir(), "car-sharing.db"))
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		return sqlite.NewJourneysHistoryRepository(db)
+	})
+}
+
+func TestOpen(t *testing.T) {
+	t.Run(`Given an already migrated database, when it's opened again, then no error is returned`, func(t *testing.T) {
+		dsn := filepath.Join(t.TempDir(), "car-sharing.db")
+		db, err := sqlite.Open(context.Background(), dsn)
+		require.NoError(t, err)
+		require.NoError(t, db.Close())
+
+		db, err = sqlite.Open(context.Background(), dsn)
+		require.NoError(t, err)
+		require.NoError(t, db.Close())
+	})
+}
+
+func TestSQLiteOutbox(t *testing.T) {
+	repositorytest.RunOutbox(t, func(t *testing.T) (app.Outbox, app.UnitOfWork) {
+		db, err := sqlite.Open(context.Background(), filepath.Join(t.TempDir(), "car-sharing.db"))
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		return sqlite.NewOutbox(db), sqlite.NewUnitOfWork(db)
+	})
+}
+
+func TestSQLiteReservationsRepository(t *testing.T) {
+	repositorytest.RunReservations(t, func(t *testing.T) (app.ReservationsRepository, app.UnitOfWork) {
+		db, err := sqlite.Open(context.Background(), filepath.Join(t.TempDir(), "car-sharing.db"))
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		return sqlite.NewReservationsRepository(db), sqlite.NewUnitOfWork(db)
+	})
+}
+
+func TestSQLiteWebhooksRepository(t *testing.T) {
+	repositorytest.RunWebhooks(t, func(t *testing.T) app.WebhooksRepository {
+		db, err := sqlite.Open(context.Background(), filepath.Join(t.TempDir(), "car-sharing.db"))
+		require.NoError(t, err)
+		t.Cleanup(func() { db.Close() })
+		return sqlite.NewWebhooksRepository(db)
+	})
+}
@@ -0,0 +1,50 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+)
+
+// querier is implemented by both *sql.DB and *sql.Tx
+type querier interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type txKey struct{}
+
+// conn returns the transaction of the unit of work in progress, if any. Otherwise, the database is returned
+func conn(ctx context.Context, db *sql.DB) querier {
+	if tx, ok := ctx.Value(txKey{}).(*sql.Tx); ok {
+		return tx
+	}
+	return db
+}
+
+// UnitOfWork implements the app.UnitOfWork interface on top of a SQLite transaction
+type UnitOfWork struct {
+	db *sql.DB
+}
+
+// NewUnitOfWork is a constructor
+func NewUnitOfWork(db *sql.DB) UnitOfWork {
+	return UnitOfWork{db: db}
+}
+
+// Do implements the app.UnitOfWork interface
+func (uow UnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*sql.Tx); ok { // already inside a unit of work, it joins it
+		return fn(ctx)
+	}
+
+	tx, err := uow.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(context.WithValue(ctx, txKey{}, tx)); err != nil {
+		_ = tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,102 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/infra/repository"
+
+	"github.com/google/uuid"
+)
+
+// WebhooksRepository is a repository
+type WebhooksRepository struct {
+	db *sql.DB
+}
+
+// NewWebhooksRepository is a constructor
+func NewWebhooksRepository(db *sql.DB) WebhooksRepository {
+	return WebhooksRepository{db: db}
+}
+
+// Add is self-described
+func (wr WebhooksRepository) Add(ctx context.Context, wh app.Webhook) error {
+	var exists bool
+	if err := conn(ctx, wr.db).QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM webhooks WHERE id = ?)`, wh.ID.String()).Scan(&exists); err != nil {
+		return err
+	}
+	if exists {
+		return repository.ErrPKConflict
+	}
+	_, err := conn(ctx, wr.db).ExecContext(ctx,
+		`INSERT INTO webhooks (id, url, events, secret) VALUES (?, ?, ?, ?)`,
+		wh.ID.String(), wh.URL, joinList(wh.Events), wh.Secret,
+	)
+	return err
+}
+
+// FindByEvent returns the webhooks subscribed to the event, in the order they were added
+func (wr WebhooksRepository) FindByEvent(ctx context.Context, name string) ([]app.Webhook, error) {
+	rows, err := conn(ctx, wr.db).QueryContext(ctx, `SELECT id, url, events, secret FROM webhooks ORDER BY seq`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var whs []app.Webhook
+	for rows.Next() {
+		var id, events string
+		wh := app.Webhook{}
+		if err := rows.Scan(&id, &wh.URL, &events, &wh.Secret); err != nil {
+			return nil, err
+		}
+		if wh.ID, err = uuid.Parse(id); err != nil {
+			return nil, err
+		}
+		wh.Events = splitList(events)
+		if wh.Subscribes(name) {
+			whs = append(whs, wh)
+		}
+	}
+	return whs, rows.Err()
+}
+
+// AddDeadLetter is self-described
+func (wr WebhooksRepository) AddDeadLetter(ctx context.Context, dl app.DeadLetter) error {
+	_, err := conn(ctx, wr.db).ExecContext(ctx,
+		`INSERT INTO dead_letters (webhook_id, url, event, payload, attempts, error, failed_at) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		dl.WebhookID.String(), dl.URL, dl.Event, dl.Payload, dl.Attempts, dl.Err, dl.FailedAt.UnixNano(),
+	)
+	return err
+}
+
+// FindDeadLetters returns the dead letters in the order they were added
+func (wr WebhooksRepository) FindDeadLetters(ctx context.Context) ([]app.DeadLetter, error) {
+	rows, err := conn(ctx, wr.db).QueryContext(ctx,
+		`SELECT webhook_id, url, event, payload, attempts, error, failed_at FROM dead_letters ORDER BY seq`,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var dls []app.DeadLetter
+	for rows.Next() {
+		var (
+			webhookID string
+			failedAt  int64
+			dl        app.DeadLetter
+		)
+		if err := rows.Scan(&webhookID, &dl.URL, &dl.Event, &dl.Payload, &dl.Attempts, &dl.Err, &failedAt); err != nil {
+			return nil, err
+		}
+		if dl.WebhookID, err = uuid.Parse(webhookID); err != nil {
+			return nil, err
+		}
+		dl.FailedAt = time.Unix(0, failedAt)
+		dls = append(dls, dl)
+	}
+	return dls, rows.Err()
+}
@@ -0,0 +1,136 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/domain"
+
+	"github.com/google/uuid"
+)
+
+type inTxKey struct{}
+
+// UnitOfWork implements the app.UnitOfWork interface for the in-memory repositories.
+// Given that there is no isolation between concurrent changes, the units of work are serialized.
+// A snapshot of the repositories is taken when the unit of work starts, and it's restored if it fails.
+type UnitOfWork struct {
+	gr *GroupsRepository
+	cr *CarRepository
+	ob *Outbox
+	rr *ReservationsRepository
+
+	mux *sync.Mutex
+}
+
+// NewUnitOfWork is a constructor
+func NewUnitOfWork(gr *GroupsRepository, cr *CarRepository) UnitOfWork {
+	return UnitOfWork{gr: gr, cr: cr, mux: &sync.Mutex{}}
+}
+
+// WithOutbox returns the unit of work also taking a snapshot of the outbox
+func (uow UnitOfWork) WithOutbox(ob *Outbox) UnitOfWork {
+	uow.ob = ob
+	return uow
+}
+
+// WithReservations returns the unit of work also taking a snapshot of the reservations
+func (uow UnitOfWork) WithReservations(rr *ReservationsRepository) UnitOfWork {
+	uow.rr = rr
+	return uow
+}
+
+// Do implements the app.UnitOfWork interface
+func (uow UnitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if ctx.Value(inTxKey{}) != nil { // already inside a unit of work, it joins it
+		return fn(ctx)
+	}
+
+	uow.mux.Lock()
+	defer uow.mux.Unlock()
+
+	groups := uow.gr.snapshot()
+	cars := uow.cr.snapshot()
+	var msgs []app.OutboxMessage
+	if uow.ob != nil {
+		msgs = uow.ob.snapshot()
+	}
+	var reservations map[uuid.UUID]domain.Reservation
+	if uow.rr != nil {
+		reservations = uow.rr.snapshot()
+	}
+
+	if err := fn(context.WithValue(ctx, inTxKey{}, struct{}{})); err != nil {
+		uow.gr.restore(groups)
+		uow.cr.restore(cars)
+		if uow.ob != nil {
+			uow.ob.restore(msgs)
+		}
+		if uow.rr != nil {
+			uow.rr.restore(reservations)
+		}
+		return err
+	}
+	return nil
+}
+
+type groupsSnapshot struct {
+	groups   map[uuid.UUID]domain.Group
+	arrivals map[uuid.UUID]uint64
+	seq      uint64
+}
+
+func (gr *GroupsRepository) snapshot() groupsSnapshot {
+	gr.mux.RLock()
+	defer gr.mux.RUnlock()
+
+	s := groupsSnapshot{
+		groups:   make(map[uuid.UUID]domain.Group, len(gr.groups)),
+		arrivals: make(map[uuid.UUID]uint64, len(gr.arrivals)),
+		seq:      *gr.seq,
+	}
+	for id, g := range gr.groups {
+		s.groups[id] = g
+	}
+	for id, a := range gr.arrivals {
+		s.arrivals[id] = a
+	}
+	return s
+}
+
+func (gr *GroupsRepository) restore(s groupsSnapshot) {
+	gr.mux.Lock()
+	defer gr.mux.Unlock()
+
+	gr.groups = s.groups
+	gr.arrivals = s.arrivals
+	*gr.seq = s.seq
+}
+
+type carsSnapshot struct {
+	cars map[uuid.UUID]domain.Car
+	ids  []uuid.UUID
+}
+
+func (cr *CarRepository) snapshot() carsSnapshot {
+	cr.mux.RLock()
+	defer cr.mux.RUnlock()
+
+	s := carsSnapshot{
+		cars: make(map[uuid.UUID]domain.Car, len(cr.cars)),
+		ids:  append([]uuid.UUID{}, *cr.ids...),
+	}
+	for id, car := range cr.cars {
+		s.cars[id] = car
+	}
+	return s
+}
+
+func (cr *CarRepository) restore(s carsSnapshot) {
+	cr.mux.Lock()
+	defer cr.mux.Unlock()
+
+	cr.cars = s.cars
+	*cr.ids = s.ids
+}
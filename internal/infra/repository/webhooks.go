@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"sync"
+
+	"theskyinflames/car-sharing/internal/app"
+)
+
+// WebhooksRepository is a repository
+type WebhooksRepository struct {
+	webhooks    *[]app.Webhook
+	deadLetters *[]app.DeadLetter
+
+	mux *sync.RWMutex
+}
+
+// NewWebhooksRepository is a constructor
+func NewWebhooksRepository() WebhooksRepository {
+	return WebhooksRepository{webhooks: &[]app.Webhook{}, deadLetters: &[]app.DeadLetter{}, mux: &sync.RWMutex{}}
+}
+
+// Add is self-described
+func (wr WebhooksRepository) Add(_ context.Context, wh app.Webhook) error {
+	wr.mux.Lock()
+	defer wr.mux.Unlock()
+
+	for _, stored := range *wr.webhooks {
+		if stored.ID == wh.ID {
+			return ErrPKConflict
+		}
+	}
+	wh.Events = append([]string{}, wh.Events...)
+	*wr.webhooks = append(*wr.webhooks, wh)
+	return nil
+}
+
+// FindByEvent returns the webhooks subscribed to the event, in the order they were added
+func (wr WebhooksRepository) FindByEvent(_ context.Context, name string) ([]app.Webhook, error) {
+	wr.mux.RLock()
+	defer wr.mux.RUnlock()
+
+	var whs []app.Webhook
+	for _, wh := range *wr.webhooks {
+		if wh.Subscribes(name) {
+			whs = append(whs, wh)
+		}
+	}
+	return whs, nil
+}
+
+// AddDeadLetter is self-described
+func (wr WebhooksRepository) AddDeadLetter(_ context.Context, dl app.DeadLetter) error {
+	wr.mux.Lock()
+	defer wr.mux.Unlock()
+
+	*wr.deadLetters = append(*wr.deadLetters, dl)
+	return nil
+}
+
+// FindDeadLetters returns the dead letters in the order they were added
+func (wr WebhooksRepository) FindDeadLetters(_ context.Context) ([]app.DeadLetter, error) {
+	wr.mux.RLock()
+	defer wr.mux.RUnlock()
+
+	return append([]app.DeadLetter{}, *wr.deadLetters...), nil
+}
@@ -0,0 +1,62 @@
+// Package webhooks sends the payloads of the webhooks through HTTP
+package webhooks
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	"theskyinflames/car-sharing/internal/app"
+)
+
+// Headers sent with each payload
+const (
+	EventHeader     = "X-Car-Sharing-Event"
+	SignatureHeader = "X-Car-Sharing-Signature"
+)
+
+// Sign returns the signature of the payload: the hex encoded HMAC-SHA256 of the payload with the secret, prefixed by sha256=
+func Sign(secret string, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(payload)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Sender implements the app.WebhookSender interface. The payload is POSTed to the webhook URL, signed with its secret
+type Sender struct {
+	client *http.Client
+}
+
+// NewSender is a constructor. The timeout applies to each attempt
+func NewSender(timeout time.Duration) Sender {
+	return Sender{client: &http.Client{Timeout: timeout}}
+}
+
+// Send implements the app.WebhookSender interface. Any response status other than 2xx is an error
+func (s Sender) Send(ctx context.Context, wh app.Webhook, event string, payload []byte) error {
+	rq, err := http.NewRequestWithContext(ctx, http.MethodPost, wh.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	rq.Header.Set("Content-Type", "application/json")
+	rq.Header.Set(EventHeader, event)
+	rq.Header.Set(SignatureHeader, Sign(wh.Secret, payload))
+
+	rs, err := s.client.Do(rq)
+	if err != nil {
+		return err
+	}
+	defer rs.Body.Close()
+	_, _ = io.Copy(io.Discard, rs.Body)
+
+	if rs.StatusCode < 200 || rs.StatusCode > 299 {
+		return fmt.Errorf("unexpected status %d", rs.StatusCode)
+	}
+	return nil
+}
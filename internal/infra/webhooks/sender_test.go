@@ -0,0 +1,62 @@
+package webhooks_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"theskyinflames/car-sharing/internal/app"
+	"theskyinflames/car-sharing/internal/infra/webhooks"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSender(t *testing.T) {
+	payload := []byte(`{"event":"group.dropped.off"}`)
+	testCases := []struct {
+		name        string
+		status      int
+		expectedErr bool
+	}{
+		{
+			name:   `Given a webhook that responds 2xx, when a payload is sent, then it's signed and no error is returned`,
+			status: http.StatusNoContent,
+		},
+		{
+			name:        `Given a webhook that responds 5xx, when a payload is sent, then an error is returned`,
+			status:      http.StatusServiceUnavailable,
+			expectedErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var got *http.Request
+			var body []byte
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				got = r
+				body, _ = io.ReadAll(r.Body)
+				w.WriteHeader(tc.status)
+			}))
+			defer srv.Close()
+
+			wh := app.Webhook{URL: srv.URL, Secret: "secret"}
+			err := webhooks.NewSender(time.Second).Send(context.Background(), wh, "group.dropped.off", payload)
+			require.Equal(t, tc.expectedErr, err != nil)
+
+			require.Equal(t, http.MethodPost, got.Method)
+			require.Equal(t, "application/json", got.Header.Get("Content-Type"))
+			require.Equal(t, "group.dropped.off", got.Header.Get(webhooks.EventHeader))
+			require.Equal(t, webhooks.Sign("secret", payload), got.Header.Get(webhooks.SignatureHeader))
+			require.Equal(t, payload, body)
+		})
+	}
+}
+
+func TestSign(t *testing.T) {
+	// echo -n '{}' | openssl dgst -sha256 -hmac secret
+	require.Equal(t, "sha256=77325902caca812dc259733aacd046b73817372c777b8d95b402647474516e13", webhooks.Sign("secret", []byte(`{}`)))
+}